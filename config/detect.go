@@ -0,0 +1,102 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EnvUnknown is returned by the Detect functions when an input does not
+// belong to exactly one built-in environment.
+const EnvUnknown = "unknown"
+
+// envProgramIDs lists the well-known program IDs and oracle public keys of each
+// built-in environment.
+var envProgramIDs = map[string][]string{
+	EnvMainnetBeta: {
+		MainnetServiceabilityProgramID,
+		MainnetTelemetryProgramID,
+		MainnetRevenueDistributionProgramID,
+		MainnetGeolocationProgramID,
+		MainnetShredSubscriptionProgramID,
+		MainnetInternetLatencyCollectorPK,
+	},
+	EnvTestnet: {
+		TestnetServiceabilityProgramID,
+		TestnetTelemetryProgramID,
+		TestnetGeolocationProgramID,
+		TestnetShredSubscriptionProgramID,
+		TestnetInternetLatencyCollectorPK,
+	},
+	EnvDevnet: {
+		DevnetServiceabilityProgramID,
+		DevnetTelemetryProgramID,
+		DevnetGeolocationProgramID,
+		DevnetShredSubscriptionProgramID,
+		DevnetInternetLatencyCollectorPK,
+	},
+	EnvLocalnet: {
+		LocalnetServiceabilityProgramID,
+		LocalnetTelemetryProgramID,
+		LocalnetGeolocationProgramID,
+		LocalnetShredSubscriptionProgramID,
+		LocalnetInternetLatencyCollectorPK,
+	},
+}
+
+// envRPCURLs lists the default ledger and Solana RPC URLs of each built-in
+// environment, matching what NetworkConfigForEnv uses.
+var envRPCURLs = map[string][]string{
+	EnvMainnetBeta: {MainnetLedgerPublicRPCURL, MainnetSolanaRPC},
+	EnvTestnet:     {TestnetLedgerPublicRPCURL, TestnetSolanaRPC},
+	EnvDevnet:      {DevnetLedgerPublicRPCURL, TestnetSolanaRPC},
+	EnvLocalnet:    {LocalnetLedgerPublicRPCURL, LocalnetSolanaRPC},
+}
+
+// DetectEnvFromProgramID returns the environment a base58 program ID or oracle
+// public key belongs to. IDs shared by several environments (e.g. the devnet
+// and localnet telemetry program) and unrecognized IDs return EnvUnknown.
+func DetectEnvFromProgramID(pid string) string {
+	pid = strings.TrimSpace(pid)
+	if pid == "" {
+		return EnvUnknown
+	}
+	return detectEnv(envProgramIDs, func(id string) bool { return id == pid })
+}
+
+// DetectEnvFromRPCURL returns the environment whose default ledger or Solana
+// RPC endpoint has the same host as rpcURL. The path is ignored so that API
+// keys embedded in it do not matter. Hosts shared by several environments
+// (e.g. the testnet/devnet ledger) and unrecognized URLs return EnvUnknown.
+func DetectEnvFromRPCURL(rpcURL string) string {
+	host := rpcHost(rpcURL)
+	if host == "" {
+		return EnvUnknown
+	}
+	return detectEnv(envRPCURLs, func(u string) bool { return rpcHost(u) == host })
+}
+
+// detectEnv returns the single environment with a value matching match.
+func detectEnv(values map[string][]string, match func(string) bool) string {
+	found := EnvUnknown
+	for env, candidates := range values {
+		for _, v := range candidates {
+			if v == "" || !match(v) {
+				continue
+			}
+			if found != EnvUnknown && found != env {
+				return EnvUnknown
+			}
+			found = env
+			break
+		}
+	}
+	return found
+}
+
+func rpcHost(rpcURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rpcURL))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
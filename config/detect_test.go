@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/malbeclabs/doublezero/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_DetectEnvFromProgramID(t *testing.T) {
+	tests := []struct {
+		pid  string
+		want string
+	}{
+		{config.MainnetServiceabilityProgramID, config.EnvMainnetBeta},
+		{config.MainnetTelemetryProgramID, config.EnvMainnetBeta},
+		{config.MainnetRevenueDistributionProgramID, config.EnvMainnetBeta},
+		{config.MainnetGeolocationProgramID, config.EnvMainnetBeta},
+		{config.MainnetInternetLatencyCollectorPK, config.EnvMainnetBeta},
+
+		{config.TestnetServiceabilityProgramID, config.EnvTestnet},
+		{config.TestnetTelemetryProgramID, config.EnvTestnet},
+		{config.TestnetGeolocationProgramID, config.EnvTestnet},
+		{config.TestnetInternetLatencyCollectorPK, config.EnvTestnet},
+
+		{config.DevnetServiceabilityProgramID, config.EnvDevnet},
+		{config.DevnetGeolocationProgramID, config.EnvDevnet},
+
+		{config.LocalnetServiceabilityProgramID, config.EnvLocalnet},
+		{config.LocalnetGeolocationProgramID, config.EnvLocalnet},
+
+		// Shared across environments.
+		{config.DevnetTelemetryProgramID, config.EnvUnknown},
+		{config.DevnetInternetLatencyCollectorPK, config.EnvUnknown},
+		{config.MainnetShredSubscriptionProgramID, config.EnvUnknown},
+
+		{"", config.EnvUnknown},
+		{"11111111111111111111111111111111", config.EnvUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pid, func(t *testing.T) {
+			require.Equal(t, tt.want, config.DetectEnvFromProgramID(tt.pid))
+		})
+	}
+}
+
+func TestConfig_DetectEnvFromRPCURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{config.MainnetLedgerPublicRPCURL, config.EnvMainnetBeta},
+		{"https://doublezero-mainnet-beta.rpcpool.com/another-api-key", config.EnvMainnetBeta},
+		{config.MainnetSolanaRPC, config.EnvMainnetBeta},
+		{"HTTPS://API.MAINNET-BETA.SOLANA.COM/", config.EnvMainnetBeta},
+
+		{config.LocalnetLedgerPublicRPCURL, config.EnvLocalnet},
+		{config.LocalnetSolanaRPC, config.EnvLocalnet},
+
+		// Shared by testnet and devnet.
+		{config.TestnetLedgerPublicRPCURL, config.EnvUnknown},
+		{config.DevnetLedgerPublicRPCURL, config.EnvUnknown},
+		{config.TestnetSolanaRPC, config.EnvUnknown},
+
+		{"", config.EnvUnknown},
+		{"https://rpc.example.com", config.EnvUnknown},
+		{"://bad", config.EnvUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			require.Equal(t, tt.want, config.DetectEnvFromRPCURL(tt.url))
+		})
+	}
+}
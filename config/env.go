@@ -33,10 +33,27 @@ type NetworkConfig struct {
 	USDCMint                      string
 }
 
+// NetworkConfigForEnv returns the network config for an exact environment
+// name. Only the canonical names and "mainnet" are accepted, since callers
+// compare the name they passed against the Env* constants; use
+// ParseEnvironment and NetworkConfigForEnvironment to accept aliases.
 func NetworkConfigForEnv(env string) (*NetworkConfig, error) {
-	var config *NetworkConfig
 	switch env {
 	case EnvMainnetBeta, EnvMainnet:
+		return NetworkConfigForEnvironment(EnvironmentMainnetBeta)
+	case EnvTestnet, EnvDevnet, EnvLocalnet:
+		return NetworkConfigForEnvironment(Environment(env))
+	default:
+		return nil, invalidEnvironmentError(env)
+	}
+}
+
+// NetworkConfigForEnvironment returns the network config for env, applying the
+// DZ_LEDGER_RPC_URL and SOLANA_RPC_URL overrides.
+func NetworkConfigForEnvironment(env Environment) (*NetworkConfig, error) {
+	var config *NetworkConfig
+	switch env {
+	case EnvironmentMainnetBeta:
 		serviceabilityProgramID, err := solana.PublicKeyFromBase58(MainnetServiceabilityProgramID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse serviceability program ID: %w", err)
@@ -74,7 +91,7 @@ func NetworkConfigForEnv(env string) (*NetworkConfig, error) {
 			TelemetryStateIngestURL:       MainnetTelemetryStateIngestURL,
 			TelemetryGNMITunnelServerAddr: MainnetTelemetryGNMITunnelServerAddr,
 		}
-	case EnvTestnet:
+	case EnvironmentTestnet:
 		serviceabilityProgramID, err := solana.PublicKeyFromBase58(TestnetServiceabilityProgramID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse serviceability program ID: %w", err)
@@ -107,7 +124,7 @@ func NetworkConfigForEnv(env string) (*NetworkConfig, error) {
 			TelemetryStateIngestURL:       TestnetTelemetryStateIngestURL,
 			TelemetryGNMITunnelServerAddr: TestnetTelemetryGNMITunnelServerAddr,
 		}
-	case EnvDevnet:
+	case EnvironmentDevnet:
 		serviceabilityProgramID, err := solana.PublicKeyFromBase58(DevnetServiceabilityProgramID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse serviceability program ID: %w", err)
@@ -140,7 +157,7 @@ func NetworkConfigForEnv(env string) (*NetworkConfig, error) {
 			TelemetryStateIngestURL:       DevnetTelemetryStateIngestURL,
 			TelemetryGNMITunnelServerAddr: DevnetTelemetryGNMITunnelServerAddr,
 		}
-	case EnvLocalnet:
+	case EnvironmentLocalnet:
 		serviceabilityProgramID, err := solana.PublicKeyFromBase58(LocalnetServiceabilityProgramID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse serviceability program ID: %w", err)
@@ -174,8 +191,7 @@ func NetworkConfigForEnv(env string) (*NetworkConfig, error) {
 			TelemetryGNMITunnelServerAddr: LocalnetTelemetryGNMITunnelServerAddr,
 		}
 	default:
-		// We intentionally do not include localnet in the error message.
-		return nil, fmt.Errorf("invalid environment %q, must be one of: %s, %s, %s", env, EnvMainnetBeta, EnvTestnet, EnvDevnet)
+		return nil, invalidEnvironmentError(string(env))
 	}
 
 	// Validate shred subscription program ID if set (empty means not yet deployed to this env).
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment is a DoubleZero network environment. Its value is the canonical
+// environment name, so it can be used wherever the Env* string constants are.
+type Environment string
+
+const (
+	EnvironmentMainnetBeta Environment = EnvMainnetBeta
+	EnvironmentTestnet     Environment = EnvTestnet
+	EnvironmentDevnet      Environment = EnvDevnet
+	EnvironmentLocalnet    Environment = EnvLocalnet
+)
+
+// environmentAliases maps lowercase names accepted by ParseEnvironment to their
+// environment.
+var environmentAliases = map[string]Environment{
+	EnvMainnetBeta: EnvironmentMainnetBeta,
+	EnvMainnet:     EnvironmentMainnetBeta,
+	"mainnet_beta": EnvironmentMainnetBeta,
+	EnvTestnet:     EnvironmentTestnet,
+	"test":         EnvironmentTestnet,
+	EnvDevnet:      EnvironmentDevnet,
+	"dev":          EnvironmentDevnet,
+	EnvLocalnet:    EnvironmentLocalnet,
+	"local":        EnvironmentLocalnet,
+}
+
+// ParseEnvironment returns the environment named by s. Matching is
+// case-insensitive, ignores surrounding whitespace, and accepts common aliases
+// such as "mainnet" for mainnet-beta.
+func ParseEnvironment(s string) (Environment, error) {
+	if env, ok := environmentAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return env, nil
+	}
+	return "", invalidEnvironmentError(s)
+}
+
+func invalidEnvironmentError(s string) error {
+	// We intentionally do not include localnet in the error message.
+	return fmt.Errorf("invalid environment %q, must be one of: %s, %s, %s", s, EnvMainnetBeta, EnvTestnet, EnvDevnet)
+}
+
+// String returns the canonical environment name, e.g. "mainnet-beta".
+func (e Environment) String() string {
+	return string(e)
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/malbeclabs/doublezero/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ParseEnvironment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want config.Environment
+	}{
+		{"mainnet-beta", config.EnvironmentMainnetBeta},
+		{"mainnet", config.EnvironmentMainnetBeta},
+		{"Mainnet-Beta", config.EnvironmentMainnetBeta},
+		{"MAINNET", config.EnvironmentMainnetBeta},
+		{"mainnet_beta", config.EnvironmentMainnetBeta},
+		{"testnet", config.EnvironmentTestnet},
+		{"test", config.EnvironmentTestnet},
+		{" devnet ", config.EnvironmentDevnet},
+		{"DEV", config.EnvironmentDevnet},
+		{"localnet", config.EnvironmentLocalnet},
+		{"local", config.EnvironmentLocalnet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := config.ParseEnvironment(tt.in)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_ParseEnvironment_Invalid(t *testing.T) {
+	for _, in := range []string{"", "invalid", "mainnet-alpha", "devnett"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := config.ParseEnvironment(in)
+			require.EqualError(t, err, `invalid environment "`+in+`", must be one of: mainnet-beta, testnet, devnet`)
+		})
+	}
+}
+
+func TestConfig_Environment_String(t *testing.T) {
+	require.Equal(t, "mainnet-beta", config.EnvironmentMainnetBeta.String())
+	require.Equal(t, "testnet", config.EnvironmentTestnet.String())
+	require.Equal(t, "devnet", config.EnvironmentDevnet.String())
+	require.Equal(t, "localnet", config.EnvironmentLocalnet.String())
+
+	// String round-trips through ParseEnvironment.
+	for _, env := range []config.Environment{config.EnvironmentMainnetBeta, config.EnvironmentTestnet, config.EnvironmentDevnet, config.EnvironmentLocalnet} {
+		got, err := config.ParseEnvironment(env.String())
+		require.NoError(t, err)
+		require.Equal(t, env, got)
+	}
+}
+
+func TestConfig_NetworkConfigForEnvironment(t *testing.T) {
+	got, err := config.NetworkConfigForEnvironment(config.EnvironmentDevnet)
+	require.NoError(t, err)
+	want, err := config.NetworkConfigForEnv(config.EnvDevnet)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// The string form stays strict; aliases go through ParseEnvironment.
+	_, err = config.NetworkConfigForEnv("MAINNET")
+	require.EqualError(t, err, `invalid environment "MAINNET", must be one of: mainnet-beta, testnet, devnet`)
+	_, err = config.NetworkConfigForEnv("dev")
+	require.EqualError(t, err, `invalid environment "dev", must be one of: mainnet-beta, testnet, devnet`)
+	env, err := config.ParseEnvironment("MAINNET")
+	require.NoError(t, err)
+	got, err = config.NetworkConfigForEnvironment(env)
+	require.NoError(t, err)
+	require.Equal(t, config.EnvMainnetBeta, got.Moniker)
+
+	// An unparsed Environment value is rejected rather than treated as an alias.
+	_, err = config.NetworkConfigForEnvironment(config.Environment("Devnet"))
+	require.EqualError(t, err, `invalid environment "Devnet", must be one of: mainnet-beta, testnet, devnet`)
+}
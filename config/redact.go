@@ -0,0 +1,181 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces credentials removed by RedactURI, matching the
+// placeholder used by url.URL.Redacted.
+const RedactedValue = "xxxxx"
+
+// sensitiveParamParts are substrings of query parameter and connection string
+// keys whose values are credentials, e.g. password, sslpassword, token,
+// access_key_id, secret_access_key, X-Amz-Signature and X-Amz-Credential.
+var sensitiveParamParts = []string{"pass", "pwd", "secret", "token", "key", "signature", "credential", "authorization"}
+
+// s3Schemes put the access key ID in the userinfo username, so it is redacted
+// along with the secret key.
+var s3Schemes = map[string]bool{"s3": true, "s3a": true, "s3n": true, "gs": true}
+
+var (
+	// keywordPairRE matches key=value pairs in keyword/value connection
+	// strings such as "host=db user=lake password=secret".
+	keywordPairRE = regexp.MustCompile(`([A-Za-z_][\w.-]*)=('(?:[^'\\]|\\.)*'|[^\s&;]*)`)
+	// userinfoRE matches the userinfo of a URI embedded in free text.
+	userinfoRE = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://)([^\s/@:]*)(:[^\s/@]*)?@`)
+)
+
+func isSensitiveParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveParamParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURI returns uri with any credentials replaced by RedactedValue, for
+// logging. It handles URIs with a user:pass@ userinfo (postgres, influx,
+// http), credentials in query parameters (S3 access keys and presigned
+// signatures, postgres password and sslpassword, influx tokens), S3-style URIs
+// carrying the access key as the username, and keyword/value connection
+// strings. Input without credentials is returned unchanged.
+func RedactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.User == nil) {
+		return redactText(uri)
+	}
+
+	changed := false
+	if u.User != nil {
+		username := u.User.Username()
+		_, hasPassword := u.User.Password()
+		switch {
+		case s3Schemes[strings.ToLower(u.Scheme)]:
+			if hasPassword {
+				u.User = url.UserPassword(RedactedValue, RedactedValue)
+			} else {
+				u.User = url.User(RedactedValue)
+			}
+			changed = true
+		case hasPassword:
+			u.User = url.UserPassword(username, RedactedValue)
+			changed = true
+		}
+	}
+	if u.RawQuery != "" {
+		if q, ok := redactQuery(u.RawQuery); ok {
+			u.RawQuery = q
+			changed = true
+		}
+	}
+	if !changed {
+		return uri
+	}
+	return u.String()
+}
+
+// redactQuery redacts the values of sensitive parameters in a raw query,
+// preserving parameter order and encoding. It reports whether anything changed.
+func redactQuery(rawQuery string) (string, bool) {
+	params := strings.Split(rawQuery, "&")
+	changed := false
+	for i, param := range params {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok || value == "" {
+			continue
+		}
+		if name, err := url.QueryUnescape(key); err == nil {
+			key = name
+		}
+		if isSensitiveParam(key) {
+			params[i] = param[:strings.IndexByte(param, '=')+1] + RedactedValue
+			changed = true
+		}
+	}
+	return strings.Join(params, "&"), changed
+}
+
+// redactText scrubs credentials from text that is not a single URI, such as a
+// keyword/value connection string or a message embedding URIs.
+func redactText(s string) string {
+	s = userinfoRE.ReplaceAllStringFunc(s, func(m string) string {
+		parts := userinfoRE.FindStringSubmatch(m)
+		scheme, user, password := parts[1], parts[2], parts[3]
+		switch {
+		case s3Schemes[strings.ToLower(strings.TrimSuffix(scheme, "://"))]:
+			if password != "" {
+				return scheme + RedactedValue + ":" + RedactedValue + "@"
+			}
+			return scheme + RedactedValue + "@"
+		case password != "":
+			return scheme + user + ":" + RedactedValue + "@"
+		}
+		return m
+	})
+	return keywordPairRE.ReplaceAllStringFunc(s, func(m string) string {
+		key, value, _ := strings.Cut(m, "=")
+		if value == "" || !isSensitiveParam(key) {
+			return m
+		}
+		return key + "=" + RedactedValue
+	})
+}
+
+// RedactAll redacts every string value in a list of slog key/value arguments
+// or slog.Attrs with RedactURI, so a call such as
+//
+//	log.Info("connecting", config.RedactAll("dsn", dsn, "bucket", s3URI)...)
+//
+// cannot log credentials. Keys and non-string values are left unchanged.
+func RedactAll(args ...any) []any {
+	out := make([]any, len(args))
+	for i := 0; i < len(args); i++ {
+		switch v := args[i].(type) {
+		case slog.Attr:
+			out[i] = redactAttr(v)
+		case string:
+			// A string followed by a value is a key; a trailing string is
+			// logged as a value.
+			if i+1 == len(args) {
+				out[i] = RedactURI(v)
+				break
+			}
+			out[i] = v
+			i++
+			out[i] = redactValue(args[i])
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return RedactURI(v)
+	case slog.Value:
+		return redactAttr(slog.Attr{Value: v}).Value
+	}
+	return v
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactURI(a.Value.String()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		redacted := make([]any, len(attrs))
+		for i, attr := range attrs {
+			redacted[i] = redactAttr(attr)
+		}
+		return slog.Group(a.Key, redacted...)
+	}
+	return a
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
@@ -70,6 +71,7 @@ var (
 	ledgerRPCTimeout             time.Duration
 	ledgerRPCMaxConns            int
 	metricsAddr                  string
+	providers                    []string
 
 	version = "dev"
 	commit  = "none"
@@ -114,13 +116,21 @@ var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run ongoing data collection operations (service mode)",
 	Long: `Run continuous collector that creates WhereItUp jobs every interval,
-RIPE Atlas measurements hourly, and exports RIPE Atlas results periodically.`,
+RIPE Atlas measurements hourly, and exports RIPE Atlas results periodically.
+Use --providers to run only a subset of the data providers.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log := collector.NewLogger(collector.LogLevel(logLevel))
 
+		selectedProviders, err := collector.ParseProviders(providers)
+		if err != nil {
+			log.Error("invalid --providers", "error", err)
+			os.Exit(1)
+		}
+
 		log.Info("Operation started: run_continuous_collector",
 			slog.String("wheresitup_interval", defaultWheresitupSamplingInterval.String()),
 			slog.Bool("dry_run", dryRun),
+			slog.Any("providers", selectedProviders),
 			slog.String("env", env),
 			slog.String("serviceability_program_id", networkConfig.ServiceabilityProgramID.String()),
 		)
@@ -165,18 +175,10 @@ RIPE Atlas measurements hourly, and exports RIPE Atlas results periodically.`,
 			os.Exit(1)
 		}
 
-		// Create data provider collectors.
-		ripeatlasCollector := ripeatlas.NewCollector(log, exporter, env, func(ctx context.Context) []collector.LocationMatch {
-			return collector.GetLocations(ctx, log, serviceabilityClient)
-		})
-		wheresitupCollector := wheresitup.NewCollector(log, exporter, env, func(ctx context.Context) []collector.LocationMatch {
-			return collector.GetLocations(ctx, log, serviceabilityClient)
-		})
-
+		// Create the selected data provider collectors.
 		config := collector.Config{
-			Logger:     log,
-			Wheresitup: wheresitupCollector,
-			RipeAtlas:  ripeatlasCollector,
+			Logger:    log,
+			Providers: selectedProviders,
 
 			WheresitupSamplingInterval:   defaultWheresitupSamplingInterval,
 			RipeAtlasSamplingInterval:    defaultRipeAtlasSamplingInterval,
@@ -188,6 +190,15 @@ RIPE Atlas measurements hourly, and exports RIPE Atlas results periodically.`,
 			ProbesPerLocation:            ripeatlasProbesPerLocation,
 			MetricsAddr:                  metricsAddr,
 		}
+		getLocations := func(ctx context.Context) []collector.LocationMatch {
+			return collector.GetLocations(ctx, log, serviceabilityClient)
+		}
+		if slices.Contains(selectedProviders, collector.ProviderRIPEAtlas) {
+			config.RipeAtlas = ripeatlas.NewCollector(log, exporter, env, getLocations)
+		}
+		if slices.Contains(selectedProviders, collector.ProviderWheresitup) {
+			config.Wheresitup = wheresitup.NewCollector(log, exporter, env, getLocations)
+		}
 
 		c, err := collector.New(config)
 		if err != nil {
@@ -447,6 +458,7 @@ func init() {
 	runCmd.Flags().DurationVar(&ripeatlasMeasurementInterval, "ripeatlas-measurement-interval", defaultRipeAtlasMeasurementInterval, "Interval at which to run RIPE Atlas measurements")
 	runCmd.Flags().DurationVar(&ledgerSubmissionInterval, "ledger-submission-interval", defaultLedgerSubmissionInterval, "Interval at which to submit metrics to the ledger")
 	runCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "127.0.0.1:2113", "Address to bind the metrics server to")
+	runCmd.Flags().StringSliceVar(&providers, "providers", []string{string(collector.ProviderRIPEAtlas), string(collector.ProviderWheresitup)}, "Data providers to run (ripeatlas, wheresitup)")
 
 	ripeatlasCreateMeasurementsCmd.Flags().IntVar(&ripeatlasProbesPerLocation, "probes-per-location", defaultAtlasProbesPerLocation, "Number of RIPE Atlas probes to associate with each DoubleZero location")
 
@@ -3,10 +3,54 @@ package collector
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 )
 
+// Provider is an internet latency data provider the collector can run.
+type Provider string
+
+const (
+	ProviderRIPEAtlas  Provider = "ripeatlas"
+	ProviderWheresitup Provider = "wheresitup"
+)
+
+// AllProviders lists every supported provider; it is the default selection.
+var AllProviders = []Provider{ProviderRIPEAtlas, ProviderWheresitup}
+
+// ParseProviders validates provider names, ignoring case, surrounding
+// whitespace and duplicates. At least one provider is required.
+func ParseProviders(names []string) ([]Provider, error) {
+	var providers []Provider
+	for _, name := range names {
+		p := Provider(strings.ToLower(strings.TrimSpace(name)))
+		if p == "" {
+			continue
+		}
+		if !slices.Contains(AllProviders, p) {
+			return nil, fmt.Errorf("unknown provider %q (expected one of: %s)", name, joinProviders(AllProviders))
+		}
+		if !slices.Contains(providers, p) {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, errors.New("at least one provider is required")
+	}
+	return providers, nil
+}
+
+func joinProviders(providers []Provider) string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
+
 type WheresitupCollectorInterface interface {
 	Run(ctx context.Context, interval time.Duration, dryRun bool, jobIDsFile, stateDir string) error
 	InitializeCreditBalance(ctx context.Context) error
@@ -21,6 +65,10 @@ type RipeAtlasCollectorInterface interface {
 type Config struct {
 	Logger *slog.Logger
 
+	// Providers selects which collectors run; empty means AllProviders. Only
+	// the collectors of selected providers are required.
+	Providers []Provider
+
 	RipeAtlas  RipeAtlasCollectorInterface
 	Wheresitup WheresitupCollectorInterface
 
@@ -39,29 +87,41 @@ func (cfg *Config) Validate() error {
 	if cfg.Logger == nil {
 		return errors.New("logger is required")
 	}
-	if cfg.Wheresitup == nil {
-		return errors.New("wheresitup collector is required")
-	}
-	if cfg.RipeAtlas == nil {
-		return errors.New("ripe atlas collector is required")
-	}
-	if cfg.WheresitupSamplingInterval <= 0 {
-		return errors.New("wheresitup sampling interval must be greater than 0")
+	if len(cfg.Providers) == 0 {
+		cfg.Providers = AllProviders
 	}
-	if cfg.RipeAtlasSamplingInterval <= 0 {
-		return errors.New("ripe atlas sampling interval must be greater than 0")
+	for _, p := range cfg.Providers {
+		if !slices.Contains(AllProviders, p) {
+			return fmt.Errorf("unknown provider %q", p)
+		}
 	}
-	if cfg.RipeAtlasMeasurementInterval <= 0 {
-		return errors.New("ripe atlas measurement interval must be greater than 0")
+	if cfg.enabled(ProviderWheresitup) {
+		if cfg.Wheresitup == nil {
+			return errors.New("wheresitup collector is required")
+		}
+		if cfg.WheresitupSamplingInterval <= 0 {
+			return errors.New("wheresitup sampling interval must be greater than 0")
+		}
+		if cfg.ProcessedJobsFile == "" {
+			return errors.New("processed jobs file is required")
+		}
 	}
-	if cfg.RipeAtlasExportInterval <= 0 {
-		return errors.New("ripe atlas export interval must be greater than 0")
-	}
-	if cfg.ProbesPerLocation <= 0 {
-		return errors.New("probes per location must be greater than 0")
-	}
-	if cfg.ProcessedJobsFile == "" {
-		return errors.New("processed jobs file is required")
+	if cfg.enabled(ProviderRIPEAtlas) {
+		if cfg.RipeAtlas == nil {
+			return errors.New("ripe atlas collector is required")
+		}
+		if cfg.RipeAtlasSamplingInterval <= 0 {
+			return errors.New("ripe atlas sampling interval must be greater than 0")
+		}
+		if cfg.RipeAtlasMeasurementInterval <= 0 {
+			return errors.New("ripe atlas measurement interval must be greater than 0")
+		}
+		if cfg.RipeAtlasExportInterval <= 0 {
+			return errors.New("ripe atlas export interval must be greater than 0")
+		}
+		if cfg.ProbesPerLocation <= 0 {
+			return errors.New("probes per location must be greater than 0")
+		}
 	}
 	if cfg.StateDir == "" {
 		return errors.New("state directory is required")
@@ -69,6 +129,10 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+func (cfg *Config) enabled(p Provider) bool {
+	return slices.Contains(cfg.Providers, p)
+}
+
 type Collector struct {
 	log *slog.Logger
 	cfg Config
@@ -0,0 +1,226 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/malbeclabs/doublezero/controlplane/internet-latency-collector/internal/metrics"
+)
+
+// ErrCircuitOpen is returned when a call is skipped because the provider's
+// circuit breaker is open.
+var ErrCircuitOpen = NewAPIError("circuit_breaker", "circuit breaker open, skipping provider call", nil)
+
+// RetryConfig controls retry-with-backoff for provider API calls. A zero
+// MaxAttempts performs a single attempt with no retries.
+type RetryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryConfig is the retry policy used by the provider API clients.
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerCooldown         = 2 * time.Minute
+)
+
+// permanentError marks an error that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry returns it without further attempts.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsRetryableStatus reports whether an HTTP status code indicates a transient
+// provider failure worth retrying.
+func IsRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Retry calls fn until it succeeds, returns a Permanent error, or MaxAttempts
+// is reached, sleeping with exponential backoff between attempts. If breaker is
+// non-nil, each attempt is gated by it and its outcome is recorded; permanent
+// errors do not count as breaker failures since the provider did respond.
+func Retry(ctx context.Context, cfg RetryConfig, breaker *CircuitBreaker, fn func(ctx context.Context) error) error {
+	attempts := max(cfg.MaxAttempts, 1)
+	backoff := cfg.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if breaker != nil && !breaker.Allow() {
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+			}
+			return ErrCircuitOpen
+		}
+
+		err = fn(ctx)
+		if err == nil {
+			if breaker != nil {
+				breaker.RecordSuccess()
+			}
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			if breaker != nil {
+				breaker.RecordSuccess()
+			}
+			return perm.err
+		}
+		if breaker != nil {
+			breaker.RecordFailure()
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, cfg.MaxBackoff)
+	}
+
+	return err
+}
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calls to a degraded provider. It opens after
+// failureThreshold consecutive failures, skips calls for the cooldown, then
+// half-opens to let a single trial call through: success closes it again,
+// failure re-opens it for another cooldown.
+type CircuitBreaker struct {
+	provider         string
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu            sync.Mutex
+	state         CircuitState
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+}
+
+func NewCircuitBreaker(provider string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	b := &CircuitBreaker{
+		provider:         provider,
+		failureThreshold: max(failureThreshold, 1),
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+	b.setState(CircuitClosed)
+	return b
+}
+
+// State returns the current breaker state, accounting for an elapsed cooldown.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a call may proceed.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		b.trialInFlight = true
+		return true
+	case CircuitHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and resets the failure count.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trialInFlight = false
+	if b.state != CircuitClosed {
+		b.setState(CircuitClosed)
+	}
+}
+
+// RecordFailure counts a failure, opening the breaker once the threshold is
+// reached or immediately if the failed call was a half-open trial.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+		b.setState(CircuitOpen)
+	}
+}
+
+// setState must be called with b.mu held (or before b is shared).
+func (b *CircuitBreaker) setState(state CircuitState) {
+	b.state = state
+	metrics.ProviderCircuitBreakerState.WithLabelValues(b.provider).Set(float64(state))
+}
@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/malbeclabs/doublezero/controlplane/internet-latency-collector/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryConfig = RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+func TestInternetLatency_Retry_TransientFailureSucceeds(t *testing.T) {
+	t.Parallel()
+
+	breaker := NewCircuitBreaker("test_transient", 5, time.Minute)
+	calls := 0
+	err := Retry(t.Context(), testRetryConfig, breaker, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestInternetLatency_Retry_ExhaustsAttempts(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	err := Retry(t.Context(), testRetryConfig, nil, func(ctx context.Context) error {
+		calls++
+		return errors.New("still failing")
+	})
+
+	require.EqualError(t, err, "still failing")
+	require.Equal(t, 3, calls)
+}
+
+func TestInternetLatency_Retry_PermanentErrorNotRetried(t *testing.T) {
+	t.Parallel()
+
+	breaker := NewCircuitBreaker("test_permanent", 1, time.Minute)
+	calls := 0
+	err := Retry(t.Context(), testRetryConfig, breaker, func(ctx context.Context) error {
+		calls++
+		return Permanent(errors.New("bad request"))
+	})
+
+	require.EqualError(t, err, "bad request")
+	require.Equal(t, 1, calls)
+	require.Equal(t, CircuitClosed, breaker.State(), "permanent errors should not trip the breaker")
+}
+
+func TestInternetLatency_Retry_ZeroConfigSingleAttempt(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	err := Retry(t.Context(), RetryConfig{}, nil, func(ctx context.Context) error {
+		calls++
+		return errors.New("failure")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestInternetLatency_CircuitBreaker_OpensThenHalfOpens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	breaker := NewCircuitBreaker("test_sustained", 3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	failing := func(ctx context.Context) error { return errors.New("provider down") }
+
+	// Sustained failures: the first call exhausts its 3 attempts and opens the breaker.
+	err := Retry(t.Context(), testRetryConfig, breaker, failing)
+	require.Error(t, err)
+	require.Equal(t, CircuitOpen, breaker.State())
+	require.Equal(t, float64(CircuitOpen), testutil.ToFloat64(metrics.ProviderCircuitBreakerState.WithLabelValues("test_sustained")))
+
+	// While open, calls are skipped without reaching the provider.
+	calls := 0
+	err = Retry(t.Context(), testRetryConfig, breaker, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Zero(t, calls)
+
+	// After the cooldown the breaker half-opens and a failed trial re-opens it.
+	now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, breaker.State())
+	calls = 0
+	err = Retry(t.Context(), testRetryConfig, breaker, func(ctx context.Context) error {
+		calls++
+		return errors.New("still down")
+	})
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.Equal(t, 1, calls, "only a single trial call should be allowed while half-open")
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	// After another cooldown a successful trial closes the breaker.
+	now = now.Add(time.Minute)
+	err = Retry(t.Context(), testRetryConfig, breaker, func(ctx context.Context) error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, CircuitClosed, breaker.State())
+	require.Equal(t, float64(CircuitClosed), testutil.ToFloat64(metrics.ProviderCircuitBreakerState.WithLabelValues("test_sustained")))
+}
@@ -149,6 +149,60 @@ func TestInternetLatency_Collector_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("Only selected providers run", func(t *testing.T) {
+		t.Parallel()
+
+		log := logger.With("test", t.Name())
+
+		mockRipe := &MockRipeAtlasCollector{}
+		mockWheresitup := &MockWheresitupCollector{
+			InitializeCreditBalanceFunc: func(ctx context.Context) error {
+				t.Error("wheresitup collector should not be initialized")
+				return nil
+			},
+		}
+
+		config := Config{
+			Logger:     log,
+			Providers:  []Provider{ProviderRIPEAtlas},
+			Wheresitup: mockWheresitup,
+			RipeAtlas:  mockRipe,
+
+			RipeAtlasSamplingInterval:    1 * time.Minute,
+			RipeAtlasMeasurementInterval: 1 * time.Hour,
+			RipeAtlasExportInterval:      2 * time.Minute,
+			DryRun:                       true,
+			StateDir:                     t.TempDir(),
+			ProbesPerLocation:            2,
+			MetricsAddr:                  "127.0.0.1:0",
+		}
+
+		c, err := New(config)
+		require.NoError(t, err)
+		require.NoError(t, c.Run(t.Context()))
+
+		require.True(t, mockRipe.wasRunCalled(), "RIPE Atlas collector should have been called")
+		require.False(t, mockWheresitup.wasRunCalled(), "Wheresitup collector should not have been called")
+	})
+
+	t.Run("Unselected provider collector is not required", func(t *testing.T) {
+		t.Parallel()
+
+		mockWheresitup := &MockWheresitupCollector{}
+		c, err := New(Config{
+			Logger:                     logger.With("test", t.Name()),
+			Providers:                  []Provider{ProviderWheresitup},
+			Wheresitup:                 mockWheresitup,
+			WheresitupSamplingInterval: 1 * time.Minute,
+			ProcessedJobsFile:          "test.csv",
+			StateDir:                   t.TempDir(),
+			MetricsAddr:                "127.0.0.1:0",
+		})
+		require.NoError(t, err)
+		require.NoError(t, c.Run(t.Context()))
+		require.True(t, mockWheresitup.wasRunCalled(), "Wheresitup collector should have been called")
+	})
+
 	t.Run("Wheresitup collector error", func(t *testing.T) {
 		t.Parallel()
 
@@ -202,3 +256,20 @@ func TestInternetLatency_Collector_Run(t *testing.T) {
 		}
 	})
 }
+
+func TestInternetLatency_ParseProviders(t *testing.T) {
+	t.Parallel()
+
+	providers, err := ParseProviders([]string{"RIPEAtlas", " wheresitup ", "ripeatlas"})
+	require.NoError(t, err)
+	require.Equal(t, []Provider{ProviderRIPEAtlas, ProviderWheresitup}, providers)
+
+	_, err = ParseProviders([]string{"cloudflare"})
+	require.ErrorContains(t, err, "unknown provider")
+
+	_, err = ParseProviders(nil)
+	require.ErrorContains(t, err, "at least one provider")
+
+	_, err = ParseProviders([]string{""})
+	require.ErrorContains(t, err, "at least one provider")
+}
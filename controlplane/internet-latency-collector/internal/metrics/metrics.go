@@ -79,6 +79,11 @@ var (
 		Help: "Haversine distance in kilometers from exchange to its nearest probe",
 	}, []string{"provider", "exchange_code"})
 
+	ProviderCircuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "doublezero_internet_latency_collector_provider_circuit_breaker_state",
+		Help: "State of the provider API circuit breaker (0=closed, 1=open, 2=half-open)",
+	}, []string{"data_provider"})
+
 	// RIPE Atlas specific metrics
 	RipeatlasMeasurementManagementRunsTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "doublezero_internet_latency_collector_ripeatlas_measurement_management_runs_total",
@@ -21,6 +21,8 @@ type Client struct {
 	APIKey     string
 	HTTPClient collector.HTTPClient
 	log        *slog.Logger
+	retry      collector.RetryConfig
+	breaker    *collector.CircuitBreaker
 }
 
 type Probe struct {
@@ -115,7 +117,9 @@ func NewClient(logger *slog.Logger) *Client {
 		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		log: logger,
+		log:     logger,
+		retry:   collector.DefaultRetryConfig,
+		breaker: collector.NewCircuitBreaker("ripeatlas", collector.DefaultBreakerFailureThreshold, collector.DefaultBreakerCooldown),
 	}
 }
 
@@ -165,21 +169,34 @@ func (c *Client) fetchProbesWithErrorHandling(ctx context.Context, lat, lng floa
 func (c *Client) makeRequest(ctx context.Context, endpoint string) (*http.Response, error) {
 	url := c.BaseURL + endpoint
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	var resp *http.Response
+	err := collector.Retry(ctx, c.retry, c.breaker, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return collector.Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
 
-	c.setCommonHeaders(req, "")
+		c.setCommonHeaders(req, "")
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
+		r, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		if r.StatusCode != http.StatusOK {
+			r.Body.Close()
+			err := fmt.Errorf("API request failed with status: %d", r.StatusCode)
+			if !collector.IsRetryableStatus(r.StatusCode) {
+				return collector.Permanent(err)
+			}
+			return err
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return resp, nil
@@ -277,9 +294,30 @@ func (c *Client) CreateMeasurement(ctx context.Context, request MeasurementReque
 
 	c.setCommonHeaders(req, "application/json")
 
-	resp, err := c.HTTPClient.Do(req)
+	// Measurement creation is not retried since a retry after a lost response
+	// could create a duplicate measurement, but it is still gated by the breaker.
+	var resp *http.Response
+	err = collector.Retry(ctx, collector.RetryConfig{}, c.breaker, func(ctx context.Context) error {
+		r, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to execute HTTP request: %w", err)
+		}
+
+		if r.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(r.Body)
+			r.Body.Close()
+			err := fmt.Errorf("measurement creation failed with status %d: %s", r.StatusCode, string(body))
+			if !collector.IsRetryableStatus(r.StatusCode) {
+				return collector.Permanent(err)
+			}
+			return err
+		}
+
+		resp = r
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -288,10 +326,6 @@ func (c *Client) CreateMeasurement(ctx context.Context, request MeasurementReque
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("measurement creation failed with status %d: %s", resp.StatusCode, string(responseBytes))
-	}
-
 	var measurementResponse MeasurementResponse
 	if err := json.Unmarshal(responseBytes, &measurementResponse); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal measurement response: %w", err)
@@ -364,31 +398,44 @@ func (c *Client) StopMeasurement(ctx context.Context, measurementID int) error {
 	endpoint := fmt.Sprintf("/measurements/%d", measurementID)
 	url := c.BaseURL + endpoint
 
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create delete request: %w", err)
-	}
+	var statusCode int
+	err := collector.Retry(ctx, c.retry, c.breaker, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+		if err != nil {
+			return collector.Permanent(fmt.Errorf("failed to create delete request: %w", err))
+		}
 
-	c.setCommonHeaders(req, "")
+		c.setCommonHeaders(req, "")
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete measurement: %w", err)
-	}
-	defer resp.Body.Close()
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to delete measurement: %w", err)
+		}
+		defer resp.Body.Close()
 
-	var responseBody []byte
-	if resp.Body != nil {
-		responseBody, _ = io.ReadAll(resp.Body)
-	}
+		var responseBody []byte
+		if resp.Body != nil {
+			responseBody, _ = io.ReadAll(resp.Body)
+		}
 
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("failed to stop measurement %d: status %d, response: %s", measurementID, resp.StatusCode, string(responseBody))
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+			err := fmt.Errorf("failed to stop measurement %d: status %d, response: %s", measurementID, resp.StatusCode, string(responseBody))
+			if !collector.IsRetryableStatus(resp.StatusCode) {
+				return collector.Permanent(err)
+			}
+			return err
+		}
+
+		statusCode = resp.StatusCode
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	c.log.Debug("Successfully stopped measurement",
 		slog.Int("measurement_id", measurementID),
-		slog.Int("status_code", resp.StatusCode))
+		slog.Int("status_code", statusCode))
 	return nil
 }
 
@@ -1063,3 +1063,42 @@ func TestInternetLatency_RIPEAtlas_GetCreditBalance(t *testing.T) {
 	require.NoError(t, err, "GetCreditBalance() should not return error")
 	require.Equal(t, 1000.0, balance, "Expected credit balance to be 1000")
 }
+
+func TestInternetLatency_RIPEAtlas_CreateMeasurementStatusFeedsBreaker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantState  collector.CircuitState
+	}{
+		{"server error counts as failure", http.StatusServiceUnavailable, collector.CircuitOpen},
+		{"rate limit counts as failure", http.StatusTooManyRequests, collector.CircuitOpen},
+		{"client error is permanent", http.StatusBadRequest, collector.CircuitClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				log:     logger.With("test", t.Name()),
+				BaseURL: "https://atlas.ripe.net/api/v2",
+				HTTPClient: &MockHTTPClient{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: tt.statusCode,
+							Body:       io.NopCloser(strings.NewReader(`{"error": "failed"}`)),
+						}, nil
+					},
+				},
+				breaker: collector.NewCircuitBreaker("ripeatlas-test", 2, time.Minute),
+			}
+
+			for range 2 {
+				_, err := client.CreateMeasurement(t.Context(), MeasurementRequest{})
+				require.ErrorContains(t, err, fmt.Sprintf("status %d", tt.statusCode))
+			}
+			require.Equal(t, tt.wantState, client.breaker.State())
+		})
+	}
+}
@@ -23,6 +23,8 @@ type Client struct {
 	APIToken   string
 	HTTPClient collector.HTTPClient
 	log        *slog.Logger
+	retry      collector.RetryConfig
+	breaker    *collector.CircuitBreaker
 }
 
 // Wheresitup probe devices are called "sources"
@@ -200,27 +202,42 @@ func NewClientWithConfig(logger *slog.Logger, config ClientConfig) *Client {
 		APIToken:   config.APIToken,
 		HTTPClient: config.HTTPClient,
 		log:        logger,
+		retry:      collector.DefaultRetryConfig,
+		breaker:    collector.NewCircuitBreaker("wheresitup", collector.DefaultBreakerFailureThreshold, collector.DefaultBreakerCooldown),
 	}
 }
 
 func (c *Client) makeRequest(ctx context.Context, endpoint string) (*http.Response, error) {
 	url := c.BaseURL + endpoint
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	var resp *http.Response
+	err := collector.Retry(ctx, c.retry, c.breaker, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return collector.Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
 
-	c.setCommonHeaders(req)
+		c.setCommonHeaders(req)
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
+		r, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		if r.StatusCode != http.StatusOK {
+			r.Body.Close()
+			err := fmt.Errorf("API request failed with status: %d", r.StatusCode)
+			if !collector.IsRetryableStatus(r.StatusCode) {
+				return collector.Permanent(err)
+			}
+			return err
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return resp, nil
@@ -340,9 +357,35 @@ func (c *Client) CreateJobWithRequest(ctx context.Context, request any, debug bo
 			slog.String("url", req.URL.String()))
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	// Job creation is not retried since a retry after a lost response could
+	// create a duplicate job, but it is still gated by the breaker.
+	var resp *http.Response
+	err = collector.Retry(ctx, collector.RetryConfig{}, c.breaker, func(ctx context.Context) error {
+		r, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(r.Body)
+			r.Body.Close()
+			if debug {
+				c.log.Debug("API error response",
+					slog.Int("status_code", r.StatusCode),
+					slog.String("response_body", string(body)))
+			}
+			err := fmt.Errorf("job creation failed with status: %d, response: %s", r.StatusCode, string(body))
+			if !collector.IsRetryableStatus(r.StatusCode) {
+				return collector.Permanent(err)
+			}
+			return err
+		}
+
+		resp = r
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -360,14 +403,6 @@ func (c *Client) CreateJobWithRequest(ctx context.Context, request any, debug bo
 			slog.Int("response_length", len(responseBody)))
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		if debug {
-			c.log.Debug("API error response",
-				slog.String("response_body", string(responseBody)))
-		}
-		return nil, fmt.Errorf("job creation failed with status: %d, response: %s", resp.StatusCode, string(responseBody))
-	}
-
 	var jobResponse JobResponse
 	if err := json.Unmarshal(responseBody, &jobResponse); err != nil {
 		// Log the unmarshal error with response body for debugging
@@ -1256,3 +1256,41 @@ func TestGetCredit(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateJobWithRequestStatusFeedsBreaker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantState  collector.CircuitState
+	}{
+		{"server error counts as failure", http.StatusBadGateway, collector.CircuitOpen},
+		{"rate limit counts as failure", http.StatusTooManyRequests, collector.CircuitOpen},
+		{"client error is permanent", http.StatusUnauthorized, collector.CircuitClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := NewClientWithConfig(logger.With("test", t.Name()), ClientConfig{
+				APIToken: "test-token",
+				HTTPClient: &MockHTTPClient{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: tt.statusCode,
+							Body:       io.NopCloser(strings.NewReader("failed")),
+						}, nil
+					},
+				},
+			})
+			client.breaker = collector.NewCircuitBreaker("wheresitup-test", 2, time.Minute)
+
+			for range 2 {
+				_, err := client.CreateJobWithRequest(t.Context(), map[string]any{"uri": "http://test.com"}, false)
+				require.ErrorContains(t, err, fmt.Sprintf("status: %d", tt.statusCode))
+			}
+			require.Equal(t, tt.wantState, client.breaker.State())
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/malbeclabs/doublezero/config"
 	twozoracle "github.com/malbeclabs/doublezero/controlplane/monitor/internal/2z-oracle"
+	"github.com/malbeclabs/doublezero/controlplane/monitor/internal/influx"
 	"github.com/malbeclabs/doublezero/controlplane/monitor/internal/worker"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/telemetry"
@@ -29,6 +30,11 @@ const (
 	defaultTwoZOracleInterval  = 5 * time.Second
 	defaultSolBalanceInterval  = 30 * time.Second
 	defaultSolBalanceThreshold = 0.1
+	defaultInfluxBatchSize     = 5000
+	defaultInfluxMaxRetries    = 3
+	influxRetryDelay           = 1 * time.Second
+	influxWriteTimeout         = 10 * time.Second
+	influxCloseTimeout         = 30 * time.Second
 )
 
 var (
@@ -47,6 +53,8 @@ var (
 	solBalanceAccounts         = flag.String("sol-balance-accounts", "", "comma-separated label:pubkey pairs (e.g., debt_accountant:ABC123,rewards_accountant:XYZ789)")
 	solBalanceThreshold        = flag.Float64("sol-balance-threshold", defaultSolBalanceThreshold, "SOL balance threshold for warning logs")
 	solBalanceInterval         = flag.Duration("sol-balance-interval", defaultSolBalanceInterval, "interval to check SOL balances")
+	influxBatchSize            = flag.Int("influx-batch-size", defaultInfluxBatchSize, "maximum number of points per InfluxDB write")
+	influxMaxRetries           = flag.Int("influx-max-retries", defaultInfluxMaxRetries, "number of times a failed InfluxDB write is retried before its points are dropped")
 
 	// Set by LDFLAGS
 	version = "dev"
@@ -160,8 +168,7 @@ func main() {
 	}
 
 	// Initialize InfluxDB writer
-	var influxClient influxdb2.Client
-	var influxWriter worker.InfluxWriter
+	var influxWriter *influx.Writer
 	var influxUrl, influxToken, influxBucket string
 
 	// Check whether writing to InfluxDB should be enabled.
@@ -181,9 +188,22 @@ func main() {
 	}
 
 	if enableInflux() {
-		influxClient = influxdb2.NewClient(influxUrl, influxToken)
-		influxWriter = influxClient.WriteAPI("rd", influxBucket)
+		influxClient := influxdb2.NewClient(influxUrl, influxToken)
 		defer influxClient.Close()
+		var err error
+		influxWriter, err = influx.NewWriter(&influx.Config{
+			Logger:       log,
+			Writer:       influxClient.WriteAPIBlocking("rd", influxBucket),
+			BatchSize:    *influxBatchSize,
+			MaxRetries:   *influxMaxRetries,
+			RetryDelay:   influxRetryDelay,
+			WriteTimeout: influxWriteTimeout,
+		})
+		if err != nil {
+			log.Error("Failed to create influx writer", "error", err)
+			flag.Usage()
+			os.Exit(1)
+		}
 	}
 
 	// Parse SOL balance accounts.
@@ -223,7 +243,7 @@ func main() {
 		AllowOwnUsers:              *allowOwnUsers,
 		TwoZOracleClient:           twoZOracleClient,
 		TwoZOracleInterval:         *twoZOracleInterval,
-		InfluxWriter:               influxWriter,
+		InfluxWriter:               influxWriterOrNil(influxWriter),
 		Env:                        *env,
 		SolBalanceRPCClient:        rpcClient,
 		SolBalanceAccounts:         solBalanceAccountsMap,
@@ -240,8 +260,25 @@ func main() {
 	defer cancel()
 
 	err = worker.Run(ctx)
+
+	// Write the points buffered since the last flush before exiting.
+	if influxWriter != nil {
+		closeCtx, closeCancel := context.WithTimeout(context.Background(), influxCloseTimeout)
+		influxWriter.Close(closeCtx)
+		closeCancel()
+	}
+
 	if err != nil {
 		log.Error("Failed to run worker", "error", err)
 		os.Exit(1)
 	}
 }
+
+// influxWriterOrNil returns w as a worker.InfluxWriter, keeping a nil writer
+// nil so watchers see InfluxDB writes as disabled.
+func influxWriterOrNil(w *influx.Writer) worker.InfluxWriter {
+	if w == nil {
+		return nil
+	}
+	return w
+}
@@ -0,0 +1,47 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// LineWriter writes line protocol records to InfluxDB, returning once the
+// write has succeeded or failed. It is satisfied by the client's
+// api.WriteAPIBlocking.
+type LineWriter interface {
+	WriteRecord(ctx context.Context, line ...string) error
+}
+
+type Config struct {
+	Logger       *slog.Logger
+	Writer       LineWriter
+	BatchSize    int
+	MaxRetries   int
+	RetryDelay   time.Duration
+	WriteTimeout time.Duration
+
+	// OnError, if set, is called with the error and the number of points
+	// dropped whenever a batch is given up on.
+	OnError func(err error, points int)
+}
+
+func (c *Config) Validate() error {
+	if c.Logger == nil {
+		return errors.New("logger is required")
+	}
+	if c.Writer == nil {
+		return errors.New("writer is required")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("batch size must be greater than 0")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("max retries must not be negative")
+	}
+	if c.WriteTimeout <= 0 {
+		return errors.New("write timeout must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package influx
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// Metric names.
+	MetricNamePointsWritten = "doublezero_monitor_influx_points_written_total"
+	MetricNamePointsDropped = "doublezero_monitor_influx_points_dropped_total"
+	MetricNameWriteRetries  = "doublezero_monitor_influx_write_retries_total"
+)
+
+var (
+	MetricPointsWritten = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: MetricNamePointsWritten,
+			Help: "Number of points written to InfluxDB",
+		},
+	)
+
+	MetricPointsDropped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: MetricNamePointsDropped,
+			Help: "Number of points dropped after their batch failed to write to InfluxDB",
+		},
+	)
+
+	MetricWriteRetries = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: MetricNameWriteRetries,
+			Help: "Number of retried InfluxDB batch writes",
+		},
+	)
+)
@@ -0,0 +1,132 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errorsBuffer is how many errors Errors holds before further errors are
+// only logged.
+const errorsBuffer = 16
+
+// ErrClosed is reported for records written after Close.
+var ErrClosed = errors.New("influx writer closed")
+
+// Writer batches line protocol records and writes them through a blocking
+// LineWriter, so failed writes are retried, counted and reported instead of
+// being lost inside the client's async writer. A batch is written once it
+// reaches the batch size and on every Flush. A batch that still fails after
+// the configured retries is dropped and its points are counted in
+// MetricPointsDropped.
+type Writer struct {
+	cfg  *Config
+	errs chan error
+
+	mu     sync.Mutex
+	buf    []string
+	closed bool
+}
+
+func NewWriter(cfg *Config) (*Writer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Writer{
+		cfg:  cfg,
+		errs: make(chan error, errorsBuffer),
+	}, nil
+}
+
+// Errors returns the errors of dropped batches. It is closed by Close.
+func (w *Writer) Errors() <-chan error {
+	return w.errs
+}
+
+// WriteRecord buffers line, writing the buffer if it has reached the batch
+// size. Records written after Close are dropped.
+func (w *Writer) WriteRecord(line string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		w.drop(1, ErrClosed)
+		return
+	}
+	w.buf = append(w.buf, line)
+	if len(w.buf) >= w.cfg.BatchSize {
+		w.flush(context.Background())
+	}
+}
+
+// Flush writes the buffered records.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flush(context.Background())
+}
+
+// Close writes the buffered records, giving up on retries once ctx is done,
+// and closes Errors.
+func (w *Writer) Close(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.flush(ctx)
+	w.closed = true
+	close(w.errs)
+}
+
+func (w *Writer) flush(ctx context.Context) {
+	for len(w.buf) > 0 {
+		batch := w.buf[:min(len(w.buf), w.cfg.BatchSize)]
+		if err := w.write(ctx, batch); err != nil {
+			w.drop(len(batch), err)
+		}
+		w.buf = w.buf[len(batch):]
+	}
+	w.buf = nil
+}
+
+// write writes batch, retrying up to MaxRetries times.
+func (w *Writer) write(ctx context.Context, batch []string) error {
+	var err error
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			MetricWriteRetries.Inc()
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+			case <-time.After(w.cfg.RetryDelay):
+			}
+		}
+		writeCtx, cancel := context.WithTimeout(ctx, w.cfg.WriteTimeout)
+		err = w.cfg.Writer.WriteRecord(writeCtx, batch...)
+		cancel()
+		if err == nil {
+			MetricPointsWritten.Add(float64(len(batch)))
+			return nil
+		}
+		w.cfg.Logger.Debug("influx write failed", "attempt", attempt+1, "points", len(batch), "error", err)
+	}
+	return err
+}
+
+func (w *Writer) drop(points int, err error) {
+	MetricPointsDropped.Add(float64(points))
+	if w.cfg.OnError != nil {
+		w.cfg.OnError(err, points)
+	}
+	err = fmt.Errorf("dropped %d points: %w", points, err)
+	if !w.closed {
+		select {
+		case w.errs <- err:
+			return
+		default:
+		}
+	}
+	w.cfg.Logger.Warn("influx write error", "error", err)
+}
@@ -0,0 +1,137 @@
+package influx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLineWriter fails the first failures writes, then records every line.
+type mockLineWriter struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	lines    []string
+}
+
+func (m *mockLineWriter) WriteRecord(ctx context.Context, line ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.failures < 0 || m.calls <= m.failures {
+		return errors.New("influx unavailable")
+	}
+	m.lines = append(m.lines, line...)
+	return nil
+}
+
+func newTestWriter(t *testing.T, mock *mockLineWriter, batchSize, maxRetries int) *Writer {
+	t.Helper()
+	w, err := NewWriter(&Config{
+		Logger:       slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Writer:       mock,
+		BatchSize:    batchSize,
+		MaxRetries:   maxRetries,
+		RetryDelay:   time.Millisecond,
+		WriteTimeout: time.Second,
+	})
+	require.NoError(t, err)
+	return w
+}
+
+func TestWriter_BatchesAndFlushes(t *testing.T) {
+	mock := &mockLineWriter{}
+	w := newTestWriter(t, mock, 2, 0)
+	written := testutil.ToFloat64(MetricPointsWritten)
+
+	w.WriteRecord("a")
+	assert.Equal(t, 0, mock.calls, "a partial batch is held until flushed")
+	w.WriteRecord("b")
+	assert.Equal(t, 1, mock.calls, "a full batch is written immediately")
+	w.WriteRecord("c")
+	w.Flush()
+
+	assert.Equal(t, 2, mock.calls)
+	assert.Equal(t, []string{"a", "b", "c"}, mock.lines)
+	assert.Equal(t, written+3, testutil.ToFloat64(MetricPointsWritten))
+}
+
+func TestWriter_RetriesFailedWrites(t *testing.T) {
+	mock := &mockLineWriter{failures: 2}
+	w := newTestWriter(t, mock, 10, 2)
+	dropped := testutil.ToFloat64(MetricPointsDropped)
+	retries := testutil.ToFloat64(MetricWriteRetries)
+
+	w.WriteRecord("a")
+	w.Flush()
+
+	assert.Equal(t, 3, mock.calls)
+	assert.Equal(t, []string{"a"}, mock.lines)
+	assert.Equal(t, retries+2, testutil.ToFloat64(MetricWriteRetries))
+	assert.Equal(t, dropped, testutil.ToFloat64(MetricPointsDropped))
+	assert.Empty(t, w.Errors())
+}
+
+func TestWriter_CountsDroppedPoints(t *testing.T) {
+	mock := &mockLineWriter{failures: -1}
+	var callbackPoints int
+	w := newTestWriter(t, mock, 2, 1)
+	w.cfg.OnError = func(err error, points int) {
+		callbackPoints += points
+	}
+	dropped := testutil.ToFloat64(MetricPointsDropped)
+
+	w.WriteRecord("a")
+	w.WriteRecord("b")
+	w.WriteRecord("c")
+	w.Flush()
+
+	assert.Equal(t, 4, mock.calls, "each of the two batches is tried twice")
+	assert.Empty(t, mock.lines)
+	assert.Equal(t, dropped+3, testutil.ToFloat64(MetricPointsDropped))
+	assert.Equal(t, 3, callbackPoints)
+	require.Len(t, w.Errors(), 2)
+	assert.ErrorContains(t, <-w.Errors(), "dropped 2 points")
+	assert.ErrorContains(t, <-w.Errors(), "dropped 1 points")
+}
+
+func TestWriter_CloseFlushesBufferedRecords(t *testing.T) {
+	mock := &mockLineWriter{}
+	w := newTestWriter(t, mock, 10, 0)
+	dropped := testutil.ToFloat64(MetricPointsDropped)
+
+	w.WriteRecord("a")
+	w.WriteRecord("b")
+	w.Close(context.Background())
+
+	assert.Equal(t, []string{"a", "b"}, mock.lines)
+	_, ok := <-w.Errors()
+	assert.False(t, ok, "errors channel is closed")
+
+	w.WriteRecord("c")
+	assert.Equal(t, dropped+1, testutil.ToFloat64(MetricPointsDropped))
+	assert.Equal(t, []string{"a", "b"}, mock.lines)
+}
+
+func TestWriter_CloseStopsRetryingOnceContextDone(t *testing.T) {
+	mock := &mockLineWriter{failures: -1}
+	w := newTestWriter(t, mock, 10, 100)
+	w.cfg.RetryDelay = time.Hour
+	dropped := testutil.ToFloat64(MetricPointsDropped)
+
+	w.WriteRecord("a")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	w.Close(ctx)
+
+	assert.Equal(t, 1, mock.calls)
+	assert.Equal(t, dropped+1, testutil.ToFloat64(MetricPointsDropped))
+}
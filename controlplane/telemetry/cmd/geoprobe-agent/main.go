@@ -37,6 +37,7 @@ const (
 	defaultTWAMPSenderTimeout         = 1 * time.Second
 	defaultTWAMPReflectorTimeout      = 1 * time.Second
 	defaultMaxOffsetAge               = 1 * time.Hour
+	defaultPreferredOffsetFreshness   = 0
 	defaultEvictionInterval           = 30 * time.Minute
 	defaultVerifyInterval             = 29 * time.Second
 	discoveryInterval                 = 60 * time.Second
@@ -55,6 +56,7 @@ var (
 	probeInterval              = flag.Duration("probe-interval", defaultProbeInterval, "Interval between measurement cycles.")
 	twampSenderTimeout         = flag.Duration("twamp-sender-timeout", defaultTWAMPSenderTimeout, "Timeout for TWAMP probes to targets.")
 	maxOffsetAge               = flag.Duration("max-offset-age", defaultMaxOffsetAge, "TTL for cached DZD offsets.")
+	preferredOffsetFreshness   = flag.Duration("preferred-offset-freshness", defaultPreferredOffsetFreshness, "Composites built from a parent DZD offset older than this (but within --max-offset-age) are still sent, but tagged as using a stale reference in logs, metrics, and --once output. 0 disables tagging.")
 	verifyInterval             = flag.Duration("verify-interval", defaultVerifyInterval, "Minimum time between signature verifications per sender for the signed TWAMP reflector in inbound probing.")
 	geolocationProgramIDStr    = flag.String("geolocation-program-id", "", "Geolocation program ID (base58). If env is provided, this is derived automatically.")
 	serviceabilityProgramIDStr = flag.String("serviceability-program-id", "", "Serviceability program ID (base58). If env is provided, this is derived automatically.")
@@ -62,6 +64,13 @@ var (
 	showVersion                = flag.Bool("version", false, "Print the version and exit.")
 	metricsEnable              = flag.Bool("metrics-enable", false, "Enable prometheus metrics.")
 	metricsAddr                = flag.String("metrics-addr", ":8080", "Address to listen on for prometheus metrics.")
+	once                       = flag.Bool("once", false, "Run a single measurement cycle against discovered targets, print the composite offsets, and exit.")
+	onceSend                   = flag.Bool("once-send", false, "With --once, also sign and send the composite offsets to their delivery addresses.")
+	onceParentWait             = flag.Duration("once-parent-wait", defaultOnceParentWait, "With --once, how long to wait for a parent DZD offset before failing.")
+	triangulate                = flag.Bool("triangulate", false, "Estimate this probe's position by least-squares triangulation when at least 3 parent DZD offsets are cached, and report it alongside the composite offsets.")
+	bestOffsetSelectionStr     = flag.String("best-offset-selection", string(defaultBestOffsetSelection), "How the parent DZD offset used for composites is chosen: min-rtt (lowest RTT), recent (most recently received within --best-offset-rtt-delta of the lowest RTT), or stable (a fixed per-parent order within --best-offset-rtt-delta, to avoid flapping between near-equal parents).")
+	bestOffsetRTTDelta         = flag.Duration("best-offset-rtt-delta", defaultBestOffsetRTTDelta, "With --best-offset-selection recent or stable, how far above the lowest RTT a parent offset may be and still be chosen.")
+	triangulationVelocity      = flag.Float64("triangulation-velocity-factor", geoprobe.DefaultTriangulationVelocityFactor, "Fraction of the speed of light used to convert parent RTTs to distances for --triangulate (~0.67 for fiber).")
 	// Set by LDFLAGS
 	version = "dev"
 	commit  = "none"
@@ -81,10 +90,23 @@ func (s *parentState) getAuthority(senderPubkey [32]byte) ([32]byte, bool) {
 	return auth, ok
 }
 
-func (s *parentState) update(authorities map[[32]byte][32]byte) {
+// update atomically replaces the parent set and returns the parents that were
+// added and removed. A parent whose authority changed is reported in both.
+func (s *parentState) update(authorities map[[32]byte][32]byte) (added, removed [][32]byte) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	for parent, auth := range authorities {
+		if old, ok := s.authorities[parent]; !ok || old != auth {
+			added = append(added, parent)
+		}
+	}
+	for parent, auth := range s.authorities {
+		if cur, ok := authorities[parent]; !ok || cur != auth {
+			removed = append(removed, parent)
+		}
+	}
 	s.authorities = authorities
+	return added, removed
 }
 
 // offsetCache stores recent DZD offsets keyed by sender (device) pubkey.
@@ -92,6 +114,9 @@ type offsetCache struct {
 	mu      sync.RWMutex
 	entries map[[32]byte]*cachedSender
 	maxAge  time.Duration
+
+	selection bestOffsetSelection
+	rttDelta  time.Duration
 }
 
 type cachedOffset struct {
@@ -108,11 +133,17 @@ type cachedSender struct {
 	backup *cachedOffset // lowest RTT seen in the recent half-maxAge window
 }
 
-func newOffsetCache(maxAge time.Duration) *offsetCache {
-	return &offsetCache{
-		entries: make(map[[32]byte]*cachedSender),
-		maxAge:  maxAge,
+func newOffsetCache(maxAge time.Duration, opts ...offsetCacheOption) *offsetCache {
+	c := &offsetCache{
+		entries:   make(map[[32]byte]*cachedSender),
+		maxAge:    maxAge,
+		selection: defaultBestOffsetSelection,
+		rttDelta:  defaultBestOffsetRTTDelta,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 func (c *offsetCache) Put(offset *geoprobe.LocationOffset) {
@@ -182,24 +213,62 @@ func (c *offsetCache) Get(senderPubkey [32]byte) *geoprobe.LocationOffset {
 	return nil
 }
 
-// GetBest returns the non-expired offset with the shortest RttNs.
+// GetBest returns the non-expired offset chosen by the cache's selection
+// mode: by default, the one with the shortest RttNs.
 func (c *offsetCache) GetBest() *geoprobe.LocationOffset {
+	best, _ := c.GetBestWithAge()
+	return best
+}
+
+// GetBestWithAge returns the offset GetBest would, along with how long ago it
+// was received.
+func (c *offsetCache) GetBestWithAge() (*geoprobe.LocationOffset, time.Duration) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	var best *geoprobe.LocationOffset
+	var candidates []*cachedOffset
+	var minRttNs uint64
 	for _, sender := range c.entries {
 		for _, entry := range []*cachedOffset{sender.best, sender.backup} {
 			if entry.expired(c.maxAge) {
 				continue
 			}
-			if best == nil || entry.offset.RttNs < best.RttNs {
-				e := entry.offset
-				best = &e
+			if len(candidates) == 0 || entry.offset.RttNs < minRttNs {
+				minRttNs = entry.offset.RttNs
 			}
+			candidates = append(candidates, entry)
 		}
 	}
-	return best
+	best := c.selectBest(candidates, minRttNs)
+	if best == nil {
+		return nil, 0
+	}
+	offset := best.offset
+	return &offset, time.Since(best.receivedAt)
+}
+
+// isStaleReference reports whether a parent offset of the given age is older
+// than the preferred freshness. A zero preferred freshness disables tagging.
+func isStaleReference(age, preferredFreshness time.Duration) bool {
+	return preferredFreshness > 0 && age > preferredFreshness
+}
+
+// All returns the current offset of every sender that has a non-expired
+// entry, as returned by Get.
+func (c *offsetCache) All() []geoprobe.LocationOffset {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var offsets []geoprobe.LocationOffset
+	for _, sender := range c.entries {
+		switch {
+		case !sender.best.expired(c.maxAge):
+			offsets = append(offsets, sender.best.offset)
+		case !sender.backup.expired(c.maxAge):
+			offsets = append(offsets, sender.backup.offset)
+		}
+	}
+	return offsets
 }
 
 // Evict removes expired entries.
@@ -228,6 +297,27 @@ func marshalBestOffset(cache *offsetCache) [][]byte {
 	return [][]byte{data}
 }
 
+// estimateProbePosition triangulates this probe's position from every cached
+// parent offset when velocityFactor is non-zero. It returns nil when disabled
+// or when fewer than geoprobe.MinTriangulationReferences parents are cached,
+// in which case the best single parent offset remains the only reference.
+func estimateProbePosition(log *slog.Logger, cache *offsetCache, velocityFactor float64) *geoprobe.PositionEstimate {
+	if velocityFactor == 0 {
+		return nil
+	}
+	est, err := geoprobe.EstimatePosition(cache.All(), velocityFactor)
+	if err != nil {
+		log.Debug("Skipping position triangulation", "error", err)
+		return nil
+	}
+	log.Info("Triangulated probe position",
+		"lat", est.Lat,
+		"lng", est.Lng,
+		"residual_km", est.ResidualKm,
+		"references", est.NumReferences)
+	return &est
+}
+
 func main() {
 	flag.Parse()
 
@@ -257,6 +347,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *triangulationVelocity <= 0 || *triangulationVelocity > 1 {
+		log.Error("Invalid flag value, must be in (0, 1]", "flag", "triangulation-velocity-factor", "value", *triangulationVelocity)
+		os.Exit(1)
+	}
+	if *preferredOffsetFreshness < 0 || *preferredOffsetFreshness > *maxOffsetAge {
+		log.Error("Invalid flag value, must be between 0 and --max-offset-age", "flag", "preferred-offset-freshness", "value", *preferredOffsetFreshness)
+		os.Exit(1)
+	}
+	bestSelection, err := parseBestOffsetSelection(*bestOffsetSelectionStr)
+	if err != nil {
+		log.Error("Invalid flag value", "flag", "best-offset-selection", "error", err)
+		os.Exit(1)
+	}
+	if *bestOffsetRTTDelta < 0 {
+		log.Error("Invalid flag value, must not be negative", "flag", "best-offset-rtt-delta", "value", *bestOffsetRTTDelta)
+		os.Exit(1)
+	}
+
 	// We need an RPC URL for slot fetching.
 	if *env == "" && *ledgerRPCURL == "" {
 		log.Error("Missing required flag: either --env or --ledger-rpc-url must be provided")
@@ -274,6 +382,7 @@ func main() {
 			os.Exit(1)
 		}
 		*ledgerRPCURL = networkConfig.LedgerPublicRPCURL
+		warnEnvMismatch(log, networkConfig, "DZ_LEDGER_RPC_URL", config.DetectEnvFromRPCURL(*ledgerRPCURL))
 	}
 
 	// Load keypair.
@@ -306,6 +415,7 @@ func main() {
 			log.Error("Failed to parse geolocation-program-id", "error", err)
 			os.Exit(1)
 		}
+		warnEnvMismatch(log, networkConfig, "geolocation-program-id", config.DetectEnvFromProgramID(*geolocationProgramIDStr))
 		geolocationProgramID = pk
 	} else if networkConfig != nil {
 		geolocationProgramID = networkConfig.GeolocationProgramID
@@ -316,6 +426,7 @@ func main() {
 			log.Error("Failed to parse serviceability-program-id", "error", err)
 			os.Exit(1)
 		}
+		warnEnvMismatch(log, networkConfig, "serviceability-program-id", config.DetectEnvFromProgramID(*serviceabilityProgramIDStr))
 		serviceabilityProgramID = pk
 	} else if networkConfig != nil {
 		serviceabilityProgramID = networkConfig.ServiceabilityProgramID
@@ -332,9 +443,11 @@ func main() {
 		"parentDiscovery", parentDiscoveryEnabled,
 		"probeInterval", *probeInterval,
 		"maxOffsetAge", *maxOffsetAge,
+		"preferredOffsetFreshness", *preferredOffsetFreshness,
 		"twampListenPort", *twampListenPort,
 		"signedTWAMPListenPort", *signedTWAMPListenPort,
 		"udpListenPort", *udpListenPort,
+		"triangulate", *triangulate,
 		"authority_pubkey", keypair.PublicKey(),
 		"geoprobe_pubkey", geoProbePubkey,
 	)
@@ -398,7 +511,7 @@ func main() {
 	defer offsetListener.Close()
 
 	// Set up offset cache.
-	cache := newOffsetCache(*maxOffsetAge)
+	cache := newOffsetCache(*maxOffsetAge, withBestOffsetSelection(bestSelection, *bestOffsetRTTDelta))
 
 	// Set up pinger for targets.
 	pinger := geoprobe.NewPinger(&geoprobe.PingerConfig{
@@ -555,7 +668,16 @@ func main() {
 				case <-ctx.Done():
 					return
 				case update := <-parentUpdateCh:
-					pState.update(update.Authorities)
+					added, removed := pState.update(update.Authorities)
+					for _, parent := range removed {
+						log.Info("Removed parent from allowlist", "parent_pubkey", solana.PublicKeyFromBytes(parent[:]))
+					}
+					for _, parent := range added {
+						auth := update.Authorities[parent]
+						log.Info("Added parent to allowlist",
+							"parent_pubkey", solana.PublicKeyFromBytes(parent[:]),
+							"authority_pubkey", solana.PublicKeyFromBytes(auth[:]))
+					}
 					m.ParentsDiscovered.Set(float64(len(update.Authorities)))
 					log.Info("Updated parent authorities from discovery",
 						"totalParents", len(update.Authorities))
@@ -581,6 +703,32 @@ func main() {
 		}
 	}
 
+	if *once {
+		o := &oneShot{
+			log:                log,
+			cache:              cache,
+			signer:             signer,
+			getCurrentSlot:     getCurrentSlot,
+			parentWait:         *onceParentWait,
+			out:                os.Stdout,
+			preferredFreshness: *preferredOffsetFreshness,
+		}
+		if *triangulate {
+			o.triangulationVelocity = *triangulationVelocity
+		}
+		if *onceSend {
+			o.send = func(addr *net.UDPAddr, offset *geoprobe.LocationOffset) error {
+				return geoprobe.SendOffset(senderConn, addr, offset)
+			}
+		}
+		if err := runOnce(ctx, log, pd, td, parentUpdateCh, targetUpdateCh, inboundKeyCh, icmpTargetUpdateCh, o, pinger, icmpPinger); err != nil {
+			log.Error("One-shot measurement failed", "error", err)
+			cancel()
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Run parent and target discovery sequentially in a single goroutine so that
 	// parent discovery always updates probeTargetUpdateCount before target
 	// discovery reads it.
@@ -633,6 +781,10 @@ func main() {
 			targetUpdateCh:     targetUpdateCh,
 			icmpTargetUpdateCh: icmpTargetUpdateCh,
 			inboundKeyCh:       inboundKeyCh,
+			preferredFreshness: *preferredOffsetFreshness,
+		}
+		if *triangulate {
+			ml.triangulationVelocity = *triangulationVelocity
 		}
 		if err := ml.run(); err != nil {
 			errCh <- fmt.Errorf("measurement loop: %w", err)
@@ -649,6 +801,16 @@ func main() {
 	}
 }
 
+// warnEnvMismatch logs a warning when a flag or env var value is known to
+// belong to a different environment than the one selected with --env.
+func warnEnvMismatch(log *slog.Logger, networkConfig *config.NetworkConfig, name, detected string) {
+	if networkConfig == nil || detected == config.EnvUnknown || detected == networkConfig.Moniker {
+		return
+	}
+	log.Warn("Value belongs to a different environment than --env",
+		"name", name, "detectedEnv", detected, "env", networkConfig.Moniker)
+}
+
 func runOffsetListener(
 	ctx context.Context,
 	log *slog.Logger,
@@ -746,6 +908,12 @@ type measurementLoop struct {
 	signedReflector signed.Reflector
 	metrics         *geoprobe.Metrics
 	deliveryDNS     *geoprobe.DeliveryDNSRefresher
+	// triangulationVelocity enables position triangulation from parent
+	// offsets when non-zero; see estimateProbePosition.
+	triangulationVelocity float64
+	// preferredFreshness tags composites whose parent offset is older than
+	// this as using a stale reference; zero disables tagging.
+	preferredFreshness time.Duration
 
 	targets           []geoprobe.ProbeAddress
 	icmpTargets       []geoprobe.ProbeAddress
@@ -950,6 +1118,7 @@ func (ml *measurementLoop) runCycle() {
 	}
 
 	sent := ml.sendCompositeOffsets(rttData, mergedDelivery, icmpSet)
+	estimateProbePosition(ml.log, ml.cache, ml.triangulationVelocity)
 
 	ml.log.Info("Completed measurement cycle",
 		"measured", len(rttData),
@@ -963,11 +1132,18 @@ func (ml *measurementLoop) sendCompositeOffsets(
 	deliveryAddrs map[geoprobe.ProbeAddress]string,
 	icmpTargets map[geoprobe.ProbeAddress]struct{},
 ) int {
-	dzdOffset := ml.cache.GetBest()
+	dzdOffset, refAge := ml.cache.GetBestWithAge()
 	if dzdOffset == nil {
 		ml.log.Warn("No valid DZD offsets in cache, skipping composite generation")
 		return 0
 	}
+	staleReference := isStaleReference(refAge, ml.preferredFreshness)
+	if staleReference {
+		ml.log.Warn("Best parent DZD offset is older than the preferred freshness, composites will be tagged as stale",
+			"ref_age", refAge,
+			"preferred_freshness", ml.preferredFreshness,
+			"ref_sender_pubkey", solana.PublicKeyFromBytes(dzdOffset.SenderPubkey[:]).String())
+	}
 
 	slot, err := ml.getCurrentSlot(ml.ctx)
 	if err != nil {
@@ -1004,17 +1180,7 @@ func (ml *measurementLoop) sendCompositeOffsets(
 			targetAddr = &net.UDPAddr{IP: net.ParseIP(addr.Host), Port: int(addr.Port)}
 		}
 
-		compositeOffset := geoprobe.LocationOffset{
-			Version:         geoprobe.LocationOffsetVersion,
-			MeasurementSlot: slot,
-			MeasuredRttNs:   measuredRttNs,
-			Lat:             dzdOffset.Lat,
-			Lng:             dzdOffset.Lng,
-			RttNs:           dzdOffset.RttNs + measuredRttNs,
-			TargetIP:        geoprobe.IPToTargetIP(addr.Host),
-			NumReferences:   1,
-			References:      []geoprobe.LocationOffset{*dzdOffset},
-		}
+		compositeOffset := newCompositeOffset(dzdOffset, addr, measuredRttNs, slot)
 
 		if err := ml.signer.SignOffset(&compositeOffset); err != nil {
 			ml.log.Error("Failed to sign composite offset", "target", addr, "error", err)
@@ -1030,6 +1196,9 @@ func (ml *measurementLoop) sendCompositeOffsets(
 
 		sentCount++
 		ml.metrics.CompositeOffsetsSent.Inc()
+		if staleReference {
+			ml.metrics.CompositeOffsetsStale.Inc()
+		}
 		ml.log.Debug("Sent composite offset",
 			"target", addr,
 			"delivery", targetAddr,
@@ -1038,6 +1207,8 @@ func (ml *measurementLoop) sendCompositeOffsets(
 			"total_rtt_ns", compositeOffset.RttNs,
 			"lat", compositeOffset.Lat,
 			"lng", compositeOffset.Lng,
+			"ref_age", refAge,
+			"stale_reference", staleReference,
 			"ref_authority_pubkey", solana.PublicKeyFromBytes(dzdOffset.AuthorityPubkey[:]).String(),
 			"ref_sender_pubkey", solana.PublicKeyFromBytes(dzdOffset.SenderPubkey[:]).String())
 	}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"io"
+	"log/slog"
 	"sync"
 	"testing"
 	"time"
@@ -291,6 +293,99 @@ func TestOffsetCache_GetBestEmpty(t *testing.T) {
 	}
 }
 
+func TestOffsetCache_GetBestWithAge(t *testing.T) {
+	cache := newOffsetCache(1 * time.Hour)
+
+	cache.Put(makeTestOffset([32]byte{1}, 1000))
+	cache.Put(makeTestOffset([32]byte{2}, 5000))
+	cache.entries[[32]byte{1}].best.receivedAt = time.Now().Add(-20 * time.Minute)
+
+	best, age := cache.GetBestWithAge()
+	if best == nil {
+		t.Fatal("expected best offset, got nil")
+	}
+	if best.RttNs != 1000 {
+		t.Errorf("expected best RttNs=1000, got %d", best.RttNs)
+	}
+	if age < 20*time.Minute || age > 21*time.Minute {
+		t.Errorf("expected age ~20m, got %s", age)
+	}
+
+	if best, age := newOffsetCache(time.Hour).GetBestWithAge(); best != nil || age != 0 {
+		t.Errorf("expected nil and zero age for empty cache, got %+v, %s", best, age)
+	}
+}
+
+func TestIsStaleReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		preferred time.Duration
+		want      bool
+	}{
+		{"fresh", 2 * time.Minute, 5 * time.Minute, false},
+		{"at threshold", 5 * time.Minute, 5 * time.Minute, false},
+		{"stale", 30 * time.Minute, 5 * time.Minute, true},
+		{"disabled", 30 * time.Minute, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStaleReference(tt.age, tt.preferred); got != tt.want {
+				t.Errorf("isStaleReference(%s, %s) = %v, want %v", tt.age, tt.preferred, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffsetCache_All(t *testing.T) {
+	cache := newOffsetCache(50 * time.Millisecond)
+
+	cache.Put(makeTestOffset([32]byte{1}, 1000))
+	time.Sleep(60 * time.Millisecond)
+	cache.Put(makeTestOffset([32]byte{2}, 2000))
+	cache.Put(makeTestOffset([32]byte{3}, 3000))
+
+	all := cache.All()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 non-expired offsets, got %d", len(all))
+	}
+	for _, o := range all {
+		if o.SenderPubkey == [32]byte{1} {
+			t.Error("expected expired sender 1 to be excluded")
+		}
+	}
+}
+
+func TestEstimateProbePosition(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := newOffsetCache(1 * time.Hour)
+
+	// Parents around Frankfurt, each ~400-600km away.
+	for i, p := range []struct{ lat, lng float64 }{{52.4, 4.9}, {48.9, 2.4}, {48.1, 11.6}} {
+		o := makeTestOffset([32]byte{byte(i + 1)}, 5_000_000)
+		o.Lat, o.Lng = p.lat, p.lng
+		cache.Put(o)
+	}
+
+	if est := estimateProbePosition(log, cache, 0); est != nil {
+		t.Errorf("expected nil estimate when disabled, got %+v", est)
+	}
+	est := estimateProbePosition(log, cache, geoprobe.DefaultTriangulationVelocityFactor)
+	if est == nil {
+		t.Fatal("expected estimate with 3 parents, got nil")
+	}
+	if est.NumReferences != 3 {
+		t.Errorf("expected 3 references, got %d", est.NumReferences)
+	}
+
+	two := newOffsetCache(1 * time.Hour)
+	two.Put(makeTestOffset([32]byte{1}, 1000))
+	two.Put(makeTestOffset([32]byte{2}, 2000))
+	if est := estimateProbePosition(log, two, geoprobe.DefaultTriangulationVelocityFactor); est != nil {
+		t.Errorf("expected nil estimate with 2 parents, got %+v", est)
+	}
+}
+
 func TestOffsetCache_Evict(t *testing.T) {
 	cache := newOffsetCache(1 * time.Millisecond)
 
@@ -352,3 +447,48 @@ func TestOffsetCache_ConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestParentState_UpdateChangesAcceptance(t *testing.T) {
+	s := &parentState{authorities: make(map[[32]byte][32]byte)}
+	parentA, parentB := [32]byte{1}, [32]byte{2}
+	authA, authB := [32]byte{10}, [32]byte{20}
+
+	if _, ok := s.getAuthority(parentA); ok {
+		t.Fatal("expected unknown parent to be rejected before any update")
+	}
+
+	added, removed := s.update(map[[32]byte][32]byte{parentA: authA})
+	if len(added) != 1 || added[0] != parentA || len(removed) != 0 {
+		t.Fatalf("unexpected diff: added=%v removed=%v", added, removed)
+	}
+	if auth, ok := s.getAuthority(parentA); !ok || auth != authA {
+		t.Fatalf("expected parent A accepted with authority A, got ok=%v auth=%v", ok, auth)
+	}
+
+	// Replace A with B: A must be rejected and B accepted immediately.
+	added, removed = s.update(map[[32]byte][32]byte{parentB: authB})
+	if len(added) != 1 || added[0] != parentB || len(removed) != 1 || removed[0] != parentA {
+		t.Fatalf("unexpected diff: added=%v removed=%v", added, removed)
+	}
+	if _, ok := s.getAuthority(parentA); ok {
+		t.Error("expected removed parent A to be rejected")
+	}
+	if auth, ok := s.getAuthority(parentB); !ok || auth != authB {
+		t.Errorf("expected parent B accepted with authority B, got ok=%v auth=%v", ok, auth)
+	}
+
+	// Same set again: no diff.
+	added, removed = s.update(map[[32]byte][32]byte{parentB: authB})
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no diff for unchanged set, got added=%v removed=%v", added, removed)
+	}
+
+	// Authority rotation is reported as both a removal and an addition.
+	added, removed = s.update(map[[32]byte][32]byte{parentB: authA})
+	if len(added) != 1 || len(removed) != 1 {
+		t.Errorf("expected authority change reported as add+remove, got added=%v removed=%v", added, removed)
+	}
+	if auth, _ := s.getAuthority(parentB); auth != authA {
+		t.Errorf("expected rotated authority, got %v", auth)
+	}
+}
@@ -0,0 +1,274 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+const (
+	defaultOnceParentWait  = 30 * time.Second
+	onceParentPollInterval = 100 * time.Millisecond
+)
+
+// errNoParentOffset is returned by a one-shot run when no parent DZD offset
+// arrives within the wait window.
+var errNoParentOffset = errors.New("no parent DZD offset received")
+
+// targetMeasurer measures RTT to all registered targets.
+type targetMeasurer interface {
+	MeasureAll(ctx context.Context) (map[geoprobe.ProbeAddress]uint64, error)
+}
+
+// oneShotResult describes a single composite offset produced by a one-shot run.
+type oneShotResult struct {
+	Target          string  `json:"target"`
+	ICMP            bool    `json:"icmp"`
+	Delivery        string  `json:"delivery,omitempty"`
+	MeasuredRttNs   uint64  `json:"measured_rtt_ns"`
+	TotalRttNs      uint64  `json:"total_rtt_ns"`
+	Lat             float64 `json:"lat"`
+	Lng             float64 `json:"lng"`
+	Slot            uint64  `json:"slot"`
+	RefSenderPubkey string  `json:"ref_sender_pubkey"`
+	RefAgeNs        int64   `json:"ref_age_ns"`      // age of the parent offset when the composite was built
+	StaleReference  bool    `json:"stale_reference"` // RefAgeNs exceeds --preferred-offset-freshness
+	Sent            bool    `json:"sent"`
+	Error           string  `json:"error,omitempty"`
+	// ProbeEstimate is this probe's triangulated position, set with
+	// --triangulate when enough parent offsets are cached.
+	ProbeEstimate *geoprobe.PositionEstimate `json:"probe_estimate,omitempty"`
+}
+
+// oneShot performs a single measurement cycle against the configured targets,
+// prints the composite offsets it would send, and optionally sends them.
+type oneShot struct {
+	log            *slog.Logger
+	cache          *offsetCache
+	twamp          targetMeasurer
+	icmp           targetMeasurer
+	icmpTargets    map[geoprobe.ProbeAddress]struct{}
+	deliveryAddrs  map[geoprobe.ProbeAddress]string
+	signer         *geoprobe.OffsetSigner
+	getCurrentSlot func(ctx context.Context) (uint64, error)
+	// send delivers a signed composite offset; nil means print only.
+	send       func(addr *net.UDPAddr, offset *geoprobe.LocationOffset) error
+	parentWait time.Duration
+	out        io.Writer
+	// triangulationVelocity enables position triangulation when non-zero.
+	triangulationVelocity float64
+	// preferredFreshness tags results whose parent offset is older than this
+	// as using a stale reference; zero disables tagging.
+	preferredFreshness time.Duration
+}
+
+// waitForParentOffset polls the cache until a non-expired parent offset is
+// available or the wait window elapses, returning it with its age.
+func (o *oneShot) waitForParentOffset(ctx context.Context) (*geoprobe.LocationOffset, time.Duration, error) {
+	deadline := time.NewTimer(o.parentWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(onceParentPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if best, age := o.cache.GetBestWithAge(); best != nil {
+			return best, age, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-deadline.C:
+			return nil, 0, fmt.Errorf("%w within %s; check that parent DZDs are discovered and sending offsets to this probe", errNoParentOffset, o.parentWait)
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single discovery pass, registers the discovered targets with
+// the pingers, and performs a one-shot measurement.
+func runOnce(
+	ctx context.Context,
+	log *slog.Logger,
+	pd *geoprobe.ParentDiscovery,
+	td *geoprobe.TargetDiscovery,
+	parentUpdateCh chan geoprobe.ParentUpdate,
+	targetUpdateCh chan geoprobe.TargetUpdate,
+	inboundKeyCh chan geoprobe.InboundKeyUpdate,
+	icmpTargetUpdateCh chan geoprobe.ICMPTargetUpdate,
+	o *oneShot,
+	pinger *geoprobe.Pinger,
+	icmpPinger *geoprobe.ICMPPinger,
+) error {
+	if pd != nil {
+		pd.Tick(ctx, parentUpdateCh)
+	}
+	if td == nil {
+		return fmt.Errorf("target discovery is not configured; --once requires a geolocation program ID")
+	}
+	td.Tick(ctx, targetUpdateCh, inboundKeyCh, icmpTargetUpdateCh)
+
+	o.deliveryAddrs = make(map[geoprobe.ProbeAddress]string)
+	o.icmpTargets = make(map[geoprobe.ProbeAddress]struct{})
+	numTargets := 0
+
+	select {
+	case update := <-targetUpdateCh:
+		for _, addr := range update.Targets {
+			if err := pinger.AddProbe(ctx, addr); err != nil {
+				log.Warn("Failed to add target probe", "target", addr, "error", err)
+				continue
+			}
+			numTargets++
+		}
+		for addr, dest := range update.DeliveryAddrs {
+			o.deliveryAddrs[addr] = dest
+		}
+		o.twamp = pinger
+	default:
+	}
+
+	select {
+	case update := <-icmpTargetUpdateCh:
+		for _, addr := range update.Targets {
+			if err := icmpPinger.AddProbe(addr); err != nil {
+				log.Warn("Failed to add ICMP target probe", "target", addr, "error", err)
+				continue
+			}
+			o.icmpTargets[addr] = struct{}{}
+			numTargets++
+		}
+		for addr, dest := range update.DeliveryAddrs {
+			o.deliveryAddrs[addr] = dest
+		}
+		o.icmp = icmpPinger
+	default:
+	}
+
+	if numTargets == 0 {
+		return fmt.Errorf("no targets discovered for this probe")
+	}
+	log.Info("Running one-shot measurement", "targets", numTargets, "send", o.send != nil)
+
+	_, err := o.run(ctx)
+	return err
+}
+
+// run executes the one-shot measurement and returns the results written to out.
+func (o *oneShot) run(ctx context.Context) ([]oneShotResult, error) {
+	dzdOffset, refAge, err := o.waitForParentOffset(ctx)
+	if err != nil {
+		return nil, err
+	}
+	staleReference := isStaleReference(refAge, o.preferredFreshness)
+	o.log.Info("Using parent DZD offset",
+		"sender_pubkey", solana.PublicKeyFromBytes(dzdOffset.SenderPubkey[:]).String(),
+		"rtt_ns", dzdOffset.RttNs,
+		"ref_age", refAge,
+		"stale_reference", staleReference)
+	estimate := estimateProbePosition(o.log, o.cache, o.triangulationVelocity)
+
+	rttData := make(map[geoprobe.ProbeAddress]uint64)
+	for _, m := range []targetMeasurer{o.twamp, o.icmp} {
+		if m == nil {
+			continue
+		}
+		results, err := m.MeasureAll(ctx)
+		if err != nil {
+			o.log.Warn("Failed to measure targets", "error", err)
+			continue
+		}
+		for k, v := range results {
+			rttData[k] = v
+		}
+	}
+	if len(rttData) == 0 {
+		return nil, fmt.Errorf("no successful target measurements")
+	}
+
+	slot, err := o.getCurrentSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current slot: %w", err)
+	}
+
+	enc := json.NewEncoder(o.out)
+	results := make([]oneShotResult, 0, len(rttData))
+	for addr, measuredRttNs := range rttData {
+		composite := newCompositeOffset(dzdOffset, addr, measuredRttNs, slot)
+		_, isICMP := o.icmpTargets[addr]
+		result := oneShotResult{
+			Target:          addr.Host,
+			ICMP:            isICMP,
+			Delivery:        o.deliveryAddrs[addr],
+			MeasuredRttNs:   measuredRttNs,
+			TotalRttNs:      composite.RttNs,
+			Lat:             composite.Lat,
+			Lng:             composite.Lng,
+			Slot:            slot,
+			RefSenderPubkey: solana.PublicKeyFromBytes(dzdOffset.SenderPubkey[:]).String(),
+			RefAgeNs:        refAge.Nanoseconds(),
+			StaleReference:  staleReference,
+			ProbeEstimate:   estimate,
+		}
+
+		if o.send != nil {
+			if err := o.deliver(addr, isICMP, &composite); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Sent = true
+			}
+		}
+
+		if err := enc.Encode(result); err != nil {
+			return nil, fmt.Errorf("failed to write result: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// deliver signs and sends a composite offset to the target's delivery address.
+func (o *oneShot) deliver(addr geoprobe.ProbeAddress, isICMP bool, composite *geoprobe.LocationOffset) error {
+	var targetAddr *net.UDPAddr
+	if dest, ok := o.deliveryAddrs[addr]; ok && dest != "" {
+		resolved, err := net.ResolveUDPAddr("udp", dest)
+		if err != nil {
+			return fmt.Errorf("failed to resolve delivery address %s: %w", dest, err)
+		}
+		targetAddr = resolved
+	} else if isICMP {
+		return fmt.Errorf("ICMP target has no result destination")
+	} else {
+		targetAddr = &net.UDPAddr{IP: net.ParseIP(addr.Host), Port: int(addr.Port)}
+	}
+
+	if err := o.signer.SignOffset(composite); err != nil {
+		return fmt.Errorf("failed to sign composite offset: %w", err)
+	}
+	return o.send(targetAddr, composite)
+}
+
+// newCompositeOffset builds an unsigned probe→target offset that references the
+// given parent DZD offset.
+func newCompositeOffset(dzdOffset *geoprobe.LocationOffset, addr geoprobe.ProbeAddress, measuredRttNs, slot uint64) geoprobe.LocationOffset {
+	return geoprobe.LocationOffset{
+		Version:         geoprobe.LocationOffsetVersion,
+		MeasurementSlot: slot,
+		MeasuredRttNs:   measuredRttNs,
+		Lat:             dzdOffset.Lat,
+		Lng:             dzdOffset.Lng,
+		RttNs:           dzdOffset.RttNs + measuredRttNs,
+		TargetIP:        geoprobe.IPToTargetIP(addr.Host),
+		NumReferences:   1,
+		References:      []geoprobe.LocationOffset{*dzdOffset},
+	}
+}
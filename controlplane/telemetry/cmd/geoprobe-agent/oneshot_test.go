@@ -0,0 +1,160 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+type fakeMeasurer struct {
+	results map[geoprobe.ProbeAddress]uint64
+}
+
+func (f *fakeMeasurer) MeasureAll(ctx context.Context) (map[geoprobe.ProbeAddress]uint64, error) {
+	return f.results, nil
+}
+
+func newTestOneShot(t *testing.T, cache *offsetCache, measurer targetMeasurer, out io.Writer) *oneShot {
+	t.Helper()
+	keypair, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	signer, err := geoprobe.NewOffsetSigner(keypair, solana.PublicKey{9})
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+	return &oneShot{
+		log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cache:          cache,
+		twamp:          measurer,
+		deliveryAddrs:  map[geoprobe.ProbeAddress]string{},
+		icmpTargets:    map[geoprobe.ProbeAddress]struct{}{},
+		signer:         signer,
+		getCurrentSlot: func(ctx context.Context) (uint64, error) { return 777, nil },
+		parentWait:     time.Second,
+		out:            out,
+	}
+}
+
+func TestOneShot_PrintsComposite(t *testing.T) {
+	cache := newOffsetCache(time.Hour)
+	cache.Put(makeTestOffset([32]byte{1}, 5000))
+
+	target := geoprobe.ProbeAddress{Host: "192.0.2.10", Port: 8925}
+	var out bytes.Buffer
+	o := newTestOneShot(t, cache, &fakeMeasurer{results: map[geoprobe.ProbeAddress]uint64{target: 2000}}, &out)
+
+	results, err := o.run(context.Background())
+	if err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	var printed oneShotResult
+	if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+		t.Fatalf("failed to decode output %q: %v", out.String(), err)
+	}
+	if printed.Target != "192.0.2.10" || printed.MeasuredRttNs != 2000 || printed.TotalRttNs != 7000 {
+		t.Errorf("unexpected result: %+v", printed)
+	}
+	if printed.Slot != 777 {
+		t.Errorf("expected slot 777, got %d", printed.Slot)
+	}
+	if printed.Sent {
+		t.Error("expected composite not to be sent without a sender")
+	}
+}
+
+func TestOneShot_SendsSignedComposite(t *testing.T) {
+	cache := newOffsetCache(time.Hour)
+	cache.Put(makeTestOffset([32]byte{1}, 5000))
+
+	target := geoprobe.ProbeAddress{Host: "192.0.2.10", Port: 8925}
+	o := newTestOneShot(t, cache, &fakeMeasurer{results: map[geoprobe.ProbeAddress]uint64{target: 2000}}, io.Discard)
+
+	var sentTo *net.UDPAddr
+	var sent *geoprobe.LocationOffset
+	o.send = func(addr *net.UDPAddr, offset *geoprobe.LocationOffset) error {
+		sentTo, sent = addr, offset
+		return nil
+	}
+
+	results, err := o.run(context.Background())
+	if err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if !results[0].Sent {
+		t.Fatalf("expected composite to be sent, got %+v", results[0])
+	}
+	if sentTo.String() != "192.0.2.10:8925" {
+		t.Errorf("expected send to target address, got %s", sentTo)
+	}
+	if err := geoprobe.VerifyOffset(sent); err != nil {
+		t.Errorf("sent composite has invalid signature: %v", err)
+	}
+	if sent.NumReferences != 1 || sent.RttNs != 7000 {
+		t.Errorf("unexpected composite: refs=%d rtt=%d", sent.NumReferences, sent.RttNs)
+	}
+}
+
+func TestOneShot_NoParentOffset(t *testing.T) {
+	target := geoprobe.ProbeAddress{Host: "192.0.2.10", Port: 8925}
+	var out bytes.Buffer
+	o := newTestOneShot(t, newOffsetCache(time.Hour), &fakeMeasurer{results: map[geoprobe.ProbeAddress]uint64{target: 2000}}, &out)
+	o.parentWait = 200 * time.Millisecond
+
+	_, err := o.run(context.Background())
+	if !errors.Is(err, errNoParentOffset) {
+		t.Fatalf("expected errNoParentOffset, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
+
+func TestOneShot_TagsStaleReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		refAge    time.Duration
+		wantStale bool
+	}{
+		{"fresh parent offset", time.Minute, false},
+		{"parent offset older than preferred freshness", 20 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newOffsetCache(time.Hour)
+			cache.Put(makeTestOffset([32]byte{1}, 5000))
+			cache.entries[[32]byte{1}].best.receivedAt = time.Now().Add(-tt.refAge)
+
+			target := geoprobe.ProbeAddress{Host: "192.0.2.10", Port: 8925}
+			o := newTestOneShot(t, cache, &fakeMeasurer{results: map[geoprobe.ProbeAddress]uint64{target: 2000}}, io.Discard)
+			o.preferredFreshness = 10 * time.Minute
+
+			results, err := o.run(context.Background())
+			if err != nil {
+				t.Fatalf("run error: %v", err)
+			}
+			if results[0].StaleReference != tt.wantStale {
+				t.Errorf("StaleReference = %v, want %v", results[0].StaleReference, tt.wantStale)
+			}
+			if got := time.Duration(results[0].RefAgeNs); got < tt.refAge {
+				t.Errorf("RefAgeNs = %s, want >= %s", got, tt.refAge)
+			}
+		})
+	}
+}
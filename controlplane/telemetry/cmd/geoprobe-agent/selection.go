@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// bestOffsetSelection is how the offset cache picks the parent offset used as
+// the reference for composite offsets.
+type bestOffsetSelection string
+
+const (
+	// selectMinRTT picks the offset with the lowest RTT. Near-equal parents
+	// can swap places from one cycle to the next.
+	selectMinRTT bestOffsetSelection = "min-rtt"
+	// selectRecent picks the most recently received offset among those within
+	// the RTT delta of the minimum.
+	selectRecent bestOffsetSelection = "recent"
+	// selectStable picks, among offsets within the RTT delta of the minimum,
+	// the one whose sender pubkey hashes lowest, so the choice only changes
+	// when a parent enters or leaves the near-tie set.
+	selectStable bestOffsetSelection = "stable"
+
+	defaultBestOffsetSelection = selectMinRTT
+	defaultBestOffsetRTTDelta  = 100 * time.Microsecond
+)
+
+func parseBestOffsetSelection(s string) (bestOffsetSelection, error) {
+	switch mode := bestOffsetSelection(s); mode {
+	case selectMinRTT, selectRecent, selectStable:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown best offset selection %q (must be %s, %s or %s)", s, selectMinRTT, selectRecent, selectStable)
+}
+
+type offsetCacheOption func(*offsetCache)
+
+// withBestOffsetSelection sets how GetBest chooses among cached offsets. For
+// the recent and stable modes, rttDelta bounds how far above the minimum RTT
+// an offset may be and still be chosen.
+func withBestOffsetSelection(mode bestOffsetSelection, rttDelta time.Duration) offsetCacheOption {
+	return func(c *offsetCache) {
+		c.selection = mode
+		c.rttDelta = rttDelta
+	}
+}
+
+// selectBest picks the best of the non-expired candidates per the cache's
+// selection mode; minRttNs is the lowest RTT among them.
+func (c *offsetCache) selectBest(candidates []*cachedOffset, minRttNs uint64) *cachedOffset {
+	var best *cachedOffset
+	for _, entry := range candidates {
+		if c.selection != selectMinRTT && entry.offset.RttNs-minRttNs > uint64(c.rttDelta) {
+			continue
+		}
+		if best == nil || c.prefer(entry, best) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// prefer reports whether a should be chosen over b. Entries the mode cannot
+// tell apart fall back to the lower RTT.
+func (c *offsetCache) prefer(a, b *cachedOffset) bool {
+	switch c.selection {
+	case selectRecent:
+		if !a.receivedAt.Equal(b.receivedAt) {
+			return a.receivedAt.After(b.receivedAt)
+		}
+	case selectStable:
+		if ha, hb := senderHash(a), senderHash(b); ha != hb {
+			return ha < hb
+		}
+	}
+	return a.offset.RttNs < b.offset.RttNs
+}
+
+func senderHash(entry *cachedOffset) uint64 {
+	h := fnv.New64a()
+	h.Write(entry.offset.SenderPubkey[:])
+	return h.Sum64()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBestOffsetSelection(t *testing.T) {
+	for _, s := range []string{"min-rtt", "recent", "stable"} {
+		if got, err := parseBestOffsetSelection(s); err != nil || string(got) != s {
+			t.Errorf("parseBestOffsetSelection(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := parseBestOffsetSelection("fastest"); err == nil {
+		t.Error("expected error for unknown selection")
+	}
+}
+
+// nearTieCycles feeds the cache two parents whose RTTs are within 20ns of
+// each other, with the lead swapping every cycle, and returns the sender of
+// the best offset after each cycle.
+func nearTieCycles(t *testing.T, cache *offsetCache, cycles int) [][32]byte {
+	t.Helper()
+	parentA, parentB := [32]byte{1}, [32]byte{2}
+	var picks [][32]byte
+	for i := range cycles {
+		base := uint64(1_000_000 - i*100)
+		if i%2 == 0 {
+			cache.Put(makeTestOffset(parentA, base))
+			cache.Put(makeTestOffset(parentB, base+20))
+		} else {
+			cache.Put(makeTestOffset(parentA, base+20))
+			cache.Put(makeTestOffset(parentB, base))
+		}
+		best := cache.GetBest()
+		if best == nil {
+			t.Fatalf("cycle %d: expected a best offset", i)
+		}
+		picks = append(picks, best.SenderPubkey)
+	}
+	return picks
+}
+
+func TestOffsetCache_MinRTTSelectionFlapsOnNearTie(t *testing.T) {
+	picks := nearTieCycles(t, newOffsetCache(time.Hour), 6)
+	for i := 1; i < len(picks); i++ {
+		if picks[i] == picks[i-1] {
+			t.Fatalf("cycle %d: expected min-rtt selection to follow the lowest RTT, got %x twice", i, picks[i][0])
+		}
+	}
+}
+
+func TestOffsetCache_StableSelection(t *testing.T) {
+	cache := newOffsetCache(time.Hour, withBestOffsetSelection(selectStable, 100*time.Nanosecond))
+	picks := nearTieCycles(t, cache, 6)
+	for i, pick := range picks {
+		if pick != picks[0] {
+			t.Fatalf("cycle %d: selection flapped from %x to %x", i, picks[0][0], pick[0])
+		}
+	}
+
+	// A parent clearly better than the near-tie set is still chosen.
+	faster := [32]byte{3}
+	cache.Put(makeTestOffset(faster, 500_000))
+	if best := cache.GetBest(); best == nil || best.SenderPubkey != faster {
+		t.Fatalf("expected parent beyond the RTT delta to win, got %+v", best)
+	}
+}
+
+func TestOffsetCache_StableSelectionPrefersLowerRTTOfSameParent(t *testing.T) {
+	cache := newOffsetCache(time.Hour, withBestOffsetSelection(selectStable, time.Millisecond))
+	pubkey := [32]byte{1}
+	cache.Put(makeTestOffset(pubkey, 1000))
+	cache.Put(makeTestOffset(pubkey, 1500)) // backup
+
+	if best := cache.GetBest(); best == nil || best.RttNs != 1000 {
+		t.Fatalf("expected the parent's lowest RTT offset, got %+v", best)
+	}
+}
+
+func TestOffsetCache_RecentSelection(t *testing.T) {
+	cache := newOffsetCache(time.Hour, withBestOffsetSelection(selectRecent, 100*time.Nanosecond))
+	parentA, parentB, parentC := [32]byte{1}, [32]byte{2}, [32]byte{3}
+
+	cache.Put(makeTestOffset(parentA, 1_000_000))
+	time.Sleep(time.Millisecond)
+	cache.Put(makeTestOffset(parentB, 1_000_050))
+	if best := cache.GetBest(); best == nil || best.SenderPubkey != parentB {
+		t.Fatalf("expected the most recent near-tie parent, got %+v", best)
+	}
+
+	// A more recent offset beyond the RTT delta is not chosen.
+	time.Sleep(time.Millisecond)
+	cache.Put(makeTestOffset(parentC, 1_000_500))
+	if best := cache.GetBest(); best == nil || best.SenderPubkey != parentB {
+		t.Fatalf("expected parent beyond the RTT delta to be skipped, got %+v", best)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+var errUnknownEncoding = errors.New("input is neither hex nor base64")
+
+// decodeDatagram parses a hex- or base64-encoded LocationOffset datagram.
+// Surrounding whitespace and a leading 0x on hex input are ignored.
+func decodeDatagram(input []byte) (*geoprobe.LocationOffset, error) {
+	text := string(bytes.Join(bytes.Fields(input), nil))
+	if text == "" {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	raw, err := hex.DecodeString(trimHexPrefix(text))
+	if err != nil {
+		if raw, err = base64.StdEncoding.DecodeString(text); err != nil {
+			if raw, err = base64.RawStdEncoding.DecodeString(text); err != nil {
+				return nil, errUnknownEncoding
+			}
+		}
+	}
+	if len(raw) > geoprobe.MaxUDPPacketSize {
+		return nil, fmt.Errorf("datagram size %d exceeds maximum %d", len(raw), geoprobe.MaxUDPPacketSize)
+	}
+
+	offset := &geoprobe.LocationOffset{}
+	if err := offset.Unmarshal(raw); err != nil {
+		return nil, fmt.Errorf("malformed LocationOffset (%d bytes): %w", len(raw), err)
+	}
+	return offset, nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		return s[2:]
+	}
+	return s
+}
+
+// runDecode reads an encoded datagram from path ("-" for stdin), verifies its
+// reference chain, and writes the decoded offset to w as text or JSON.
+func runDecode(path string, w io.Writer, jsonOutput bool, velocityFactor float64, units distanceUnits) error {
+	var (
+		input []byte
+		err   error
+	)
+	if path == "-" {
+		input, err = io.ReadAll(os.Stdin)
+	} else {
+		input, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read datagram: %w", err)
+	}
+
+	offset, err := decodeDatagram(input)
+	if err != nil {
+		return err
+	}
+
+	verifyError := geoprobe.VerifyOffsetChain(offset)
+	output := formatLocationOffset(offset, nil, verifyError == nil, verifyError, velocityFactor, units)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal offset output: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	_, err = fmt.Fprint(w, formatTextOutput(output))
+	return err
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+// signedDatagram returns a probe offset referencing a DZD offset, both signed,
+// serialized as it would be sent on the wire.
+func signedDatagram(t *testing.T) []byte {
+	t.Helper()
+	sign := func(o *geoprobe.LocationOffset) {
+		signer, err := geoprobe.NewOffsetSigner(solana.NewWallet().PrivateKey, solana.NewWallet().PublicKey())
+		if err != nil {
+			t.Fatalf("NewOffsetSigner: %v", err)
+		}
+		if err := signer.SignOffset(o); err != nil {
+			t.Fatalf("SignOffset: %v", err)
+		}
+	}
+
+	dzd := geoprobe.LocationOffset{
+		Version:         geoprobe.LocationOffsetVersion,
+		MeasurementSlot: 100,
+		MeasuredRttNs:   2_000_000,
+		Lat:             52.37,
+		Lng:             4.90,
+		RttNs:           2_000_000,
+		TargetIP:        [4]byte{192, 0, 2, 10},
+	}
+	sign(&dzd)
+
+	probe := geoprobe.LocationOffset{
+		Version:         geoprobe.LocationOffsetVersion,
+		MeasurementSlot: 101,
+		MeasuredRttNs:   3_000_000,
+		Lat:             dzd.Lat,
+		Lng:             dzd.Lng,
+		RttNs:           5_000_000,
+		TargetIP:        [4]byte{198, 51, 100, 7},
+		NumReferences:   1,
+		References:      []geoprobe.LocationOffset{dzd},
+	}
+	sign(&probe)
+
+	data, err := probe.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return data
+}
+
+func TestDecodeDatagram_KnownGood(t *testing.T) {
+	data := signedDatagram(t)
+
+	inputs := map[string]string{
+		"hex":              hex.EncodeToString(data),
+		"hex with 0x":      "0x" + hex.EncodeToString(data) + "\n",
+		"hex line-wrapped": hex.EncodeToString(data[:40]) + "\n" + hex.EncodeToString(data[40:]) + "\n",
+		"base64":           base64.StdEncoding.EncodeToString(data),
+		"base64 unpadded":  base64.RawStdEncoding.EncodeToString(data),
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			offset, err := decodeDatagram([]byte(input))
+			if err != nil {
+				t.Fatalf("decodeDatagram: %v", err)
+			}
+			if offset.RttNs != 5_000_000 || offset.MeasurementSlot != 101 {
+				t.Errorf("decoded RttNs=%d slot=%d, want 5000000/101", offset.RttNs, offset.MeasurementSlot)
+			}
+			if len(offset.References) != 1 || offset.References[0].MeasurementSlot != 100 {
+				t.Fatalf("decoded references = %+v, want one DZD reference at slot 100", offset.References)
+			}
+			if err := geoprobe.VerifyOffsetChain(offset); err != nil {
+				t.Errorf("VerifyOffsetChain: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeDatagram_Malformed(t *testing.T) {
+	data := signedDatagram(t)
+
+	tests := map[string]string{
+		"empty":          "  \n",
+		"not encoded":    "not a datagram!",
+		"truncated":      hex.EncodeToString(data[:len(data)/2]),
+		"single byte":    "01",
+		"oversized":      hex.EncodeToString(make([]byte, geoprobe.MaxUDPPacketSize+1)),
+		"bad version":    hex.EncodeToString(append(append(append([]byte{}, data[:64]...), 99), data[65:]...)),
+		"odd-length hex": hex.EncodeToString(data)[1:],
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeDatagram([]byte(input)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := decodeDatagram([]byte("not a datagram!")); !errors.Is(err, errUnknownEncoding) {
+		t.Errorf("expected errUnknownEncoding, got %v", err)
+	}
+}
+
+func TestRunDecode(t *testing.T) {
+	data := signedDatagram(t)
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.hex")
+	if err := os.WriteFile(good, []byte(hex.EncodeToString(data)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runDecode(good, &buf, true, 1.0, distanceUnits{miles: true, km: true}); err != nil {
+		t.Fatalf("runDecode: %v", err)
+	}
+	var out OffsetOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if !out.SignatureValid || out.SignatureError != "" {
+		t.Errorf("signature valid=%v error=%q, want valid", out.SignatureValid, out.SignatureError)
+	}
+	if out.TargetIP != "198.51.100.7" || len(out.DZDReferenceChain) != 1 {
+		t.Errorf("target=%s chain=%d, want 198.51.100.7 with 1 reference", out.TargetIP, len(out.DZDReferenceChain))
+	}
+
+	// Flip a byte in the referenced DZD offset's coordinates: the datagram
+	// still decodes but the chain no longer verifies.
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)-20] ^= 0xff
+	corruptPath := filepath.Join(dir, "corrupt.b64")
+	if err := os.WriteFile(corruptPath, []byte(base64.StdEncoding.EncodeToString(corrupt)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := runDecode(corruptPath, &buf, false, 1.0, distanceUnits{miles: true, km: true}); err != nil {
+		t.Fatalf("runDecode: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Signature: INVALID") {
+		t.Errorf("expected invalid signature in output:\n%s", buf.String())
+	}
+
+	if err := runDecode(filepath.Join(dir, "missing"), &buf, false, 1.0, distanceUnits{miles: true, km: true}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
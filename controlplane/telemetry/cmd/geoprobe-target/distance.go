@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	kmPerMile  = 1.60934
+	nmiPerMile = 0.868976
+
+	defaultDistanceUnits = "mi,km"
+)
+
+// distanceUnits selects which max distance fields appear in text and JSON
+// output.
+type distanceUnits struct {
+	miles bool
+	km    bool
+	nmi   bool
+}
+
+// parseDistanceUnits parses a comma-separated list of mi, km and nmi, or all.
+func parseDistanceUnits(s string) (distanceUnits, error) {
+	var units distanceUnits
+	for _, name := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "mi":
+			units.miles = true
+		case "km":
+			units.km = true
+		case "nmi":
+			units.nmi = true
+		case "all":
+			units = distanceUnits{miles: true, km: true, nmi: true}
+		case "":
+		default:
+			return distanceUnits{}, fmt.Errorf("unknown unit %q (must be mi, km, nmi or all)", name)
+		}
+	}
+	if units == (distanceUnits{}) {
+		return distanceUnits{}, fmt.Errorf("no units given")
+	}
+	return units, nil
+}
+
+// maxDistances is the maximum one-way distance for an RTT in each unit.
+type maxDistances struct {
+	miles float64
+	km    float64
+	nmi   float64
+}
+
+// calculateMaxDistances converts calculateMaxDistance to every supported
+// unit.
+func calculateMaxDistances(rttNs uint64, velocityFactor float64) maxDistances {
+	miles := calculateMaxDistance(rttNs, velocityFactor)
+	return maxDistances{
+		miles: miles,
+		km:    miles * kmPerMile,
+		nmi:   miles * nmiPerMile,
+	}
+}
+
+// formatMaxDistance renders the selected distances for text output, e.g.
+// "620 miles (998 km)".
+func formatMaxDistance(output OffsetOutput) string {
+	var parts []string
+	if output.MaxDistanceMiles != nil {
+		parts = append(parts, fmt.Sprintf("%.0f miles", *output.MaxDistanceMiles))
+	}
+	if output.MaxDistanceKm != nil {
+		parts = append(parts, fmt.Sprintf("%.0f km", *output.MaxDistanceKm))
+	}
+	if output.MaxDistanceNmi != nil {
+		parts = append(parts, fmt.Sprintf("%.0f nmi", *output.MaxDistanceNmi))
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return fmt.Sprintf("%s (%s)", parts[0], strings.Join(parts[1:], ", "))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+func TestParseDistanceUnits(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    distanceUnits
+		wantErr bool
+	}{
+		{in: defaultDistanceUnits, want: distanceUnits{miles: true, km: true}},
+		{in: "mi", want: distanceUnits{miles: true}},
+		{in: "km", want: distanceUnits{km: true}},
+		{in: "nmi", want: distanceUnits{nmi: true}},
+		{in: "all", want: distanceUnits{miles: true, km: true, nmi: true}},
+		{in: " KM , nmi ", want: distanceUnits{km: true, nmi: true}},
+		{in: "", wantErr: true},
+		{in: "ft", wantErr: true},
+		{in: "mi,furlongs", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDistanceUnits(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDistanceUnits(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDistanceUnits(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateMaxDistances(t *testing.T) {
+	// 10ms RTT -> 5ms one way -> 620 miles at c.
+	got := calculateMaxDistances(10_000_000, 1.0)
+	for _, tt := range []struct {
+		unit      string
+		got, want float64
+	}{
+		{"miles", got.miles, 620},
+		{"km", got.km, 997.7908},
+		{"nmi", got.nmi, 538.76512},
+	} {
+		if math.Abs(tt.got-tt.want) > 1e-6 {
+			t.Errorf("%s = %f, want %f", tt.unit, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFormatLocationOffset_DistanceUnits(t *testing.T) {
+	offset := &geoprobe.LocationOffset{RttNs: 10_000_000}
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 8923}
+
+	tests := []struct {
+		name     string
+		units    distanceUnits
+		wantText string
+		wantJSON []string
+		omitJSON []string
+	}{
+		{
+			name:     "default",
+			units:    distanceUnits{miles: true, km: true},
+			wantText: "Max Distance: 620 miles (998 km) at 1.00c",
+			wantJSON: []string{`"max_distance_miles":620`, `"max_distance_km":997.79`},
+			omitJSON: []string{"max_distance_nmi"},
+		},
+		{
+			name:     "miles",
+			units:    distanceUnits{miles: true},
+			wantText: "Max Distance: 620 miles at 1.00c",
+			wantJSON: []string{`"max_distance_miles":620`},
+			omitJSON: []string{"max_distance_km", "max_distance_nmi"},
+		},
+		{
+			name:     "km",
+			units:    distanceUnits{km: true},
+			wantText: "Max Distance: 998 km at 1.00c",
+			wantJSON: []string{`"max_distance_km":997.79`},
+			omitJSON: []string{"max_distance_miles", "max_distance_nmi"},
+		},
+		{
+			name:     "nmi",
+			units:    distanceUnits{nmi: true},
+			wantText: "Max Distance: 539 nmi at 1.00c",
+			wantJSON: []string{`"max_distance_nmi":538.76`},
+			omitJSON: []string{"max_distance_miles", "max_distance_km"},
+		},
+		{
+			name:     "all",
+			units:    distanceUnits{miles: true, km: true, nmi: true},
+			wantText: "Max Distance: 620 miles (998 km, 539 nmi) at 1.00c",
+			wantJSON: []string{`"max_distance_miles":620`, `"max_distance_km":997.79`, `"max_distance_nmi":538.76`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := formatLocationOffset(offset, addr, true, nil, 1.0, tt.units)
+
+			if text := formatTextOutput(output); !strings.Contains(text, tt.wantText) {
+				t.Errorf("text output missing %q:\n%s", tt.wantText, text)
+			}
+
+			data, err := json.Marshal(output)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			for _, want := range tt.wantJSON {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("JSON output missing %s: %s", want, data)
+				}
+			}
+			for _, omit := range tt.omitJSON {
+				if strings.Contains(string(data), omit) {
+					t.Errorf("JSON output contains %s: %s", omit, data)
+				}
+			}
+		})
+	}
+}
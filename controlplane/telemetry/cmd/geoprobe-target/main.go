@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/netip"
 	"os"
 	"os/signal"
 	"strings"
@@ -30,6 +31,7 @@ const (
 	nanosecondsPerMs         = 1000000.0
 	rateLimitCleanupInterval = 5 * time.Minute
 	rateLimitEntryTTL        = 10 * time.Minute
+	rateLimitIPv6PrefixLen   = 64
 )
 
 var (
@@ -37,8 +39,14 @@ var (
 	udpPort         = flag.Uint("udp-port", defaultUDPPort, "Port to listen for LocationOffset UDP datagrams")
 	logFormat       = flag.String("log-format", "text", "Log format: text or json")
 	verifySignature = flag.Bool("verify-signatures", true, "Verify Ed25519 signatures on received offsets")
-	rateLimit       = flag.Uint("rate-limit", defaultRateLimit, "Maximum packets per second per source IP (0 disables rate limiting)")
+	rateLimit       = flag.Uint("rate-limit", defaultRateLimit, "Maximum packets per second per source IP, or per /64 for IPv6 sources (0 disables rate limiting)")
 	maxOffsetAge    = flag.Duration("max-offset-age", 1*time.Hour, "TTL for cached offsets; best/second-best tracking window")
+	velocityFactor  = flag.Float64("velocity-factor", 1.0, "Fraction of the speed of light used for max distance (1.0 = theoretical max, ~0.67 for fiber)")
+	distanceUnitsF  = flag.String("distance-units", defaultDistanceUnits, "Comma-separated max distance units shown in output: mi, km, nmi, or all")
+	dropBogons      = flag.Bool("drop-bogon-sources", false, "Drop UDP offsets from private, loopback, link-local, documentation, multicast and other reserved source ranges")
+	sourceAllow     = flag.String("source-allow", "", "Comma-separated CIDRs always accepted by the UDP source filter, even if denied (e.g. a lab 10.0.0.0/8)")
+	sourceDeny      = flag.String("source-deny", "", "Comma-separated CIDRs whose UDP offsets are dropped, in addition to --drop-bogon-sources")
+	decode          = flag.String("decode", "", "Decode a hex or base64 LocationOffset datagram from this file (- for stdin), print it in --log-format and exit")
 	verbose         = flag.Bool("verbose", false, "Enable verbose logging")
 	showVersion     = flag.Bool("version", false, "Print version and exit")
 
@@ -63,6 +71,35 @@ func main() {
 		fmt.Fprintf(os.Stderr, "invalid udp-port: must be 1-65535\n")
 		os.Exit(1)
 	}
+	if *velocityFactor <= 0 || *velocityFactor > 1 {
+		fmt.Fprintf(os.Stderr, "invalid velocity-factor: must be in (0, 1]\n")
+		os.Exit(1)
+	}
+	units, err := parseDistanceUnits(*distanceUnitsF)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid distance-units: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *decode != "" {
+		if err := runDecode(*decode, os.Stdout, *logFormat == "json", *velocityFactor, units); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to decode datagram: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	allowPrefixes, err := parsePrefixes(*sourceAllow)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid source-allow: %v\n", err)
+		os.Exit(1)
+	}
+	denyPrefixes, err := parsePrefixes(*sourceDeny)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid source-deny: %v\n", err)
+		os.Exit(1)
+	}
+	filter := newSourceFilter(*dropBogons, allowPrefixes, denyPrefixes)
 
 	log := setupLogger(*logFormat, *verbose)
 	log.Info("starting geoprobe-target",
@@ -75,6 +112,11 @@ func main() {
 		"rate_limit", *rateLimit,
 		"max_reference_depth", maxReferenceDepth,
 		"max_offset_age", *maxOffsetAge,
+		"velocity_factor", *velocityFactor,
+		"distance_units", *distanceUnitsF,
+		"drop_bogon_sources", *dropBogons,
+		"source_allow", allowPrefixes,
+		"source_deny", denyPrefixes,
 	)
 
 	// Keyed by SenderPubkey (geoprobe identity). Each geoprobe is an independent
@@ -104,7 +146,7 @@ func main() {
 	go sweepCaches(ctx, caches)
 
 	go runTWAMPReflector(ctx, log, *twampPort, errCh)
-	go runUDPListener(ctx, log, *udpPort, *verifySignature, limiter, chWriter, caches, errCh)
+	go runUDPListener(ctx, log, *udpPort, *verifySignature, *velocityFactor, units, filter, limiter, chWriter, caches, errCh)
 
 	select {
 	case err := <-errCh:
@@ -156,6 +198,18 @@ func newRateLimiter(maxTokens uint) *rateLimiter {
 	}
 }
 
+// rateLimitKey returns the rate limiter bucket for a source address. IPv4
+// sources are limited per address. IPv6 sources are limited per /64, the
+// smallest prefix routinely assigned to one host, so a sender cannot sidestep
+// the limit by rotating through the addresses of its own subnet.
+func rateLimitKey(addr netip.Addr) string {
+	addr = addr.Unmap().WithZone("")
+	if addr.Is4() {
+		return addr.String()
+	}
+	return netip.PrefixFrom(addr, rateLimitIPv6PrefixLen).Masked().String()
+}
+
 func (rl *rateLimiter) allow(ip string) bool {
 	if rl.maxTokens == 0 {
 		return true
@@ -245,7 +299,7 @@ func runTWAMPReflector(ctx context.Context, log *slog.Logger, port uint, errCh c
 	}
 }
 
-func runUDPListener(ctx context.Context, log *slog.Logger, port uint, verifySignatures bool, limiter *rateLimiter, chWriter *geoprobe.ClickhouseWriter, caches *geoprobe.MinCacheMap[[32]byte, geoprobe.LocationOffset], errCh chan<- error) {
+func runUDPListener(ctx context.Context, log *slog.Logger, port uint, verifySignatures bool, velocityFactor float64, units distanceUnits, filter *sourceFilter, limiter *rateLimiter, chWriter *geoprobe.ClickhouseWriter, caches *geoprobe.MinCacheMap[[32]byte, geoprobe.LocationOffset], errCh chan<- error) {
 	conn, err := geoprobe.NewUDPListener(int(port))
 	if err != nil {
 		errCh <- fmt.Errorf("failed to create UDP listener: %w", err)
@@ -288,8 +342,15 @@ func runUDPListener(ctx context.Context, log *slog.Logger, port uint, verifySign
 			return
 		}
 
-		sourceIP := addr.IP.String()
-		if !limiter.allow(sourceIP) {
+		if !filter.accept(addr.AddrPort().Addr()) {
+			log.Debug("dropped offset from filtered source",
+				"from", addr,
+				"dropped_total", filter.dropped.Load(),
+			)
+			continue
+		}
+
+		if !limiter.allow(rateLimitKey(addr.AddrPort().Addr())) {
 			log.Warn("rate limit exceeded",
 				"from", addr,
 				"limit", limiter.maxTokens,
@@ -307,7 +368,7 @@ func runUDPListener(ctx context.Context, log *slog.Logger, port uint, verifySign
 			continue
 		}
 
-		handleOffset(log, offset, addr, verifySignatures, chWriter, caches)
+		handleOffset(log, offset, addr, verifySignatures, velocityFactor, units, chWriter, caches)
 	}
 }
 
@@ -325,7 +386,7 @@ func countReferenceDepth(offset *geoprobe.LocationOffset) int {
 	return maxDepth + 1
 }
 
-func handleOffset(log *slog.Logger, offset *geoprobe.LocationOffset, addr *net.UDPAddr, verifySignatures bool, chWriter *geoprobe.ClickhouseWriter, caches *geoprobe.MinCacheMap[[32]byte, geoprobe.LocationOffset]) {
+func handleOffset(log *slog.Logger, offset *geoprobe.LocationOffset, addr *net.UDPAddr, verifySignatures bool, velocityFactor float64, units distanceUnits, chWriter *geoprobe.ClickhouseWriter, caches *geoprobe.MinCacheMap[[32]byte, geoprobe.LocationOffset]) {
 	signatureValid := true
 	var verifyError error
 
@@ -352,7 +413,7 @@ func handleOffset(log *slog.Logger, offset *geoprobe.LocationOffset, addr *net.U
 	cache := caches.Get(offset.SenderPubkey)
 	info := cache.Update(*offset)
 
-	output := formatLocationOffset(offset, addr, signatureValid, verifyError)
+	output := formatLocationOffset(offset, addr, signatureValid, verifyError, velocityFactor, units)
 
 	if *verbose || info.Changed() {
 		if *logFormat == "json" {
@@ -391,7 +452,7 @@ func handleOffset(log *slog.Logger, offset *geoprobe.LocationOffset, addr *net.U
 		"sender_pubkey", output.SenderPubkey,
 		"target_ip", output.TargetIP,
 		"rtt_ms", output.RttMs,
-		"max_distance_miles", output.MaxDistanceMiles,
+		"max_distance_miles", calculateMaxDistance(offset.RttNs, velocityFactor),
 		"signature_valid", signatureValid,
 		"cache_result", info.Result.String(),
 		"cache_promoted", info.Promoted,
@@ -408,8 +469,10 @@ type OffsetOutput struct {
 	ReferencePoint    CoordinateOutput  `json:"reference_point"`
 	RttMs             float64           `json:"rtt_ms"`
 	MeasuredRttMs     float64           `json:"measured_rtt_ms"`
-	MaxDistanceMiles  float64           `json:"max_distance_miles"`
-	MaxDistanceKm     float64           `json:"max_distance_km"`
+	MaxDistanceMiles  *float64          `json:"max_distance_miles,omitempty"`
+	MaxDistanceKm     *float64          `json:"max_distance_km,omitempty"`
+	MaxDistanceNmi    *float64          `json:"max_distance_nmi,omitempty"`
+	VelocityFactor    float64           `json:"velocity_factor"`
 	MeasurementSlot   uint64            `json:"measurement_slot"`
 	SignatureValid    bool              `json:"signature_valid"`
 	SignatureError    string            `json:"signature_error,omitempty"`
@@ -433,27 +496,36 @@ type ReferenceOutput struct {
 	MeasuredRttMs   float64          `json:"measured_rtt_ms"`
 }
 
-func formatLocationOffset(offset *geoprobe.LocationOffset, addr *net.UDPAddr, signatureValid bool, verifyError error) OffsetOutput {
+func formatLocationOffset(offset *geoprobe.LocationOffset, addr *net.UDPAddr, signatureValid bool, verifyError error, velocityFactor float64, units distanceUnits) OffsetOutput {
 	rttMs := float64(offset.RttNs) / nanosecondsPerMs
 	measuredRttMs := float64(offset.MeasuredRttNs) / nanosecondsPerMs
-	maxDistanceMiles := calculateMaxDistance(offset.RttNs)
-	maxDistanceKm := maxDistanceMiles * 1.60934
+	distances := calculateMaxDistances(offset.RttNs, velocityFactor)
 
 	output := OffsetOutput{
-		Timestamp:        time.Now().UTC().Format("2006-01-02 15:04:05 MST"),
-		SourceAddr:       addr.String(),
-		AuthorityPubkey:  solana.PublicKeyFromBytes(offset.AuthorityPubkey[:]).String(),
-		SenderPubkey:     solana.PublicKeyFromBytes(offset.SenderPubkey[:]).String(),
-		TargetIP:         geoprobe.FormatTargetIP(offset.TargetIP),
-		ReferencePoint:   formatCoordinate(offset.Lat, offset.Lng),
-		RttMs:            rttMs,
-		MeasuredRttMs:    measuredRttMs,
-		MaxDistanceMiles: maxDistanceMiles,
-		MaxDistanceKm:    maxDistanceKm,
-		MeasurementSlot:  offset.MeasurementSlot,
-		SignatureValid:   signatureValid,
+		Timestamp:       time.Now().UTC().Format("2006-01-02 15:04:05 MST"),
+		AuthorityPubkey: solana.PublicKeyFromBytes(offset.AuthorityPubkey[:]).String(),
+		SenderPubkey:    solana.PublicKeyFromBytes(offset.SenderPubkey[:]).String(),
+		TargetIP:        geoprobe.FormatTargetIP(offset.TargetIP),
+		ReferencePoint:  formatCoordinate(offset.Lat, offset.Lng),
+		RttMs:           rttMs,
+		MeasuredRttMs:   measuredRttMs,
+		VelocityFactor:  velocityFactor,
+		MeasurementSlot: offset.MeasurementSlot,
+		SignatureValid:  signatureValid,
+	}
+	if units.miles {
+		output.MaxDistanceMiles = &distances.miles
+	}
+	if units.km {
+		output.MaxDistanceKm = &distances.km
+	}
+	if units.nmi {
+		output.MaxDistanceNmi = &distances.nmi
 	}
 
+	if addr != nil {
+		output.SourceAddr = addr.String()
+	}
 	if verifyError != nil {
 		output.SignatureError = verifyError.Error()
 	}
@@ -485,7 +557,7 @@ func formatTextOutput(output OffsetOutput) string {
 	sb.WriteString(fmt.Sprintf("  Reference Point: %s\n", output.ReferencePoint.Formatted))
 	sb.WriteString(fmt.Sprintf("  RTT to Target: %.2fms\n", output.RttMs))
 	sb.WriteString(fmt.Sprintf("  Measured RTT:  %.2fms\n", output.MeasuredRttMs))
-	sb.WriteString(fmt.Sprintf("  Max Distance: %.0f miles (%.0f km)\n", output.MaxDistanceMiles, output.MaxDistanceKm))
+	sb.WriteString(fmt.Sprintf("  Max Distance: %s at %.2fc\n", formatMaxDistance(output), output.VelocityFactor))
 	sb.WriteString(fmt.Sprintf("  Measurement Slot: %d\n", output.MeasurementSlot))
 	sb.WriteString("\n")
 
@@ -516,9 +588,11 @@ func formatTextOutput(output OffsetOutput) string {
 	return sb.String()
 }
 
-func calculateMaxDistance(rttNs uint64) float64 {
+// calculateMaxDistance returns the maximum one-way distance in miles reachable
+// within half the RTT at velocityFactor times the speed of light.
+func calculateMaxDistance(rttNs uint64, velocityFactor float64) float64 {
 	rttMs := float64(rttNs) / (2 * nanosecondsPerMs)
-	return rttMs * speedOfLightMilesPerMs
+	return rttMs * speedOfLightMilesPerMs * velocityFactor
 }
 
 func formatCoordinate(lat, lng float64) CoordinateOutput {
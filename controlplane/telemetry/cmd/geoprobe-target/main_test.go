@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
+)
+
+func TestCalculateMaxDistance_VelocityFactor(t *testing.T) {
+	// 10ms RTT -> 5ms one way -> 620 miles at c.
+	const rttNs = 10_000_000
+
+	full := calculateMaxDistance(rttNs, 1.0)
+	if math.Abs(full-620) > 1e-9 {
+		t.Fatalf("distance at 1.0c = %f, want 620", full)
+	}
+
+	for _, factor := range []float64{0.5, 0.67, 0.1} {
+		got := calculateMaxDistance(rttNs, factor)
+		if want := full * factor; math.Abs(got-want) > 1e-9 {
+			t.Errorf("distance at %.2fc = %f, want %f", factor, got, want)
+		}
+	}
+}
+
+func TestFormatLocationOffset_IncludesVelocityFactor(t *testing.T) {
+	offset := &geoprobe.LocationOffset{RttNs: 10_000_000}
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 8923}
+
+	output := formatLocationOffset(offset, addr, true, nil, 0.5, distanceUnits{miles: true, km: true})
+	if output.VelocityFactor != 0.5 {
+		t.Errorf("VelocityFactor = %f, want 0.5", output.VelocityFactor)
+	}
+	if output.MaxDistanceMiles == nil || math.Abs(*output.MaxDistanceMiles-310) > 1e-9 {
+		t.Errorf("MaxDistanceMiles = %v, want 310", output.MaxDistanceMiles)
+	}
+	if output.MaxDistanceKm == nil || math.Abs(*output.MaxDistanceKm-310*1.60934) > 1e-9 {
+		t.Errorf("MaxDistanceKm = %v, want %f", output.MaxDistanceKm, 310*1.60934)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"velocity_factor":0.5`) {
+		t.Errorf("JSON output missing velocity_factor: %s", data)
+	}
+
+	if text := formatTextOutput(output); !strings.Contains(text, "Max Distance: 310 miles (499 km) at 0.50c") {
+		t.Errorf("text output missing velocity factor:\n%s", text)
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"198.51.100.7", "198.51.100.7"},
+		{"::ffff:198.51.100.7", "198.51.100.7"},
+		{"2001:db8:1:2:aaaa::1", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2:bbbb::99", "2001:db8:1:2::/64"},
+		{"2001:db8:1:3::1", "2001:db8:1:3::/64"},
+		{"fe80::1%eth0", "fe80::/64"},
+	}
+	for _, tt := range tests {
+		if got := rateLimitKey(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("rateLimitKey(%s) = %s, want %s", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiter_IPv6SharesBucketPerPrefix(t *testing.T) {
+	limiter := newRateLimiter(2)
+
+	// Rotating through addresses in one /64 draws from a single bucket.
+	for i, addr := range []string{"2001:db8:1:2::1", "2001:db8:1:2::2"} {
+		if !limiter.allow(rateLimitKey(netip.MustParseAddr(addr))) {
+			t.Fatalf("packet %d from %s rejected within limit", i, addr)
+		}
+	}
+	if limiter.allow(rateLimitKey(netip.MustParseAddr("2001:db8:1:2::3"))) {
+		t.Error("third packet from the same /64 was allowed")
+	}
+
+	// Other prefixes and IPv4 sources keep their own buckets.
+	if !limiter.allow(rateLimitKey(netip.MustParseAddr("2001:db8:1:3::1"))) {
+		t.Error("packet from a different /64 was rejected")
+	}
+	if !limiter.allow(rateLimitKey(netip.MustParseAddr("198.51.100.7"))) {
+		t.Error("packet from an IPv4 source was rejected")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// bogonPrefixes are private, loopback, link-local, documentation, multicast
+// and otherwise reserved ranges that never carry legitimate internet traffic.
+var bogonPrefixes = mustParsePrefixes(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"100::/64",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// sourceFilter drops UDP datagrams by source address. A source matching an
+// allow prefix is always accepted; otherwise it is dropped if it matches a
+// deny prefix.
+type sourceFilter struct {
+	allow   []netip.Prefix
+	deny    []netip.Prefix
+	dropped atomic.Uint64
+}
+
+// newSourceFilter returns a filter denying the bogon ranges (if dropBogons is
+// set) plus deny, with allow as exceptions. It returns nil when nothing would
+// be denied, so the filter is off by default.
+func newSourceFilter(dropBogons bool, allow, deny []netip.Prefix) *sourceFilter {
+	var denied []netip.Prefix
+	if dropBogons {
+		denied = append(denied, bogonPrefixes...)
+	}
+	denied = append(denied, deny...)
+	if len(denied) == 0 {
+		return nil
+	}
+	return &sourceFilter{allow: allow, deny: denied}
+}
+
+// accept reports whether a datagram from addr should be processed, counting
+// the ones that are not.
+func (f *sourceFilter) accept(addr netip.Addr) bool {
+	if f == nil {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, p := range f.allow {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	for _, p := range f.deny {
+		if p.Contains(addr) {
+			f.dropped.Add(1)
+			return false
+		}
+	}
+	return true
+}
+
+// parsePrefixes parses a comma-separated list of CIDRs. Bare addresses are
+// treated as single-host prefixes.
+func parsePrefixes(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", field, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix %q: %w", field, err)
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
+
+func mustParsePrefixes(cidrs ...string) []netip.Prefix {
+	prefixes, err := parsePrefixes(strings.Join(cidrs, ","))
+	if err != nil {
+		panic(err)
+	}
+	return prefixes
+}
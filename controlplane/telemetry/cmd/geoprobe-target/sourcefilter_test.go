@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestSourceFilter_DropBogons(t *testing.T) {
+	filter := newSourceFilter(true, nil, nil)
+
+	tests := []struct {
+		addr   string
+		accept bool
+	}{
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"127.0.0.1", false},
+		{"100.64.0.1", false},
+		{"169.254.10.10", false},
+		{"198.51.100.7", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:10.0.0.1", false}, // IPv4-mapped private address
+		{"8.8.8.8", true},
+		{"1.1.1.1", true},
+		{"172.32.0.1", true},
+		{"2606:4700::1111", true},
+		{"::ffff:8.8.8.8", true},
+	}
+	for _, tt := range tests {
+		if got := filter.accept(netip.MustParseAddr(tt.addr)); got != tt.accept {
+			t.Errorf("accept(%s) = %v, want %v", tt.addr, got, tt.accept)
+		}
+	}
+
+	var wantDropped uint64
+	for _, tt := range tests {
+		if !tt.accept {
+			wantDropped++
+		}
+	}
+	if got := filter.dropped.Load(); got != wantDropped {
+		t.Errorf("dropped = %d, want %d", got, wantDropped)
+	}
+}
+
+func TestSourceFilter_AllowAndDenyLists(t *testing.T) {
+	allow, err := parsePrefixes("10.0.0.0/8, 192.168.1.5")
+	if err != nil {
+		t.Fatalf("parsePrefixes(allow): %v", err)
+	}
+	deny, err := parsePrefixes("203.0.113.0/24,8.8.4.4")
+	if err != nil {
+		t.Fatalf("parsePrefixes(deny): %v", err)
+	}
+	filter := newSourceFilter(true, allow, deny)
+
+	tests := []struct {
+		addr   string
+		accept bool
+	}{
+		{"10.20.30.40", true},   // allowlisted private range
+		{"192.168.1.5", true},   // allowlisted single host
+		{"192.168.1.6", false},  // other private hosts still dropped
+		{"8.8.4.4", false},      // explicitly denied public host
+		{"8.8.8.8", true},       // other public hosts accepted
+		{"203.0.113.99", false}, // denied range
+	}
+	for _, tt := range tests {
+		if got := filter.accept(netip.MustParseAddr(tt.addr)); got != tt.accept {
+			t.Errorf("accept(%s) = %v, want %v", tt.addr, got, tt.accept)
+		}
+	}
+}
+
+func TestSourceFilter_OffByDefault(t *testing.T) {
+	filter := newSourceFilter(false, nil, nil)
+	if filter != nil {
+		t.Fatalf("expected nil filter when nothing is denied, got %+v", filter)
+	}
+	// A nil filter accepts everything, including private sources.
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 8923}
+	if !filter.accept(addr.AddrPort().Addr()) {
+		t.Error("expected nil filter to accept private source")
+	}
+
+	// A deny list alone enables the filter without the bogon ranges.
+	deny, _ := parsePrefixes("198.51.100.0/24")
+	filter = newSourceFilter(false, nil, deny)
+	if !filter.accept(netip.MustParseAddr("10.0.0.1")) {
+		t.Error("expected private source accepted without --drop-bogon-sources")
+	}
+	if filter.accept(netip.MustParseAddr("198.51.100.1")) {
+		t.Error("expected denied source dropped")
+	}
+}
+
+func TestParsePrefixes_Invalid(t *testing.T) {
+	for _, s := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0.0/8,bogus/8"} {
+		if _, err := parsePrefixes(s); err == nil {
+			t.Errorf("parsePrefixes(%q): expected error", s)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configEnvPrefix is prepended to a flag name (upper-cased, dashes replaced by
+// underscores) to form its environment variable, e.g. --probe-interval is
+// DZ_TELEMETRY_PROBE_INTERVAL.
+const configEnvPrefix = "DZ_TELEMETRY_"
+
+// configFlagName is the flag that points at the config file. It cannot be set
+// from the file itself or from the environment.
+const configFlagName = "config"
+
+// applyConfig sets flags that were not given on the command line from the
+// config file at path (if any) and from the environment, with the precedence
+// file < env < flag. Config keys are flag names; unknown keys are rejected.
+func applyConfig(fs *flag.FlagSet, path string, lookupEnv func(string) (string, bool)) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if path != "" {
+		values, err := loadConfigFile(fs, path)
+		if err != nil {
+			return err
+		}
+		for name, value := range values {
+			if explicit[name] {
+				continue
+			}
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("config file %s: invalid value %q for %s: %w", path, value, name, err)
+			}
+		}
+	}
+
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if envErr != nil || f.Name == configFlagName || explicit[f.Name] {
+			return
+		}
+		key := configEnvVar(f.Name)
+		value, ok := lookupEnv(key)
+		if !ok {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf("environment variable %s: invalid value %q: %w", key, value, err)
+		}
+	})
+	return envErr
+}
+
+// loadConfigFile parses a JSON or YAML config file into flag values keyed by
+// flag name. JSON is accepted because it is valid YAML.
+func loadConfigFile(fs *flag.FlagSet, path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var unknown []string
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if key == configFlagName || fs.Lookup(key) == nil {
+			unknown = append(unknown, key)
+			continue
+		}
+		switch v := value.(type) {
+		case string, bool, int, int64, uint64, float64:
+			values[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("config file %s: %s must be a string, number or boolean", path, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("config file %s: unknown keys: %s", path, strings.Join(unknown, ", "))
+	}
+	return values, nil
+}
+
+func configEnvVar(flagName string) string {
+	return configEnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
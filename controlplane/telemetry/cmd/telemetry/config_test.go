@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testConfigFlags struct {
+	fs              *flag.FlagSet
+	config          *string
+	probeInterval   *time.Duration
+	metricsEnable   *bool
+	metricsAddr     *string
+	twampListenPort *uint
+}
+
+func newTestConfigFlags() *testConfigFlags {
+	fs := flag.NewFlagSet("telemetry", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return &testConfigFlags{
+		fs:              fs,
+		config:          fs.String(configFlagName, "", ""),
+		probeInterval:   fs.Duration("probe-interval", 10*time.Second, ""),
+		metricsEnable:   fs.Bool("metrics-enable", false, ""),
+		metricsAddr:     fs.String("metrics-addr", ":8080", ""),
+		twampListenPort: fs.Uint("twamp-listen-port", 862, ""),
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func envFrom(m map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+func TestApplyConfig_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "telemetry.yaml", `
+probe-interval: 20s
+metrics-enable: true
+metrics-addr: ":9000"
+twamp-listen-port: 1000
+`)
+	f := newTestConfigFlags()
+	require.NoError(t, f.fs.Parse([]string{"--config", path, "--metrics-addr", ":9200"}))
+
+	env := envFrom(map[string]string{
+		"DZ_TELEMETRY_TWAMP_LISTEN_PORT": "2000",
+		"DZ_TELEMETRY_METRICS_ADDR":      ":9100",
+	})
+	require.NoError(t, applyConfig(f.fs, *f.config, env))
+
+	require.Equal(t, 20*time.Second, *f.probeInterval, "file value applies when nothing overrides it")
+	require.True(t, *f.metricsEnable, "file value applies when nothing overrides it")
+	require.Equal(t, uint(2000), *f.twampListenPort, "env overrides file")
+	require.Equal(t, ":9200", *f.metricsAddr, "flag overrides env and file")
+}
+
+func TestApplyConfig_JSON(t *testing.T) {
+	path := writeConfigFile(t, "telemetry.json", `{"probe-interval": "5s", "twamp-listen-port": 1862}`)
+	f := newTestConfigFlags()
+	require.NoError(t, f.fs.Parse(nil))
+
+	require.NoError(t, applyConfig(f.fs, path, envFrom(nil)))
+	require.Equal(t, 5*time.Second, *f.probeInterval)
+	require.Equal(t, uint(1862), *f.twampListenPort)
+}
+
+func TestApplyConfig_EnvWithoutFile(t *testing.T) {
+	f := newTestConfigFlags()
+	require.NoError(t, f.fs.Parse(nil))
+
+	require.NoError(t, applyConfig(f.fs, "", envFrom(map[string]string{"DZ_TELEMETRY_METRICS_ENABLE": "true"})))
+	require.True(t, *f.metricsEnable)
+	require.Equal(t, 10*time.Second, *f.probeInterval, "defaults are kept")
+}
+
+func TestApplyConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown keys",
+			content: "probe-interval: 5s\nprobe-intervall: 5s\nbogus: 1\n",
+			wantErr: "unknown keys: bogus, probe-intervall",
+		},
+		{
+			name:    "config key in file",
+			content: "config: other.yaml\n",
+			wantErr: "unknown keys: config",
+		},
+		{
+			name:    "invalid value",
+			content: "probe-interval: soon\n",
+			wantErr: `invalid value "soon" for probe-interval`,
+		},
+		{
+			name:    "non-scalar value",
+			content: "metrics-addr: [a, b]\n",
+			wantErr: "metrics-addr must be a string, number or boolean",
+		},
+		{
+			name:    "malformed file",
+			content: "{not yaml",
+			wantErr: "failed to parse config file",
+		},
+		{
+			name:    "invalid env value",
+			env:     map[string]string{"DZ_TELEMETRY_TWAMP_LISTEN_PORT": "-1"},
+			wantErr: "environment variable DZ_TELEMETRY_TWAMP_LISTEN_PORT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, "telemetry.yaml", tt.content)
+			f := newTestConfigFlags()
+			require.NoError(t, f.fs.Parse(nil))
+
+			err := applyConfig(f.fs, path, envFrom(tt.env))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestApplyConfig_MissingFile(t *testing.T) {
+	f := newTestConfigFlags()
+	require.NoError(t, f.fs.Parse(nil))
+
+	err := applyConfig(f.fs, filepath.Join(t.TempDir(), "missing.yaml"), envFrom(nil))
+	require.ErrorContains(t, err, "failed to read config file")
+}
@@ -21,17 +21,19 @@ import (
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/geoprobe"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/gnmitunnel"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/metrics"
-	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netns"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netutil"
 	telemetrysvc "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/serviceability"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/state"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/telemetry"
 	telemetryconfig "github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/config"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	geolocation "github.com/malbeclabs/doublezero/sdk/geolocation/go"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
 	sdktelemetry "github.com/malbeclabs/doublezero/smartcontract/sdk/go/telemetry"
 	stateingest "github.com/malbeclabs/doublezero/telemetry/state-ingest/pkg/client"
 	twamplight "github.com/malbeclabs/doublezero/tools/twamp/pkg/light"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -71,6 +73,11 @@ var (
 	submissionInterval         = flag.Duration("submission-interval", defaultSubmissionInterval, "The interval to submit samples.")
 	twampSenderTimeout         = flag.Duration("twamp-sender-timeout", defaultTWAMPSenderTimeout, "The timeout for sending twamp probes.")
 	twampReflectorTimeout      = flag.Duration("twamp-reflector-timeout", defaultTWAMPReflectorTimeout, "The timeout for the twamp reflector.")
+	twampDSCP                  = flag.Uint("twamp-dscp", 0, "The DSCP value (0-63) to mark twamp probes with. 0 is best effort.")
+	twampDSCPByLinkType        = flag.String("twamp-dscp-by-link-type", "", "Per link type DSCP overrides for twamp probes, e.g. 'wan=46,dzx=0'. Link types without an override use --twamp-dscp.")
+	twampSelfTest              = flag.Bool("twamp-self-test", true, "Probe the local twamp reflector over loopback at startup and log an error if it does not answer with a sane RTT.")
+	twampSelfTestRequired      = flag.Bool("twamp-self-test-required", false, "Exit if the twamp reflector self-test fails.")
+	twampSelfTestMaxRTT        = flag.Duration("twamp-self-test-max-rtt", telemetry.DefaultReflectorSelfTestMaxRTT, "The largest loopback RTT the twamp reflector self-test accepts.")
 	peersRefreshInterval       = flag.Duration("peers-refresh-interval", defaultPeersRefreshInterval, "The interval to refresh the peer discovery.")
 	senderTTL                  = flag.Duration("sender-ttl", defaultSenderTTL, "The time to live for a sender instance until it's recreated.")
 	submitterMaxConcurrency    = flag.Int("submitter-max-concurrency", defaultSubmitterMaxConcurrency, "The maximum number of concurrent submissions.")
@@ -85,10 +92,13 @@ var (
 	showVersion                = flag.Bool("version", false, "Print the version of the doublezero-agent and exit.")
 	metricsEnable              = flag.Bool("metrics-enable", false, "Enable prometheus metrics.")
 	metricsAddr                = flag.String("metrics-addr", ":8080", "Address to listen on for prometheus metrics.")
+	debugAddr                  = flag.String("debug-addr", "", "Address to serve debug endpoints (e.g. "+telemetry.PeersDebugPath+") on, in the management namespace if set. Disabled if empty.")
+	configPath                 = flag.String(configFlagName, "", "Path to a JSON or YAML config file keyed by flag name. Precedence: file < "+configEnvPrefix+"<FLAG> env vars < flags.")
 
 	// gNMI tunnel flags
 	gnmiTunnelEnable     = flag.Bool("gnmi-tunnel-enable", false, "Enable gNMI tunnel client for remote access.")
 	gnmiTunnelServerAddr = flag.String("gnmi-tunnel-server-addr", "", "Address of the gNMI tunnel server (defaults to env config, e.g., gnmic-devnet.doublezero.xyz:443).")
+	gnmiTunnelLocalWait  = flag.Duration("gnmi-tunnel-local-wait", 0, "If set, wait up to this long for the local gNMI socket to accept connections before registering with the tunnel server, and reject tunnel sessions while it is unreachable (0 disables).")
 
 	// geoprobe flags
 	geolocationProgramID = flag.String("geolocation-program-id", "", "The ID of the geolocation program for onchain GeoProbe discovery. If env is provided, this flag is ignored.")
@@ -111,6 +121,11 @@ var (
 func main() {
 	flag.Parse()
 
+	if err := applyConfig(flag.CommandLine, *configPath, os.LookupEnv); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	if *probeInterval >= *submissionInterval {
 		fmt.Println("probe-interval must be less than submission-interval")
 		os.Exit(1)
@@ -211,6 +226,10 @@ func main() {
 		"probeInterval", *probeInterval,
 		"submissionInterval", *submissionInterval,
 		"twampListenPort", *twampListenPort,
+		"twampDSCP", *twampDSCP,
+		"twampDSCPByLinkType", *twampDSCPByLinkType,
+		"twampSelfTest", *twampSelfTest,
+		"twampSelfTestRequired", *twampSelfTestRequired,
 		"senderTTL", *senderTTL,
 	)
 
@@ -230,25 +249,12 @@ func main() {
 	if *metricsEnable {
 		metrics.BuildInfo.WithLabelValues(version, commit, date).Set(1)
 		go func() {
-			var listener net.Listener
-			if *managementNamespace != "" {
-				// If the management namespace is provided, we need to run the metrics server in that namespace.
-				listener, err = netns.RunInNamespace(*managementNamespace, func() (net.Listener, error) {
-					return net.Listen("tcp", *metricsAddr)
-				})
-				if err != nil {
-					log.Error("Failed to start prometheus metrics server listener in namespace", "error", err, "namespace", *managementNamespace)
-					return
-				}
-				log.Info("Prometheus metrics server listening", "namespace", *managementNamespace, "address", listener.Addr().String())
-			} else {
-				listener, err = net.Listen("tcp", *metricsAddr)
-				if err != nil {
-					log.Error("Failed to start prometheus metrics server listener", "error", err)
-					return
-				}
-				log.Info("Prometheus metrics server listening", "address", listener.Addr().String())
+			listener, err := listenManagement(*metricsAddr)
+			if err != nil {
+				log.Error("Failed to start prometheus metrics server listener", "error", err, "namespace", *managementNamespace)
+				return
 			}
+			log.Info("Prometheus metrics server listening", "namespace", *managementNamespace, "address", listener.Addr().String())
 			http.Handle("/metrics", promhttp.Handler())
 			if err := http.Serve(listener, nil); err != nil {
 				log.Error("Failed to start prometheus metrics server", "error", err)
@@ -262,6 +268,13 @@ func main() {
 		log.Error("failed to create TWAMP reflector", "error", err)
 		os.Exit(1)
 	}
+	promauto.NewCounterFunc(
+		prometheus.CounterOpts{
+			Name: metrics.MetricNameReflectorRecoverableErrors,
+			Help: "Number of transient TWAMP reflector socket errors that were logged and skipped",
+		},
+		func() float64 { return float64(reflector.RecoverableErrors()) },
+	)
 
 	// Build solana RPC client.
 	var rpcClient *solanarpc.Client
@@ -282,6 +295,15 @@ func main() {
 		log.Error("failed to parse program ID", "error", err)
 		os.Exit(1)
 	}
+	if *twampDSCP > twamplight.MaxDSCP {
+		log.Error("Invalid twamp DSCP", "dscp", *twampDSCP, "max", twamplight.MaxDSCP)
+		os.Exit(1)
+	}
+	dscpByLinkType, err := telemetry.ParseDSCPByLinkType(*twampDSCPByLinkType)
+	if err != nil {
+		log.Error("Failed to parse twamp DSCP by link type", "error", err)
+		os.Exit(1)
+	}
 	localNet := netutil.NewLocalNet(log)
 	cachedSvcClient := telemetrysvc.NewCachingFetcher(
 		serviceability.New(rpcClient, serviceabilityProgramID),
@@ -296,6 +318,9 @@ func main() {
 			LocalNet:        localNet,
 			TWAMPPort:       uint16(*twampListenPort),
 			RefreshInterval: *peersRefreshInterval,
+
+			TWAMPDSCP:           uint8(*twampDSCP),
+			TWAMPDSCPByLinkType: dscpByLinkType,
 		},
 	)
 	if err != nil {
@@ -303,6 +328,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Set up debug server if enabled.
+	if *debugAddr != "" {
+		listener, err := listenManagement(*debugAddr)
+		if err != nil {
+			log.Error("Failed to start debug server listener", "error", err, "namespace", *managementNamespace)
+			os.Exit(1)
+		}
+		log.Info("Debug server listening", "namespace", *managementNamespace, "address", listener.Addr().String())
+		mux := http.NewServeMux()
+		mux.Handle(telemetry.PeersDebugPath, telemetry.NewPeersDebugHandler(peerDiscovery))
+		go func() {
+			if err := http.Serve(listener, mux); err != nil {
+				log.Error("Failed to serve debug server", "error", err)
+			}
+		}()
+	}
+
 	// Create geolocation client if program ID is provided.
 	var geolocationClient geoprobe.GeolocationClient
 	if *geolocationProgramID != "" {
@@ -348,6 +390,9 @@ func main() {
 		GeolocationClient:          geolocationClient,
 		AgentVersion:               version,
 		AgentCommit:                commit,
+		ReflectorSelfTest:          *twampSelfTest,
+		ReflectorSelfTestRequired:  *twampSelfTestRequired,
+		ReflectorSelfTestMaxRTT:    *twampSelfTestMaxRTT,
 	})
 	if err != nil {
 		log.Error("failed to create telemetry collector", "error", err)
@@ -405,6 +450,17 @@ func main() {
 	}
 }
 
+// listenManagement listens on addr in the management namespace if one is
+// configured, and in the current namespace otherwise.
+func listenManagement(addr string) (net.Listener, error) {
+	if *managementNamespace == "" {
+		return net.Listen("tcp", addr)
+	}
+	return netns.RunInNamespace(*managementNamespace, func() (net.Listener, error) {
+		return net.Listen("tcp", addr)
+	})
+}
+
 func startBGPStatusSubmitter(
 	ctx context.Context,
 	cancel context.CancelFunc,
@@ -523,11 +579,12 @@ func startGNMITunnelClient(ctx context.Context, cancel context.CancelFunc, log *
 	// - TargetType: GNMI_GNOI for gNMI/gNOI services
 	// - LocalDialAddr: standard Arista gNMI socket path
 	cfg := &gnmitunnel.Config{
-		Logger:           log,
-		TargetID:         localDevicePK.String(),
-		TargetType:       gnmitunnel.TargetTypeGNMIGNOI,
-		LocalDialAddr:    "/var/run/gnmiServer.sock",
-		TunnelServerAddr: *gnmiTunnelServerAddr,
+		Logger:                  log,
+		TargetID:                localDevicePK.String(),
+		TargetType:              gnmitunnel.TargetTypeGNMIGNOI,
+		LocalDialAddr:           "/var/run/gnmiServer.sock",
+		TunnelServerAddr:        *gnmiTunnelServerAddr,
+		LocalHealthCheckTimeout: *gnmiTunnelLocalWait,
 	}
 
 	// If using a management namespace, configure namespace-aware dialers.
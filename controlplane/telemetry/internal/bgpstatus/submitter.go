@@ -9,9 +9,9 @@ import (
 	"time"
 
 	"github.com/gagliardetto/solana-go"
-	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netns"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netutil"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/state"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
 )
 
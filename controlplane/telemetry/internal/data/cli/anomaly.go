@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	devicedata "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
+)
+
+const (
+	anomalyFlagLoss = "loss"
+	anomalyFlagP99  = "p99"
+)
+
+// anomalyThresholds marks circuit summaries whose loss or RTT P99 exceed
+// operator-supplied limits. A zero threshold is disabled.
+type anomalyThresholds struct {
+	LossPercent float64 // loss rate in percent, as shown in the Loss (%) column
+	RTTP99      float64 // in the display unit
+	OnlyFlagged bool    // drop summaries with no flags
+}
+
+func (t anomalyThresholds) enabled() bool {
+	return t.LossPercent > 0 || t.RTTP99 > 0
+}
+
+// flags returns the thresholds s exceeds. RTT P99 is not checked for fully
+// lost circuits, which have no RTT samples.
+func (t anomalyThresholds) flags(s devicedata.CircuitSummary) []string {
+	var flags []string
+	if t.LossPercent > 0 && s.LossRate*100 > t.LossPercent {
+		flags = append(flags, anomalyFlagLoss)
+	}
+	if t.RTTP99 > 0 && s.LossRate < 1 && s.RTTP99 > t.RTTP99 {
+		flags = append(flags, anomalyFlagP99)
+	}
+	return flags
+}
+
+// filter returns the summaries to print: all of them, or with OnlyFlagged just
+// those exceeding a threshold.
+func (t anomalyThresholds) filter(stats []devicedata.CircuitSummary) []devicedata.CircuitSummary {
+	if !t.OnlyFlagged {
+		return stats
+	}
+	flagged := make([]devicedata.CircuitSummary, 0, len(stats))
+	for _, s := range stats {
+		if len(t.flags(s)) > 0 {
+			flagged = append(flagged, s)
+		}
+	}
+	return flagged
+}
+
+// describeThresholds renders the enabled thresholds for the table preamble,
+// e.g. "loss > 1.0% or RTT P99 > 25.000 ms".
+func describeThresholds(t anomalyThresholds, unit devicedata.Unit) string {
+	var parts []string
+	if t.LossPercent > 0 {
+		parts = append(parts, fmt.Sprintf("loss > %.1f%%", t.LossPercent))
+	}
+	if t.RTTP99 > 0 {
+		parts = append(parts, fmt.Sprintf("RTT P99 > %.3f %s", t.RTTP99, unit))
+	}
+	return strings.Join(parts, " or ")
+}
+
+// formatAnomalyFlags renders the Flags column: an asterisk followed by the
+// exceeded thresholds, or empty for a healthy circuit.
+func formatAnomalyFlags(flags []string) string {
+	if len(flags) == 0 {
+		return ""
+	}
+	return "* " + strings.Join(flags, ",")
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	devicedata "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/stats"
+	"github.com/stretchr/testify/require"
+)
+
+func testCircuitSummary(circuit string, lossRate, rttP99 float64) devicedata.CircuitSummary {
+	return devicedata.CircuitSummary{
+		Circuit:  circuit,
+		LinkType: "WAN",
+		CircuitLatencyStat: stats.CircuitLatencyStat{
+			Circuit:  circuit,
+			RTTP99:   rttP99,
+			LossRate: lossRate,
+		},
+	}
+}
+
+func TestAnomalyThresholds_Flags(t *testing.T) {
+	thresholds := anomalyThresholds{LossPercent: 1, RTTP99: 50}
+
+	tests := []struct {
+		name    string
+		summary devicedata.CircuitSummary
+		want    []string
+	}{
+		{"healthy", testCircuitSummary("a", 0.001, 20), nil},
+		{"at thresholds", testCircuitSummary("b", 0.01, 50), nil},
+		{"lossy", testCircuitSummary("c", 0.05, 20), []string{anomalyFlagLoss}},
+		{"slow", testCircuitSummary("d", 0, 80), []string{anomalyFlagP99}},
+		{"lossy and slow", testCircuitSummary("e", 0.2, 80), []string{anomalyFlagLoss, anomalyFlagP99}},
+		{"fully lost", testCircuitSummary("f", 1, 0), []string{anomalyFlagLoss}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, thresholds.flags(tt.summary))
+		})
+	}
+
+	require.Empty(t, anomalyThresholds{}.flags(testCircuitSummary("g", 0.5, 500)))
+}
+
+func TestPrintDeviceSummaries_Flagged(t *testing.T) {
+	summaries := []devicedata.CircuitSummary{
+		testCircuitSummary("healthy-link", 0, 20),
+		testCircuitSummary("lossy-link", 0.05, 20),
+		testCircuitSummary("slow-link", 0, 80),
+	}
+	thresholds := anomalyThresholds{LossPercent: 1, RTTP99: 50}
+
+	var out bytes.Buffer
+	printDeviceSummaries(&out, summaries, "testnet", 0, nil, devicedata.UnitMillisecond, thresholds)
+	require.Contains(t, out.String(), "* Flagged circuits exceed loss > 1.0% or RTT P99 > 50.000 ms")
+	require.Contains(t, out.String(), "Flags")
+	require.Contains(t, out.String(), "healthy-link")
+	require.Contains(t, out.String(), "* loss")
+	require.Contains(t, out.String(), "* p99")
+
+	thresholds.OnlyFlagged = true
+	out.Reset()
+	printDeviceSummaries(&out, summaries, "testnet", 0, nil, devicedata.UnitMillisecond, thresholds)
+	require.NotContains(t, out.String(), "healthy-link")
+	require.Contains(t, out.String(), "lossy-link")
+	require.Contains(t, out.String(), "slow-link")
+
+	out.Reset()
+	printDeviceSummaries(&out, summaries, "testnet", 0, nil, devicedata.UnitMillisecond, anomalyThresholds{})
+	require.NotContains(t, out.String(), "Flags")
+	require.NotContains(t, out.String(), "Flagged")
+}
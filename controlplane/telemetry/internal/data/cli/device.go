@@ -3,10 +3,10 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
-	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jonboulle/clockwork"
 	"github.com/malbeclabs/doublezero/config"
 	devicedata "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
@@ -74,6 +75,61 @@ func (c *DeviceCmd) Command() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to get link-type flag: %w", err)
 			}
+			link, err := cmd.Flags().GetString("link")
+			if err != nil {
+				return fmt.Errorf("failed to get link flag: %w", err)
+			}
+			watch, err := cmd.Flags().GetBool("watch")
+			if err != nil {
+				return fmt.Errorf("failed to get watch flag: %w", err)
+			}
+			interval, err := cmd.Flags().GetDuration("interval")
+			if err != nil {
+				return fmt.Errorf("failed to get interval flag: %w", err)
+			}
+			flagThresholdLoss, err := cmd.Flags().GetFloat64("flag-threshold-loss")
+			if err != nil {
+				return fmt.Errorf("failed to get flag-threshold-loss flag: %w", err)
+			}
+			flagThresholdP99, err := cmd.Flags().GetFloat64("flag-threshold-p99")
+			if err != nil {
+				return fmt.Errorf("failed to get flag-threshold-p99 flag: %w", err)
+			}
+			onlyFlagged, err := cmd.Flags().GetBool("only-flagged")
+			if err != nil {
+				return fmt.Errorf("failed to get only-flagged flag: %w", err)
+			}
+			samplesFile, err := cmd.Flags().GetString("samples-file")
+			if err != nil {
+				return fmt.Errorf("failed to get samples-file flag: %w", err)
+			}
+			dumpSamplesPath, err := cmd.Flags().GetString("dump-samples")
+			if err != nil {
+				return fmt.Errorf("failed to get dump-samples flag: %w", err)
+			}
+			if flagThresholdLoss < 0 || flagThresholdP99 < 0 {
+				return fmt.Errorf("flag thresholds must not be negative")
+			}
+			thresholds := anomalyThresholds{
+				LossPercent: flagThresholdLoss,
+				RTTP99:      flagThresholdP99,
+				OnlyFlagged: onlyFlagged,
+			}
+			if onlyFlagged && !thresholds.enabled() {
+				return fmt.Errorf("--only-flagged requires --flag-threshold-loss or --flag-threshold-p99")
+			}
+			if watch && interval <= 0 {
+				return fmt.Errorf("interval must be positive")
+			}
+			if watch && rawCSVPath != "" {
+				return fmt.Errorf("--watch cannot be combined with --raw-csv")
+			}
+			if dumpSamplesPath != "" && (watch || rawCSVPath != "") {
+				return fmt.Errorf("--dump-samples cannot be combined with --watch or --raw-csv")
+			}
+			if samplesFile != "" && (watch || rawCSVPath != "" || dumpSamplesPath != "") {
+				return fmt.Errorf("--samples-file cannot be combined with --watch, --raw-csv or --dump-samples")
+			}
 
 			// Convert link types to lowercase.
 			for i, linkType := range linkTypes {
@@ -90,6 +146,43 @@ func (c *DeviceCmd) Command() *cobra.Command {
 				return fmt.Errorf("invalid unit: %s", unitStr)
 			}
 
+			usingTimeWindow := recentTime > 0
+			usingEpochWindow := recentEpochs > 1
+			usingExactEpoch := epoch != 0
+			usingEpochRange := fromEpoch != 0 || toEpoch != 0
+			selectors := 0
+			if usingTimeWindow {
+				selectors++
+			}
+			if usingEpochWindow {
+				selectors++
+			}
+			if usingExactEpoch {
+				selectors++
+			}
+			if usingEpochRange {
+				selectors++
+			}
+			if selectors > 1 {
+				return fmt.Errorf("specify only one of: recent-time, recent-epochs, epoch, or from/to epoch range")
+			}
+			if usingEpochRange && fromEpoch != 0 && toEpoch != 0 && fromEpoch > toEpoch {
+				return fmt.Errorf("from-epoch must be less than to-epoch")
+			}
+
+			// A samples file fixes the circuits' samples and window, so nothing
+			// is fetched over RPC.
+			if samplesFile != "" {
+				if selectors > 0 {
+					return fmt.Errorf("--samples-file cannot be combined with recent-time, recent-epochs, epoch, or from/to epoch range")
+				}
+				snapshot, err := devicedata.ReadSamplesSnapshot(samplesFile)
+				if err != nil {
+					return err
+				}
+				return printSnapshotSummaries(os.Stdout, snapshot, link, linkTypes, unit, thresholds)
+			}
+
 			log := newLogger(verbose)
 
 			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -107,73 +200,102 @@ func (c *DeviceCmd) Command() *cobra.Command {
 				os.Exit(1)
 			}
 
-			// Filter by link type, if provided.
-			if len(linkTypes) > 0 {
-				filteredCircuits := make([]devicedata.Circuit, 0, len(circuits))
-				for _, circuit := range circuits {
-					if slices.Contains(linkTypes, strings.ToLower(circuit.Link.LinkType)) {
-						filteredCircuits = append(filteredCircuits, circuit)
+			// Filter by link and link type, if provided.
+			circuits, err = filterCircuits(circuits, link, linkTypes)
+			if err != nil {
+				return err
+			}
+
+			// resolveWindow is evaluated on every refresh in watch mode, so
+			// recent-time and current-epoch windows move forward.
+			resolveWindow := func(ctx context.Context) (*devicedata.TimeRange, *devicedata.EpochRange, error) {
+				switch {
+				case usingTimeWindow:
+					now := time.Now().UTC()
+					return &devicedata.TimeRange{From: now.Add(-recentTime), To: now}, nil, nil
+				case usingExactEpoch:
+					e := uint64(epoch)
+					return nil, &devicedata.EpochRange{From: e, To: e}, nil
+				case usingEpochWindow:
+					epochInfo, err := rpcClient.GetEpochInfo(ctx, solanarpc.CommitmentFinalized)
+					if err != nil {
+						return nil, nil, fmt.Errorf("failed to get current epoch: %w", err)
+					}
+					cur := epochInfo.Epoch
+					n := uint64(recentEpochs - 1)
+					from := uint64(0)
+					if cur >= n {
+						from = cur - n
+					}
+					return nil, &devicedata.EpochRange{From: from, To: cur}, nil
+				case usingEpochRange:
+					return nil, &devicedata.EpochRange{From: uint64(fromEpoch), To: uint64(toEpoch)}, nil
+				default:
+					epochInfo, err := rpcClient.GetEpochInfo(ctx, solanarpc.CommitmentFinalized)
+					if err != nil {
+						return nil, nil, fmt.Errorf("failed to get current epoch: %w", err)
 					}
+					cur := epochInfo.Epoch
+					return nil, &devicedata.EpochRange{From: cur, To: cur}, nil
 				}
-				circuits = filteredCircuits
 			}
 
-			usingTimeWindow := recentTime > 0
-			usingEpochWindow := recentEpochs > 1
-			usingExactEpoch := epoch != 0
-			usingEpochRange := fromEpoch != 0 || toEpoch != 0
-			selectors := 0
-			if usingTimeWindow {
-				selectors++
-			}
-			if usingEpochWindow {
-				selectors++
-			}
-			if usingExactEpoch {
-				selectors++
+			circuitCodes := make([]string, 0, len(circuits))
+			for _, circuit := range circuits {
+				circuitCodes = append(circuitCodes, circuit.Code)
 			}
-			if usingEpochRange {
-				selectors++
+			printSummaries := func(ctx context.Context, w io.Writer, timeRange *devicedata.TimeRange, epochRange *devicedata.EpochRange) error {
+				stats, err := provider.GetSummaryForCircuits(ctx, devicedata.GetSummaryForCircuitsConfig{
+					Circuits: circuitCodes,
+					Epochs:   epochRange,
+					Time:     timeRange,
+					Unit:     unit,
+				})
+				if err != nil {
+					return err
+				}
+				printDeviceSummaries(w, stats, env, recentTime, epochRange, unit, thresholds)
+				return nil
 			}
-			if selectors > 1 {
-				return fmt.Errorf("specify only one of: recent-time, recent-epochs, epoch, or from/to epoch range")
+
+			if watch {
+				buildSummaries := func(ctx context.Context, w io.Writer) error {
+					timeRange, epochRange, err := resolveWindow(ctx)
+					if err != nil {
+						return err
+					}
+					return printSummaries(ctx, w, timeRange, epochRange)
+				}
+				resize := make(chan os.Signal, 1)
+				signal.Notify(resize, syscall.SIGWINCH)
+				defer signal.Stop(resize)
+				return runWatch(ctx, os.Stdout, clockwork.NewRealClock(), interval, resize, buildSummaries)
 			}
-			if usingEpochRange && fromEpoch != 0 && toEpoch != 0 && fromEpoch > toEpoch {
-				return fmt.Errorf("from-epoch must be less than to-epoch")
+
+			timeRange, epochRange, err := resolveWindow(ctx)
+			if err != nil {
+				log.Error("Failed to resolve aggregation window", "error", err)
+				os.Exit(1)
 			}
 
-			var timeRange *devicedata.TimeRange
-			var epochRange *devicedata.EpochRange
-			switch {
-			case usingTimeWindow:
-				now := time.Now().UTC()
-				timeRange = &devicedata.TimeRange{From: now.Add(-recentTime), To: now}
-			case usingExactEpoch:
-				e := uint64(epoch)
-				epochRange = &devicedata.EpochRange{From: e, To: e}
-			case usingEpochWindow:
-				epochInfo, err := rpcClient.GetEpochInfo(ctx, solanarpc.CommitmentFinalized)
+			if dumpSamplesPath != "" {
+				snapshot, err := fetchSamplesSnapshot(ctx, provider, circuits, env, timeRange, epochRange)
 				if err != nil {
-					log.Error("failed to get current epoch", "error", err)
+					log.Error("Failed to get samples", "error", err)
 					os.Exit(1)
 				}
-				cur := epochInfo.Epoch
-				n := uint64(recentEpochs - 1)
-				from := uint64(0)
-				if cur >= n {
-					from = cur - n
+				if err := devicedata.WriteSamplesSnapshot(dumpSamplesPath, snapshot); err != nil {
+					log.Error("Failed to write samples snapshot", "error", err, "path", dumpSamplesPath)
+					os.Exit(1)
 				}
-				epochRange = &devicedata.EpochRange{From: from, To: cur}
-			case usingEpochRange:
-				epochRange = &devicedata.EpochRange{From: uint64(fromEpoch), To: uint64(toEpoch)}
-			default:
-				epochInfo, err := rpcClient.GetEpochInfo(ctx, solanarpc.CommitmentFinalized)
+				log.Info("Wrote samples snapshot", "path", dumpSamplesPath, "circuits", len(snapshot.Circuits))
+
+				stats, err := snapshot.Summaries(unit)
 				if err != nil {
-					log.Error("failed to get current epoch", "error", err)
-					os.Exit(1)
+					return err
 				}
-				cur := epochInfo.Epoch
-				epochRange = &devicedata.EpochRange{From: cur, To: cur}
+				printDeviceSummaries(os.Stdout, stats, env, recentTime, epochRange, unit, thresholds)
+				return nil
 			}
 
 			if rawCSVPath != "" {
@@ -224,23 +346,10 @@ func (c *DeviceCmd) Command() *cobra.Command {
 				return nil
 			}
 
-			circuitCodes := make([]string, 0, len(circuits))
-			for _, circuit := range circuits {
-				circuitCodes = append(circuitCodes, circuit.Code)
-			}
-			stats, err := provider.GetSummaryForCircuits(ctx, devicedata.GetSummaryForCircuitsConfig{
-				Circuits: circuitCodes,
-				Epochs:   epochRange,
-				Time:     timeRange,
-				Unit:     unit,
-			})
-			if err != nil {
+			if err := printSummaries(ctx, os.Stdout, timeRange, epochRange); err != nil {
 				log.Error("Failed to get summary for circuits", "error", err)
-				return nil
 			}
 
-			printDeviceSummaries(stats, env, recentTime, epochRange, unit)
-
 			return nil
 		},
 	}
@@ -253,6 +362,14 @@ func (c *DeviceCmd) Command() *cobra.Command {
 	cmd.Flags().String("raw-csv", "", "Path to save raw data to CSV")
 	cmd.Flags().String("unit", "ms", "Unit to display latencies in (ms, us)")
 	cmd.Flags().StringSlice("link-type", []string{}, "Filter by link type (wan, dzx)")
+	cmd.Flags().String("link", "", "Restrict to a single link, by code or pubkey")
+	cmd.Flags().Bool("watch", false, "Re-fetch and redraw the summary table every --interval until interrupted")
+	cmd.Flags().Duration("interval", 30*time.Second, "Refresh interval for --watch")
+	cmd.Flags().Float64("flag-threshold-loss", 0, "Flag circuits whose loss exceeds this percentage (0 disables)")
+	cmd.Flags().Float64("flag-threshold-p99", 0, "Flag circuits whose RTT P99 exceeds this value, in --unit (0 disables)")
+	cmd.Flags().Bool("only-flagged", false, "Only print circuits flagged by --flag-threshold-loss or --flag-threshold-p99")
+	cmd.Flags().String("dump-samples", "", "Path to save the raw samples of the selected circuits to, as a JSON snapshot for --samples-file")
+	cmd.Flags().String("samples-file", "", "Summarize a JSON snapshot saved with --dump-samples instead of fetching samples over RPC")
 
 	return cmd
 }
@@ -283,19 +400,24 @@ func newDeviceProvider(log *slog.Logger, env string) (devicedata.Provider, *sola
 	return provider, rpcClient, nil
 }
 
-func printDeviceSummaries(stats []devicedata.CircuitSummary, env string, recentTime time.Duration, epochRange *devicedata.EpochRange, unit devicedata.Unit) {
-	fmt.Println("Environment:", env)
+func printDeviceSummaries(w io.Writer, stats []devicedata.CircuitSummary, env string, recentTime time.Duration, epochRange *devicedata.EpochRange, unit devicedata.Unit, thresholds anomalyThresholds) {
+	fmt.Fprintln(w, "Environment:", env)
 	if recentTime > 0 {
-		fmt.Println("Recent time:", recentTime)
+		fmt.Fprintln(w, "Recent time:", recentTime)
 	}
 	if epochRange != nil {
 		if epochRange.From == epochRange.To {
-			fmt.Println("Epoch:", epochRange.From)
+			fmt.Fprintln(w, "Epoch:", epochRange.From)
 		} else {
-			fmt.Println("Epochs:", epochRange.From, "-", epochRange.To)
+			fmt.Fprintln(w, "Epochs:", epochRange.From, "-", epochRange.To)
 		}
 	}
-	fmt.Println("* RTT aggregates are in", unit)
+	fmt.Fprintln(w, "* RTT aggregates are in", unit)
+	if thresholds.enabled() {
+		fmt.Fprintln(w, "* Flagged circuits exceed", describeThresholds(thresholds, unit))
+	}
+
+	stats = thresholds.filter(stats)
 
 	sort.Slice(stats, func(i, j int) bool {
 		if stats[i].Circuit == stats[j].Circuit {
@@ -304,13 +426,13 @@ func printDeviceSummaries(stats []devicedata.CircuitSummary, env string, recentT
 		return stats[i].Circuit < stats[j].Circuit
 	})
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := tablewriter.NewWriter(w)
 	table.SetAutoWrapText(false)
 	table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
 	table.SetAutoFormatHeaders(false)
 	table.SetBorder(true)
 	table.SetRowLine(true)
-	table.SetHeader([]string{
+	header := []string{
 		"Circuit",
 		"Link Type",
 		"RTT Mean\n(" + string(unit) + ")",
@@ -324,11 +446,15 @@ func printDeviceSummaries(stats []devicedata.CircuitSummary, env string, recentT
 		"RTT\nP50",
 		"RTT\nP90", "RTT\nP95", "RTT\nP99", "RTT\nMin", "RTT\nMax",
 		"Success\n(#)", "Loss\n(#)", "Loss\n(%)",
-	})
+	}
+	if thresholds.enabled() {
+		header = append(header, "Flags")
+	}
+	table.SetHeader(header)
 
 	for _, s := range stats {
 		f := NewValueFormatter(s.LossRate)
-		table.Append([]string{
+		row := []string{
 			s.Circuit,
 			s.LinkType,
 			f.Format(s.RTTMean),
@@ -348,7 +474,11 @@ func printDeviceSummaries(stats []devicedata.CircuitSummary, env string, recentT
 			fmt.Sprintf("%d", s.SuccessCount),
 			fmt.Sprintf("%d", s.LossCount),
 			fmt.Sprintf("%.1f%%", s.LossRate*100),
-		})
+		}
+		if thresholds.enabled() {
+			row = append(row, formatAnomalyFlags(thresholds.flags(s)))
+		}
+		table.Append(row)
 	}
 	table.Render()
 }
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	devicedata "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
+)
+
+// filterCircuits restricts circuits to the given link (code or pubkey) and
+// link types, when set.
+func filterCircuits(circuits []devicedata.Circuit, link string, linkTypes []string) ([]devicedata.Circuit, error) {
+	if link != "" {
+		var err error
+		circuits, err = devicedata.FilterCircuitsByLink(circuits, link)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(linkTypes) > 0 {
+		filtered := make([]devicedata.Circuit, 0, len(circuits))
+		for _, circuit := range circuits {
+			if slices.Contains(linkTypes, strings.ToLower(circuit.Link.LinkType)) {
+				filtered = append(filtered, circuit)
+			}
+		}
+		circuits = filtered
+	}
+	return circuits, nil
+}
+
+// fetchSamplesSnapshot fetches the raw samples of every circuit over the
+// window, for --dump-samples.
+func fetchSamplesSnapshot(ctx context.Context, provider devicedata.Provider, circuits []devicedata.Circuit, env string, timeRange *devicedata.TimeRange, epochRange *devicedata.EpochRange) (*devicedata.SamplesSnapshot, error) {
+	snapshot := &devicedata.SamplesSnapshot{
+		Env:      env,
+		Epochs:   epochRange,
+		Time:     timeRange,
+		Circuits: make([]devicedata.CircuitSamples, len(circuits)),
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(circuits))
+	for i, circuit := range circuits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			samples, err := provider.GetCircuitSamples(ctx, devicedata.GetCircuitLatenciesConfig{
+				Circuit: circuit.Code,
+				Time:    timeRange,
+				Epochs:  epochRange,
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get samples for circuit %s: %w", circuit.Code, err)
+				return
+			}
+			snapshot.Circuits[i] = devicedata.CircuitSamples{Circuit: circuit, Samples: samples}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// printSnapshotSummaries summarizes the circuits of a samples snapshot
+// selected by link and link types, without RPC access.
+func printSnapshotSummaries(w io.Writer, snapshot *devicedata.SamplesSnapshot, link string, linkTypes []string, unit devicedata.Unit, thresholds anomalyThresholds) error {
+	circuits := make([]devicedata.Circuit, 0, len(snapshot.Circuits))
+	for _, cs := range snapshot.Circuits {
+		circuits = append(circuits, cs.Circuit)
+	}
+	circuits, err := filterCircuits(circuits, link, linkTypes)
+	if err != nil {
+		return err
+	}
+	selected := make(map[string]bool, len(circuits))
+	for _, circuit := range circuits {
+		selected[circuit.Code] = true
+	}
+	filtered := *snapshot
+	filtered.Circuits = slices.DeleteFunc(slices.Clone(snapshot.Circuits), func(cs devicedata.CircuitSamples) bool {
+		return !selected[cs.Circuit.Code]
+	})
+
+	stats, err := filtered.Summaries(unit)
+	if err != nil {
+		return err
+	}
+
+	var window time.Duration
+	if snapshot.Time != nil {
+		window = snapshot.Time.To.Sub(snapshot.Time.From)
+	}
+	printDeviceSummaries(w, stats, snapshot.Env, window, snapshot.Epochs, unit, thresholds)
+	return nil
+}
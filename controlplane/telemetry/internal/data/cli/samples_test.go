@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	devicedata "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/stats"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSnapshotSummaries(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	samples := []stats.CircuitLatencySample{{Timestamp: base, RTT: 10_000}, {Timestamp: base.Add(time.Second), RTT: 12_000}}
+	snapshot := &devicedata.SamplesSnapshot{
+		Env:    "testnet",
+		Epochs: &devicedata.EpochRange{From: 7, To: 8},
+		Circuits: []devicedata.CircuitSamples{
+			{Circuit: devicedata.Circuit{Code: "wan-circuit", Link: devicedata.Link{Code: "wan-link", LinkType: "WAN"}}, Samples: samples},
+			{Circuit: devicedata.Circuit{Code: "dzx-circuit", Link: devicedata.Link{Code: "dzx-link", LinkType: "DZX"}}, Samples: samples},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printSnapshotSummaries(&out, snapshot, "", nil, devicedata.UnitMillisecond, anomalyThresholds{}))
+	require.Contains(t, out.String(), "Environment: testnet")
+	require.Contains(t, out.String(), "Epochs: 7 - 8")
+	require.Contains(t, out.String(), "wan-circuit")
+	require.Contains(t, out.String(), "dzx-circuit")
+	require.Contains(t, out.String(), "11.000")
+
+	out.Reset()
+	require.NoError(t, printSnapshotSummaries(&out, snapshot, "", []string{"dzx"}, devicedata.UnitMillisecond, anomalyThresholds{}))
+	require.NotContains(t, out.String(), "wan-circuit")
+	require.Contains(t, out.String(), "dzx-circuit")
+
+	out.Reset()
+	require.NoError(t, printSnapshotSummaries(&out, snapshot, "wan-link", nil, devicedata.UnitMillisecond, anomalyThresholds{}))
+	require.Contains(t, out.String(), "wan-circuit")
+	require.NotContains(t, out.String(), "dzx-circuit")
+	require.Len(t, snapshot.Circuits, 2)
+
+	require.Error(t, printSnapshotSummaries(&out, snapshot, "missing-link", nil, devicedata.UnitMillisecond, anomalyThresholds{}))
+}
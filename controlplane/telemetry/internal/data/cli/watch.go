@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// clearScreen moves the cursor to the top-left and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// runWatch calls render immediately and then every interval until ctx is done,
+// replacing the previous output in place. Each render is buffered, so a slow
+// fetch leaves the last table on screen until the new one is ready. A failed
+// refresh keeps the last table and shows the error. A signal on resize redraws
+// the last output without re-fetching.
+func runWatch(ctx context.Context, out io.Writer, clock clockwork.Clock, interval time.Duration, resize <-chan os.Signal, render func(ctx context.Context, w io.Writer) error) error {
+	var (
+		last        []byte
+		lastRefresh time.Time
+		lastErr     error
+		failedAt    time.Time
+	)
+
+	draw := func() {
+		fmt.Fprint(out, clearScreen)
+		if lastRefresh.IsZero() {
+			fmt.Fprintf(out, "Last refresh: never (every %s, Ctrl-C to exit)\n", interval)
+		} else {
+			fmt.Fprintf(out, "Last refresh: %s (every %s, Ctrl-C to exit)\n", lastRefresh.Format(time.DateTime), interval)
+		}
+		if lastErr != nil {
+			fmt.Fprintf(out, "Refresh failed at %s: %v\n", failedAt.Format(time.DateTime), lastErr)
+		}
+		fmt.Fprintln(out)
+		_, _ = out.Write(last)
+	}
+
+	refresh := func() {
+		var buf bytes.Buffer
+		if err := render(ctx, &buf); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			lastErr, failedAt = err, clock.Now()
+		} else {
+			last, lastRefresh, lastErr = buf.Bytes(), clock.Now(), nil
+		}
+		draw()
+	}
+
+	refresh()
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.Chan():
+			refresh()
+		case <-resize:
+			draw()
+		}
+	}
+}
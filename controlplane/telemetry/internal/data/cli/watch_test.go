@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWatch_RefreshesOnInterval(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var calls atomic.Int32
+	render := func(ctx context.Context, w io.Writer) error {
+		n := calls.Add(1)
+		fmt.Fprintf(w, "summary %d\n", n)
+		return nil
+	}
+
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- runWatch(ctx, &out, clock, 30*time.Second, nil, render)
+	}()
+
+	for want := int32(2); want <= 3; want++ {
+		require.NoError(t, clock.BlockUntilContext(ctx, 1))
+		clock.Advance(30 * time.Second)
+		require.Eventually(t, func() bool { return calls.Load() == want }, time.Second, time.Millisecond)
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, 3, strings.Count(out.String(), clearScreen))
+	require.Contains(t, out.String(), "summary 3\n")
+	require.Contains(t, out.String(), "Last refresh: "+clock.Now().Format(time.DateTime))
+}
+
+func TestRunWatch_FailedRefreshKeepsLastOutput(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var calls atomic.Int32
+	render := func(ctx context.Context, w io.Writer) error {
+		if calls.Add(1) > 1 {
+			return errors.New("rpc unavailable")
+		}
+		fmt.Fprintln(w, "first summary")
+		return nil
+	}
+
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- runWatch(ctx, &out, clock, time.Minute, nil, render)
+	}()
+
+	require.NoError(t, clock.BlockUntilContext(ctx, 1))
+	clock.Advance(time.Minute)
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	frames := strings.Split(out.String(), clearScreen)
+	lastFrame := frames[len(frames)-1]
+	require.Contains(t, lastFrame, "Refresh failed at")
+	require.Contains(t, lastFrame, "rpc unavailable")
+	require.Contains(t, lastFrame, "first summary")
+}
+
+func TestRunWatch_ResizeRedrawsWithoutRefetch(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var calls atomic.Int32
+	render := func(ctx context.Context, w io.Writer) error {
+		calls.Add(1)
+		fmt.Fprintln(w, "summary")
+		return nil
+	}
+
+	resize := make(chan os.Signal)
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- runWatch(ctx, &out, clock, time.Minute, resize, render)
+	}()
+
+	// The unbuffered send completes once the loop has received it; the
+	// second send ensures the first redraw has been written.
+	resize <- syscall.SIGWINCH
+	resize <- syscall.SIGWINCH
+
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, int32(1), calls.Load())
+	require.GreaterOrEqual(t, strings.Count(out.String(), clearScreen), 2)
+}
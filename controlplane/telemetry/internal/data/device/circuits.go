@@ -84,3 +84,18 @@ func (p *provider) GetCircuits(ctx context.Context) ([]Circuit, error) {
 
 	return circuits, nil
 }
+
+// FilterCircuitsByLink returns the circuits over the link identified by its
+// code or base58 pubkey. It errors if no circuit uses a matching link.
+func FilterCircuitsByLink(circuits []Circuit, link string) ([]Circuit, error) {
+	filtered := make([]Circuit, 0, 2)
+	for _, circuit := range circuits {
+		if circuit.Link.Code == link || circuit.Link.PK.String() == link {
+			filtered = append(filtered, circuit)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("link %q not found", link)
+	}
+	return filtered, nil
+}
@@ -251,6 +251,48 @@ func TestTelemetry_Data_Device_Provider_GetCircuits(t *testing.T) {
 	})
 }
 
+func TestTelemetry_Data_Device_FilterCircuitsByLink(t *testing.T) {
+	t.Parallel()
+
+	l1 := solana.NewWallet().PublicKey()
+	l2 := solana.NewWallet().PublicKey()
+	circuits := []data.Circuit{
+		{Code: "A → B (L1)", Link: data.Link{PK: l1, Code: "A:B"}},
+		{Code: "B → A (L1)", Link: data.Link{PK: l1, Code: "A:B"}},
+		{Code: "B → C (L2)", Link: data.Link{PK: l2, Code: "B:C"}},
+		{Code: "C → B (L2)", Link: data.Link{PK: l2, Code: "B:C"}},
+	}
+
+	t.Run("by code", func(t *testing.T) {
+		t.Parallel()
+
+		filtered, err := data.FilterCircuitsByLink(circuits, "B:C")
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+		for _, c := range filtered {
+			assert.Equal(t, l2, c.Link.PK)
+		}
+	})
+
+	t.Run("by pubkey", func(t *testing.T) {
+		t.Parallel()
+
+		filtered, err := data.FilterCircuitsByLink(circuits, l1.String())
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+		for _, c := range filtered {
+			assert.Equal(t, "A:B", c.Link.Code)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := data.FilterCircuitsByLink(circuits, "X:Y")
+		require.EqualError(t, err, `link "X:Y" not found`)
+	})
+}
+
 func toPubKeyBytes(pk solana.PublicKey) [32]byte {
 	var arr [32]byte
 	copy(arr[:], pk.Bytes())
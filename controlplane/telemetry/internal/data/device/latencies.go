@@ -24,6 +24,29 @@ func (p *provider) GetCircuitLatencies(ctx context.Context, cfg GetCircuitLatenc
 		return nil, fmt.Errorf("invalid unit: %s (must be %s or %s)", cfg.Unit, UnitMillisecond, UnitMicrosecond)
 	}
 
+	samples, err := p.GetCircuitSamples(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := datastats.Aggregate(cfg.Circuit, samples, cfg.MaxPoints, cfg.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate circuit latencies: %w", err)
+	}
+
+	if cfg.Unit == UnitMillisecond {
+		for i := range stats {
+			stats[i].ConvertUnit(1000.0)
+		}
+	}
+
+	return stats, nil
+}
+
+// GetCircuitSamples returns the raw latency samples of the circuit over the
+// configured epoch or time range, in microseconds. Unit, MaxPoints and
+// Interval are ignored.
+func (p *provider) GetCircuitSamples(ctx context.Context, cfg GetCircuitLatenciesConfig) ([]datastats.CircuitLatencySample, error) {
 	if cfg.Epochs != nil && cfg.Time != nil {
 		return nil, fmt.Errorf("from_epoch and to_epoch or from_time and to_time cannot be set at the same time")
 	}
@@ -53,18 +76,7 @@ func (p *provider) GetCircuitLatencies(ctx context.Context, cfg GetCircuitLatenc
 		return nil, fmt.Errorf("no time or epoch range provided")
 	}
 
-	stats, err := datastats.Aggregate(cfg.Circuit, samples, cfg.MaxPoints, cfg.Interval)
-	if err != nil {
-		return nil, fmt.Errorf("failed to aggregate circuit latencies: %w", err)
-	}
-
-	if cfg.Unit == UnitMillisecond {
-		for i := range stats {
-			stats[i].ConvertUnit(1000.0)
-		}
-	}
-
-	return stats, nil
+	return samples, nil
 }
 
 func (p *provider) GetCircuitLatenciesForEpoch(ctx context.Context, circuitCode string, epoch uint64) (*CircuitLatenciesWithHeader, error) {
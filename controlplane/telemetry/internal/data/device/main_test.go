@@ -67,6 +67,7 @@ func (m *mockTelemetryClient) GetDeviceLatencySamplesHeader(ctx context.Context,
 type mockProvider struct {
 	GetCircuitsFunc           func(context.Context) ([]data.Circuit, error)
 	GetCircuitLatenciesFunc   func(context.Context, data.GetCircuitLatenciesConfig) ([]stats.CircuitLatencyStat, error)
+	GetCircuitSamplesFunc     func(context.Context, data.GetCircuitLatenciesConfig) ([]stats.CircuitLatencySample, error)
 	GetSummaryForCircuitsFunc func(context.Context, data.GetSummaryForCircuitsConfig) ([]data.CircuitSummary, error)
 	GetAgentVersionsFunc      func(context.Context) ([]data.DeviceAgentVersion, error)
 }
@@ -79,6 +80,10 @@ func (m *mockProvider) GetCircuitLatencies(ctx context.Context, cfg data.GetCirc
 	return m.GetCircuitLatenciesFunc(ctx, cfg)
 }
 
+func (m *mockProvider) GetCircuitSamples(ctx context.Context, cfg data.GetCircuitLatenciesConfig) ([]stats.CircuitLatencySample, error) {
+	return m.GetCircuitSamplesFunc(ctx, cfg)
+}
+
 func (m *mockProvider) GetSummaryForCircuits(ctx context.Context, cfg data.GetSummaryForCircuitsConfig) ([]data.CircuitSummary, error) {
 	return m.GetSummaryForCircuitsFunc(ctx, cfg)
 }
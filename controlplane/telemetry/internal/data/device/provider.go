@@ -97,6 +97,7 @@ type DeviceAgentVersion struct {
 type Provider interface {
 	GetCircuits(ctx context.Context) ([]Circuit, error)
 	GetCircuitLatencies(ctx context.Context, cfg GetCircuitLatenciesConfig) ([]stats.CircuitLatencyStat, error)
+	GetCircuitSamples(ctx context.Context, cfg GetCircuitLatenciesConfig) ([]stats.CircuitLatencySample, error)
 	GetSummaryForCircuits(ctx context.Context, cfg GetSummaryForCircuitsConfig) ([]CircuitSummary, error)
 	GetAgentVersions(ctx context.Context) ([]DeviceAgentVersion, error)
 }
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	datastats "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/stats"
+)
+
+// SamplesSnapshot is a saved set of raw circuit latency samples, with the
+// circuit metadata needed to summarize them without RPC access.
+type SamplesSnapshot struct {
+	Env      string           `json:"env"`
+	Epochs   *EpochRange      `json:"epochs,omitempty"`
+	Time     *TimeRange       `json:"time,omitempty"`
+	Circuits []CircuitSamples `json:"circuits"`
+}
+
+// CircuitSamples is the raw latency samples of a circuit, in microseconds.
+type CircuitSamples struct {
+	Circuit Circuit                          `json:"circuit"`
+	Samples []datastats.CircuitLatencySample `json:"samples"`
+}
+
+// Summaries computes the circuit summaries of the snapshot in the given unit,
+// as GetSummaryForCircuits does from live data. Circuits without samples are
+// omitted.
+func (s *SamplesSnapshot) Summaries(unit Unit) ([]CircuitSummary, error) {
+	summaries := []CircuitSummary{}
+	for _, cs := range s.Circuits {
+		summary, ok, err := SummarizeCircuit(cs.Circuit, cs.Samples, unit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize circuit %s: %w", cs.Circuit.Code, err)
+		}
+		if ok {
+			summaries = append(summaries, summary)
+		}
+	}
+	sortCircuitSummaries(summaries)
+	return summaries, nil
+}
+
+// WriteSamplesSnapshot writes the snapshot to path as JSON.
+func WriteSamplesSnapshot(path string, snapshot *SamplesSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal samples snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write samples snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSamplesSnapshot reads a snapshot written by WriteSamplesSnapshot.
+func ReadSamplesSnapshot(path string) (*SamplesSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read samples snapshot: %w", err)
+	}
+	var snapshot SamplesSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse samples snapshot %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,142 @@
+package data_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	data "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/device"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/stats"
+	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetry_Data_Device_SamplesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("summaries are identical after dumping and reloading samples", func(t *testing.T) {
+		t.Parallel()
+
+		c1 := defaultCircuit()
+		c1.Link.LinkType = "WAN"
+		c1.Link.CommittedRTT = 20_000
+		c1.Link.CommittedJitter = 1_000
+		c2 := defaultCircuit2()
+		c2.Link.CommittedRTT = 5_000
+		c2.Link.CommittedJitter = 500
+		c3 := defaultCircuit()
+
+		base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		samples := func(rtts ...uint32) []stats.CircuitLatencySample {
+			out := make([]stats.CircuitLatencySample, 0, len(rtts))
+			for i, rtt := range rtts {
+				out = append(out, stats.CircuitLatencySample{Timestamp: base.Add(time.Duration(i) * time.Second), RTT: rtt})
+			}
+			return out
+		}
+		snapshot := &data.SamplesSnapshot{
+			Env:    "testnet",
+			Epochs: &data.EpochRange{From: 10, To: 12},
+			Circuits: []data.CircuitSamples{
+				{Circuit: c1, Samples: samples(21_000, 0, 19_500, 22_250, 20_125)},
+				{Circuit: c2, Samples: samples(4_000, 6_500, 0, 0)},
+				// Circuits without samples are omitted from the summaries.
+				{Circuit: c3},
+			},
+		}
+
+		path := filepath.Join(t.TempDir(), "samples.json")
+		require.NoError(t, data.WriteSamplesSnapshot(path, snapshot))
+		loaded, err := data.ReadSamplesSnapshot(path)
+		require.NoError(t, err)
+		assert.Equal(t, "testnet", loaded.Env)
+		assert.Equal(t, snapshot.Epochs, loaded.Epochs)
+		assert.Nil(t, loaded.Time)
+
+		for _, unit := range []data.Unit{data.UnitMicrosecond, data.UnitMillisecond} {
+			want, err := snapshot.Summaries(unit)
+			require.NoError(t, err)
+			require.Len(t, want, 2)
+
+			got, err := loaded.Summaries(unit)
+			require.NoError(t, err)
+			assert.Equal(t, want, got, unit)
+		}
+
+		ms, err := loaded.Summaries(data.UnitMillisecond)
+		require.NoError(t, err)
+		byCircuit := map[string]data.CircuitSummary{}
+		for _, s := range ms {
+			byCircuit[s.Circuit] = s
+		}
+		got := byCircuit[c1.Code]
+		assert.Equal(t, "WAN", got.LinkType)
+		assert.Equal(t, uint64(4), got.SuccessCount)
+		assert.Equal(t, uint64(1), got.LossCount)
+		assert.Equal(t, 20.0, got.CommittedRTT)
+		assert.Equal(t, 1.0, got.CommittedJitter)
+		assert.InDelta(t, 20.0-got.RTTMean, got.CommittedRTTDelta, 1e-9)
+	})
+
+	t.Run("snapshot summaries match live summaries of the same samples", func(t *testing.T) {
+		t.Parallel()
+
+		c := defaultCircuit()
+		start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		p := newTestProvider(t, func(uint64) (*telemetry.DeviceLatencySamples, error) {
+			return &telemetry.DeviceLatencySamples{
+				DeviceLatencySamplesHeader: telemetry.DeviceLatencySamplesHeader{
+					StartTimestampMicroseconds:   uint64(start.UnixMicro()),
+					SamplingIntervalMicroseconds: 1_000_000,
+				},
+				Samples: []uint32{10_000, 0, 50_000, 30_000},
+			}, nil
+		}, c)
+		timeRange := &data.TimeRange{From: start, To: start.Add(time.Minute)}
+
+		// GetCircuits returns both directions of the link; keep the one the
+		// samples are fetched for.
+		circuits, err := p.GetCircuits(context.Background())
+		require.NoError(t, err)
+		idx := slices.IndexFunc(circuits, func(circuit data.Circuit) bool { return circuit.Code == c.Code })
+		require.NotEqual(t, -1, idx)
+		samples, err := p.GetCircuitSamples(context.Background(), data.GetCircuitLatenciesConfig{Circuit: c.Code, Time: timeRange})
+		require.NoError(t, err)
+		require.Len(t, samples, 4)
+
+		path := filepath.Join(t.TempDir(), "samples.json")
+		require.NoError(t, data.WriteSamplesSnapshot(path, &data.SamplesSnapshot{
+			Time:     timeRange,
+			Circuits: []data.CircuitSamples{{Circuit: circuits[idx], Samples: samples}},
+		}))
+		loaded, err := data.ReadSamplesSnapshot(path)
+		require.NoError(t, err)
+
+		live, err := p.GetSummaryForCircuits(context.Background(), data.GetSummaryForCircuitsConfig{
+			Circuits: []string{c.Code},
+			Unit:     data.UnitMillisecond,
+			Time:     timeRange,
+		})
+		require.NoError(t, err)
+		offline, err := loaded.Summaries(data.UnitMillisecond)
+		require.NoError(t, err)
+
+		// Committed values are zero here, so change ratios are NaN and
+		// compared separately.
+		require.Len(t, live, 1)
+		require.Len(t, offline, 1)
+		assert.Equal(t, live[0].CircuitLatencyStat, offline[0].CircuitLatencyStat)
+		assert.Equal(t, live[0].CommittedRTTDelta, offline[0].CommittedRTTDelta)
+		assert.Equal(t, live[0].CommittedJitterDelta, offline[0].CommittedJitterDelta)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := data.ReadSamplesSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+		require.Error(t, err)
+	})
+}
@@ -3,8 +3,11 @@ package data
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
+
+	datastats "github.com/malbeclabs/doublezero/controlplane/telemetry/internal/data/stats"
 )
 
 func (p *provider) GetSummaryForCircuits(ctx context.Context, cfg GetSummaryForCircuitsConfig) ([]CircuitSummary, error) {
@@ -26,72 +29,100 @@ func (p *provider) GetSummaryForCircuits(ctx context.Context, cfg GetSummaryForC
 		go func(circuitCode string) {
 			defer wg.Done()
 
-			series, err := p.GetCircuitLatencies(ctx, GetCircuitLatenciesConfig{
-				Circuit:   circuitCode,
-				Epochs:    cfg.Epochs,
-				Time:      cfg.Time,
-				MaxPoints: 1,
-				Unit:      cfg.Unit,
+			circuit, ok := circuitsByCode[circuitCode]
+			if !ok {
+				p.log.Warn("circuit not found", "circuit", circuitCode)
+				return
+			}
+
+			samples, err := p.GetCircuitSamples(ctx, GetCircuitLatenciesConfig{
+				Circuit: circuitCode,
+				Epochs:  cfg.Epochs,
+				Time:    cfg.Time,
 			})
 			if err != nil {
 				p.log.Warn("failed to get circuit latencies", "error", err, "circuit", circuitCode)
 				return
 			}
-			p.log.Debug("Got circuit latencies", "circuit", circuitCode, "series", len(series))
-			if len(series) == 0 {
+			p.log.Debug("Got circuit latencies", "circuit", circuitCode, "samples", len(samples))
+
+			summary, ok, err := SummarizeCircuit(circuit, samples, cfg.Unit)
+			if err != nil {
+				p.log.Warn("failed to summarize circuit latencies", "error", err, "circuit", circuitCode)
 				return
 			}
-			if len(series) > 1 {
-				p.log.Warn("got more than one series for circuit summary", "circuit", circuitCode, "series", len(series))
-			}
-			measured := series[0]
-
-			// Calculate committed RTT and jitter deltas and change ratios.
-			circuit, ok := circuitsByCode[circuitCode]
 			if !ok {
-				p.log.Warn("circuit not found", "circuit", circuitCode)
 				return
 			}
-			committedRTT := circuit.Link.CommittedRTT
-			committedJitter := circuit.Link.CommittedJitter
-			switch cfg.Unit {
-			case UnitMillisecond:
-				committedRTT /= 1000.0
-				committedJitter /= 1000.0
-			case UnitMicrosecond:
-			}
-			committedRTTDelta := committedRTT - measured.RTTMean
-			committedJitterDelta := committedJitter - measured.JitterAvg
-			committedRTTChangeRatio := (committedRTTDelta / committedRTT)
-			committedJitterChangeRatio := (committedJitterDelta / committedJitter)
 
 			// Add the circuit summary to the output.
 			mu.Lock()
-			output = append(output, CircuitSummary{
-				Circuit:  circuitCode,
-				LinkType: circuit.Link.LinkType,
-
-				CircuitLatencyStat: measured,
-
-				CommittedRTT:    committedRTT,
-				CommittedJitter: committedJitter,
-
-				CommittedRTTDelta:          committedRTTDelta,
-				CommittedJitterDelta:       committedJitterDelta,
-				CommittedRTTChangeRatio:    committedRTTChangeRatio,
-				CommittedJitterChangeRatio: committedJitterChangeRatio,
-			})
+			output = append(output, summary)
 			mu.Unlock()
 		}(circuitCode)
 	}
 	wg.Wait()
 
-	sort.Slice(output, func(i, j int) bool {
-		if output[i].Circuit == output[j].Circuit {
-			return output[i].Timestamp < output[j].Timestamp
-		}
-		return output[i].Circuit < output[j].Circuit
-	})
+	sortCircuitSummaries(output)
 
 	return output, nil
 }
+
+// SummarizeCircuit aggregates the raw samples of a circuit, in microseconds,
+// into a single summary in the given unit, with deltas and change ratios
+// against the link's committed RTT and jitter. It returns false if there are
+// no samples to summarize.
+func SummarizeCircuit(circuit Circuit, samples []datastats.CircuitLatencySample, unit Unit) (CircuitSummary, bool, error) {
+	var factor float64
+	switch unit {
+	case UnitMillisecond:
+		factor = 1000.0
+	case UnitMicrosecond:
+		factor = 1.0
+	default:
+		return CircuitSummary{}, false, fmt.Errorf("invalid unit: %s (must be %s or %s)", unit, UnitMillisecond, UnitMicrosecond)
+	}
+
+	// Aggregate sorts the samples, so leave the caller's slice alone.
+	series, err := datastats.Aggregate(circuit.Code, slices.Clone(samples), 1, 0)
+	if err != nil {
+		return CircuitSummary{}, false, fmt.Errorf("failed to aggregate circuit latencies: %w", err)
+	}
+	if len(series) == 0 {
+		return CircuitSummary{}, false, nil
+	}
+	measured := series[0]
+	measured.ConvertUnit(factor)
+
+	// Calculate committed RTT and jitter deltas and change ratios.
+	committedRTT := circuit.Link.CommittedRTT / factor
+	committedJitter := circuit.Link.CommittedJitter / factor
+	committedRTTDelta := committedRTT - measured.RTTMean
+	committedJitterDelta := committedJitter - measured.JitterAvg
+	committedRTTChangeRatio := (committedRTTDelta / committedRTT)
+	committedJitterChangeRatio := (committedJitterDelta / committedJitter)
+
+	return CircuitSummary{
+		Circuit:  circuit.Code,
+		LinkType: circuit.Link.LinkType,
+
+		CircuitLatencyStat: measured,
+
+		CommittedRTT:    committedRTT,
+		CommittedJitter: committedJitter,
+
+		CommittedRTTDelta:          committedRTTDelta,
+		CommittedJitterDelta:       committedJitterDelta,
+		CommittedRTTChangeRatio:    committedRTTChangeRatio,
+		CommittedJitterChangeRatio: committedJitterChangeRatio,
+	}, true, nil
+}
+
+func sortCircuitSummaries(summaries []CircuitSummary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Circuit == summaries[j].Circuit {
+			return summaries[i].Timestamp < summaries[j].Timestamp
+		}
+		return summaries[i].Circuit < summaries[j].Circuit
+	})
+}
@@ -41,6 +41,7 @@ func TestMain(m *testing.M) {
 type mockDeviceProvider struct {
 	GetCircuitsFunc           func(context.Context) ([]devicedata.Circuit, error)
 	GetCircuitLatenciesFunc   func(context.Context, devicedata.GetCircuitLatenciesConfig) ([]stats.CircuitLatencyStat, error)
+	GetCircuitSamplesFunc     func(context.Context, devicedata.GetCircuitLatenciesConfig) ([]stats.CircuitLatencySample, error)
 	GetSummaryForCircuitsFunc func(context.Context, devicedata.GetSummaryForCircuitsConfig) ([]devicedata.CircuitSummary, error)
 	GetAgentVersionsFunc      func(context.Context) ([]devicedata.DeviceAgentVersion, error)
 }
@@ -53,6 +54,10 @@ func (m *mockDeviceProvider) GetCircuitLatencies(ctx context.Context, cfg device
 	return m.GetCircuitLatenciesFunc(ctx, cfg)
 }
 
+func (m *mockDeviceProvider) GetCircuitSamples(ctx context.Context, cfg devicedata.GetCircuitLatenciesConfig) ([]stats.CircuitLatencySample, error) {
+	return m.GetCircuitSamplesFunc(ctx, cfg)
+}
+
 func (m *mockDeviceProvider) GetSummaryForCircuits(ctx context.Context, cfg devicedata.GetSummaryForCircuitsConfig) ([]devicedata.CircuitSummary, error) {
 	return m.GetSummaryForCircuitsFunc(ctx, cfg)
 }
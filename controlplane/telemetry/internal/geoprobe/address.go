@@ -46,6 +46,8 @@ func (p ProbeAddress) ValidateICMP() error {
 	if ip == nil {
 		return fmt.Errorf("host must be a valid IP address")
 	}
+	// Offsets identify their target with a 4-byte TargetIP, so IPv6 targets
+	// cannot be represented and are rejected here.
 	if ip.To4() == nil {
 		return fmt.Errorf("host %s must be an IPv4 address", p.Host)
 	}
@@ -57,6 +57,15 @@ func TestProbeAddress_Validate(t *testing.T) {
 			},
 			wantErr: "host must be a valid IP address",
 		},
+		{
+			name: "IPv6 target rejected",
+			addr: ProbeAddress{
+				Host:      "2001:db8::1",
+				Port:      10000,
+				TWAMPPort: 8925,
+			},
+			wantErr: "must be an IPv4 address",
+		},
 		{
 			name: "Zero twamp port",
 			addr: ProbeAddress{
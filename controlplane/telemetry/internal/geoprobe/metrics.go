@@ -17,6 +17,7 @@ const (
 	MetricNameOffsetsReceived              = "doublezero_geoprobe_offsets_received_total"
 	MetricNameOffsetsRejected              = "doublezero_geoprobe_offsets_rejected_total"
 	MetricNameCompositeOffsetsSent         = "doublezero_geoprobe_composite_offsets_sent_total"
+	MetricNameCompositeOffsetsStale        = "doublezero_geoprobe_composite_offsets_stale_reference_total"
 	MetricNameTargetsDiscovered            = "doublezero_geoprobe_targets_discovered"
 	MetricNameParentsDiscovered            = "doublezero_geoprobe_parents_discovered"
 	MetricNameIcmpTargetsDiscovered        = "doublezero_geoprobe_icmp_targets_discovered"
@@ -63,6 +64,7 @@ type Metrics struct {
 	OffsetsReceived              prometheus.Counter
 	OffsetsRejected              *prometheus.CounterVec
 	CompositeOffsetsSent         prometheus.Counter
+	CompositeOffsetsStale        prometheus.Counter
 	TargetsDiscovered            prometheus.Gauge
 	ParentsDiscovered            prometheus.Gauge
 	IcmpTargetsDiscovered        prometheus.Gauge
@@ -140,6 +142,13 @@ func NewMetrics(source, geoProbePubkey string, reg prometheus.Registerer) *Metri
 				ConstLabels: constLabels,
 			},
 		),
+		CompositeOffsetsStale: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        MetricNameCompositeOffsetsStale,
+				Help:        "Total composite offsets sent whose parent DZD offset was older than the preferred freshness",
+				ConstLabels: constLabels,
+			},
+		),
 		TargetsDiscovered: prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name:        MetricNameTargetsDiscovered,
@@ -180,6 +189,7 @@ func NewMetrics(source, geoProbePubkey string, reg prometheus.Registerer) *Metri
 		m.OffsetsReceived,
 		m.OffsetsRejected,
 		m.CompositeOffsetsSent,
+		m.CompositeOffsetsStale,
 		m.TargetsDiscovered,
 		m.ParentsDiscovered,
 		m.IcmpTargetsDiscovered,
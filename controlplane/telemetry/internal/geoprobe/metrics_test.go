@@ -35,6 +35,9 @@ func TestNewMetrics(t *testing.T) {
 	if m.CompositeOffsetsSent == nil {
 		t.Fatal("CompositeOffsetsSent is nil")
 	}
+	if m.CompositeOffsetsStale == nil {
+		t.Fatal("CompositeOffsetsStale is nil")
+	}
 	if m.TargetsDiscovered == nil {
 		t.Fatal("TargetsDiscovered is nil")
 	}
@@ -76,7 +79,7 @@ func TestNewMetrics_ConstantLabels(t *testing.T) {
 	}
 }
 
-func TestNewMetrics_RegistersTwelveCollectors(t *testing.T) {
+func TestNewMetrics_RegistersThirteenCollectors(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	m := NewMetrics(SourceGeoProbeAgent, "DevPK789", reg)
 
@@ -99,6 +102,7 @@ func TestNewMetrics_RegistersTwelveCollectors(t *testing.T) {
 		MetricNameOffsetsReceived:              false,
 		MetricNameOffsetsRejected:              false,
 		MetricNameCompositeOffsetsSent:         false,
+		MetricNameCompositeOffsetsStale:        false,
 		MetricNameTargetsDiscovered:            false,
 		MetricNameParentsDiscovered:            false,
 		MetricNameIcmpTargetsDiscovered:        false,
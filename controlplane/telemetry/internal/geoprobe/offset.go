@@ -39,6 +39,8 @@ type LocationOffset struct {
 }
 
 // IPToTargetIP converts an IP address string to a [4]byte for use in TargetIP.
+// It returns the zero value for anything but an IPv4 address; callers reject
+// such hosts beforehand with ProbeAddress.Validate or ValidateICMP.
 func IPToTargetIP(host string) [4]byte {
 	ip := net.ParseIP(host)
 	if ip == nil {
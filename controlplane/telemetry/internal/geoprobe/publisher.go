@@ -10,7 +10,7 @@ import (
 
 	"github.com/gagliardetto/solana-go"
 	solanarpc "github.com/gagliardetto/solana-go/rpc"
-	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netns"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
 )
 
@@ -441,6 +441,19 @@ func TestPublisher_AddProbe(t *testing.T) {
 		err := pub.AddProbe(context.Background(), addr)
 		require.NoError(t, err)
 	})
+
+	t.Run("rejects IPv6 target", func(t *testing.T) {
+		// Offsets carry a 4-byte TargetIP, so an IPv6 target must be refused
+		// rather than published with a zeroed TargetIP.
+		v6 := ProbeAddress{Host: "2001:db8::1", Port: 9999, TWAMPPort: 8925}
+		err := pub.AddProbe(context.Background(), v6)
+		require.ErrorContains(t, err, "must be an IPv4 address")
+
+		pub.connsMu.Lock()
+		_, exists := pub.conns[v6.Host]
+		pub.connsMu.Unlock()
+		assert.False(t, exists)
+	})
 }
 
 func TestPublisher_RemoveProbe(t *testing.T) {
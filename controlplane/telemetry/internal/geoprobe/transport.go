@@ -55,6 +55,11 @@ func ReceiveOffset(conn *net.UDPConn) (*LocationOffset, *net.UDPAddr, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read UDP datagram: %w", err)
 	}
+	// A dual-stack socket reports IPv4 senders as IPv4-mapped IPv6 addresses;
+	// hand callers the plain 4-byte form so IPv4 sources look the same either way.
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		addr.IP = ip4
+	}
 
 	offset := &LocationOffset{}
 	if err := offset.Unmarshal(buf[:n]); err != nil {
@@ -65,18 +70,18 @@ func ReceiveOffset(conn *net.UDPConn) (*LocationOffset, *net.UDPAddr, error) {
 }
 
 // NewUDPListener creates a UDP listener on the specified port.
-// The listener binds to all interfaces (0.0.0.0).
+// The listener binds to all interfaces and accepts both IPv4 and IPv6 senders
+// (dual-stack); on hosts without IPv6 it falls back to IPv4 only.
 func NewUDPListener(port int) (*net.UDPConn, error) {
 	if port < 0 || port > 65535 {
 		return nil, fmt.Errorf("invalid port %d: must be in range 0-65535", port)
 	}
 
 	addr := &net.UDPAddr{
-		IP:   net.IPv4zero,
 		Port: port,
 	}
 
-	conn, err := net.ListenUDP("udp4", addr)
+	conn, err := net.ListenUDP("udp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create UDP listener on port %d: %w", port, err)
 	}
@@ -84,14 +89,14 @@ func NewUDPListener(port int) (*net.UDPConn, error) {
 	return conn, nil
 }
 
-// NewUDPConn creates an unbound UDP connection that can send to any address.
+// NewUDPConn creates an unbound UDP connection that can send to any address,
+// IPv4 or IPv6, so one connection serves a mix of v4 and v6 destinations.
 func NewUDPConn() (*net.UDPConn, error) {
 	addr := &net.UDPAddr{
-		IP:   net.IPv4zero,
 		Port: 0, // OS picks an ephemeral port
 	}
 
-	conn, err := net.ListenUDP("udp4", addr)
+	conn, err := net.ListenUDP("udp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create UDP connection: %w", err)
 	}
@@ -0,0 +1,93 @@
+package geoprobe
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testTransportOffset() *LocationOffset {
+	return &LocationOffset{
+		Version:         LocationOffsetVersion,
+		MeasurementSlot: 12345,
+		Lat:             52.3676,
+		Lng:             4.9041,
+		MeasuredRttNs:   800000,
+		RttNs:           800000,
+		TargetIP:        IPToTargetIP("203.0.113.10"),
+		References:      []LocationOffset{},
+	}
+}
+
+// roundTripOffset sends an offset from a fresh NewUDPConn to a fresh
+// NewUDPListener at dst and returns what the listener received.
+func roundTripOffset(t *testing.T, dst net.IP) (*LocationOffset, *net.UDPAddr) {
+	t.Helper()
+
+	listener, err := NewUDPListener(0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sender, err := NewUDPConn()
+	require.NoError(t, err)
+	defer sender.Close()
+
+	port := listener.LocalAddr().(*net.UDPAddr).Port
+	require.NoError(t, SendOffset(sender, &net.UDPAddr{IP: dst, Port: port}, testTransportOffset()))
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(2*time.Second)))
+	offset, addr, err := ReceiveOffset(listener)
+	require.NoError(t, err)
+	return offset, addr
+}
+
+func skipWithoutIPv6Loopback(t *testing.T) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	conn.Close()
+}
+
+func TestUDPTransport_IPv4RoundTrip(t *testing.T) {
+	offset, addr := roundTripOffset(t, net.IPv4(127, 0, 0, 1))
+	require.Equal(t, uint64(12345), offset.MeasurementSlot)
+	// IPv4 senders come back as 4-byte addresses even on a dual-stack listener.
+	require.Len(t, addr.IP, net.IPv4len)
+	require.Equal(t, "127.0.0.1", addr.IP.String())
+}
+
+func TestUDPTransport_IPv6RoundTrip(t *testing.T) {
+	skipWithoutIPv6Loopback(t)
+
+	offset, addr := roundTripOffset(t, net.IPv6loopback)
+	require.Equal(t, uint64(12345), offset.MeasurementSlot)
+	require.Equal(t, uint64(800000), offset.RttNs)
+	require.True(t, addr.IP.Equal(net.IPv6loopback), "got sender %s", addr)
+	require.Nil(t, addr.IP.To4())
+}
+
+func TestUDPTransport_MixedFamiliesOnOneConn(t *testing.T) {
+	skipWithoutIPv6Loopback(t)
+
+	listener, err := NewUDPListener(0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	sender, err := NewUDPConn()
+	require.NoError(t, err)
+	defer sender.Close()
+
+	port := listener.LocalAddr().(*net.UDPAddr).Port
+	for _, dst := range []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback} {
+		require.NoError(t, SendOffset(sender, &net.UDPAddr{IP: dst, Port: port}, testTransportOffset()), "send to %s", dst)
+
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(2*time.Second)))
+		_, addr, err := ReceiveOffset(listener)
+		require.NoError(t, err)
+		require.Equal(t, dst.To4() != nil, addr.IP.To4() != nil, "sender family for %s", dst)
+	}
+}
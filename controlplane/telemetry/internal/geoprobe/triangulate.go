@@ -0,0 +1,145 @@
+package geoprobe
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// MinTriangulationReferences is the minimum number of reference offsets
+	// needed for a position estimate.
+	MinTriangulationReferences = 3
+
+	// DefaultTriangulationVelocityFactor approximates propagation in fiber.
+	// Triangulation treats RTT-derived distances as actual distances rather
+	// than upper bounds, so the theoretical maximum (1.0) would overestimate.
+	DefaultTriangulationVelocityFactor = 2.0 / 3.0
+
+	speedOfLightKmPerMs = 299.792458
+	earthRadiusKm       = 6371.0
+
+	triangulationMaxIterations = 20
+	triangulationTolerance     = 1e-6 // km
+)
+
+var (
+	// ErrTooFewReferences is returned when fewer than MinTriangulationReferences
+	// reference offsets are available.
+	ErrTooFewReferences = errors.New("too few reference offsets for triangulation")
+	// ErrDegenerateReferences is returned when the reference points are
+	// coincident or collinear, so no unique position exists.
+	ErrDegenerateReferences = errors.New("reference offsets are coincident or collinear")
+)
+
+// PositionEstimate is a least-squares position estimate from several
+// reference points and their RTT-derived distances.
+type PositionEstimate struct {
+	Lat           float64 `json:"lat"`
+	Lng           float64 `json:"lng"`
+	ResidualKm    float64 `json:"residual_km"` // RMS of (distance to reference - RTT-derived distance)
+	NumReferences int     `json:"num_references"`
+}
+
+// RTTDistanceKm returns the one-way distance in km covered in half the RTT at
+// velocityFactor times the speed of light.
+func RTTDistanceKm(rttNs uint64, velocityFactor float64) float64 {
+	return float64(rttNs) / 2 / 1e6 * speedOfLightKmPerMs * velocityFactor
+}
+
+// EstimatePosition multilaterates a position from reference offsets, using each
+// offset's Lat/Lng as a known point and its RttNs as the distance to the
+// unknown position. Points are projected onto a local plane around their
+// centroid, solved linearly, and refined with Gauss-Newton on the distance
+// residuals.
+func EstimatePosition(refs []LocationOffset, velocityFactor float64) (PositionEstimate, error) {
+	if len(refs) < MinTriangulationReferences {
+		return PositionEstimate{}, ErrTooFewReferences
+	}
+
+	var lat0, lng0 float64
+	for _, r := range refs {
+		lat0 += r.Lat
+		lng0 += r.Lng
+	}
+	lat0 /= float64(len(refs))
+	lng0 /= float64(len(refs))
+	kmPerDegLat := earthRadiusKm * math.Pi / 180
+	kmPerDegLng := kmPerDegLat * math.Cos(lat0*math.Pi/180)
+
+	xs := make([]float64, len(refs))
+	ys := make([]float64, len(refs))
+	rs := make([]float64, len(refs))
+	for i, r := range refs {
+		xs[i] = (r.Lng - lng0) * kmPerDegLng
+		ys[i] = (r.Lat - lat0) * kmPerDegLat
+		rs[i] = RTTDistanceKm(r.RttNs, velocityFactor)
+	}
+
+	// Linear solution: subtracting the first circle equation from the others
+	// gives 2(xi-x0)x + 2(yi-y0)y = r0²-ri² + xi²-x0² + yi²-y0².
+	var a11, a12, a22, b1, b2 float64
+	for i := 1; i < len(refs); i++ {
+		ax := 2 * (xs[i] - xs[0])
+		ay := 2 * (ys[i] - ys[0])
+		b := rs[0]*rs[0] - rs[i]*rs[i] + xs[i]*xs[i] - xs[0]*xs[0] + ys[i]*ys[i] - ys[0]*ys[0]
+		a11 += ax * ax
+		a12 += ax * ay
+		a22 += ay * ay
+		b1 += ax * b
+		b2 += ay * b
+	}
+	x, y, ok := solve2x2(a11, a12, a22, b1, b2)
+	if !ok {
+		return PositionEstimate{}, ErrDegenerateReferences
+	}
+
+	// Gauss-Newton refinement of sum((|p - pi| - ri)²).
+	for range triangulationMaxIterations {
+		var j11, j12, j22, g1, g2 float64
+		for i := range refs {
+			dx, dy := x-xs[i], y-ys[i]
+			d := math.Hypot(dx, dy)
+			if d == 0 {
+				continue
+			}
+			jx, jy := dx/d, dy/d
+			res := d - rs[i]
+			j11 += jx * jx
+			j12 += jx * jy
+			j22 += jy * jy
+			g1 += jx * res
+			g2 += jy * res
+		}
+		sx, sy, ok := solve2x2(j11, j12, j22, g1, g2)
+		if !ok {
+			break
+		}
+		x -= sx
+		y -= sy
+		if math.Hypot(sx, sy) < triangulationTolerance {
+			break
+		}
+	}
+
+	var sumSq float64
+	for i := range refs {
+		res := math.Hypot(x-xs[i], y-ys[i]) - rs[i]
+		sumSq += res * res
+	}
+
+	return PositionEstimate{
+		Lat:           lat0 + y/kmPerDegLat,
+		Lng:           lng0 + x/kmPerDegLng,
+		ResidualKm:    math.Sqrt(sumSq / float64(len(refs))),
+		NumReferences: len(refs),
+	}, nil
+}
+
+// solve2x2 solves the symmetric system [a11 a12; a12 a22][x y] = [b1 b2].
+func solve2x2(a11, a12, a22, b1, b2 float64) (float64, float64, bool) {
+	det := a11*a22 - a12*a12
+	if a11 == 0 || a22 == 0 || math.Abs(det) <= 1e-9*a11*a22 {
+		return 0, 0, false
+	}
+	return (a22*b1 - a12*b2) / det, (a11*b2 - a12*b1) / det, true
+}
@@ -0,0 +1,143 @@
+package geoprobe
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// haversineKm returns the great-circle distance between two points.
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLng := (lng2 - lng1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// syntheticRef returns a reference offset at (lat, lng) whose RTT corresponds
+// to the great-circle distance to the target plus extraKm.
+func syntheticRef(lat, lng, targetLat, targetLng, extraKm, velocityFactor float64) LocationOffset {
+	d := haversineKm(lat, lng, targetLat, targetLng) + extraKm
+	rttNs := 2 * d / (speedOfLightKmPerMs * velocityFactor) * 1e6
+	return LocationOffset{Lat: lat, Lng: lng, RttNs: uint64(rttNs)}
+}
+
+func TestRTTDistanceKm(t *testing.T) {
+	// 10ms RTT is 5ms one-way: ~1499km at c, ~999km at 2/3c.
+	if got := RTTDistanceKm(10_000_000, 1); math.Abs(got-1498.96) > 0.01 {
+		t.Errorf("RTTDistanceKm(10ms, 1) = %f, want ~1498.96", got)
+	}
+	if got := RTTDistanceKm(10_000_000, DefaultTriangulationVelocityFactor); math.Abs(got-999.31) > 0.01 {
+		t.Errorf("RTTDistanceKm(10ms, 2/3) = %f, want ~999.31", got)
+	}
+}
+
+func TestEstimatePosition(t *testing.T) {
+	vf := DefaultTriangulationVelocityFactor
+
+	tests := []struct {
+		name        string
+		targetLat   float64
+		targetLng   float64
+		refs        [][2]float64
+		extraKm     []float64
+		maxErrorKm  float64
+		maxResidual float64
+	}{
+		{
+			name:        "exact distances around the target",
+			targetLat:   50.1,
+			targetLng:   8.7,
+			refs:        [][2]float64{{52.4, 4.9}, {48.9, 2.4}, {48.1, 11.6}, {52.5, 13.4}},
+			maxErrorKm:  15,
+			maxResidual: 15,
+		},
+		{
+			name:        "three references",
+			targetLat:   40.7,
+			targetLng:   -74.0,
+			refs:        [][2]float64{{41.9, -87.6}, {38.9, -77.0}, {42.4, -71.1}},
+			maxErrorKm:  25,
+			maxResidual: 15,
+		},
+		{
+			name:        "noisy distances",
+			targetLat:   50.1,
+			targetLng:   8.7,
+			refs:        [][2]float64{{52.4, 4.9}, {48.9, 2.4}, {48.1, 11.6}, {52.5, 13.4}, {47.4, 8.5}},
+			extraKm:     []float64{12, 5, 20, 8, 3},
+			maxErrorKm:  30,
+			maxResidual: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var refs []LocationOffset
+			for i, p := range tt.refs {
+				extra := 0.0
+				if i < len(tt.extraKm) {
+					extra = tt.extraKm[i]
+				}
+				refs = append(refs, syntheticRef(p[0], p[1], tt.targetLat, tt.targetLng, extra, vf))
+			}
+
+			est, err := EstimatePosition(refs, vf)
+			if err != nil {
+				t.Fatalf("EstimatePosition: %v", err)
+			}
+			if est.NumReferences != len(refs) {
+				t.Errorf("NumReferences = %d, want %d", est.NumReferences, len(refs))
+			}
+			if d := haversineKm(est.Lat, est.Lng, tt.targetLat, tt.targetLng); d > tt.maxErrorKm {
+				t.Errorf("estimate (%f, %f) is %.1fkm from target (%f, %f), want <= %.0fkm",
+					est.Lat, est.Lng, d, tt.targetLat, tt.targetLng, tt.maxErrorKm)
+			}
+			if est.ResidualKm > tt.maxResidual {
+				t.Errorf("ResidualKm = %f, want <= %f", est.ResidualKm, tt.maxResidual)
+			}
+
+			// The estimate must be tighter than the best single reference's radius.
+			best := refs[0]
+			for _, r := range refs[1:] {
+				if r.RttNs < best.RttNs {
+					best = r
+				}
+			}
+			if d := haversineKm(est.Lat, est.Lng, tt.targetLat, tt.targetLng); d >= RTTDistanceKm(best.RttNs, vf) {
+				t.Errorf("estimate error %.1fkm is not tighter than best reference radius %.1fkm", d, RTTDistanceKm(best.RttNs, vf))
+			}
+		})
+	}
+}
+
+func TestEstimatePosition_TooFewReferences(t *testing.T) {
+	refs := []LocationOffset{{Lat: 50, Lng: 8, RttNs: 1_000_000}, {Lat: 52, Lng: 4, RttNs: 2_000_000}}
+	if _, err := EstimatePosition(refs, DefaultTriangulationVelocityFactor); !errors.Is(err, ErrTooFewReferences) {
+		t.Errorf("expected ErrTooFewReferences, got %v", err)
+	}
+}
+
+func TestEstimatePosition_Degenerate(t *testing.T) {
+	tests := map[string][]LocationOffset{
+		"coincident": {
+			{Lat: 50, Lng: 8, RttNs: 1_000_000},
+			{Lat: 50, Lng: 8, RttNs: 2_000_000},
+			{Lat: 50, Lng: 8, RttNs: 3_000_000},
+		},
+		"collinear": {
+			{Lat: 48, Lng: 8, RttNs: 1_000_000},
+			{Lat: 50, Lng: 8, RttNs: 2_000_000},
+			{Lat: 52, Lng: 8, RttNs: 3_000_000},
+		},
+	}
+	for name, refs := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := EstimatePosition(refs, DefaultTriangulationVelocityFactor); !errors.Is(err, ErrDegenerateReferences) {
+				t.Errorf("expected ErrDegenerateReferences, got %v", err)
+			}
+		})
+	}
+}
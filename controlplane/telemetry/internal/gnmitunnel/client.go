@@ -56,6 +56,12 @@ type Config struct {
 	TLS                   *TLSConfig            // Optional, defaults to TLS enabled
 	InitialBackoff        time.Duration         // Optional, defaults to 1s
 	MaxBackoff            time.Duration         // Optional, defaults to 1m
+
+	// LocalHealthCheckTimeout, when set, makes the client wait up to this long
+	// for LocalDialAddr to accept connections before registering, and reject
+	// sessions while it does not. Zero disables the health check.
+	LocalHealthCheckTimeout  time.Duration
+	LocalHealthCheckInterval time.Duration // Optional, defaults to 1s; also bounds each check's dial
 }
 
 func (c *Config) setDefaults() {
@@ -108,6 +114,9 @@ func (c *Config) setDefaults() {
 	if c.MaxBackoff <= 0 {
 		c.MaxBackoff = time.Minute
 	}
+	if c.LocalHealthCheckInterval <= 0 {
+		c.LocalHealthCheckInterval = time.Second
+	}
 }
 
 func (c *Config) makeTransportCredentials(logger *slog.Logger) (credentials.TransportCredentials, error) {
@@ -256,6 +265,11 @@ func (c *Client) Run(ctx context.Context) error {
 }
 
 func (c *Client) connect(ctx context.Context) error {
+	// Don't advertise the target until the local gNMI server can serve it.
+	if err := c.waitForLocal(ctx); err != nil {
+		return err
+	}
+
 	creds, err := c.cfg.makeTransportCredentials(c.log)
 	if err != nil {
 		return fmt.Errorf("configure TLS: %w", err)
@@ -279,6 +293,12 @@ func (c *Client) connect(ctx context.Context) error {
 			if t.ID != c.cfg.TargetID {
 				return fmt.Errorf("unexpected target: %s", t.ID)
 			}
+			if c.cfg.LocalHealthCheckTimeout > 0 {
+				if err := c.checkLocal(ctx); err != nil {
+					c.log.Warn("rejecting session, local target not reachable", "addr", c.cfg.LocalDialAddr, "error", err)
+					return fmt.Errorf("local target not reachable: %w", err)
+				}
+			}
 			return nil
 		},
 		Handler: func(t tunnel.Target, rwc io.ReadWriteCloser) error {
@@ -303,12 +323,7 @@ func (c *Client) handleSession(ctx context.Context, t tunnel.Target, rwc io.Read
 		return fmt.Errorf("unexpected target: %s/%s", t.ID, t.Type)
 	}
 
-	network := "unix"
-	if len(c.cfg.LocalDialAddr) > 0 && c.cfg.LocalDialAddr[0] != '/' {
-		network = "tcp"
-	}
-
-	conn, err := c.cfg.LocalDialer(ctx, network, c.cfg.LocalDialAddr)
+	conn, err := c.cfg.LocalDialer(ctx, c.localNetwork(), c.cfg.LocalDialAddr)
 	if err != nil {
 		return fmt.Errorf("dial local: %w", err)
 	}
@@ -0,0 +1,61 @@
+package gnmitunnel
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// localNetwork returns the network for LocalDialAddr: tcp for host:port
+// addresses, unix for socket paths.
+func (c *Client) localNetwork() string {
+	if len(c.cfg.LocalDialAddr) > 0 && c.cfg.LocalDialAddr[0] != '/' {
+		return "tcp"
+	}
+	return "unix"
+}
+
+// checkLocal dials the local target once and closes the connection, returning
+// an error if it does not accept connections within LocalHealthCheckInterval.
+func (c *Client) checkLocal(ctx context.Context) error {
+	dialCtx, cancel := context.WithTimeout(ctx, c.cfg.LocalHealthCheckInterval)
+	defer cancel()
+	conn, err := c.cfg.LocalDialer(dialCtx, c.localNetwork(), c.cfg.LocalDialAddr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// waitForLocal polls the local target until it accepts a connection, giving up
+// after LocalHealthCheckTimeout. It returns immediately when the health check
+// is disabled.
+func (c *Client) waitForLocal(ctx context.Context) error {
+	if c.cfg.LocalHealthCheckTimeout <= 0 {
+		return nil
+	}
+
+	deadline := time.NewTimer(c.cfg.LocalHealthCheckTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(c.cfg.LocalHealthCheckInterval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
+		err := c.checkLocal(ctx)
+		if err == nil {
+			if attempt > 1 {
+				c.log.Info("local target is reachable", "addr", c.cfg.LocalDialAddr, "attempts", attempt)
+			}
+			return nil
+		}
+		c.log.Debug("local target not reachable yet", "addr", c.cfg.LocalDialAddr, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("local target %s not reachable after %s: %w", c.cfg.LocalDialAddr, c.cfg.LocalHealthCheckTimeout, err)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package gnmitunnel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestClient_WaitForLocal_DelayedThenAvailable(t *testing.T) {
+	t.Parallel()
+
+	// Reserve a port, then free it so the first checks fail.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	listening := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			close(listening)
+			return
+		}
+		listening <- l
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	var dials atomic.Int64
+	cfg := validConfig()
+	cfg.LocalDialAddr = addr
+	cfg.LocalDialer = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials.Add(1)
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}
+	cfg.LocalHealthCheckTimeout = 5 * time.Second
+	cfg.LocalHealthCheckInterval = 20 * time.Millisecond
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, client.waitForLocal(context.Background()))
+	require.Greater(t, dials.Load(), int64(1), "should retry until the local target is up")
+
+	l, ok := <-listening
+	require.True(t, ok, "failed to re-listen on %s", addr)
+	l.Close()
+}
+
+func TestClient_WaitForLocal_Timeout(t *testing.T) {
+	t.Parallel()
+
+	cfg := validConfig()
+	cfg.LocalHealthCheckTimeout = 100 * time.Millisecond
+	cfg.LocalHealthCheckInterval = 10 * time.Millisecond
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	err = client.waitForLocal(context.Background())
+	require.ErrorContains(t, err, "not reachable after 100ms")
+}
+
+func TestClient_WaitForLocal_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var dials atomic.Int64
+	cfg := validConfig()
+	cfg.LocalDialer = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dials.Add(1)
+		return nil, errors.New("mock")
+	}
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, client.waitForLocal(context.Background()))
+	require.Zero(t, dials.Load())
+}
+
+func TestClient_Run_WaitsForLocalBeforeRegistering(t *testing.T) {
+	t.Parallel()
+
+	var serverDials atomic.Int64
+	cfg := validConfig()
+	cfg.GRPCClientConnFactory = func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+		serverDials.Add(1)
+		return nil, errors.New("mock")
+	}
+	cfg.LocalHealthCheckTimeout = time.Minute
+	cfg.LocalHealthCheckInterval = 10 * time.Millisecond
+
+	client, err := NewClient(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, client.Run(ctx))
+	require.Zero(t, serverDials.Load(), "should not contact the tunnel server while the local target is down")
+}
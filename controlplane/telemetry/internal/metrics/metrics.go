@@ -10,6 +10,7 @@ const (
 	MetricNameBuildInfo                        = "doublezero_device_telemetry_agent_build_info"
 	MetricNameErrors                           = "doublezero_device_telemetry_agent_errors_total"
 	MetricNamePeerDiscoveryLocalTunnelNotFound = "doublezero_device_telemetry_agent_peer_discovery_not_found_tunnels"
+	MetricNameReflectorRecoverableErrors       = "doublezero_device_telemetry_agent_twamp_reflector_recoverable_errors_total"
 
 	// Labels.
 	LabelVersion       = "version"
@@ -13,7 +13,7 @@ import (
 
 	"github.com/m-lab/tcp-info/inetdiag"
 	mtcp "github.com/m-lab/tcp-info/tcp"
-	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netns"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/mdlayher/netlink"
 	"golang.org/x/sys/unix"
 )
@@ -144,6 +144,17 @@ func (c *Collector) Run(ctx context.Context) error {
 		}
 	}()
 
+	// Check the reflector answers before probing peers, so a misconfigured
+	// reflector shows up at startup rather than as bad samples.
+	if err := c.runReflectorSelfTest(runCtx); err != nil {
+		cancel()
+		wg.Wait()
+		if cerr := c.Close(); cerr != nil {
+			c.log.Warn("Failed to close telemetry collector", "error", cerr)
+		}
+		return err
+	}
+
 	// Start the peer discovery component in the background.
 	wg.Add(1)
 	go func() {
@@ -260,6 +271,7 @@ type senderEntry struct {
 	lastUsed          time.Time
 	createdAt         time.Time
 	consecutiveLosses int
+	dscp              uint8
 }
 
 func (c *Collector) getOrCreateSender(ctx context.Context, peer *Peer) twamplight.Sender {
@@ -271,7 +283,8 @@ func (c *Collector) getOrCreateSender(ctx context.Context, peer *Peer) twampligh
 	if ok {
 		entry.lastUsed = now
 		ttl := c.cfg.SenderTTL
-		if ttl > 0 && now.Sub(entry.createdAt) >= ttl {
+		// Recreate the sender if it expired or the peer's DSCP class changed.
+		if (ttl > 0 && now.Sub(entry.createdAt) >= ttl) || entry.dscp != peer.DSCP {
 			_ = entry.sender.Close()
 			delete(c.senders, key)
 		} else {
@@ -284,7 +297,7 @@ func (c *Collector) getOrCreateSender(ctx context.Context, peer *Peer) twampligh
 
 	sourceAddr := &net.UDPAddr{IP: peer.Tunnel.SourceIP, Port: 0}
 	targetAddr := &net.UDPAddr{IP: peer.Tunnel.TargetIP, Port: int(peer.TWAMPPort)}
-	sender, err := twamplight.NewSender(ctx, c.log, peer.Tunnel.Interface, sourceAddr, targetAddr)
+	sender, err := twamplight.NewSender(ctx, c.log, peer.Tunnel.Interface, sourceAddr, targetAddr, twamplight.WithDSCP(peer.DSCP))
 	if err != nil {
 		c.log.Error("Failed to create sender", "error", err)
 		return nil
@@ -295,6 +308,7 @@ func (c *Collector) getOrCreateSender(ctx context.Context, peer *Peer) twampligh
 		sender:    sender,
 		lastUsed:  c.cfg.NowFunc(),
 		createdAt: c.cfg.NowFunc(),
+		dscp:      peer.DSCP,
 	}
 	c.sendersMu.Unlock()
 
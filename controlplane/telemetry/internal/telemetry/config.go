@@ -74,6 +74,19 @@ type Config struct {
 
 	// AgentCommit is the short git commit hash of this telemetry agent binary.
 	AgentCommit string
+
+	// ReflectorSelfTest enables probing the local TWAMP reflector at startup,
+	// before peers are probed, so a misconfigured reflector is logged instead
+	// of silently producing bad samples.
+	ReflectorSelfTest bool
+
+	// ReflectorSelfTestRequired stops the collector if the reflector
+	// self-test fails. Otherwise the failure is only logged.
+	ReflectorSelfTestRequired bool
+
+	// ReflectorSelfTestMaxRTT is the largest loopback RTT the reflector
+	// self-test accepts. Defaults to DefaultReflectorSelfTestMaxRTT.
+	ReflectorSelfTestMaxRTT time.Duration
 }
 
 func (c *Config) Validate() error {
@@ -115,6 +128,9 @@ func (c *Config) Validate() error {
 	if c.MaxConsecutiveSenderLosses <= 0 {
 		c.MaxConsecutiveSenderLosses = 30
 	}
+	if c.ReflectorSelfTestMaxRTT <= 0 {
+		c.ReflectorSelfTestMaxRTT = DefaultReflectorSelfTestMaxRTT
+	}
 
 	geoprobeEnabled := c.GeolocationClient != nil
 	if geoprobeEnabled {
@@ -39,6 +39,10 @@ func (m *mockReflector) LocalAddr() *net.UDPAddr {
 	return &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0}
 }
 
+func (m *mockReflector) RecoverableErrors() uint64 {
+	return 0
+}
+
 type mockPeerDiscovery struct{}
 
 func (m *mockPeerDiscovery) Run(ctx context.Context) error {
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// PeersDebugPath is the path NewPeersDebugHandler is served on.
+const PeersDebugPath = "/debug/peers"
+
+// PeersDebugResponse is the JSON body served by NewPeersDebugHandler.
+type PeersDebugResponse struct {
+	// LastRefresh is when the peer set was last refreshed, if the discovery
+	// reports it and has refreshed at least once.
+	LastRefresh *time.Time       `json:"last_refresh,omitempty"`
+	Peers       []PeersDebugPeer `json:"peers"`
+}
+
+// PeersDebugPeer describes one discovered peer.
+type PeersDebugPeer struct {
+	LinkPK      string `json:"link_pk"`
+	LinkCode    string `json:"link_code"`
+	DevicePK    string `json:"device_pk"`
+	DeviceCode  string `json:"device_code"`
+	TunnelFound bool   `json:"tunnel_found"`
+	Interface   string `json:"interface,omitempty"`
+	SourceIP    string `json:"source_ip,omitempty"`
+	TargetIP    string `json:"target_ip,omitempty"`
+	TWAMPPort   uint16 `json:"twamp_port"`
+	DSCP        uint8  `json:"dscp"`
+}
+
+// NewPeersDebugHandler returns a handler serving the current peer set of
+// discovery as JSON, sorted by link code. Peers without a local tunnel are
+// included with tunnel_found=false, since they are not probed.
+func NewPeersDebugHandler(discovery PeerDiscovery) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := PeersDebugResponse{Peers: make([]PeersDebugPeer, 0)}
+		if d, ok := discovery.(interface{ LastRefresh() time.Time }); ok {
+			if t := d.LastRefresh(); !t.IsZero() {
+				resp.LastRefresh = &t
+			}
+		}
+
+		for _, peer := range discovery.GetPeers() {
+			p := PeersDebugPeer{
+				LinkPK:     peer.LinkPK.String(),
+				LinkCode:   peer.LinkCode,
+				DevicePK:   peer.DevicePK.String(),
+				DeviceCode: peer.DeviceCode,
+				TWAMPPort:  peer.TWAMPPort,
+				DSCP:       peer.DSCP,
+			}
+			if peer.Tunnel != nil {
+				p.TunnelFound = true
+				p.Interface = peer.Tunnel.Interface
+				p.SourceIP = peer.Tunnel.SourceIP.String()
+				p.TargetIP = peer.Tunnel.TargetIP.String()
+			}
+			resp.Peers = append(resp.Peers, p)
+		}
+		sort.Slice(resp.Peers, func(i, j int) bool {
+			if resp.Peers[i].LinkCode != resp.Peers[j].LinkCode {
+				return resp.Peers[i].LinkCode < resp.Peers[j].LinkCode
+			}
+			return resp.Peers[i].LinkPK < resp.Peers[j].LinkPK
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(resp)
+	})
+}
@@ -0,0 +1,137 @@
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netutil"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/telemetry"
+	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentTelemetry_PeersDebugHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serves discovered peers", func(t *testing.T) {
+		t.Parallel()
+
+		log := log.With("test", t.Name())
+		localDevicePK := stringToPubkey("device1")
+
+		discovery, err := telemetry.NewLedgerPeerDiscovery(&telemetry.LedgerPeerDiscoveryConfig{
+			Logger:          log,
+			LocalDevicePK:   localDevicePK,
+			TWAMPPort:       12345,
+			RefreshInterval: 10 * time.Millisecond,
+			ProgramClient: &mockServiceabilityProgramClient{
+				GetProgramDataFunc: func(ctx context.Context) (*serviceability.ProgramData, error) {
+					return &serviceability.ProgramData{
+						Devices: []serviceability.Device{
+							{PubKey: localDevicePK, Code: "ams01"},
+							{PubKey: stringToPubkey("device2"), Code: "fra01"},
+							{PubKey: stringToPubkey("device3"), Code: "lon01"},
+						},
+						Links: []serviceability.Link{
+							{PubKey: stringToPubkey("link_1-3"), Code: "ams01:lon01", LinkType: serviceability.LinkLinkTypeWAN, Status: serviceability.LinkStatusActivated, SideAPubKey: localDevicePK, SideZPubKey: stringToPubkey("device3"), TunnelNet: [5]uint8{10, 1, 1, 2, 31}},
+							{PubKey: stringToPubkey("link_1-2"), Code: "ams01:fra01", LinkType: serviceability.LinkLinkTypeWAN, Status: serviceability.LinkStatusActivated, SideAPubKey: localDevicePK, SideZPubKey: stringToPubkey("device2"), TunnelNet: [5]uint8{10, 1, 1, 0, 31}},
+						},
+					}, nil
+				},
+			},
+			LocalNet: &netutil.MockLocalNet{
+				InterfacesFunc: func() ([]netutil.Interface, error) {
+					// Only the tunnel to device2 exists locally.
+					return []netutil.Interface{{
+						Name:  "tun1-2",
+						Addrs: []net.Addr{&net.IPNet{IP: ipv4([4]uint8{10, 1, 1, 0}), Mask: net.CIDRMask(31, 32)}},
+					}}, nil
+				},
+			},
+			TWAMPDSCPByLinkType: map[serviceability.LinkLinkType]uint8{serviceability.LinkLinkTypeWAN: 46},
+		})
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(telemetry.NewPeersDebugHandler(discovery))
+		defer srv.Close()
+
+		// Before the first refresh the peer set is empty and has no refresh time.
+		got := getPeersDebug(t, srv.URL)
+		assert.Nil(t, got.LastRefresh)
+		assert.Empty(t, got.Peers)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- discovery.Run(ctx)
+		}()
+		require.Eventually(t, func() bool {
+			return len(discovery.GetPeers()) == 2
+		}, 2*time.Second, 10*time.Millisecond)
+		cancel()
+		require.NoError(t, <-errCh)
+
+		got = getPeersDebug(t, srv.URL)
+		require.NotNil(t, got.LastRefresh)
+		assert.WithinDuration(t, time.Now(), *got.LastRefresh, time.Minute)
+		assert.Equal(t, []telemetry.PeersDebugPeer{
+			{
+				LinkPK:      stringToPubkey("link_1-2").String(),
+				LinkCode:    "ams01:fra01",
+				DevicePK:    stringToPubkey("device2").String(),
+				DeviceCode:  "fra01",
+				TunnelFound: true,
+				Interface:   "tun1-2",
+				SourceIP:    "10.1.1.0",
+				TargetIP:    "10.1.1.1",
+				TWAMPPort:   12345,
+				DSCP:        46,
+			},
+			{
+				LinkPK:     stringToPubkey("link_1-3").String(),
+				LinkCode:   "ams01:lon01",
+				DevicePK:   stringToPubkey("device3").String(),
+				DeviceCode: "lon01",
+				TWAMPPort:  12345,
+				DSCP:       46,
+			},
+		}, got.Peers)
+	})
+
+	t.Run("discovery without refresh time", func(t *testing.T) {
+		t.Parallel()
+
+		discovery := newMockPeerDiscovery()
+		discovery.UpdatePeers(t, []*telemetry.Peer{
+			{LinkPK: stringToPubkey("link"), DevicePK: stringToPubkey("device"), TWAMPPort: 862},
+		})
+
+		srv := httptest.NewServer(telemetry.NewPeersDebugHandler(discovery))
+		defer srv.Close()
+
+		got := getPeersDebug(t, srv.URL)
+		assert.Nil(t, got.LastRefresh)
+		require.Len(t, got.Peers, 1)
+		assert.False(t, got.Peers[0].TunnelFound)
+		assert.Equal(t, uint16(862), got.Peers[0].TWAMPPort)
+	})
+}
+
+func getPeersDebug(t *testing.T, url string) telemetry.PeersDebugResponse {
+	t.Helper()
+	resp, err := http.Get(url + telemetry.PeersDebugPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body telemetry.PeersDebugResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"net"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/metrics"
 	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netutil"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
+	twamplight "github.com/malbeclabs/doublezero/tools/twamp/pkg/light"
 )
 
 type Peer struct {
@@ -21,6 +24,14 @@ type Peer struct {
 	DevicePK  solana.PublicKey
 	Tunnel    *netutil.LocalTunnel
 	TWAMPPort uint16
+
+	// LinkCode and DeviceCode are the onchain codes of the link and remote
+	// device, for display only.
+	LinkCode   string
+	DeviceCode string
+
+	// DSCP is the DSCP value to mark probes to this peer with; 0 is best effort.
+	DSCP uint8
 }
 
 func (p *Peer) String() string {
@@ -43,6 +54,51 @@ type LedgerPeerDiscoveryConfig struct {
 	LocalNet        netutil.LocalNet
 	TWAMPPort       uint16
 	RefreshInterval time.Duration
+
+	// TWAMPDSCP is the DSCP value for probes over links without an entry in
+	// TWAMPDSCPByLinkType. Defaults to 0 (best effort).
+	TWAMPDSCP uint8
+
+	// TWAMPDSCPByLinkType overrides TWAMPDSCP per link type, so probes match
+	// the traffic class of production traffic over that kind of link.
+	TWAMPDSCPByLinkType map[serviceability.LinkLinkType]uint8
+}
+
+// dscpForLink returns the probe DSCP for the given link.
+func (c *LedgerPeerDiscoveryConfig) dscpForLink(link serviceability.Link) uint8 {
+	if dscp, ok := c.TWAMPDSCPByLinkType[link.LinkType]; ok {
+		return dscp
+	}
+	return c.TWAMPDSCP
+}
+
+// ParseDSCPByLinkType parses a comma-separated list of <link-type>=<dscp>
+// pairs, e.g. "wan=46,dzx=0". Link types are matched case-insensitively.
+func ParseDSCPByLinkType(s string) (map[serviceability.LinkLinkType]uint8, error) {
+	result := make(map[serviceability.LinkLinkType]uint8)
+	if strings.TrimSpace(s) == "" {
+		return result, nil
+	}
+	linkTypes := map[string]serviceability.LinkLinkType{
+		"wan": serviceability.LinkLinkTypeWAN,
+		"dzx": serviceability.LinkLinkTypeDZX,
+	}
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q (expected <link-type>=<dscp>)", pair)
+		}
+		linkType, ok := linkTypes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown link type %q (expected wan or dzx)", name)
+		}
+		dscp, err := strconv.ParseUint(strings.TrimSpace(value), 10, 8)
+		if err != nil || dscp > twamplight.MaxDSCP {
+			return nil, fmt.Errorf("invalid DSCP %q for link type %s: must be 0-%d", value, name, twamplight.MaxDSCP)
+		}
+		result[linkType] = uint8(dscp)
+	}
+	return result, nil
 }
 
 // ledgerPeerDiscovery implements the PeerDiscovery interface by periodically
@@ -57,6 +113,8 @@ type ledgerPeerDiscovery struct {
 	config  *LedgerPeerDiscoveryConfig
 	peers   []*Peer
 	peersMu sync.RWMutex
+
+	lastRefresh time.Time
 }
 
 func NewLedgerPeerDiscovery(cfg *LedgerPeerDiscoveryConfig) (*ledgerPeerDiscovery, error) {
@@ -81,6 +139,14 @@ func NewLedgerPeerDiscovery(cfg *LedgerPeerDiscoveryConfig) (*ledgerPeerDiscover
 	if cfg.RefreshInterval == 0 {
 		return nil, errors.New("RefreshInterval is required")
 	}
+	if cfg.TWAMPDSCP > twamplight.MaxDSCP {
+		return nil, fmt.Errorf("TWAMPDSCP must be 0-%d", twamplight.MaxDSCP)
+	}
+	for linkType, dscp := range cfg.TWAMPDSCPByLinkType {
+		if dscp > twamplight.MaxDSCP {
+			return nil, fmt.Errorf("TWAMPDSCPByLinkType[%s] must be 0-%d", linkType, twamplight.MaxDSCP)
+		}
+	}
 
 	return &ledgerPeerDiscovery{
 		log:    cfg.Logger,
@@ -114,6 +180,14 @@ func (p *ledgerPeerDiscovery) GetPeers() []*Peer {
 	return slices.Clone(p.peers)
 }
 
+// LastRefresh returns when the peer set was last successfully refreshed, or
+// the zero time if it has not been yet.
+func (p *ledgerPeerDiscovery) LastRefresh() time.Time {
+	p.peersMu.RLock()
+	defer p.peersMu.RUnlock()
+	return p.lastRefresh
+}
+
 func (p *ledgerPeerDiscovery) refresh(ctx context.Context) error {
 	data, err := p.config.ProgramClient.GetProgramData(ctx)
 	if err != nil {
@@ -203,10 +277,16 @@ func (p *ledgerPeerDiscovery) refresh(ctx context.Context) error {
 			DevicePK:  solana.PublicKeyFromBytes(device.PubKey[:]),
 			Tunnel:    tunnel,
 			TWAMPPort: p.config.TWAMPPort,
+
+			LinkCode:   link.Code,
+			DeviceCode: device.Code,
+
+			DSCP: p.config.dscpForLink(link),
 		})
 	}
 
 	p.peers = peers
+	p.lastRefresh = time.Now()
 	p.log.Debug("Refreshed peers", "devices", len(devices), "links", len(links), "peers", len(peers), "tunnelsNotFound", tunnelsNotFound)
 
 	// Record the number of tunnels not found.
@@ -564,6 +564,69 @@ func TestAgentTelemetry_PeerDiscovery_Ledger(t *testing.T) {
 		}, 2*time.Second, 50*time.Millisecond, "peer should be removed after link disappears")
 	})
 
+	t.Run("sets DSCP per link type", func(t *testing.T) {
+		t.Parallel()
+
+		log := log.With("test", t.Name())
+		localDevicePK := stringToPubkey("device1")
+
+		serviceabilityProgram := &mockServiceabilityProgramClient{
+			GetProgramDataFunc: func(ctx context.Context) (*serviceability.ProgramData, error) {
+				return &serviceability.ProgramData{
+					Devices: []serviceability.Device{
+						{PubKey: localDevicePK, PublicIp: [4]uint8{192, 168, 1, 1}},
+						{PubKey: stringToPubkey("device2"), PublicIp: [4]uint8{192, 168, 1, 2}},
+						{PubKey: stringToPubkey("device3"), PublicIp: [4]uint8{192, 168, 1, 3}},
+					},
+					Links: []serviceability.Link{
+						{PubKey: stringToPubkey("link_1-2"), Status: serviceability.LinkStatusActivated, LinkType: serviceability.LinkLinkTypeWAN, SideAPubKey: localDevicePK, SideZPubKey: stringToPubkey("device2"), TunnelNet: [5]uint8{10, 1, 1, 0, 31}},
+						{PubKey: stringToPubkey("link_1-3"), Status: serviceability.LinkStatusActivated, LinkType: serviceability.LinkLinkTypeDZX, SideAPubKey: localDevicePK, SideZPubKey: stringToPubkey("device3"), TunnelNet: [5]uint8{10, 1, 1, 2, 31}},
+					},
+				}, nil
+			},
+		}
+
+		config := &telemetry.LedgerPeerDiscoveryConfig{
+			Logger:          log,
+			LocalDevicePK:   localDevicePK,
+			TWAMPPort:       1234,
+			RefreshInterval: 100 * time.Millisecond,
+			ProgramClient:   serviceabilityProgram,
+			LocalNet: &netutil.MockLocalNet{
+				InterfacesFunc: func() ([]netutil.Interface, error) {
+					return []netutil.Interface{}, nil
+				},
+			},
+			TWAMPDSCP:           10,
+			TWAMPDSCPByLinkType: map[serviceability.LinkLinkType]uint8{serviceability.LinkLinkTypeWAN: 46},
+		}
+
+		peers, err := telemetry.NewLedgerPeerDiscovery(config)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- peers.Run(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			return len(peers.GetPeers()) == 2
+		}, 2*time.Second, 50*time.Millisecond)
+
+		cancel()
+		assert.NoError(t, <-errCh)
+
+		dscpByLink := map[solana.PublicKey]uint8{}
+		for _, peer := range peers.GetPeers() {
+			dscpByLink[peer.LinkPK] = peer.DSCP
+		}
+		assert.Equal(t, map[solana.PublicKey]uint8{
+			stringToPubkey("link_1-2"): 46,
+			stringToPubkey("link_1-3"): 10,
+		}, dscpByLink)
+	})
+
 	t.Run("invalid config", func(t *testing.T) {
 		t.Parallel()
 
@@ -616,9 +679,37 @@ func TestAgentTelemetry_PeerDiscovery_Ledger(t *testing.T) {
 		cfg = valid
 		cfg.RefreshInterval = 0
 		base(cfg, "zero refresh interval")
+
+		cfg = valid
+		cfg.TWAMPDSCP = 64
+		base(cfg, "out of range TWAMP DSCP")
+
+		cfg = valid
+		cfg.TWAMPDSCPByLinkType = map[serviceability.LinkLinkType]uint8{serviceability.LinkLinkTypeWAN: 64}
+		base(cfg, "out of range TWAMP DSCP by link type")
 	})
 }
 
+func TestAgentTelemetry_ParseDSCPByLinkType(t *testing.T) {
+	t.Parallel()
+
+	got, err := telemetry.ParseDSCPByLinkType("")
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	got, err = telemetry.ParseDSCPByLinkType("WAN=46, dzx=10")
+	require.NoError(t, err)
+	require.Equal(t, map[serviceability.LinkLinkType]uint8{
+		serviceability.LinkLinkTypeWAN: 46,
+		serviceability.LinkLinkTypeDZX: 10,
+	}, got)
+
+	for _, invalid := range []string{"wan", "wan=", "wan=64", "wan=-1", "lan=10"} {
+		_, err := telemetry.ParseDSCPByLinkType(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
 func ipv4(bytes [4]uint8) net.IP {
 	return net.IP{bytes[0], bytes[1], bytes[2], bytes[3]}
 }
@@ -87,6 +87,7 @@ func (p *Pinger) Tick(ctx context.Context) {
 					Timestamp: ts,
 					RTT:       0,
 					Loss:      true,
+					DSCP:      peer.DSCP,
 				})
 				return
 			}
@@ -100,6 +101,7 @@ func (p *Pinger) Tick(ctx context.Context) {
 					Timestamp: ts,
 					RTT:       0,
 					Loss:      true,
+					DSCP:      peer.DSCP,
 				})
 				return
 			}
@@ -123,6 +125,7 @@ func (p *Pinger) Tick(ctx context.Context) {
 					Timestamp: ts,
 					RTT:       0,
 					Loss:      true,
+					DSCP:      peer.DSCP,
 				})
 				if p.cfg.RecordProbeResult != nil {
 					p.cfg.RecordProbeResult(peer, false)
@@ -134,6 +137,7 @@ func (p *Pinger) Tick(ctx context.Context) {
 				Timestamp: ts,
 				RTT:       rtt,
 				Loss:      false,
+				DSCP:      peer.DSCP,
 			})
 			if p.cfg.RecordProbeResult != nil {
 				p.cfg.RecordProbeResult(peer, true)
@@ -44,6 +44,7 @@ func TestAgentTelemetry_Pinger(t *testing.T) {
 					SourceIP:  ipv4([4]uint8{127, 0, 0, 1}),
 					TargetIP:  ipv4([4]uint8{127, 0, 0, 2}),
 				},
+				DSCP: 46,
 			},
 		})
 
@@ -76,6 +77,7 @@ func TestAgentTelemetry_Pinger(t *testing.T) {
 		require.Len(t, s, 1)
 		assert.False(t, s[0].Loss)
 		assert.Equal(t, 42*time.Millisecond, s[0].RTT)
+		assert.Equal(t, uint8(46), s[0].DSCP)
 	})
 
 	t.Run("records loss when tunnel is nil", func(t *testing.T) {
@@ -91,6 +93,7 @@ func TestAgentTelemetry_Pinger(t *testing.T) {
 				DevicePK: peerPK,
 				LinkPK:   linkPK,
 				Tunnel:   nil,
+				DSCP:     46,
 			},
 		})
 
@@ -114,6 +117,7 @@ func TestAgentTelemetry_Pinger(t *testing.T) {
 				require.Len(t, val, 1)
 				assert.True(t, val[0].Loss)
 				assert.Zero(t, val[0].RTT)
+				assert.Equal(t, uint8(46), val[0].DSCP)
 				found = true
 			}
 		}
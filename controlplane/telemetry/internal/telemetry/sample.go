@@ -13,4 +13,9 @@ type Sample struct {
 
 	// Loss is true if the probe was lost.
 	Loss bool `json:"loss"`
+
+	// DSCP is the DSCP value the probe was marked with. It is only kept in
+	// memory and on the debug endpoint: the on-chain DeviceLatencySamples
+	// account stores bare RTTs, so submitted samples do not carry it.
+	DSCP uint8 `json:"dscp"`
 }
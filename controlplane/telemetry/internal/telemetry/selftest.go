@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	twamplight "github.com/malbeclabs/doublezero/tools/twamp/pkg/light"
+)
+
+const (
+	// DefaultReflectorSelfTestMaxRTT is the largest loopback RTT the reflector
+	// self-test accepts by default. Loopback probes normally return in well
+	// under a millisecond.
+	DefaultReflectorSelfTestMaxRTT = 100 * time.Millisecond
+
+	reflectorSelfTestAttempts = 3
+)
+
+// ReflectorSelfTest probes the TWAMP reflector listening on reflectorAddr from
+// the local host, and returns the RTT of the first probe answered with an RTT
+// in (0, maxRTT]. A reflector listening on the unspecified address is probed
+// at 127.0.0.1. Each of up to three probes waits at most timeout; if none
+// passes, the error describes every attempt.
+func ReflectorSelfTest(ctx context.Context, log *slog.Logger, reflectorAddr *net.UDPAddr, timeout, maxRTT time.Duration) (time.Duration, error) {
+	if reflectorAddr == nil {
+		return 0, errors.New("reflector has no local address")
+	}
+	target := &net.UDPAddr{IP: reflectorAddr.IP, Port: reflectorAddr.Port}
+	if target.IP == nil || target.IP.IsUnspecified() {
+		target.IP = net.IPv4(127, 0, 0, 1)
+	}
+
+	sender, err := twamplight.NewSender(ctx, log, "", nil, target)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sender to %s: %w", target, err)
+	}
+	defer sender.Close()
+
+	var errs []error
+	for attempt := 1; attempt <= reflectorSelfTestAttempts; attempt++ {
+		probeCtx, cancel := context.WithTimeout(ctx, timeout)
+		rtt, err := sender.Probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("attempt %d: %w", attempt, err))
+		case rtt <= 0 || rtt > maxRTT:
+			errs = append(errs, fmt.Errorf("attempt %d: rtt %s outside (0, %s]", attempt, rtt, maxRTT))
+		default:
+			return rtt, nil
+		}
+	}
+	return 0, fmt.Errorf("no sane response from reflector at %s: %w", target, errors.Join(errs...))
+}
+
+// runReflectorSelfTest runs the reflector self-test if enabled, logging the
+// outcome. It returns an error only if the self-test failed and is required.
+func (c *Collector) runReflectorSelfTest(ctx context.Context) error {
+	if !c.cfg.ReflectorSelfTest {
+		return nil
+	}
+
+	rtt, err := ReflectorSelfTest(ctx, c.log, c.reflector.LocalAddr(), c.cfg.TWAMPSenderTimeout, c.cfg.ReflectorSelfTestMaxRTT)
+	if err == nil {
+		c.log.Info("TWAMP reflector self-test passed", "reflector", c.reflector.LocalAddr(), "rtt", rtt)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	c.log.Error("TWAMP reflector self-test failed; peers will not get valid latency samples from this device. Check the reflector listen port and local firewall rules",
+		"reflector", c.reflector.LocalAddr(),
+		"required", c.cfg.ReflectorSelfTestRequired,
+		"error", err)
+	if c.cfg.ReflectorSelfTestRequired {
+		return fmt.Errorf("twamp reflector self-test failed: %w", err)
+	}
+	return nil
+}
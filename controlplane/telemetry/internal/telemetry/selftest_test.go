@@ -0,0 +1,162 @@
+package telemetry_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/telemetry"
+	twamplight "github.com/malbeclabs/doublezero/tools/twamp/pkg/light"
+	"github.com/stretchr/testify/require"
+)
+
+// brokenReflector listens like a reflector but never answers, as when the
+// reflector is bound to the wrong port or its replies are filtered.
+type brokenReflector struct {
+	conn *net.UDPConn
+}
+
+func newBrokenReflector(t *testing.T) *brokenReflector {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return &brokenReflector{conn: conn}
+}
+
+func (r *brokenReflector) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r *brokenReflector) Close() error              { return r.conn.Close() }
+func (r *brokenReflector) LocalAddr() *net.UDPAddr   { return r.conn.LocalAddr().(*net.UDPAddr) }
+func (r *brokenReflector) RecoverableErrors() uint64 { return 0 }
+
+func runTestReflector(t *testing.T, reflector twamplight.Reflector) {
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = reflector.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestAgentTelemetry_ReflectorSelfTest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("working reflector", func(t *testing.T) {
+		t.Parallel()
+
+		reflector := newTestReflector(t)
+		runTestReflector(t, reflector)
+
+		rtt, err := telemetry.ReflectorSelfTest(t.Context(), log, reflector.LocalAddr(), time.Second, telemetry.DefaultReflectorSelfTestMaxRTT)
+		require.NoError(t, err)
+		require.Greater(t, rtt, time.Duration(0))
+		require.LessOrEqual(t, rtt, telemetry.DefaultReflectorSelfTestMaxRTT)
+	})
+
+	t.Run("reflector on unspecified address is probed over loopback", func(t *testing.T) {
+		t.Parallel()
+
+		reflector, err := twamplight.NewReflector(log, "0.0.0.0:0", time.Second)
+		require.NoError(t, err)
+		t.Cleanup(func() { reflector.Close() })
+		runTestReflector(t, reflector)
+
+		_, err = telemetry.ReflectorSelfTest(t.Context(), log, reflector.LocalAddr(), time.Second, telemetry.DefaultReflectorSelfTestMaxRTT)
+		require.NoError(t, err)
+	})
+
+	t.Run("broken reflector", func(t *testing.T) {
+		t.Parallel()
+
+		reflector := newBrokenReflector(t)
+
+		_, err := telemetry.ReflectorSelfTest(t.Context(), log, reflector.LocalAddr(), 100*time.Millisecond, telemetry.DefaultReflectorSelfTestMaxRTT)
+		require.ErrorContains(t, err, "no sane response from reflector")
+		require.ErrorContains(t, err, "attempt 3")
+	})
+
+	t.Run("rtt above maximum", func(t *testing.T) {
+		t.Parallel()
+
+		reflector := newTestReflector(t)
+		runTestReflector(t, reflector)
+
+		_, err := telemetry.ReflectorSelfTest(t.Context(), log, reflector.LocalAddr(), time.Second, time.Nanosecond)
+		require.ErrorContains(t, err, "outside (0, 1ns]")
+	})
+}
+
+func TestAgentTelemetry_Collector_ReflectorSelfTest(t *testing.T) {
+	t.Parallel()
+
+	newCollector := func(t *testing.T, reflector twamplight.Reflector, required bool) *telemetry.Collector {
+		collector, err := telemetry.New(log, telemetry.Config{
+			LocalDevicePK:             stringToPubkey("device"),
+			ProbeInterval:             100 * time.Millisecond,
+			SubmissionInterval:        time.Second,
+			TWAMPSenderTimeout:        100 * time.Millisecond,
+			SenderTTL:                 time.Minute,
+			SubmitterMaxConcurrency:   10,
+			TWAMPReflector:            reflector,
+			PeerDiscovery:             newMockPeerDiscovery(),
+			TelemetryProgramClient:    newMemoryTelemetryProgramClient(),
+			GetCurrentEpochFunc:       func(ctx context.Context) (uint64, error) { return 100, nil },
+			ReflectorSelfTest:         true,
+			ReflectorSelfTestRequired: required,
+		})
+		require.NoError(t, err)
+		return collector
+	}
+
+	t.Run("required self-test failure stops the collector", func(t *testing.T) {
+		t.Parallel()
+
+		collector := newCollector(t, newBrokenReflector(t), true)
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- collector.Run(t.Context()) }()
+
+		select {
+		case err := <-errCh:
+			require.ErrorContains(t, err, "twamp reflector self-test failed")
+		case <-time.After(5 * time.Second):
+			t.Fatal("collector did not stop after a failed required self-test")
+		}
+	})
+
+	t.Run("optional self-test failure is only logged", func(t *testing.T) {
+		t.Parallel()
+
+		collector := newCollector(t, newBrokenReflector(t), false)
+		ctx, cancel := context.WithCancel(t.Context())
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- collector.Run(ctx) }()
+
+		require.Never(t, func() bool { return len(errCh) > 0 }, time.Second, 50*time.Millisecond)
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+
+	t.Run("working reflector passes", func(t *testing.T) {
+		t.Parallel()
+
+		collector := newCollector(t, newTestReflector(t), true)
+		ctx, cancel := context.WithCancel(t.Context())
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- collector.Run(ctx) }()
+
+		require.Never(t, func() bool { return len(errCh) > 0 }, time.Second, 50*time.Millisecond)
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+}
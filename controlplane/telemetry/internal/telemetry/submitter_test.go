@@ -879,4 +879,80 @@ func TestAgentTelemetry_Submitter(t *testing.T) {
 		assert.Len(t, got, 0, "failed samples should be dropped when requeue would meet capacity exactly")
 	})
 
+	t.Run("submits_samples_buffered_across_epochs_to_their_own_accounts", func(t *testing.T) {
+		t.Parallel()
+
+		log := log.With("test", t.Name())
+
+		// Samples measured in epochs 99 and 100 are still buffered when the
+		// ledger becomes reachable again in epoch 101.
+		origin := solana.NewWallet().PublicKey()
+		target := solana.NewWallet().PublicKey()
+		link := solana.NewWallet().PublicKey()
+		key99 := telemetry.PartitionKey{OriginDevicePK: origin, TargetDevicePK: target, LinkPK: link, Epoch: 99}
+		key100 := telemetry.PartitionKey{OriginDevicePK: origin, TargetDevicePK: target, LinkPK: link, Epoch: 100}
+
+		buf := buffer.NewMemoryPartitionedBuffer[telemetry.PartitionKey, telemetry.Sample](1024)
+		for i := range 3 {
+			buf.Add(key99, telemetry.Sample{Timestamp: time.Now(), RTT: time.Duration(10+i) * time.Microsecond})
+		}
+		for i := range 2 {
+			buf.Add(key100, telemetry.Sample{Timestamp: time.Now(), RTT: time.Duration(20+i) * time.Microsecond})
+		}
+
+		var mu sync.Mutex
+		var outage atomic.Bool
+		outage.Store(true)
+		initialized := map[uint64]bool{100: true}
+		written := map[uint64][]uint32{}
+		prog := &mockTelemetryProgramClient{
+			InitializeDeviceLatencySamplesFunc: func(_ context.Context, config sdktelemetry.InitializeDeviceLatencySamplesInstructionConfig) (solana.Signature, *solanarpc.GetTransactionResult, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				initialized[*config.Epoch] = true
+				return solana.Signature{}, nil, nil
+			},
+			WriteDeviceLatencySamplesFunc: func(_ context.Context, config sdktelemetry.WriteDeviceLatencySamplesInstructionConfig) (solana.Signature, *solanarpc.GetTransactionResult, error) {
+				if outage.Load() {
+					return solana.Signature{}, nil, errors.New("ledger unreachable")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if !initialized[*config.Epoch] {
+					return solana.Signature{}, nil, sdktelemetry.ErrAccountNotFound
+				}
+				written[*config.Epoch] = append(written[*config.Epoch], config.Samples...)
+				return solana.Signature{}, nil, nil
+			},
+		}
+
+		s, err := telemetry.NewSubmitter(log, &telemetry.SubmitterConfig{
+			Interval:        time.Hour,
+			Buffer:          buf,
+			ProgramClient:   prog,
+			MaxAttempts:     1,
+			MaxConcurrency:  10,
+			BackoffFunc:     func(int) time.Duration { return 0 },
+			GetCurrentEpoch: func(context.Context) (uint64, error) { return 101, nil },
+		})
+		require.NoError(t, err)
+
+		// Submission fails during the outage and the samples stay buffered per epoch.
+		s.Tick(context.Background())
+		require.Equal(t, 3, buf.Len(key99))
+		require.Equal(t, 2, buf.Len(key100))
+
+		outage.Store(false)
+		s.Tick(context.Background())
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []uint32{10, 11, 12}, written[99])
+		assert.Equal(t, []uint32{20, 21}, written[100])
+		assert.NotContains(t, written, uint64(101), "no samples should be attributed to the current epoch")
+		assert.True(t, initialized[99], "the missing epoch 99 account should be initialized for its own samples")
+		assert.NotContains(t, initialized, uint64(101))
+		assert.Equal(t, 0, buf.Len(key99))
+		assert.Equal(t, 0, buf.Len(key100))
+	})
 }
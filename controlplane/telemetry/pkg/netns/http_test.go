@@ -11,7 +11,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/malbeclabs/doublezero/controlplane/telemetry/internal/netns"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/stretchr/testify/require"
 )
 
@@ -0,0 +1,45 @@
+package netns
+
+// Namespace runs functions inside a network namespace.
+type Namespace interface {
+	// Name returns the namespace name, or "" for the current namespace.
+	Name() string
+	// Do runs fn with the calling goroutine's OS thread switched into the
+	// namespace, restoring the original namespace afterwards. Sockets created by
+	// fn stay in the namespace. The returned error only reports namespace
+	// switching failures, in which case fn is not run.
+	Do(fn func()) error
+}
+
+// New returns the Namespace for name, which must name an entry under
+// /var/run/netns/. An empty name returns a Namespace that runs functions in the
+// current namespace.
+func New(name string) Namespace {
+	if name == "" {
+		return current{}
+	}
+	return named(name)
+}
+
+type current struct{}
+
+func (current) Name() string { return "" }
+
+func (current) Do(fn func()) error {
+	fn()
+	return nil
+}
+
+type named string
+
+func (n named) Name() string { return string(n) }
+
+// Do locks the OS thread for the duration of fn, so fn should not block for
+// longer than the socket setup and I/O it needs.
+func (n named) Do(fn func()) error {
+	_, err := RunInNamespace(string(n), func() (struct{}, error) {
+		fn()
+		return struct{}{}, nil
+	})
+	return err
+}
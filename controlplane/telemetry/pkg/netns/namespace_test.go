@@ -0,0 +1,28 @@
+package netns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespace_New(t *testing.T) {
+	t.Parallel()
+
+	ns := New("")
+	require.Equal(t, "", ns.Name())
+	ran := false
+	require.NoError(t, ns.Do(func() { ran = true }))
+	require.True(t, ran)
+
+	require.Equal(t, "dz-mgmt", New("dz-mgmt").Name())
+}
+
+func TestNamespace_DoMissingNamespace(t *testing.T) {
+	t.Parallel()
+
+	ran := false
+	err := New("netns-test-does-not-exist").Do(func() { ran = true })
+	require.ErrorContains(t, err, `"netns-test-does-not-exist"`)
+	require.False(t, ran, "fn must not run when the namespace cannot be entered")
+}
@@ -43,6 +43,9 @@ func RunInNamespace[T any](nsName string, fn func() (T, error)) (T, error) {
 	result, fnErr := fn()
 
 	if err := netns.Set(origNS); err != nil {
+		// The thread is left in the wrong namespace; keep it locked so the
+		// runtime discards it instead of reusing it.
+		runtime.LockOSThread()
 		return zero, fmt.Errorf("restore original netns: %w", err)
 	}
 
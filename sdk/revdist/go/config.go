@@ -1,6 +1,11 @@
 package revdist
 
-import "github.com/gagliardetto/solana-go"
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gagliardetto/solana-go"
+)
 
 // ProgramID is the revenue distribution program ID (same across all environments).
 var ProgramID = solana.MustPublicKeyFromBase58("dzrevZC94tBLwuHw1dyynZxaXTWyp7yocsinyEVPtt4")
@@ -13,6 +18,12 @@ var SolanaRPCURLs = map[string]string{
 	"localnet":     "http://localhost:8899",
 }
 
+// OracleURLs are the SOL/2Z oracle API URLs per environment.
+var OracleURLs = map[string]string{
+	"mainnet-beta": "https://sol-2z-oracle-api-v1.mainnet-beta.doublezero.xyz",
+	"testnet":      "https://sol-2z-oracle-api-v1.testnet.doublezero.xyz",
+}
+
 // LedgerRPCURLs are the DZ Ledger RPC URLs per environment.
 var LedgerRPCURLs = map[string]string{
 	"mainnet-beta": "https://doublezero-mainnet-beta.rpcpool.com/db336024-e7a8-46b1-80e5-352dd77060ab",
@@ -20,3 +31,43 @@ var LedgerRPCURLs = map[string]string{
 	"devnet":       "https://doublezerolocalnet.rpcpool.com/8a4fd3f4-0977-449f-88c7-63d4b0f10f16",
 	"localnet":     "http://localhost:8899",
 }
+
+// ResolveSolanaRPCURL returns override if set, otherwise the Solana RPC URL for
+// env. An override must be an absolute http or https URL.
+func ResolveSolanaRPCURL(env, override string) (string, error) {
+	return resolveURL("solana rpc", SolanaRPCURLs, env, override)
+}
+
+// ResolveOracleURL returns override if set, otherwise the SOL/2Z oracle API URL
+// for env. An override must be an absolute http or https URL.
+func ResolveOracleURL(env, override string) (string, error) {
+	return resolveURL("oracle", OracleURLs, env, override)
+}
+
+func resolveURL(name string, urls map[string]string, env, override string) (string, error) {
+	if override != "" {
+		if err := validateURL(override); err != nil {
+			return "", fmt.Errorf("invalid %s url %q: %w", name, override, err)
+		}
+		return override, nil
+	}
+	u, ok := urls[env]
+	if !ok {
+		return "", fmt.Errorf("no %s url for environment: %s", name, env)
+	}
+	return u, nil
+}
+
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
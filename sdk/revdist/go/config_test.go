@@ -0,0 +1,52 @@
+package revdist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSolanaRPCURL(t *testing.T) {
+	got, err := ResolveSolanaRPCURL("testnet", "")
+	require.NoError(t, err)
+	assert.Equal(t, SolanaRPCURLs["testnet"], got)
+
+	got, err = ResolveSolanaRPCURL("testnet", "https://rpc.example.com/key")
+	require.NoError(t, err)
+	assert.Equal(t, "https://rpc.example.com/key", got, "override takes precedence over the env default")
+
+	// An override does not need a known environment.
+	got, err = ResolveSolanaRPCURL("", "http://10.0.0.1:8899")
+	require.NoError(t, err)
+	assert.Equal(t, "http://10.0.0.1:8899", got)
+
+	_, err = ResolveSolanaRPCURL("nonexistent", "")
+	assert.ErrorContains(t, err, "no solana rpc url for environment: nonexistent")
+}
+
+func TestResolveOracleURL(t *testing.T) {
+	got, err := ResolveOracleURL("mainnet-beta", "")
+	require.NoError(t, err)
+	assert.Equal(t, OracleURLs["mainnet-beta"], got)
+
+	got, err = ResolveOracleURL("mainnet-beta", "https://oracle.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "https://oracle.example.com", got, "override takes precedence over the env default")
+
+	// Oracle URLs only exist for mainnet-beta and testnet.
+	_, err = ResolveOracleURL("devnet", "")
+	assert.ErrorContains(t, err, "no oracle url for environment: devnet")
+}
+
+func TestResolveURL_InvalidOverride(t *testing.T) {
+	for _, override := range []string{
+		"rpc.example.com",
+		"ftp://rpc.example.com",
+		"https://",
+		"http://[::1",
+	} {
+		_, err := ResolveSolanaRPCURL("mainnet-beta", override)
+		assert.ErrorContains(t, err, "invalid solana rpc url", "override %q", override)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	revdist "github.com/malbeclabs/doublezero/sdk/revdist/go"
+)
+
+func main() {
+	env := flag.String("env", "mainnet-beta", "Environment: mainnet-beta, testnet, devnet, localnet")
+	rpcURL := flag.String("rpc", "", "Solana RPC URL (overrides --env)")
+	expand := flag.Bool("expand", false, "Print one row per (service key, recipient, share) instead of one row per contributor")
+	jsonOutput := flag.Bool("json", false, "Print JSON instead of a table")
+	flag.Parse()
+
+	validEnvs := map[string]bool{"mainnet-beta": true, "testnet": true, "devnet": true, "localnet": true}
+	if !validEnvs[*env] {
+		fmt.Fprintf(os.Stderr, "Invalid environment: %s\n", *env)
+		os.Exit(1)
+	}
+
+	url, err := revdist.ResolveSolanaRPCURL(*env, *rpcURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	client := revdist.New(revdist.NewRPCClient(url), revdist.ProgramID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rewards, err := client.FetchAllContributorRewards(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching contributor rewards: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *expand {
+		rows := revdist.ExpandRecipientShares(rewards)
+		if *jsonOutput {
+			printJSON(rows)
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SERVICE KEY\tRECIPIENT\tSHARE")
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", row.ServiceKey, row.RecipientKey, revdist.FormatShare(row.Share))
+		}
+		tw.Flush()
+		return
+	}
+
+	if *jsonOutput {
+		type contributor struct {
+			ServiceKey        string                              `json:"service_key"`
+			RewardsManagerKey string                              `json:"rewards_manager_key"`
+			Recipients        []revdist.ContributorRecipientShare `json:"recipients"`
+		}
+		out := make([]contributor, 0, len(rewards))
+		for _, r := range rewards {
+			out = append(out, contributor{
+				ServiceKey:        r.ServiceKey.String(),
+				RewardsManagerKey: r.RewardsManagerKey.String(),
+				Recipients:        revdist.ExpandRecipientShares([]revdist.ContributorRewards{r}),
+			})
+		}
+		printJSON(out)
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE KEY\tREWARDS MANAGER\tRECIPIENTS")
+	for _, r := range rewards {
+		var recipients []string
+		for _, row := range revdist.ExpandRecipientShares([]revdist.ContributorRewards{r}) {
+			recipients = append(recipients, fmt.Sprintf("%s:%s", row.RecipientKey, revdist.FormatShare(row.Share)))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.ServiceKey, r.RewardsManagerKey, strings.Join(recipients, ", "))
+	}
+	tw.Flush()
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
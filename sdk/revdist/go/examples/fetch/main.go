@@ -13,7 +13,9 @@ import (
 
 func main() {
 	env := flag.String("env", "mainnet-beta", "Environment: mainnet-beta, testnet, devnet, localnet")
+	rpcURL := flag.String("rpc", "", "Solana RPC URL (overrides --env)")
 	epoch := flag.Uint64("epoch", 0, "Specific epoch to fetch distribution for (0 = use latest from config)")
+	summaryOnly := flag.Bool("summary-only", false, "Only print the validator deposits summary, not individual deposits")
 	flag.Parse()
 
 	validEnvs := map[string]bool{"mainnet-beta": true, "testnet": true, "devnet": true, "localnet": true}
@@ -24,7 +26,12 @@ func main() {
 
 	fmt.Printf("Fetching revenue distribution data from %s...\n\n", *env)
 
-	client := revdist.NewForEnv(*env)
+	url, err := revdist.ResolveSolanaRPCURL(*env, *rpcURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	client := revdist.New(revdist.NewRPCClient(url), revdist.ProgramID)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -107,14 +114,21 @@ func main() {
 		fmt.Printf("  Error: %v\n", err)
 	} else {
 		fmt.Printf("=== Validator Deposits (%d) ===\n", len(deposits))
-		for i, dep := range deposits {
-			if i >= 10 {
-				fmt.Printf("  ... and %d more\n", len(deposits)-10)
-				break
+		if !*summaryOnly {
+			for i, dep := range deposits {
+				if i >= 10 {
+					fmt.Printf("  ... and %d more\n", len(deposits)-10)
+					break
+				}
+				nodeID := solana.PublicKeyFromBytes(dep.NodeID[:])
+				fmt.Printf("  %s: written off debt %d\n", nodeID.String()[:16]+"...", dep.WrittenOffSOLDebt)
 			}
-			nodeID := solana.PublicKeyFromBytes(dep.NodeID[:])
-			fmt.Printf("  %s: written off debt %d\n", nodeID.String()[:16]+"...", dep.WrittenOffSOLDebt)
 		}
+		summary := revdist.SummarizeValidatorDeposits(deposits)
+		fmt.Printf("Deposits:                   %d\n", summary.Count)
+		fmt.Printf("Deposits With Write-offs:   %d\n", summary.WrittenOffCount)
+		fmt.Printf("Total Written-off Debt:     %d lamports (%.9f SOL)\n",
+			summary.TotalWrittenOffSOLDebt, float64(summary.TotalWrittenOffSOLDebt)/float64(solana.LAMPORTS_PER_SOL))
 	}
 	fmt.Println()
 
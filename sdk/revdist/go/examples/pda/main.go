@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gagliardetto/solana-go"
+	revdist "github.com/malbeclabs/doublezero/sdk/revdist/go"
+)
+
+func main() {
+	programIDStr := flag.String("program-id", revdist.ProgramID.String(), "Revenue distribution program ID")
+	jsonOutput := flag.Bool("json", false, "Print JSON instead of text")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	programID, err := solana.PublicKeyFromBase58(*programIDStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid program ID %q: %v\n", *programIDStr, err)
+		os.Exit(1)
+	}
+
+	kind := flag.Arg(0)
+	addr, bump, err := revdist.DerivePDA(programID, kind, flag.Args()[1:]...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out := struct {
+			Kind    string `json:"kind"`
+			Address string `json:"address"`
+			Bump    uint8  `json:"bump"`
+		}{kind, addr.String(), bump}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Address: %s\n", addr)
+	fmt.Printf("Bump:    %d\n", bump)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: pda [flags] <kind> [arg]\n\nKinds:\n")
+	for _, k := range revdist.PDAKinds {
+		if k.Arg == "" {
+			fmt.Fprintf(os.Stderr, "  %s\n", k.Name)
+		} else {
+			fmt.Fprintf(os.Stderr, "  %s <%s>\n", k.Name, k.Arg)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	revdist "github.com/malbeclabs/doublezero/sdk/revdist/go"
+)
+
+func main() {
+	env := flag.String("env", "mainnet-beta", "Environment: mainnet-beta, testnet, devnet, localnet")
+	rpcURL := flag.String("rpc", "", "Solana RPC URL (overrides --env)")
+	epochs := flag.Uint64("epochs", 10, "Number of most recent completed epochs to reconcile")
+	flag.Parse()
+
+	validEnvs := map[string]bool{"mainnet-beta": true, "testnet": true, "devnet": true, "localnet": true}
+	if !validEnvs[*env] {
+		fmt.Fprintf(os.Stderr, "Invalid environment: %s\n", *env)
+		os.Exit(1)
+	}
+	if *epochs == 0 {
+		fmt.Fprintln(os.Stderr, "--epochs must be greater than 0")
+		os.Exit(1)
+	}
+
+	url, err := revdist.ResolveSolanaRPCURL(*env, *rpcURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	client := revdist.New(revdist.NewRPCClient(url), revdist.ProgramID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	config, err := client.FetchConfig(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Reconcile the most recent completed epochs, newest first.
+	var targets []uint64
+	for epoch := config.NextCompletedDZEpoch; epoch > 0 && uint64(len(targets)) < *epochs; epoch-- {
+		targets = append(targets, epoch-1)
+	}
+
+	discrepancies, missing, err := client.Reconcile(ctx, targets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reconciling: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Reconciled journal against %d distributions on %s\n", len(targets)-len(missing), *env)
+	if len(missing) > 0 {
+		fmt.Printf("Skipped epochs without a distribution: %v\n", missing)
+	}
+	if len(discrepancies) == 0 {
+		fmt.Println("No discrepancies found")
+		return
+	}
+
+	fmt.Printf("Found %d discrepancies:\n", len(discrepancies))
+	for _, d := range discrepancies {
+		fmt.Printf("  %s\n", d)
+	}
+	os.Exit(2)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	revdist "github.com/malbeclabs/doublezero/sdk/revdist/go"
+)
+
+func main() {
+	env := flag.String("env", "mainnet-beta", "Environment: mainnet-beta, testnet")
+	oracleURL := flag.String("oracle-url", "", "Oracle API URL (overrides --env)")
+	watch := flag.Bool("watch", false, "Poll the oracle every --interval until interrupted")
+	interval := flag.Duration("interval", time.Minute, "Polling interval with --watch")
+	output := flag.String("output", "", "With --watch, append each observation to this file")
+	format := flag.String("format", "csv", "Output file format: csv or jsonl")
+	flag.Parse()
+
+	url, err := revdist.ResolveOracleURL(*env, *oracleURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	oracle := revdist.NewOracleClient(url)
+
+	if !*watch {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		rate, err := oracle.FetchSwapRate(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching swap rate: %v\n", err)
+			os.Exit(1)
+		}
+		printRate(rate)
+		return
+	}
+
+	var recorder *revdist.SwapRateRecorder
+	if *output != "" {
+		f, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening output file: %v\n", err)
+			os.Exit(1)
+		}
+		recorder, err = revdist.NewSwapRateRecorder(f, revdist.SwapRateFormat(*format), info.Size() == 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	err = revdist.WatchSwapRate(ctx, oracle, *interval, func(rate *revdist.SwapRate) error {
+		log.Info("swap rate",
+			"rate", rate.Rate,
+			"sol_price_usd", rate.SOLPriceUSD,
+			"twoz_price_usd", rate.TwoZPriceUSD,
+			"timestamp", rate.Timestamp,
+			"cache_hit", rate.CacheHit)
+		if recorder != nil {
+			return recorder.Record(rate)
+		}
+		return nil
+	}, func(err error) {
+		log.Error("failed to fetch swap rate", "error", err)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printRate(rate *revdist.SwapRate) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Swap Rate:\t%v\n", rate.Rate)
+	fmt.Fprintf(tw, "SOL Price (USD):\t%s\n", rate.SOLPriceUSD)
+	fmt.Fprintf(tw, "2Z Price (USD):\t%s\n", rate.TwoZPriceUSD)
+	fmt.Fprintf(tw, "Timestamp:\t%s\n", time.Unix(rate.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(tw, "Cache Hit:\t%v\n", rate.CacheHit)
+	tw.Flush()
+}
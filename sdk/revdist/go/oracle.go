@@ -2,9 +2,12 @@ package revdist
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -55,3 +58,115 @@ func (c *OracleClient) FetchSwapRate(ctx context.Context) (*SwapRate, error) {
 	}
 	return &rate, nil
 }
+
+// SwapRateFetcher fetches the current swap rate. *OracleClient implements it.
+type SwapRateFetcher interface {
+	FetchSwapRate(ctx context.Context) (*SwapRate, error)
+}
+
+// WatchSwapRate polls the oracle immediately and then every interval until ctx
+// is done, passing each observation to observe. Fetch errors are passed to
+// onError (if non-nil) and polling continues; an error from observe stops the
+// watch and is returned.
+func WatchSwapRate(ctx context.Context, oracle SwapRateFetcher, interval time.Duration, observe func(*SwapRate) error, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive: %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		rate, err := oracle.FetchSwapRate(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if onError != nil {
+				onError(err)
+			}
+		} else if err := observe(rate); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SwapRateFormat is the file format written by SwapRateRecorder.
+type SwapRateFormat string
+
+const (
+	SwapRateFormatCSV   SwapRateFormat = "csv"
+	SwapRateFormatJSONL SwapRateFormat = "jsonl"
+)
+
+var swapRateCSVHeader = []string{"timestamp", "swap_rate", "sol_price_usd", "twoz_price_usd", "cache_hit"}
+
+// SwapRateRecorder appends swap rate observations to a writer as CSV rows or
+// JSON lines.
+type SwapRateRecorder struct {
+	w          io.Writer
+	format     SwapRateFormat
+	csv        *csv.Writer
+	needHeader bool
+}
+
+// NewSwapRateRecorder returns a recorder writing format to w. For CSV, a
+// header row is written before the first observation if writeHeader is set,
+// which callers appending to an existing non-empty file should leave unset.
+func NewSwapRateRecorder(w io.Writer, format SwapRateFormat, writeHeader bool) (*SwapRateRecorder, error) {
+	r := &SwapRateRecorder{w: w, format: format}
+	switch format {
+	case SwapRateFormatCSV:
+		r.csv = csv.NewWriter(w)
+		r.needHeader = writeHeader
+	case SwapRateFormatJSONL:
+	default:
+		return nil, fmt.Errorf("unknown swap rate format %q (must be csv or jsonl)", format)
+	}
+	return r, nil
+}
+
+// Record appends one observation.
+func (r *SwapRateRecorder) Record(rate *SwapRate) error {
+	if r.format == SwapRateFormatJSONL {
+		line := struct {
+			Timestamp    int64   `json:"timestamp"`
+			Rate         float64 `json:"swap_rate"`
+			SOLPriceUSD  string  `json:"sol_price_usd"`
+			TwoZPriceUSD string  `json:"twoz_price_usd"`
+			CacheHit     bool    `json:"cache_hit"`
+		}{rate.Timestamp, rate.Rate, rate.SOLPriceUSD, rate.TwoZPriceUSD, rate.CacheHit}
+		if err := json.NewEncoder(r.w).Encode(line); err != nil {
+			return fmt.Errorf("writing swap rate: %w", err)
+		}
+		return nil
+	}
+
+	if r.needHeader {
+		if err := r.csv.Write(swapRateCSVHeader); err != nil {
+			return fmt.Errorf("writing swap rate header: %w", err)
+		}
+		r.needHeader = false
+	}
+	row := []string{
+		strconv.FormatInt(rate.Timestamp, 10),
+		strconv.FormatFloat(rate.Rate, 'f', -1, 64),
+		rate.SOLPriceUSD,
+		rate.TwoZPriceUSD,
+		strconv.FormatBool(rate.CacheHit),
+	}
+	if err := r.csv.Write(row); err != nil {
+		return fmt.Errorf("writing swap rate: %w", err)
+	}
+	r.csv.Flush()
+	if err := r.csv.Error(); err != nil {
+		return fmt.Errorf("writing swap rate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package revdist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOracle serves the given responses from /swap-rate in order, repeating the
+// last one. A nil response returns a 500.
+func fakeOracle(t *testing.T, responses []*SwapRate) *httptest.Server {
+	t.Helper()
+	var (
+		mu sync.Mutex
+		i  int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		resp := responses[min(i, len(responses)-1)]
+		i++
+		mu.Unlock()
+		if resp == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// watchN runs WatchSwapRate against the fake oracle until n observations have
+// been recorded, returning the number of fetch errors seen.
+func watchN(t *testing.T, srv *httptest.Server, rec *SwapRateRecorder, n int) int {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var observed, errs int
+	err := WatchSwapRate(ctx, NewOracleClient(srv.URL), time.Millisecond, func(rate *SwapRate) error {
+		if err := rec.Record(rate); err != nil {
+			return err
+		}
+		observed++
+		if observed == n {
+			cancel()
+		}
+		return nil
+	}, func(error) { errs++ })
+	if err != nil {
+		t.Fatalf("WatchSwapRate: %v", err)
+	}
+	if observed != n {
+		t.Fatalf("observed %d rates, want %d", observed, n)
+	}
+	return errs
+}
+
+func testSwapRates() []*SwapRate {
+	return []*SwapRate{
+		{Rate: 1000, Timestamp: 1700000000, SOLPriceUSD: "150.25", TwoZPriceUSD: "0.15"},
+		nil,
+		{Rate: 1010, Timestamp: 1700000060, SOLPriceUSD: "151.5", TwoZPriceUSD: "0.15", CacheHit: true},
+		nil,
+		nil,
+		{Rate: 995.5, Timestamp: 1700000120, SOLPriceUSD: "149", TwoZPriceUSD: "0.1497"},
+	}
+}
+
+func TestWatchSwapRate_CSV(t *testing.T) {
+	srv := fakeOracle(t, testSwapRates())
+
+	var buf bytes.Buffer
+	rec, err := NewSwapRateRecorder(&buf, SwapRateFormatCSV, true)
+	if err != nil {
+		t.Fatalf("NewSwapRateRecorder: %v", err)
+	}
+	if errs := watchN(t, srv, rec, 3); errs != 3 {
+		t.Errorf("fetch errors = %d, want 3", errs)
+	}
+
+	want := strings.Join([]string{
+		"timestamp,swap_rate,sol_price_usd,twoz_price_usd,cache_hit",
+		"1700000000,1000,150.25,0.15,false",
+		"1700000060,1010,151.5,0.15,true",
+		"1700000120,995.5,149,0.1497,false",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Errorf("recorded series:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWatchSwapRate_JSONL(t *testing.T) {
+	srv := fakeOracle(t, testSwapRates())
+
+	var buf bytes.Buffer
+	rec, err := NewSwapRateRecorder(&buf, SwapRateFormatJSONL, true)
+	if err != nil {
+		t.Fatalf("NewSwapRateRecorder: %v", err)
+	}
+	watchN(t, srv, rec, 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	wantRates := []float64{1000, 1010, 995.5}
+	for i, line := range lines {
+		var got struct {
+			Timestamp int64   `json:"timestamp"`
+			Rate      float64 `json:"swap_rate"`
+			CacheHit  bool    `json:"cache_hit"`
+		}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if got.Rate != wantRates[i] {
+			t.Errorf("line %d rate = %v, want %v", i, got.Rate, wantRates[i])
+		}
+		if got.CacheHit != (i == 1) {
+			t.Errorf("line %d cache_hit = %v", i, got.CacheHit)
+		}
+	}
+}
+
+func TestWatchSwapRate_ObserveErrorStops(t *testing.T) {
+	srv := fakeOracle(t, testSwapRates())
+
+	wantErr := fmt.Errorf("disk full")
+	err := WatchSwapRate(context.Background(), NewOracleClient(srv.URL), time.Millisecond, func(*SwapRate) error {
+		return wantErr
+	}, nil)
+	if err != wantErr {
+		t.Errorf("WatchSwapRate error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestNewSwapRateRecorder_CSVAppendWithoutHeader(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewSwapRateRecorder(&buf, SwapRateFormatCSV, false)
+	if err != nil {
+		t.Fatalf("NewSwapRateRecorder: %v", err)
+	}
+	if err := rec.Record(&SwapRate{Rate: 1, Timestamp: 2, SOLPriceUSD: "3", TwoZPriceUSD: "4"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if got, want := buf.String(), "2,1,3,4,false\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := NewSwapRateRecorder(&buf, "xml", true); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -3,6 +3,9 @@ package revdist
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/mr-tron/base58"
@@ -67,3 +70,67 @@ func DeriveValidatorDepositPDA(programID solana.PublicKey, nodeID solana.PublicK
 func DeriveContributorRewardsPDA(programID solana.PublicKey, serviceKey solana.PublicKey) (solana.PublicKey, uint8, error) {
 	return solana.FindProgramAddress([][]byte{seedContributorRewards, serviceKey.Bytes()}, programID)
 }
+
+// PDAKind describes an account kind accepted by DerivePDA.
+type PDAKind struct {
+	Name string // kind name, e.g. "distribution"
+	Arg  string // seed argument the kind takes, or "" if none
+}
+
+// PDAKinds lists the account kinds DerivePDA accepts.
+var PDAKinds = []PDAKind{
+	{Name: "config"},
+	{Name: "journal"},
+	{Name: "distribution", Arg: "epoch"},
+	{Name: "deposit", Arg: "node-id"},
+	{Name: "contributor", Arg: "service-key"},
+}
+
+// DerivePDA derives the address and bump of a program account by kind name, parsing the seed
+// argument from its string form: a DZ epoch for "distribution", a validator node ID for "deposit"
+// and a contributor service key for "contributor". "config" and "journal" take no argument.
+func DerivePDA(programID solana.PublicKey, kind string, args ...string) (solana.PublicKey, uint8, error) {
+	var want *PDAKind
+	for i := range PDAKinds {
+		if PDAKinds[i].Name == kind {
+			want = &PDAKinds[i]
+			break
+		}
+	}
+	if want == nil {
+		names := make([]string, len(PDAKinds))
+		for i, k := range PDAKinds {
+			names[i] = k.Name
+		}
+		return solana.PublicKey{}, 0, fmt.Errorf("unknown account kind %q (want one of %s)", kind, strings.Join(names, ", "))
+	}
+	if want.Arg == "" && len(args) != 0 {
+		return solana.PublicKey{}, 0, fmt.Errorf("%s takes no arguments, got %d", kind, len(args))
+	}
+	if want.Arg != "" && len(args) != 1 {
+		return solana.PublicKey{}, 0, fmt.Errorf("%s takes exactly one argument <%s>, got %d", kind, want.Arg, len(args))
+	}
+
+	switch kind {
+	case "config":
+		return DeriveConfigPDA(programID)
+	case "journal":
+		return DeriveJournalPDA(programID)
+	case "distribution":
+		epoch, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return solana.PublicKey{}, 0, fmt.Errorf("invalid epoch %q: must be a non-negative integer", args[0])
+		}
+		return DeriveDistributionPDA(programID, epoch)
+	case "deposit", "contributor":
+		key, err := solana.PublicKeyFromBase58(args[0])
+		if err != nil {
+			return solana.PublicKey{}, 0, fmt.Errorf("invalid %s %q: %w", want.Arg, args[0], err)
+		}
+		if kind == "deposit" {
+			return DeriveValidatorDepositPDA(programID, key)
+		}
+		return DeriveContributorRewardsPDA(programID, key)
+	}
+	return solana.PublicKey{}, 0, fmt.Errorf("unknown account kind %q", kind)
+}
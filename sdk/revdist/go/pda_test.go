@@ -1,6 +1,7 @@
 package revdist
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gagliardetto/solana-go"
@@ -104,3 +105,62 @@ func TestDeriveRecordKey(t *testing.T) {
 		t.Errorf("DeriveRecordKey = %s, want %s", got, want)
 	}
 }
+
+func TestDerivePDA_KnownValues(t *testing.T) {
+	key := "84s5hmJUjfRhsQ443M1iWnCfNNmLbQLHmWTRyHtxbQzw"
+
+	tests := []struct {
+		kind     string
+		args     []string
+		wantAddr string
+		wantBump uint8
+	}{
+		{"config", nil, "8hCG3Mc1wmCTJYGn4QzFWEmvevonGunRxBTH9qPg1Um9", 250},
+		{"journal", nil, "2x9zkLfiLAQLYeiibHp2ccSSsF4d8X5UQ3vtBDwbQhuo", 253},
+		{"distribution", []string{"42"}, "ER1mKNMu4BGRv9BhTJR86SUtA8QKGxeM2BxCX44Mnd3j", 253},
+		{"deposit", []string{key}, "7KHJ4Mf7kXFp4bHzXu9KDbRvqJLtjaqmHVDTN8e6dgSL", 255},
+		{"contributor", []string{key}, "5DfMKLWwZfroypHXTjM8vFCvnP3T2TAii2EsmURKfPn9", 253},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			addr, bump, err := DerivePDA(testProgramID, tt.kind, tt.args...)
+			if err != nil {
+				t.Fatalf("DerivePDA: %v", err)
+			}
+			if addr.String() != tt.wantAddr || bump != tt.wantBump {
+				t.Errorf("DerivePDA(%s) = %s (bump %d), want %s (bump %d)", tt.kind, addr, bump, tt.wantAddr, tt.wantBump)
+			}
+		})
+	}
+
+	// DerivePDA agrees with the typed derivation functions.
+	want, wantBump, _ := DeriveDistributionPDA(testProgramID, 42)
+	got, gotBump, _ := DerivePDA(testProgramID, "distribution", "42")
+	if got != want || gotBump != wantBump {
+		t.Errorf("DerivePDA(distribution) = %s, DeriveDistributionPDA = %s", got, want)
+	}
+}
+
+func TestDerivePDA_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown kind", "ledger", nil, `unknown account kind "ledger"`},
+		{"extra argument", "config", []string{"1"}, "config takes no arguments"},
+		{"missing argument", "distribution", nil, "distribution takes exactly one argument <epoch>"},
+		{"negative epoch", "distribution", []string{"-1"}, `invalid epoch "-1"`},
+		{"bad node id", "deposit", []string{"not-a-key"}, `invalid node-id "not-a-key"`},
+		{"bad service key", "contributor", []string{"0OIl"}, `invalid service-key "0OIl"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := DerivePDA(testProgramID, tt.kind, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DerivePDA error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package revdist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Reconciliation check names reported in Discrepancy.Check.
+const (
+	CheckLifetimeSwapped2Z     = "lifetime_swapped_2z"
+	CheckDistribution2ZOutflow = "distribution_2z_outflow"
+	CheckDistributionSOLDebt   = "distribution_sol_debt"
+)
+
+// Discrepancy is a violated invariant between the journal and distributions.
+// DZEpoch is nil for checks spanning all distributions.
+type Discrepancy struct {
+	Check   string
+	DZEpoch *uint64
+	Detail  string
+}
+
+func (d Discrepancy) String() string {
+	if d.DZEpoch != nil {
+		return fmt.Sprintf("%s (epoch %d): %s", d.Check, *d.DZEpoch, d.Detail)
+	}
+	return fmt.Sprintf("%s: %s", d.Check, d.Detail)
+}
+
+// LifetimeSwapped2Z returns the journal's lifetime swapped 2Z amount (u128).
+func (j *Journal) LifetimeSwapped2Z() *big.Int {
+	be := make([]byte, len(j.LifetimeSwapped2ZAmount))
+	for i, b := range j.LifetimeSwapped2ZAmount {
+		be[len(be)-1-i] = b
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// Reconcile checks the journal against a set of distributions and returns any
+// violated invariants. The distributions may be any subset of epochs:
+//
+//   - lifetime swapped 2Z in the journal is at least the 2Z converted from SOL
+//     across the given distributions;
+//   - per distribution, distributed plus burned 2Z does not exceed the 2Z
+//     collected (prepaid payments plus 2Z converted from SOL);
+//   - per distribution, collected SOL validator payments do not exceed the
+//     total SOL validator debt.
+func Reconcile(journal *Journal, distributions []Distribution) []Discrepancy {
+	var discrepancies []Discrepancy
+
+	converted := new(big.Int)
+	for _, dist := range distributions {
+		epoch := dist.DZEpoch
+		converted.Add(converted, new(big.Int).SetUint64(dist.Collected2ZConvertedFromSOL))
+
+		outflow := new(big.Int).Add(new(big.Int).SetUint64(dist.Distributed2ZAmount), new(big.Int).SetUint64(dist.Burned2ZAmount))
+		inflow := new(big.Int).Add(new(big.Int).SetUint64(dist.CollectedPrepaid2ZPayments), new(big.Int).SetUint64(dist.Collected2ZConvertedFromSOL))
+		if outflow.Cmp(inflow) > 0 {
+			discrepancies = append(discrepancies, Discrepancy{
+				Check:   CheckDistribution2ZOutflow,
+				DZEpoch: &epoch,
+				Detail: fmt.Sprintf("distributed %d + burned %d = %s exceeds collected prepaid %d + converted from SOL %d = %s",
+					dist.Distributed2ZAmount, dist.Burned2ZAmount, outflow, dist.CollectedPrepaid2ZPayments, dist.Collected2ZConvertedFromSOL, inflow),
+			})
+		}
+
+		if dist.CollectedSolanaValidatorPayments > dist.TotalSolanaValidatorDebt {
+			discrepancies = append(discrepancies, Discrepancy{
+				Check:   CheckDistributionSOLDebt,
+				DZEpoch: &epoch,
+				Detail: fmt.Sprintf("collected validator payments %d lamports exceed total validator debt %d lamports",
+					dist.CollectedSolanaValidatorPayments, dist.TotalSolanaValidatorDebt),
+			})
+		}
+	}
+
+	if lifetime := journal.LifetimeSwapped2Z(); lifetime.Cmp(converted) < 0 {
+		discrepancies = append(discrepancies, Discrepancy{
+			Check: CheckLifetimeSwapped2Z,
+			Detail: fmt.Sprintf("journal lifetime swapped 2Z %s is less than 2Z converted from SOL %s across %d distributions",
+				lifetime, converted, len(distributions)),
+		})
+	}
+
+	return discrepancies
+}
+
+// Reconcile fetches the journal and the distributions for the given epochs and
+// checks them with Reconcile. Epochs without a distribution account are skipped
+// and returned separately.
+func (c *Client) Reconcile(ctx context.Context, epochs []uint64) (discrepancies []Discrepancy, missing []uint64, err error) {
+	journal, err := c.FetchJournal(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching journal: %w", err)
+	}
+	distributions := make([]Distribution, 0, len(epochs))
+	for _, epoch := range epochs {
+		dist, err := c.FetchDistribution(ctx, epoch)
+		if errors.Is(err, ErrAccountNotFound) {
+			missing = append(missing, epoch)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching distribution for epoch %d: %w", epoch, err)
+		}
+		distributions = append(distributions, *dist)
+	}
+	return Reconcile(journal, distributions), missing, nil
+}
@@ -0,0 +1,122 @@
+package revdist
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func journalWithLifetimeSwapped(lo, hi uint64) *Journal {
+	var j Journal
+	binary.LittleEndian.PutUint64(j.LifetimeSwapped2ZAmount[:8], lo)
+	binary.LittleEndian.PutUint64(j.LifetimeSwapped2ZAmount[8:], hi)
+	return &j
+}
+
+func TestJournalLifetimeSwapped2Z(t *testing.T) {
+	if got := journalWithLifetimeSwapped(1234, 0).LifetimeSwapped2Z().String(); got != "1234" {
+		t.Errorf("LifetimeSwapped2Z = %s, want 1234", got)
+	}
+	// 1<<64 + 1
+	if got := journalWithLifetimeSwapped(1, 1).LifetimeSwapped2Z().String(); got != "18446744073709551617" {
+		t.Errorf("LifetimeSwapped2Z = %s, want 18446744073709551617", got)
+	}
+}
+
+func consistentDistributions() []Distribution {
+	return []Distribution{
+		{
+			DZEpoch:                          10,
+			TotalSolanaValidatorDebt:         1_000,
+			CollectedSolanaValidatorPayments: 900,
+			CollectedPrepaid2ZPayments:       100,
+			Collected2ZConvertedFromSOL:      500,
+			Distributed2ZAmount:              450,
+			Burned2ZAmount:                   150,
+		},
+		{
+			DZEpoch:                          11,
+			TotalSolanaValidatorDebt:         2_000,
+			CollectedSolanaValidatorPayments: 2_000,
+			Collected2ZConvertedFromSOL:      700,
+			Distributed2ZAmount:              600,
+		},
+	}
+}
+
+func TestReconcile_Consistent(t *testing.T) {
+	got := Reconcile(journalWithLifetimeSwapped(1_200, 0), consistentDistributions())
+	if len(got) != 0 {
+		t.Fatalf("expected no discrepancies, got %v", got)
+	}
+}
+
+func TestReconcile_Inconsistent(t *testing.T) {
+	dists := consistentDistributions()
+	dists[0].Burned2ZAmount = 151                     // 450 + 151 > 100 + 500
+	dists[1].CollectedSolanaValidatorPayments = 2_001 // exceeds debt
+
+	got := Reconcile(journalWithLifetimeSwapped(1_199, 0), dists)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 discrepancies, got %d: %v", len(got), got)
+	}
+
+	want := []struct {
+		check string
+		epoch *uint64
+	}{
+		{CheckDistribution2ZOutflow, ptr(uint64(10))},
+		{CheckDistributionSOLDebt, ptr(uint64(11))},
+		{CheckLifetimeSwapped2Z, nil},
+	}
+	for i, w := range want {
+		if got[i].Check != w.check {
+			t.Errorf("discrepancy %d check = %s, want %s", i, got[i].Check, w.check)
+		}
+		if (got[i].DZEpoch == nil) != (w.epoch == nil) || (w.epoch != nil && *got[i].DZEpoch != *w.epoch) {
+			t.Errorf("discrepancy %d epoch = %v, want %v", i, got[i].DZEpoch, w.epoch)
+		}
+		if got[i].Detail == "" {
+			t.Errorf("discrepancy %d has no detail", i)
+		}
+	}
+}
+
+func TestClientReconcile(t *testing.T) {
+	programID := testProgramID
+	journalAddr, _, _ := DeriveJournalPDA(programID)
+	distAddr, _, _ := DeriveDistributionPDA(programID, 10)
+
+	journalData := buildAccountData(DiscriminatorJournal, 64)
+	// LifetimeSwapped2ZAmount (u128) at offset 48 of the journal body.
+	binary.LittleEndian.PutUint64(journalData[discriminatorSize+48:], 100)
+
+	distData := buildAccountData(DiscriminatorDistribution, 448)
+	binary.LittleEndian.PutUint64(distData[discriminatorSize:], 10) // DZEpoch
+	// Collected2ZConvertedFromSOL at offset 168 of the distribution body.
+	binary.LittleEndian.PutUint64(distData[discriminatorSize+168:], 500)
+
+	mock := &mockRPC{
+		accounts: map[solana.PublicKey]*rpc.Account{
+			journalAddr: {Data: rpc.DataBytesOrJSONFromBytes(journalData)},
+			distAddr:    {Data: rpc.DataBytesOrJSONFromBytes(distData)},
+		},
+	}
+	client := New(mock, programID)
+
+	discrepancies, missing, err := client.Reconcile(context.Background(), []uint64{10, 11})
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 11 {
+		t.Errorf("missing = %v, want [11]", missing)
+	}
+	if len(discrepancies) != 1 || discrepancies[0].Check != CheckLifetimeSwapped2Z {
+		t.Errorf("discrepancies = %v, want a single %s", discrepancies, CheckLifetimeSwapped2Z)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
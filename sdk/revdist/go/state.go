@@ -1,6 +1,8 @@
 package revdist
 
 import (
+	"fmt"
+
 	"github.com/gagliardetto/solana-go"
 )
 
@@ -115,6 +117,26 @@ type SolanaValidatorDeposit struct {
 	Reserved1         [32]byte         // 32 bytes storage gap
 }
 
+// ValidatorDepositSummary aggregates written-off debt across validator deposits.
+type ValidatorDepositSummary struct {
+	Count                  int
+	WrittenOffCount        int    // deposits with non-zero written-off debt
+	TotalWrittenOffSOLDebt uint64 // lamports
+}
+
+// SummarizeValidatorDeposits totals written-off SOL debt across deposits.
+func SummarizeValidatorDeposits(deposits []SolanaValidatorDeposit) ValidatorDepositSummary {
+	summary := ValidatorDepositSummary{Count: len(deposits)}
+	for _, dep := range deposits {
+		if dep.WrittenOffSOLDebt == 0 {
+			continue
+		}
+		summary.WrittenOffCount++
+		summary.TotalWrittenOffSOLDebt += dep.WrittenOffSOLDebt
+	}
+	return summary
+}
+
 // ContributorRewards represents a contributor's reward configuration.
 // On-chain size: 8 (discriminator) + 600 = 608 bytes.
 type ContributorRewards struct {
@@ -136,6 +158,39 @@ type RecipientShare struct {
 // 272 bytes total (8 * 34).
 type RecipientShares [8]RecipientShare
 
+// FormatShare formats a UnitShare16 value as a percentage with two decimals,
+// e.g. 1234 as "12.34%".
+func FormatShare(share uint16) string {
+	return fmt.Sprintf("%d.%02d%%", share/100, share%100)
+}
+
+// ContributorRecipientShare is one recipient of a contributor's rewards.
+type ContributorRecipientShare struct {
+	ServiceKey   solana.PublicKey `json:"service_key"`
+	RecipientKey solana.PublicKey `json:"recipient_key"`
+	Share        uint16           `json:"share"` // UnitShare16, max 10_000 (100%)
+}
+
+// ExpandRecipientShares flattens contributor rewards into one row per
+// (service key, recipient, share), skipping unused (zero) recipient slots, so
+// shares can be aggregated per recipient across contributors.
+func ExpandRecipientShares(rewards []ContributorRewards) []ContributorRecipientShare {
+	var rows []ContributorRecipientShare
+	for _, r := range rewards {
+		for _, rs := range r.RecipientShares {
+			if rs.RecipientKey.IsZero() {
+				continue
+			}
+			rows = append(rows, ContributorRecipientShare{
+				ServiceKey:   r.ServiceKey,
+				RecipientKey: rs.RecipientKey,
+				Share:        rs.Share,
+			})
+		}
+	}
+	return rows
+}
+
 // Journal tracks aggregate balances across the program.
 // On-chain size: 8 (discriminator) + 64 = 72 bytes.
 type Journal struct {
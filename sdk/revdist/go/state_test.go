@@ -3,9 +3,12 @@ package revdist
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"io"
 	"testing"
 	"unsafe"
+
+	"github.com/gagliardetto/solana-go"
 )
 
 func newReader(data []byte) io.Reader {
@@ -84,3 +87,75 @@ func TestSolanaValidatorDepositDeserialization(t *testing.T) {
 		t.Errorf("WrittenOffSOLDebt = %d, want 999", deposit.WrittenOffSOLDebt)
 	}
 }
+
+func TestSummarizeValidatorDeposits(t *testing.T) {
+	deposits := []SolanaValidatorDeposit{
+		{WrittenOffSOLDebt: 0},
+		{WrittenOffSOLDebt: 1_500_000_000},
+		{WrittenOffSOLDebt: 0},
+		{WrittenOffSOLDebt: 250_000_000},
+		{WrittenOffSOLDebt: 1},
+	}
+
+	got := SummarizeValidatorDeposits(deposits)
+	want := ValidatorDepositSummary{Count: 5, WrittenOffCount: 3, TotalWrittenOffSOLDebt: 1_750_000_001}
+	if got != want {
+		t.Errorf("SummarizeValidatorDeposits() = %+v, want %+v", got, want)
+	}
+
+	if empty := SummarizeValidatorDeposits(nil); empty != (ValidatorDepositSummary{}) {
+		t.Errorf("SummarizeValidatorDeposits(nil) = %+v, want zero", empty)
+	}
+}
+
+func TestFormatShare(t *testing.T) {
+	tests := map[uint16]string{
+		0:      "0.00%",
+		5:      "0.05%",
+		1234:   "12.34%",
+		5000:   "50.00%",
+		10_000: "100.00%",
+	}
+	for share, want := range tests {
+		if got := FormatShare(share); got != want {
+			t.Errorf("FormatShare(%d) = %q, want %q", share, got, want)
+		}
+	}
+}
+
+func TestExpandRecipientShares(t *testing.T) {
+	svcA, svcB := solana.PublicKey{1}, solana.PublicKey{2}
+	recipX, recipY := solana.PublicKey{10}, solana.PublicKey{11}
+
+	a := ContributorRewards{ServiceKey: svcA}
+	a.RecipientShares[0] = RecipientShare{RecipientKey: recipX, Share: 7_500}
+	a.RecipientShares[1] = RecipientShare{RecipientKey: recipY, Share: 2_500}
+
+	b := ContributorRewards{ServiceKey: svcB}
+	// Unused slots may sit before used ones; only zero recipients are skipped.
+	b.RecipientShares[3] = RecipientShare{RecipientKey: recipX, Share: 10_000}
+
+	got := ExpandRecipientShares([]ContributorRewards{a, {ServiceKey: solana.PublicKey{3}}, b})
+	want := []ContributorRecipientShare{
+		{ServiceKey: svcA, RecipientKey: recipX, Share: 7_500},
+		{ServiceKey: svcA, RecipientKey: recipY, Share: 2_500},
+		{ServiceKey: svcB, RecipientKey: recipX, Share: 10_000},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ExpandRecipientShares() returned %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	data, err := json.Marshal(got[0])
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	wantJSON := `{"service_key":"` + svcA.String() + `","recipient_key":"` + recipX.String() + `","share":7500}`
+	if string(data) != wantJSON {
+		t.Errorf("json = %s, want %s", data, wantJSON)
+	}
+}
@@ -0,0 +1,100 @@
+package serviceability
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DzPrefixCapacity is the address usage of one of a device's dz_prefixes.
+type DzPrefixCapacity struct {
+	// Index is the prefix's position in Device.DzPrefixes, which is also the
+	// index of its DzPrefixBlock resource extension.
+	Index  int
+	Prefix *net.IPNet
+	// Total is the number of addresses in the prefix.
+	Total uint64
+	// Reserved is the first address, which the program keeps for the device's
+	// user tunnel endpoint.
+	Reserved uint64
+	// Allocated is the number of other addresses in the prefix held by users
+	// or device interfaces.
+	Allocated uint64
+	// Free is the number of addresses still available for users.
+	Free uint64
+}
+
+// DzPrefixCapacity returns the address usage of each dz_prefix of the device
+// with the given pubkey, in on-chain order. It returns ErrDeviceNotFound if
+// there is no such device.
+func (c *Client) DzPrefixCapacity(ctx context.Context, devicePK solana.PublicKey) ([]DzPrefixCapacity, error) {
+	data, err := c.GetProgramData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program data: %w", err)
+	}
+	for _, dev := range data.Devices {
+		if solana.PublicKeyFromBytes(dev.PubKey[:]).Equals(devicePK) {
+			return BuildDzPrefixCapacity(data, dev), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, devicePK)
+}
+
+// BuildDzPrefixCapacity computes the dz_prefix usage of an already-fetched
+// device from the users connected to it and its own interfaces. IBRL users,
+// and any other user whose DZ IP is its client IP, do not consume dz_prefix
+// addresses; neither do multicast publishers allocated from the global
+// publisher block, whose addresses fall outside the prefix. Prefixes with an
+// invalid length are skipped.
+func BuildDzPrefixCapacity(data *ProgramData, dev Device) []DzPrefixCapacity {
+	var held []net.IP
+	for _, user := range data.Users {
+		if user.DevicePubKey != dev.PubKey {
+			continue
+		}
+		if user.UserType == UserTypeIBRL || user.DzIp == user.ClientIp {
+			continue
+		}
+		held = append(held, net.IP(user.DzIp[:]))
+	}
+	for _, iface := range deviceInterfaces(dev) {
+		if iface.IpNet[4] == 0 {
+			continue
+		}
+		held = append(held, net.IP(iface.IpNet[:4]))
+	}
+
+	out := make([]DzPrefixCapacity, 0, len(dev.DzPrefixes))
+	for i, prefix := range dev.DzPrefixes {
+		_, ipNet, err := net.ParseCIDR(onChainNetToString(prefix))
+		if err != nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		capacity := DzPrefixCapacity{
+			Index:    i,
+			Prefix:   ipNet,
+			Total:    uint64(1) << (bits - ones),
+			Reserved: 1,
+		}
+
+		// Count each address once, however many accounts claim it, and leave
+		// the reserved first address out of the allocated count.
+		first := ipNet.IP.To4()
+		seen := make(map[string]struct{})
+		for _, ip := range held {
+			if ip.IsUnspecified() || !ipNet.Contains(ip) || ip.Equal(first) {
+				continue
+			}
+			seen[ip.String()] = struct{}{}
+		}
+		capacity.Allocated = uint64(len(seen))
+		if used := capacity.Reserved + capacity.Allocated; used < capacity.Total {
+			capacity.Free = capacity.Total - used
+		}
+		out = append(out, capacity)
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package serviceability
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDzPrefixCapacity(t *testing.T) {
+	dev := Device{
+		PubKey: [32]byte{1},
+		DzPrefixes: [][5]uint8{
+			{100, 64, 0, 0, 29},  // partially used
+			{100, 64, 0, 8, 30},  // full
+			{100, 64, 0, 12, 31}, // one address after the reserved one
+			{100, 64, 0, 14, 32}, // only the reserved address
+			{100, 64, 0, 16, 33}, // invalid, skipped
+			{100, 64, 1, 0, 24},  // empty
+		},
+		Interfaces: []Interface{
+			// The reserved first address configured on Loopback100 is not counted twice.
+			{Name: "Loopback100", LoopbackType: LoopbackTypeNone, IpNet: [5]uint8{100, 64, 0, 0, 32}},
+			{Name: "Loopback101", IpNet: [5]uint8{100, 64, 0, 9, 32}},
+			// Outside every prefix.
+			{Name: "Loopback255", LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}},
+			// Unset.
+			{Name: "Ethernet1"},
+		},
+	}
+
+	data := &ProgramData{
+		Devices: []Device{dev},
+		Users: []User{
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 1}, DzIp: [4]uint8{100, 64, 0, 1}},
+			{UserType: UserTypeEdgeFiltering, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 2}, DzIp: [4]uint8{100, 64, 0, 2}},
+			// Two accounts holding the same address count once.
+			{UserType: UserTypeEdgeFiltering, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 3}, DzIp: [4]uint8{100, 64, 0, 2}},
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 4}, DzIp: [4]uint8{100, 64, 0, 10}},
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 5}, DzIp: [4]uint8{100, 64, 0, 11}},
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 6}, DzIp: [4]uint8{100, 64, 0, 13}},
+			// IBRL users reuse their client IP, even one inside the prefix.
+			{UserType: UserTypeIBRL, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{100, 64, 0, 3}, DzIp: [4]uint8{100, 64, 0, 3}},
+			// Multicast subscribers also use their client IP; publishers get
+			// addresses from the global publisher block.
+			{UserType: UserTypeMulticast, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{100, 64, 0, 4}, DzIp: [4]uint8{100, 64, 0, 4}},
+			{UserType: UserTypeMulticast, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 7}, DzIp: [4]uint8{147, 51, 126, 1}},
+			// Not yet activated.
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: dev.PubKey, ClientIp: [4]uint8{198, 51, 100, 8}},
+			// Another device's user.
+			{UserType: UserTypeIBRLWithAllocatedIP, DevicePubKey: [32]byte{2}, ClientIp: [4]uint8{198, 51, 100, 9}, DzIp: [4]uint8{100, 64, 0, 5}},
+		},
+	}
+
+	got := BuildDzPrefixCapacity(data, dev)
+	require.Len(t, got, 5)
+
+	tests := []struct {
+		index     int
+		prefix    string
+		total     uint64
+		allocated uint64
+		free      uint64
+	}{
+		{0, "100.64.0.0/29", 8, 2, 5},
+		{1, "100.64.0.8/30", 4, 3, 0},
+		{2, "100.64.0.12/31", 2, 1, 0},
+		{3, "100.64.0.14/32", 1, 0, 0},
+		{5, "100.64.1.0/24", 256, 0, 255},
+	}
+	for i, tt := range tests {
+		c := got[i]
+		assert.Equal(t, tt.index, c.Index, tt.prefix)
+		assert.Equal(t, tt.prefix, c.Prefix.String())
+		assert.Equal(t, tt.total, c.Total, tt.prefix)
+		assert.Equal(t, uint64(1), c.Reserved, tt.prefix)
+		assert.Equal(t, tt.allocated, c.Allocated, tt.prefix)
+		assert.Equal(t, tt.free, c.Free, tt.prefix)
+	}
+
+	assert.Empty(t, BuildDzPrefixCapacity(data, Device{PubKey: [32]byte{3}}))
+}
+
+func TestDzPrefixCapacity(t *testing.T) {
+	devicePK := solana.NewWallet().PublicKey()
+	client := New(&mockSolanaClient{payload: strings.TrimSuffix(devicePayload, "\n"), pubkey: devicePK}, solana.NewWallet().PublicKey())
+
+	got, err := client.DzPrefixCapacity(t.Context(), devicePK)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "180.87.154.112/29", got[0].Prefix.String())
+	assert.Equal(t, uint64(8), got[0].Total)
+	assert.Equal(t, uint64(7), got[0].Free)
+
+	_, err = client.DzPrefixCapacity(t.Context(), solana.NewWallet().PublicKey())
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+}
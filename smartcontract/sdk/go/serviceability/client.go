@@ -3,13 +3,62 @@ package serviceability
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultCacheFetchTimeout bounds a cached GetProgramData fetch, which runs
+	// detached from the caller's context so that one caller cancelling does
+	// not fail the others sharing the fetch. Each caller still stops waiting
+	// when its own context is done.
+	DefaultCacheFetchTimeout = 30 * time.Second
+
+	MetricNameProgramDataCacheRequests = "doublezero_serviceability_program_data_cache_requests_total"
+
+	LabelResult = "result"
+
+	cacheResultHit  = "hit"
+	cacheResultMiss = "miss"
 )
 
 type Client struct {
 	rpc       RPCClient
 	programID solana.PublicKey
+
+	// Program data cache, enabled by WithCacheTTL. A zero cacheTTL means every
+	// GetProgramData call fetches from RPC.
+	cacheTTL      time.Duration
+	cacheRequests *prometheus.CounterVec
+	mu            sync.RWMutex
+	cached        *ProgramData
+	cachedSeq     uint64 // fetchSeq of the fetch that produced cached
+	fetchSeq      uint64 // incremented as each fetch starts
+	fetchedAt     time.Time
+	group         singleflight.Group
+	now           func() time.Time
+}
+
+type ClientOption func(*Client)
+
+// WithCacheTTL enables caching of GetProgramData results for ttl. Concurrent
+// and repeated calls within the TTL share a single RPC fetch and the same
+// *ProgramData, so callers must treat it as read-only.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
+// WithCacheMetrics registers the program data cache hit/miss counter with reg.
+func WithCacheMetrics(reg prometheus.Registerer) ClientOption {
+	return func(c *Client) {
+		reg.MustRegister(c.cacheRequests)
+	}
 }
 
 type ProgramData struct {
@@ -31,15 +80,105 @@ type ProgramData struct {
 	Feeds              []Feed
 }
 
-func New(rpc RPCClient, programID solana.PublicKey) *Client {
-	return &Client{rpc: rpc, programID: programID}
+func New(rpc RPCClient, programID solana.PublicKey, opts ...ClientOption) *Client {
+	c := &Client{
+		rpc:       rpc,
+		programID: programID,
+		cacheRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricNameProgramDataCacheRequests,
+				Help: "Number of cached GetProgramData calls by result (hit or miss)",
+			},
+			[]string{LabelResult},
+		),
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) ProgramID() solana.PublicKey {
 	return c.programID
 }
 
+// GetProgramData fetches and deserializes all program accounts. With
+// WithCacheTTL, a result younger than the TTL is returned without an RPC call;
+// the returned data is then shared with other callers and must not be
+// modified.
 func (c *Client) GetProgramData(ctx context.Context) (*ProgramData, error) {
+	if c.cacheTTL <= 0 {
+		return c.fetchProgramData(ctx)
+	}
+
+	if data := c.cachedProgramData(); data != nil {
+		c.cacheRequests.WithLabelValues(cacheResultHit).Inc()
+		return data, nil
+	}
+	c.cacheRequests.WithLabelValues(cacheResultMiss).Inc()
+	return c.reload(ctx, "fetch")
+}
+
+// ForceReload fetches program data from RPC regardless of the cache age and
+// replaces the cached copy. Concurrent ForceReload calls share one fetch.
+func (c *Client) ForceReload(ctx context.Context) (*ProgramData, error) {
+	return c.reload(ctx, "reload")
+}
+
+func (c *Client) cachedProgramData() *ProgramData {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.cached != nil && c.now().Sub(c.fetchedAt) < c.cacheTTL {
+		return c.cached
+	}
+	return nil
+}
+
+func (c *Client) reload(ctx context.Context, key string) (*ProgramData, error) {
+	ch := c.group.DoChan(key, func() (any, error) {
+		// A plain fetch may have been satisfied by a concurrent one.
+		if key == "fetch" {
+			if data := c.cachedProgramData(); data != nil {
+				return data, nil
+			}
+		}
+
+		c.mu.Lock()
+		c.fetchSeq++
+		seq := c.fetchSeq
+		c.mu.Unlock()
+
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultCacheFetchTimeout)
+		defer cancel()
+		data, err := c.fetchProgramData(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		// A plain fetch and a ForceReload can overlap; keep the result of
+		// whichever started last so a slow, stale fetch does not replace it.
+		c.mu.Lock()
+		if seq > c.cachedSeq {
+			c.cached = data
+			c.cachedSeq = seq
+			c.fetchedAt = c.now()
+		}
+		c.mu.Unlock()
+		return data, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*ProgramData), nil
+	}
+}
+
+func (c *Client) fetchProgramData(ctx context.Context) (*ProgramData, error) {
 	out, err := c.rpc.GetProgramAccounts(ctx, c.programID)
 	if err != nil {
 		return nil, err
@@ -165,11 +304,16 @@ func (c *Client) GetMulticastPublisherBlockResourceExtension(ctx context.Context
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive MulticastPublisherBlock PDA: %w", err)
 	}
+	return c.getResourceExtension(ctx, pda)
+}
 
+// getResourceExtension fetches and deserializes the resource extension at pda.
+// Returns nil if the account doesn't exist yet.
+func (c *Client) getResourceExtension(ctx context.Context, pda solana.PublicKey) (*ResourceExtension, error) {
 	// Fetch the account data
 	accountInfo, err := c.rpc.GetAccountInfo(ctx, pda)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch MulticastPublisherBlock account: %w", err)
+		return nil, fmt.Errorf("failed to fetch resource extension account %s: %w", pda, err)
 	}
 
 	if accountInfo == nil || accountInfo.Value == nil {
@@ -3,12 +3,18 @@ package serviceability
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 var configPayload = `
@@ -117,10 +123,13 @@ func (m *mockSolanaClient) GetProgramAccounts(context.Context, solana.PublicKey)
 	}, nil
 }
 
-func (m *mockSolanaClient) GetAccountInfo(context.Context, solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+func (m *mockSolanaClient) GetAccountInfo(_ context.Context, account solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
 	if m.returnEmpty {
 		return nil, nil
 	}
+	if !m.pubkey.IsZero() && !account.Equals(m.pubkey) {
+		return &rpc.GetAccountInfoResult{}, nil
+	}
 	data, err := hex.DecodeString(strings.ReplaceAll(m.payload, "\n", ""))
 	if err != nil {
 		return nil, err
@@ -594,3 +603,199 @@ func TestSDK_Serviceability_GetProgramData_EmptyResult(t *testing.T) {
 		t.Fatalf("expected error to contain %q, got: %v", expectedErrSubstring, err)
 	}
 }
+
+type countingRPCClient struct {
+	RPCClient
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (c *countingRPCClient) GetProgramAccounts(ctx context.Context, programID solana.PublicKey) (rpc.GetProgramAccountsResult, error) {
+	c.calls.Add(1)
+	time.Sleep(c.delay)
+	return c.RPCClient.GetProgramAccounts(ctx, programID)
+}
+
+// gatedRPCClient blocks its first GetProgramAccounts call until release is
+// closed.
+type gatedRPCClient struct {
+	RPCClient
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (c *gatedRPCClient) GetProgramAccounts(ctx context.Context, programID solana.PublicKey) (rpc.GetProgramAccountsResult, error) {
+	if c.calls.Add(1) == 1 {
+		<-c.release
+	}
+	return c.RPCClient.GetProgramAccounts(ctx, programID)
+}
+
+func TestSDK_Serviceability_GetProgramData_Cache(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	rpcClient := &countingRPCClient{
+		RPCClient: &mockSolanaClient{payload: configPayload, pubkey: getOwner(configPayload)},
+		delay:     20 * time.Millisecond,
+	}
+	reg := prometheus.NewRegistry()
+	client := New(rpcClient, programID, WithCacheTTL(time.Minute), WithCacheMetrics(reg))
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	results := make([]*ProgramData, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := client.GetProgramData(t.Context())
+			if err != nil {
+				t.Errorf("GetProgramData: %v", err)
+			}
+			results[i] = data
+		}()
+	}
+	wg.Wait()
+	if got := rpcClient.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 RPC call for concurrent loads, got %d", got)
+	}
+	for _, data := range results {
+		if data != results[0] {
+			t.Fatal("expected concurrent loads to share the same program data")
+		}
+	}
+
+	// Within the TTL the cached copy is returned.
+	if _, err := client.GetProgramData(t.Context()); err != nil {
+		t.Fatalf("GetProgramData: %v", err)
+	}
+	if got := rpcClient.calls.Load(); got != 1 {
+		t.Fatalf("expected cache hit within TTL, got %d RPC calls", got)
+	}
+	if got := testutil.ToFloat64(client.cacheRequests.WithLabelValues(cacheResultHit)); got < 1 {
+		t.Errorf("expected at least 1 cache hit, got %v", got)
+	}
+
+	// After the TTL the data is refetched.
+	now = now.Add(time.Minute)
+	if _, err := client.GetProgramData(t.Context()); err != nil {
+		t.Fatalf("GetProgramData: %v", err)
+	}
+	if got := rpcClient.calls.Load(); got != 2 {
+		t.Fatalf("expected refetch after TTL expiry, got %d RPC calls", got)
+	}
+
+	// ForceReload always fetches.
+	if _, err := client.ForceReload(t.Context()); err != nil {
+		t.Fatalf("ForceReload: %v", err)
+	}
+	if got := rpcClient.calls.Load(); got != 3 {
+		t.Fatalf("expected ForceReload to fetch, got %d RPC calls", got)
+	}
+	if got, err := testutil.GatherAndCount(reg, MetricNameProgramDataCacheRequests); err != nil || got != 2 {
+		t.Errorf("expected hit and miss series, got %d (err %v)", got, err)
+	}
+}
+
+func TestSDK_Serviceability_GetProgramData_Uncached(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	rpcClient := &countingRPCClient{
+		RPCClient: &mockSolanaClient{payload: configPayload, pubkey: getOwner(configPayload)},
+	}
+	client := New(rpcClient, programID)
+
+	for range 3 {
+		if _, err := client.GetProgramData(t.Context()); err != nil {
+			t.Fatalf("GetProgramData: %v", err)
+		}
+	}
+	if got := rpcClient.calls.Load(); got != 3 {
+		t.Fatalf("expected every uncached call to hit RPC, got %d", got)
+	}
+}
+
+func TestSDK_Serviceability_GetProgramData_CacheDoesNotStoreErrors(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	rpcClient := &countingRPCClient{RPCClient: &mockSolanaClient{returnEmpty: true}}
+	client := New(rpcClient, programID, WithCacheTTL(time.Minute))
+
+	for range 2 {
+		if _, err := client.GetProgramData(t.Context()); err == nil {
+			t.Fatal("expected error for empty result")
+		}
+	}
+	if got := rpcClient.calls.Load(); got != 2 {
+		t.Fatalf("expected failed fetches not to be cached, got %d RPC calls", got)
+	}
+}
+
+func TestSDK_Serviceability_GetProgramData_CacheHonoursCallerContext(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	rpcClient := &countingRPCClient{
+		RPCClient: &mockSolanaClient{payload: configPayload, pubkey: getOwner(configPayload)},
+		delay:     200 * time.Millisecond,
+	}
+	client := New(rpcClient, programID, WithCacheTTL(time.Minute))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetProgramData(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= rpcClient.delay {
+		t.Fatalf("expected caller to stop waiting at its deadline, waited %v", elapsed)
+	}
+
+	// The detached fetch still completes and fills the cache for later callers.
+	if _, err := client.GetProgramData(t.Context()); err != nil {
+		t.Fatalf("GetProgramData: %v", err)
+	}
+	if got := rpcClient.calls.Load(); got != 1 {
+		t.Fatalf("expected the abandoned fetch to be shared, got %d RPC calls", got)
+	}
+}
+
+func TestSDK_Serviceability_GetProgramData_StaleFetchDoesNotOverwriteReload(t *testing.T) {
+	programID := solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	rpcClient := &gatedRPCClient{
+		RPCClient: &mockSolanaClient{payload: configPayload, pubkey: getOwner(configPayload)},
+		release:   make(chan struct{}),
+	}
+	client := New(rpcClient, programID, WithCacheTTL(time.Minute))
+
+	// A plain fetch starts and stalls.
+	stale := make(chan *ProgramData, 1)
+	go func() {
+		data, err := client.GetProgramData(t.Context())
+		if err != nil {
+			t.Errorf("GetProgramData: %v", err)
+		}
+		stale <- data
+	}()
+	for rpcClient.calls.Load() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A ForceReload started after it completes first.
+	reloaded, err := client.ForceReload(t.Context())
+	if err != nil {
+		t.Fatalf("ForceReload: %v", err)
+	}
+
+	// The stale fetch finishing afterwards must not replace the reloaded data.
+	close(rpcClient.release)
+	if data := <-stale; data == reloaded {
+		t.Fatal("expected the stalled fetch to return its own result")
+	}
+	got, err := client.GetProgramData(t.Context())
+	if err != nil {
+		t.Fatalf("GetProgramData: %v", err)
+	}
+	if got != reloaded {
+		t.Fatal("expected the cache to keep the newer ForceReload result")
+	}
+	if calls := rpcClient.calls.Load(); calls != 2 {
+		t.Fatalf("expected a cache hit after both fetches, got %d RPC calls", calls)
+	}
+}
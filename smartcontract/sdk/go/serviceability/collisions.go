@@ -0,0 +1,146 @@
+package serviceability
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// CollisionKind identifies which allocated resource collided.
+type CollisionKind string
+
+const (
+	CollisionKindLoopbackIP  CollisionKind = "loopback_ip"
+	CollisionKindTunnelNet   CollisionKind = "tunnel_net"
+	CollisionKindTunnelID    CollisionKind = "tunnel_id"
+	CollisionKindMulticastIP CollisionKind = "multicast_ip"
+)
+
+// CollisionEntity is an account holding a colliding allocation.
+type CollisionEntity struct {
+	Type   string // "device", "link", "user" or "multicast_group"
+	PubKey solana.PublicKey
+	Code   string // account code, empty for users
+	Detail string // e.g. the interface name for a device loopback
+}
+
+// AllocationCollision is a single value allocated to more than one entity.
+type AllocationCollision struct {
+	Kind  CollisionKind
+	Value string
+	// Device scopes the collision for per-device allocations (tunnel ids); zero otherwise.
+	Device   solana.PublicKey
+	Entities []CollisionEntity
+}
+
+// DetectAllocationCollisions fetches all program accounts and reports any
+// loopback IP, tunnel net, per-device tunnel id or multicast IP allocated to
+// more than one entity. It is a read-only audit.
+func (c *Client) DetectAllocationCollisions(ctx context.Context) ([]AllocationCollision, error) {
+	data, err := c.GetProgramData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program data: %w", err)
+	}
+	return FindAllocationCollisions(data), nil
+}
+
+type collisionKey struct {
+	kind   CollisionKind
+	device solana.PublicKey
+	value  string
+}
+
+// FindAllocationCollisions reports duplicate allocations in already-fetched
+// program data. Unset values (zero IPs, empty nets, tunnel id 0) are ignored.
+// Results are sorted by kind, device and value.
+func FindAllocationCollisions(data *ProgramData) []AllocationCollision {
+	seen := make(map[collisionKey][]CollisionEntity)
+	add := func(kind CollisionKind, device solana.PublicKey, value string, entity CollisionEntity) {
+		key := collisionKey{kind: kind, device: device, value: value}
+		seen[key] = append(seen[key], entity)
+	}
+
+	for _, dev := range data.Devices {
+		for _, iface := range deviceInterfaces(dev) {
+			if iface.LoopbackType == LoopbackTypeNone || iface.IpNet[4] == 0 {
+				continue
+			}
+			ip := net.IP(iface.IpNet[:4])
+			if ip.IsUnspecified() {
+				continue
+			}
+			add(CollisionKindLoopbackIP, solana.PublicKey{}, ip.String(), CollisionEntity{
+				Type: "device", PubKey: dev.PubKey, Code: dev.Code, Detail: iface.Name,
+			})
+		}
+	}
+
+	for _, link := range data.Links {
+		entity := CollisionEntity{Type: "link", PubKey: link.PubKey, Code: link.Code}
+		if tunnelNet := onChainNetToString(link.TunnelNet); tunnelNet != "" {
+			add(CollisionKindTunnelNet, solana.PublicKey{}, tunnelNet, entity)
+		}
+		// A link's tunnel id is allocated on both of its devices.
+		if link.TunnelId != 0 {
+			tunnelID := fmt.Sprintf("%d", link.TunnelId)
+			add(CollisionKindTunnelID, link.SideAPubKey, tunnelID, entity)
+			if link.SideZPubKey != link.SideAPubKey {
+				add(CollisionKindTunnelID, link.SideZPubKey, tunnelID, entity)
+			}
+		}
+	}
+
+	for _, user := range data.Users {
+		entity := CollisionEntity{Type: "user", PubKey: user.PubKey}
+		if tunnelNet := onChainNetToString(user.TunnelNet); tunnelNet != "" {
+			add(CollisionKindTunnelNet, solana.PublicKey{}, tunnelNet, entity)
+		}
+		if user.TunnelId != 0 {
+			add(CollisionKindTunnelID, user.DevicePubKey, fmt.Sprintf("%d", user.TunnelId), entity)
+		}
+	}
+
+	for _, mg := range data.MulticastGroups {
+		ip := net.IP(mg.MulticastIp[:])
+		if ip.IsUnspecified() {
+			continue
+		}
+		add(CollisionKindMulticastIP, solana.PublicKey{}, ip.String(), CollisionEntity{
+			Type: "multicast_group", PubKey: mg.PubKey, Code: mg.Code,
+		})
+	}
+
+	var collisions []AllocationCollision
+	for key, entities := range seen {
+		if len(entities) < 2 {
+			continue
+		}
+		collisions = append(collisions, AllocationCollision{
+			Kind:     key.kind,
+			Value:    key.value,
+			Device:   key.device,
+			Entities: entities,
+		})
+	}
+	slices.SortFunc(collisions, func(a, b AllocationCollision) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Device.String(), b.Device.String()),
+			cmp.Compare(a.Value, b.Value),
+		)
+	})
+	return collisions
+}
+
+// deviceInterfaces returns the device's interfaces, preferring the trailing
+// forward-compat vec and falling back to the deprecated one.
+func deviceInterfaces(dev Device) []Interface {
+	if len(dev.Interfaces) > 0 {
+		return dev.Interfaces
+	}
+	return dev.DeprecatedInterfaces
+}
@@ -0,0 +1,139 @@
+package serviceability
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAllocationCollisions(t *testing.T) {
+	dev1 := [32]byte{1}
+	dev2 := [32]byte{2}
+
+	data := &ProgramData{
+		Devices: []Device{
+			{
+				PubKey: dev1, Code: "dz1",
+				Interfaces: []Interface{
+					{Name: "Loopback255", LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}},
+					{Name: "Loopback256", LoopbackType: LoopbackTypeIpv4, IpNet: [5]uint8{172, 16, 1, 1, 32}},
+					{Name: "Ethernet1", IpNet: [5]uint8{10, 0, 0, 1, 31}},
+				},
+			},
+			{
+				PubKey: dev2, Code: "dz2",
+				DeprecatedInterfaces: []Interface{
+					{Name: "Loopback255", LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}},
+					// Non-loopback interfaces sharing an IP are not loopback collisions.
+					{Name: "Ethernet1", IpNet: [5]uint8{10, 0, 0, 1, 31}},
+					// Unset loopbacks are ignored.
+					{Name: "Loopback257", LoopbackType: LoopbackTypeIpv4},
+				},
+			},
+		},
+		Links: []Link{
+			{PubKey: [32]byte{10}, Code: "dz1:dz2", TunnelNet: [5]uint8{172, 16, 2, 0, 31}},
+			{PubKey: [32]byte{11}, Code: "dz2:dz3", TunnelNet: [5]uint8{172, 16, 2, 2, 31}},
+		},
+		Users: []User{
+			{PubKey: [32]byte{20}, DevicePubKey: dev1, TunnelId: 500, TunnelNet: [5]uint8{172, 16, 2, 0, 31}},
+			{PubKey: [32]byte{21}, DevicePubKey: dev1, TunnelId: 500, TunnelNet: [5]uint8{169, 254, 0, 0, 31}},
+			// Same tunnel id on a different device is not a collision.
+			{PubKey: [32]byte{22}, DevicePubKey: dev2, TunnelId: 500, TunnelNet: [5]uint8{169, 254, 0, 2, 31}},
+			// Unallocated users are ignored.
+			{PubKey: [32]byte{23}, DevicePubKey: dev2},
+			{PubKey: [32]byte{24}, DevicePubKey: dev2},
+		},
+		MulticastGroups: []MulticastGroup{
+			{PubKey: [32]byte{30}, Code: "mg1", MulticastIp: [4]uint8{233, 84, 178, 0}},
+			{PubKey: [32]byte{31}, Code: "mg2", MulticastIp: [4]uint8{233, 84, 178, 0}},
+			{PubKey: [32]byte{32}, Code: "mg3", MulticastIp: [4]uint8{233, 84, 178, 1}},
+			{PubKey: [32]byte{33}, Code: "mg4"},
+			{PubKey: [32]byte{34}, Code: "mg5"},
+		},
+	}
+
+	got := FindAllocationCollisions(data)
+	require.Len(t, got, 4)
+
+	assert.Equal(t, CollisionKindLoopbackIP, got[0].Kind)
+	assert.Equal(t, "172.16.0.1", got[0].Value)
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "device", PubKey: dev1, Code: "dz1", Detail: "Loopback255"},
+		{Type: "device", PubKey: dev2, Code: "dz2", Detail: "Loopback255"},
+	}, got[0].Entities)
+
+	assert.Equal(t, CollisionKindMulticastIP, got[1].Kind)
+	assert.Equal(t, "233.84.178.0", got[1].Value)
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "multicast_group", PubKey: [32]byte{30}, Code: "mg1"},
+		{Type: "multicast_group", PubKey: [32]byte{31}, Code: "mg2"},
+	}, got[1].Entities)
+
+	assert.Equal(t, CollisionKindTunnelID, got[2].Kind)
+	assert.Equal(t, "500", got[2].Value)
+	assert.Equal(t, solana.PublicKey(dev1), got[2].Device)
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "user", PubKey: [32]byte{20}},
+		{Type: "user", PubKey: [32]byte{21}},
+	}, got[2].Entities)
+
+	assert.Equal(t, CollisionKindTunnelNet, got[3].Kind)
+	assert.Equal(t, "172.16.2.0/31", got[3].Value)
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "link", PubKey: [32]byte{10}, Code: "dz1:dz2"},
+		{Type: "user", PubKey: [32]byte{20}},
+	}, got[3].Entities)
+}
+
+func TestFindAllocationCollisions_None(t *testing.T) {
+	data := &ProgramData{
+		Links: []Link{
+			{PubKey: [32]byte{10}, TunnelNet: [5]uint8{172, 16, 2, 0, 31}},
+			{PubKey: [32]byte{11}, TunnelNet: [5]uint8{172, 16, 2, 2, 31}},
+		},
+	}
+	assert.Empty(t, FindAllocationCollisions(data))
+}
+
+func TestFindAllocationCollisions_LinkTunnelIDs(t *testing.T) {
+	dev1 := [32]byte{1}
+	dev2 := [32]byte{2}
+	dev3 := [32]byte{3}
+
+	data := &ProgramData{
+		Links: []Link{
+			{PubKey: [32]byte{10}, Code: "dz1:dz2", SideAPubKey: dev1, SideZPubKey: dev2, TunnelId: 7},
+			// Shares tunnel id 7 with dz1:dz2 on dz2 only.
+			{PubKey: [32]byte{11}, Code: "dz2:dz3", SideAPubKey: dev2, SideZPubKey: dev3, TunnelId: 7},
+			// Unallocated links are ignored.
+			{PubKey: [32]byte{12}, Code: "dz1:dz3", SideAPubKey: dev1, SideZPubKey: dev3},
+		},
+		Users: []User{
+			// Clashes with dz2:dz3 on its Z side.
+			{PubKey: [32]byte{20}, DevicePubKey: dev3, TunnelId: 7},
+		},
+	}
+
+	got := FindAllocationCollisions(data)
+	require.Len(t, got, 2)
+	for _, c := range got {
+		assert.Equal(t, CollisionKindTunnelID, c.Kind)
+		assert.Equal(t, "7", c.Value)
+	}
+
+	byDevice := map[solana.PublicKey][]CollisionEntity{}
+	for _, c := range got {
+		byDevice[c.Device] = c.Entities
+	}
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "link", PubKey: [32]byte{10}, Code: "dz1:dz2"},
+		{Type: "link", PubKey: [32]byte{11}, Code: "dz2:dz3"},
+	}, byDevice[dev2])
+	assert.ElementsMatch(t, []CollisionEntity{
+		{Type: "link", PubKey: [32]byte{11}, Code: "dz2:dz3"},
+		{Type: "user", PubKey: [32]byte{20}},
+	}, byDevice[dev3])
+}
@@ -0,0 +1,122 @@
+package serviceability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// GeoJSONFeatureCollection is a GeoJSON (RFC 7946) FeatureCollection.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a single GeoJSON Feature. Geometry is nil for entities
+// whose coordinates could not be resolved.
+type GeoJSONFeature struct {
+	Type       string           `json:"type"`
+	Geometry   *GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any   `json:"properties"`
+}
+
+// GeoJSONGeometry is a Point ([lng, lat]) or LineString ([[lng, lat], ...]).
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// TopologyGeoJSON fetches all program accounts and returns the device/link
+// topology as a GeoJSON FeatureCollection.
+func (c *Client) TopologyGeoJSON(ctx context.Context) (*GeoJSONFeatureCollection, error) {
+	data, err := c.GetProgramData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program data: %w", err)
+	}
+	return BuildTopologyGeoJSON(data), nil
+}
+
+// BuildTopologyGeoJSON converts already-fetched program data into a GeoJSON
+// FeatureCollection: a Point per device at its metro (exchange) coordinates,
+// followed by a LineString per link between its two devices. Devices whose
+// exchange is unknown or has no coordinates, and links touching such devices
+// or unknown devices, are kept with a null geometry and "located": false so
+// consumers can tell them apart from real features.
+func BuildTopologyGeoJSON(data *ProgramData) *GeoJSONFeatureCollection {
+	exchanges := make(map[solana.PublicKey]Exchange, len(data.Exchanges))
+	for _, ex := range data.Exchanges {
+		exchanges[ex.PubKey] = ex
+	}
+
+	type devicePosition struct {
+		code     string
+		coords   []float64
+		resolved bool
+	}
+	devices := make(map[solana.PublicKey]devicePosition, len(data.Devices))
+
+	fc := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]GeoJSONFeature, 0, len(data.Devices)+len(data.Links)),
+	}
+
+	for _, dev := range data.Devices {
+		ex, ok := exchanges[dev.ExchangePubKey]
+		resolved := ok && (ex.Lat != 0 || ex.Lng != 0)
+		pos := devicePosition{code: dev.Code, resolved: resolved}
+
+		props := map[string]any{
+			"kind":        "device",
+			"pubkey":      solana.PublicKey(dev.PubKey).String(),
+			"code":        dev.Code,
+			"status":      dev.Status.String(),
+			"device_type": dev.DeviceType.String(),
+			"located":     resolved,
+		}
+		if ok {
+			props["exchange"] = ex.Code
+		}
+
+		var geometry *GeoJSONGeometry
+		if resolved {
+			pos.coords = []float64{ex.Lng, ex.Lat}
+			geometry = &GeoJSONGeometry{Type: "Point", Coordinates: pos.coords}
+		}
+		devices[dev.PubKey] = pos
+
+		fc.Features = append(fc.Features, GeoJSONFeature{Type: "Feature", Geometry: geometry, Properties: props})
+	}
+
+	for _, link := range data.Links {
+		sideA, okA := devices[link.SideAPubKey]
+		sideZ, okZ := devices[link.SideZPubKey]
+		resolved := okA && okZ && sideA.resolved && sideZ.resolved
+
+		var geometry *GeoJSONGeometry
+		if resolved {
+			geometry = &GeoJSONGeometry{Type: "LineString", Coordinates: [][]float64{sideA.coords, sideZ.coords}}
+		}
+
+		fc.Features = append(fc.Features, GeoJSONFeature{
+			Type:     "Feature",
+			Geometry: geometry,
+			Properties: map[string]any{
+				"kind":      "link",
+				"pubkey":    solana.PublicKey(link.PubKey).String(),
+				"code":      link.Code,
+				"status":    link.Status.String(),
+				"link_type": link.LinkType.String(),
+				"side_a":    sideA.code,
+				"side_z":    sideZ.code,
+				"side_a_pk": solana.PublicKey(link.SideAPubKey).String(),
+				"side_z_pk": solana.PublicKey(link.SideZPubKey).String(),
+				"bandwidth": link.Bandwidth,
+				"delay_ns":  link.DelayNs,
+				"located":   resolved,
+			},
+		})
+	}
+
+	return fc
+}
@@ -0,0 +1,91 @@
+package serviceability
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTopologyGeoJSON(t *testing.T) {
+	ams := [32]byte{1}
+	fra := [32]byte{2}
+	dev1 := [32]byte{10}
+	dev2 := [32]byte{11}
+	dev3 := [32]byte{12}
+
+	data := &ProgramData{
+		Exchanges: []Exchange{
+			{PubKey: ams, Code: "ams", Lat: 52.37, Lng: 4.9},
+			{PubKey: fra, Code: "fra", Lat: 50.11, Lng: 8.68},
+		},
+		Devices: []Device{
+			{PubKey: dev1, Code: "ams-dz1", ExchangePubKey: ams, Status: DeviceStatusActivated},
+			{PubKey: dev2, Code: "fra-dz1", ExchangePubKey: fra, Status: DeviceStatusActivated},
+			// Exchange not in program data: kept without coordinates.
+			{PubKey: dev3, Code: "xxx-dz1", ExchangePubKey: [32]byte{99}},
+		},
+		Links: []Link{
+			{PubKey: [32]byte{20}, Code: "ams-dz1:fra-dz1", SideAPubKey: dev1, SideZPubKey: dev2, LinkType: LinkLinkTypeWAN, Status: LinkStatusActivated},
+			{PubKey: [32]byte{21}, Code: "fra-dz1:xxx-dz1", SideAPubKey: dev2, SideZPubKey: dev3, LinkType: LinkLinkTypeWAN},
+		},
+	}
+
+	fc := BuildTopologyGeoJSON(data)
+	require.Equal(t, "FeatureCollection", fc.Type)
+	require.Len(t, fc.Features, 5)
+
+	d1 := fc.Features[0]
+	assert.Equal(t, "Feature", d1.Type)
+	require.NotNil(t, d1.Geometry)
+	assert.Equal(t, "Point", d1.Geometry.Type)
+	assert.Equal(t, []float64{4.9, 52.37}, d1.Geometry.Coordinates)
+	assert.Equal(t, "device", d1.Properties["kind"])
+	assert.Equal(t, "ams-dz1", d1.Properties["code"])
+	assert.Equal(t, "ams", d1.Properties["exchange"])
+	assert.Equal(t, solana.PublicKey(dev1).String(), d1.Properties["pubkey"])
+	assert.Equal(t, true, d1.Properties["located"])
+
+	d3 := fc.Features[2]
+	assert.Nil(t, d3.Geometry)
+	assert.Equal(t, false, d3.Properties["located"])
+	assert.NotContains(t, d3.Properties, "exchange")
+
+	l1 := fc.Features[3]
+	require.NotNil(t, l1.Geometry)
+	assert.Equal(t, "LineString", l1.Geometry.Type)
+	assert.Equal(t, [][]float64{{4.9, 52.37}, {8.68, 50.11}}, l1.Geometry.Coordinates)
+	assert.Equal(t, "link", l1.Properties["kind"])
+	assert.Equal(t, "WAN", l1.Properties["link_type"])
+	assert.Equal(t, "ams-dz1", l1.Properties["side_a"])
+	assert.Equal(t, "fra-dz1", l1.Properties["side_z"])
+
+	l2 := fc.Features[4]
+	assert.Nil(t, l2.Geometry)
+	assert.Equal(t, false, l2.Properties["located"])
+
+	// The collection round-trips as valid GeoJSON with null geometries for unlocated features.
+	raw, err := json.Marshal(fc)
+	require.NoError(t, err)
+	var decoded struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Type     string           `json:"type"`
+			Geometry *json.RawMessage `json:"geometry"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, "FeatureCollection", decoded.Type)
+	require.Len(t, decoded.Features, 5)
+	assert.Nil(t, decoded.Features[2].Geometry)
+	assert.NotNil(t, decoded.Features[0].Geometry)
+}
+
+func TestBuildTopologyGeoJSON_Empty(t *testing.T) {
+	fc := BuildTopologyGeoJSON(&ProgramData{})
+	raw, err := json.Marshal(fc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"FeatureCollection","features":[]}`, string(raw))
+}
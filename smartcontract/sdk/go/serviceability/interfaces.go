@@ -0,0 +1,103 @@
+package serviceability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/gagliardetto/solana-go"
+	solanarpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrDeviceNotFound is returned when no device account exists for a pubkey.
+var ErrDeviceNotFound = errors.New("device not found")
+
+// DeviceInterface is a device interface with its IP resolved and its loopback
+// role classified.
+type DeviceInterface struct {
+	Interface
+
+	// IPNet is the interface's address and prefix, or nil if none is set.
+	IPNet *net.IPNet
+	// Allocated reports whether the interface has a non-zero IP assigned.
+	Allocated bool
+	// Loopback reports whether the interface is a loopback with a role
+	// (LoopbackType other than none); the role itself is in LoopbackType.
+	Loopback bool
+}
+
+// IP returns the interface's address, or nil if none is set.
+func (i DeviceInterface) IP() net.IP {
+	if i.IPNet == nil {
+		return nil
+	}
+	return i.IPNet.IP
+}
+
+// GetDeviceInterfaces returns the interfaces of the device with the given
+// pubkey, in on-chain order. Only the device account is fetched. It returns
+// ErrDeviceNotFound if there is no such device.
+func (c *Client) GetDeviceInterfaces(ctx context.Context, devicePK solana.PublicKey) ([]DeviceInterface, error) {
+	dev, err := c.getDevice(ctx, devicePK)
+	if err != nil {
+		return nil, err
+	}
+	return BuildDeviceInterfaces(*dev), nil
+}
+
+// getDevice fetches and deserializes the device account at devicePK. The RPC
+// client reports a missing account either as ErrNotFound or as a nil value;
+// both, and an account of another type, yield ErrDeviceNotFound.
+func (c *Client) getDevice(ctx context.Context, devicePK solana.PublicKey) (*Device, error) {
+	info, err := c.rpc.GetAccountInfo(ctx, devicePK)
+	if errors.Is(err, solanarpc.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, devicePK)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch device account %s: %w", devicePK, err)
+	}
+	if info == nil || info.Value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, devicePK)
+	}
+	data := info.Value.Data.GetBinary()
+	if len(data) == 0 || AccountType(data[0]) != DeviceType {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, devicePK)
+	}
+
+	var dev Device
+	DeserializeDevice(NewByteReader(data), &dev)
+	dev.PubKey = devicePK
+	return &dev, nil
+}
+
+// BuildDeviceInterfaces classifies the interfaces of an already-fetched
+// device, preferring the size-prefixed Interfaces over DeprecatedInterfaces.
+func BuildDeviceInterfaces(dev Device) []DeviceInterface {
+	ifaces := deviceInterfaces(dev)
+	out := make([]DeviceInterface, 0, len(ifaces))
+	for _, iface := range ifaces {
+		di := DeviceInterface{
+			Interface: iface,
+			Loopback:  iface.LoopbackType != LoopbackTypeNone,
+		}
+		if _, ipNet, err := net.ParseCIDR(onChainNetToString(iface.IpNet)); err == nil {
+			ipNet.IP = net.IPv4(iface.IpNet[0], iface.IpNet[1], iface.IpNet[2], iface.IpNet[3]).To4()
+			di.IPNet = ipNet
+			di.Allocated = !ipNet.IP.IsUnspecified()
+		}
+		out = append(out, di)
+	}
+	return out
+}
+
+// LoopbackInterfaces returns the interfaces with a loopback role.
+func LoopbackInterfaces(ifaces []DeviceInterface) []DeviceInterface {
+	var out []DeviceInterface
+	for _, iface := range ifaces {
+		if iface.Loopback {
+			out = append(out, iface)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package serviceability
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDeviceInterfaces(t *testing.T) {
+	dev := Device{
+		Interfaces: []Interface{
+			{Name: "Loopback255", InterfaceType: InterfaceTypeLoopback, LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}},
+			{Name: "Loopback256", InterfaceType: InterfaceTypeLoopback, LoopbackType: LoopbackTypeIpv4, IpNet: [5]uint8{172, 16, 1, 1, 32}},
+			// A loopback whose IP has not been allocated yet.
+			{Name: "Loopback257", InterfaceType: InterfaceTypeLoopback, LoopbackType: LoopbackTypePimRpAddr},
+			{Name: "Ethernet1", InterfaceType: InterfaceTypePhysical, IpNet: [5]uint8{10, 0, 0, 1, 31}},
+			{Name: "Ethernet2", InterfaceType: InterfaceTypePhysical},
+			// Prefix set but address zeroed.
+			{Name: "Ethernet3", InterfaceType: InterfaceTypePhysical, IpNet: [5]uint8{0, 0, 0, 0, 32}},
+		},
+		// Ignored when the size-prefixed Interfaces are present.
+		DeprecatedInterfaces: []Interface{{Name: "legacy"}},
+	}
+
+	ifaces := BuildDeviceInterfaces(dev)
+	require.Len(t, ifaces, 6)
+
+	tests := []struct {
+		name      string
+		loopback  bool
+		allocated bool
+		ipNet     string
+	}{
+		{"Loopback255", true, true, "172.16.0.1/32"},
+		{"Loopback256", true, true, "172.16.1.1/32"},
+		{"Loopback257", true, false, ""},
+		{"Ethernet1", false, true, "10.0.0.1/31"},
+		{"Ethernet2", false, false, ""},
+		{"Ethernet3", false, false, "0.0.0.0/32"},
+	}
+	for i, tt := range tests {
+		got := ifaces[i]
+		assert.Equal(t, tt.name, got.Name)
+		assert.Equal(t, tt.loopback, got.Loopback, tt.name)
+		assert.Equal(t, tt.allocated, got.Allocated, tt.name)
+		if tt.ipNet == "" {
+			assert.Nil(t, got.IPNet, tt.name)
+			assert.Nil(t, got.IP(), tt.name)
+		} else {
+			require.NotNil(t, got.IPNet, tt.name)
+			assert.Equal(t, tt.ipNet, got.IPNet.String(), tt.name)
+		}
+	}
+	// The address is kept as assigned, not masked to the network.
+	assert.True(t, ifaces[3].IP().Equal(net.IPv4(10, 0, 0, 1)))
+
+	loopbacks := LoopbackInterfaces(ifaces)
+	require.Len(t, loopbacks, 3)
+	assert.Equal(t, LoopbackTypeVpnv4, loopbacks[0].LoopbackType)
+	assert.Equal(t, LoopbackTypeIpv4, loopbacks[1].LoopbackType)
+	assert.Equal(t, LoopbackTypePimRpAddr, loopbacks[2].LoopbackType)
+}
+
+func TestBuildDeviceInterfaces_Deprecated(t *testing.T) {
+	dev := Device{DeprecatedInterfaces: []Interface{
+		{Name: "lo0", LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{10, 2, 3, 4, 32}},
+	}}
+	ifaces := BuildDeviceInterfaces(dev)
+	require.Len(t, ifaces, 1)
+	assert.Equal(t, "lo0", ifaces[0].Name)
+	assert.True(t, ifaces[0].Loopback)
+}
+
+func TestGetDeviceInterfaces(t *testing.T) {
+	devicePK := solana.NewWallet().PublicKey()
+	client := New(&mockSolanaClient{payload: strings.TrimSuffix(devicePayload, "\n"), pubkey: devicePK}, solana.NewWallet().PublicKey())
+
+	ifaces, err := client.GetDeviceInterfaces(t.Context(), devicePK)
+	require.NoError(t, err)
+	require.Len(t, ifaces, 2)
+
+	assert.Equal(t, "switch1/1/1", ifaces[0].Name)
+	assert.False(t, ifaces[0].Loopback)
+	assert.True(t, ifaces[0].Allocated)
+	assert.Equal(t, "10.1.2.3/29", ifaces[0].IPNet.String())
+
+	assert.Equal(t, "lo0", ifaces[1].Name)
+	assert.True(t, ifaces[1].Loopback)
+	assert.Equal(t, LoopbackTypeVpnv4, ifaces[1].LoopbackType)
+	assert.Equal(t, "10.2.3.4/29", ifaces[1].IPNet.String())
+
+	loopbacks := LoopbackInterfaces(ifaces)
+	require.Len(t, loopbacks, 1)
+	assert.Equal(t, "lo0", loopbacks[0].Name)
+
+	_, err = client.GetDeviceInterfaces(t.Context(), solana.NewWallet().PublicKey())
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+
+	// An account of another type is not a device.
+	configPK := solana.NewWallet().PublicKey()
+	client = New(&mockSolanaClient{payload: configPayload, pubkey: configPK}, solana.NewWallet().PublicKey())
+	_, err = client.GetDeviceInterfaces(t.Context(), configPK)
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+
+	client = New(&accountNotFoundClient{}, solana.NewWallet().PublicKey())
+	_, err = client.GetDeviceInterfaces(t.Context(), devicePK)
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+}
+
+// accountNotFoundClient reports every account as missing the way the RPC
+// client does, with rpc.ErrNotFound.
+type accountNotFoundClient struct {
+	mockSolanaClient
+}
+
+func (*accountNotFoundClient) GetAccountInfo(context.Context, solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	return nil, rpc.ErrNotFound
+}
@@ -0,0 +1,69 @@
+package serviceability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DeviceLink is a link seen from one of its endpoint devices, with the device
+// on the other side resolved.
+type DeviceLink struct {
+	// Link is a named field rather than embedded so Link's MarshalJSON is not
+	// promoted and the fields below are kept in JSON output.
+	Link Link
+
+	// PeerPubKey is the pubkey of the device on the other side of the link.
+	PeerPubKey solana.PublicKey
+	// Peer is the device on the other side of the link, or nil if no device
+	// account exists for PeerPubKey. It points into the program data the link
+	// was built from, which may be the client's shared cached copy, so it
+	// must not be modified.
+	Peer *Device
+	// LocalIfaceName and PeerIfaceName are the link's interface names on the
+	// local and peer device.
+	LocalIfaceName string
+	PeerIfaceName  string
+}
+
+// GetLinksForDevice returns the links where the device with the given pubkey
+// is side A or side Z, in on-chain order. A device with no links yields an
+// empty result. It returns ErrDeviceNotFound if there is no such device.
+func (c *Client) GetLinksForDevice(ctx context.Context, devicePK solana.PublicKey) ([]DeviceLink, error) {
+	data, err := c.GetProgramData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program data: %w", err)
+	}
+	return BuildDeviceLinks(data, devicePK)
+}
+
+// BuildDeviceLinks returns the links adjacent to a device in already-fetched
+// program data. It returns ErrDeviceNotFound if there is no such device.
+func BuildDeviceLinks(data *ProgramData, devicePK solana.PublicKey) ([]DeviceLink, error) {
+	devices := make(map[solana.PublicKey]*Device, len(data.Devices))
+	for i := range data.Devices {
+		devices[solana.PublicKeyFromBytes(data.Devices[i].PubKey[:])] = &data.Devices[i]
+	}
+	if _, ok := devices[devicePK]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, devicePK)
+	}
+
+	out := []DeviceLink{}
+	for _, link := range data.Links {
+		sideA := solana.PublicKeyFromBytes(link.SideAPubKey[:])
+		sideZ := solana.PublicKeyFromBytes(link.SideZPubKey[:])
+		var dl DeviceLink
+		switch {
+		case sideA.Equals(devicePK):
+			dl = DeviceLink{Link: link, PeerPubKey: sideZ, LocalIfaceName: link.SideAIfaceName, PeerIfaceName: link.SideZIfaceName}
+		case sideZ.Equals(devicePK):
+			dl = DeviceLink{Link: link, PeerPubKey: sideA, LocalIfaceName: link.SideZIfaceName, PeerIfaceName: link.SideAIfaceName}
+		default:
+			continue
+		}
+		dl.Peer = devices[dl.PeerPubKey]
+		out = append(out, dl)
+	}
+	return out, nil
+}
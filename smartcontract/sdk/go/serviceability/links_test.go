@@ -0,0 +1,109 @@
+package serviceability
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDeviceLinks(t *testing.T) {
+	data := &ProgramData{
+		Devices: []Device{
+			{PubKey: [32]byte{1}, Code: "dz1"},
+			{PubKey: [32]byte{2}, Code: "dz2"},
+			{PubKey: [32]byte{3}, Code: "dz3"},
+			{PubKey: [32]byte{4}, Code: "dz4"},
+		},
+		Links: []Link{
+			{PubKey: [32]byte{10}, Code: "dz1:dz2", SideAPubKey: [32]byte{1}, SideZPubKey: [32]byte{2}, SideAIfaceName: "Ethernet1", SideZIfaceName: "Ethernet2"},
+			{PubKey: [32]byte{11}, Code: "dz3:dz1", SideAPubKey: [32]byte{3}, SideZPubKey: [32]byte{1}, SideAIfaceName: "Ethernet3", SideZIfaceName: "Ethernet4"},
+			{PubKey: [32]byte{12}, Code: "dz2:dz3", SideAPubKey: [32]byte{2}, SideZPubKey: [32]byte{3}},
+			// The peer device account no longer exists.
+			{PubKey: [32]byte{13}, Code: "dz1:gone", SideAPubKey: [32]byte{1}, SideZPubKey: [32]byte{9}},
+		},
+	}
+
+	links, err := BuildDeviceLinks(data, solana.PublicKey{1})
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+
+	tests := []struct {
+		code       string
+		peer       solana.PublicKey
+		peerCode   string
+		localIface string
+		peerIface  string
+	}{
+		{"dz1:dz2", solana.PublicKey{2}, "dz2", "Ethernet1", "Ethernet2"},
+		{"dz3:dz1", solana.PublicKey{3}, "dz3", "Ethernet4", "Ethernet3"},
+		{"dz1:gone", solana.PublicKey{9}, "", "", ""},
+	}
+	for i, tt := range tests {
+		got := links[i]
+		assert.Equal(t, tt.code, got.Link.Code)
+		assert.Equal(t, tt.peer, got.PeerPubKey, tt.code)
+		assert.Equal(t, tt.localIface, got.LocalIfaceName, tt.code)
+		assert.Equal(t, tt.peerIface, got.PeerIfaceName, tt.code)
+		if tt.peerCode == "" {
+			assert.Nil(t, got.Peer, tt.code)
+		} else {
+			require.NotNil(t, got.Peer, tt.code)
+			assert.Equal(t, tt.peerCode, got.Peer.Code)
+		}
+	}
+
+	links, err = BuildDeviceLinks(data, solana.PublicKey{4})
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = BuildDeviceLinks(data, solana.PublicKey{5})
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+}
+
+func TestDeviceLink_MarshalJSON(t *testing.T) {
+	dl := DeviceLink{
+		Link:           Link{Code: "dz1:dz2", Status: LinkStatusActivated},
+		PeerPubKey:     solana.PublicKey{2},
+		Peer:           &Device{Code: "dz2"},
+		LocalIfaceName: "Ethernet1",
+		PeerIfaceName:  "Ethernet2",
+	}
+
+	b, err := json.Marshal(dl)
+	require.NoError(t, err)
+
+	var got struct {
+		Link struct {
+			Code   string
+			Status string
+		}
+		PeerPubKey     string
+		Peer           struct{ Code string }
+		LocalIfaceName string
+		PeerIfaceName  string
+	}
+	require.NoError(t, json.Unmarshal(b, &got), string(b))
+	assert.Equal(t, "dz1:dz2", got.Link.Code)
+	assert.Equal(t, "activated", got.Link.Status)
+	assert.Equal(t, solana.PublicKey{2}.String(), got.PeerPubKey)
+	assert.Equal(t, "dz2", got.Peer.Code)
+	assert.Equal(t, "Ethernet1", got.LocalIfaceName)
+	assert.Equal(t, "Ethernet2", got.PeerIfaceName)
+}
+
+func TestGetLinksForDevice(t *testing.T) {
+	devicePK := solana.NewWallet().PublicKey()
+	client := New(&mockSolanaClient{payload: strings.TrimSuffix(devicePayload, "\n"), pubkey: devicePK}, solana.NewWallet().PublicKey())
+
+	links, err := client.GetLinksForDevice(t.Context(), devicePK)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = client.GetLinksForDevice(t.Context(), solana.NewWallet().PublicKey())
+	assert.True(t, errors.Is(err, ErrDeviceNotFound), "got %v", err)
+}
@@ -0,0 +1,170 @@
+package serviceability
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	MetricNameAccounts          = "doublezero_serviceability_accounts"
+	MetricNameAccountsActivated = "doublezero_serviceability_accounts_activated"
+	MetricNameMetroDevices      = "doublezero_serviceability_metro_devices"
+	MetricNameFetchErrors       = "doublezero_serviceability_inventory_fetch_errors_total"
+
+	LabelKind  = "kind"
+	LabelMetro = "metro"
+
+	KindDevice         = "device"
+	KindLink           = "link"
+	KindUser           = "user"
+	KindMulticastGroup = "multicast_group"
+
+	// unknownMetro labels devices whose exchange account was not found.
+	unknownMetro = "unknown"
+)
+
+// InventoryCollector periodically fetches program data and exports onchain
+// inventory counts as Prometheus gauges, so components report them uniformly.
+// When a fetch fails the gauges keep their last-good values and the fetch
+// error counter is incremented.
+type InventoryCollector struct {
+	log      *slog.Logger
+	provider ProgramDataProvider
+	interval time.Duration
+
+	accounts          *prometheus.GaugeVec
+	accountsActivated *prometheus.GaugeVec
+	metroDevices      *prometheus.GaugeVec
+	fetchErrors       prometheus.Counter
+
+	mu sync.Mutex
+}
+
+// NewInventoryCollector creates an InventoryCollector and registers its
+// metrics with reg.
+func NewInventoryCollector(log *slog.Logger, provider ProgramDataProvider, interval time.Duration, reg prometheus.Registerer) *InventoryCollector {
+	c := &InventoryCollector{
+		log:      log,
+		provider: provider,
+		interval: interval,
+		accounts: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameAccounts,
+				Help: "Number of serviceability accounts by kind",
+			},
+			[]string{LabelKind},
+		),
+		accountsActivated: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameAccountsActivated,
+				Help: "Number of activated serviceability accounts by kind",
+			},
+			[]string{LabelKind},
+		),
+		metroDevices: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNameMetroDevices,
+				Help: "Number of devices per metro (exchange code)",
+			},
+			[]string{LabelMetro},
+		),
+		fetchErrors: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: MetricNameFetchErrors,
+				Help: "Number of failed program data fetches by the inventory collector",
+			},
+		),
+	}
+
+	reg.MustRegister(c.accounts, c.accountsActivated, c.metroDevices, c.fetchErrors)
+
+	return c
+}
+
+// Run refreshes the gauges immediately and then every interval until ctx is
+// done.
+func (c *InventoryCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.log.Warn("Failed to refresh serviceability inventory metrics", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches program data once and updates the gauges. On error the
+// gauges are left unchanged.
+func (c *InventoryCollector) Refresh(ctx context.Context) error {
+	data, err := c.provider.GetProgramData(ctx)
+	if err != nil {
+		c.fetchErrors.Inc()
+		return err
+	}
+	c.update(data)
+	return nil
+}
+
+func (c *InventoryCollector) update(data *ProgramData) {
+	metros := make(map[[32]byte]string, len(data.Exchanges))
+	for _, ex := range data.Exchanges {
+		metros[ex.PubKey] = ex.Code
+	}
+
+	var activatedDevices, activatedLinks, activatedUsers, activatedGroups int
+	perMetro := make(map[string]int)
+	for _, dev := range data.Devices {
+		if dev.Status == DeviceStatusActivated {
+			activatedDevices++
+		}
+		metro, ok := metros[dev.ExchangePubKey]
+		if !ok {
+			metro = unknownMetro
+		}
+		perMetro[metro]++
+	}
+	for _, link := range data.Links {
+		if link.Status == LinkStatusActivated {
+			activatedLinks++
+		}
+	}
+	for _, user := range data.Users {
+		if user.Status == UserStatusActivated {
+			activatedUsers++
+		}
+	}
+	for _, group := range data.MulticastGroups {
+		if group.Status == MulticastGroupStatusActivated {
+			activatedGroups++
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.accounts.WithLabelValues(KindDevice).Set(float64(len(data.Devices)))
+	c.accounts.WithLabelValues(KindLink).Set(float64(len(data.Links)))
+	c.accounts.WithLabelValues(KindUser).Set(float64(len(data.Users)))
+	c.accounts.WithLabelValues(KindMulticastGroup).Set(float64(len(data.MulticastGroups)))
+
+	c.accountsActivated.WithLabelValues(KindDevice).Set(float64(activatedDevices))
+	c.accountsActivated.WithLabelValues(KindLink).Set(float64(activatedLinks))
+	c.accountsActivated.WithLabelValues(KindUser).Set(float64(activatedUsers))
+	c.accountsActivated.WithLabelValues(KindMulticastGroup).Set(float64(activatedGroups))
+
+	// Reset so metros without devices anymore drop out instead of going stale.
+	c.metroDevices.Reset()
+	for metro, n := range perMetro {
+		c.metroDevices.WithLabelValues(metro).Set(float64(n))
+	}
+}
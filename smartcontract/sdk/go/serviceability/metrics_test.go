@@ -0,0 +1,88 @@
+package serviceability
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProgramDataProvider struct {
+	data *ProgramData
+	err  error
+}
+
+func (f *fakeProgramDataProvider) GetProgramData(context.Context) (*ProgramData, error) {
+	return f.data, f.err
+}
+
+func (f *fakeProgramDataProvider) ProgramID() solana.PublicKey {
+	return solana.PublicKey{}
+}
+
+func TestInventoryCollector_Refresh(t *testing.T) {
+	ams, fra := [32]byte{1}, [32]byte{2}
+	provider := &fakeProgramDataProvider{data: &ProgramData{
+		Exchanges: []Exchange{
+			{PubKey: ams, Code: "ams"},
+			{PubKey: fra, Code: "fra"},
+		},
+		Devices: []Device{
+			{Code: "ams1", ExchangePubKey: ams, Status: DeviceStatusActivated},
+			{Code: "ams2", ExchangePubKey: ams, Status: DeviceStatusDrained},
+			{Code: "fra1", ExchangePubKey: fra, Status: DeviceStatusActivated},
+			{Code: "orphan", ExchangePubKey: [32]byte{9}, Status: DeviceStatusActivated},
+		},
+		Links: []Link{
+			{Code: "ams1:fra1", Status: LinkStatusActivated},
+			{Code: "ams2:fra1", Status: LinkStatusSoftDrained},
+		},
+		Users: []User{
+			{Status: UserStatusActivated},
+			{Status: UserStatusActivated},
+			{Status: UserStatusBanned},
+		},
+		MulticastGroups: []MulticastGroup{
+			{Code: "mg1", Status: MulticastGroupStatusActivated},
+		},
+	}}
+
+	reg := prometheus.NewRegistry()
+	c := NewInventoryCollector(slog.Default(), provider, time.Minute, reg)
+	require.NoError(t, c.Refresh(context.Background()))
+
+	for kind, want := range map[string]float64{KindDevice: 4, KindLink: 2, KindUser: 3, KindMulticastGroup: 1} {
+		require.Equal(t, want, testutil.ToFloat64(c.accounts.WithLabelValues(kind)), "total %s", kind)
+	}
+	for kind, want := range map[string]float64{KindDevice: 3, KindLink: 1, KindUser: 2, KindMulticastGroup: 1} {
+		require.Equal(t, want, testutil.ToFloat64(c.accountsActivated.WithLabelValues(kind)), "activated %s", kind)
+	}
+	require.Equal(t, 2.0, testutil.ToFloat64(c.metroDevices.WithLabelValues("ams")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.metroDevices.WithLabelValues("fra")))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.metroDevices.WithLabelValues(unknownMetro)))
+	require.Equal(t, 0.0, testutil.ToFloat64(c.fetchErrors))
+
+	// A failed fetch keeps the last-good values and counts the error.
+	provider.data, provider.err = nil, errors.New("rpc unavailable")
+	require.Error(t, c.Refresh(context.Background()))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.fetchErrors))
+	require.Equal(t, 4.0, testutil.ToFloat64(c.accounts.WithLabelValues(KindDevice)))
+	require.Equal(t, 2.0, testutil.ToFloat64(c.metroDevices.WithLabelValues("ams")))
+
+	// Metros that no longer have devices drop out on the next refresh.
+	provider.err = nil
+	provider.data = &ProgramData{
+		Exchanges: []Exchange{{PubKey: ams, Code: "ams"}},
+		Devices:   []Device{{Code: "ams1", ExchangePubKey: ams, Status: DeviceStatusActivated}},
+	}
+	require.NoError(t, c.Refresh(context.Background()))
+	require.Equal(t, 1, testutil.CollectAndCount(c.metroDevices))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.accounts.WithLabelValues(KindDevice)))
+	require.Equal(t, 0.0, testutil.ToFloat64(c.accounts.WithLabelValues(KindLink)))
+}
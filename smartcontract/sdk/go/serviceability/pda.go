@@ -24,6 +24,8 @@ const (
 	SeedAccessPass              = "accesspass"
 	SeedTunnelIds               = "tunnelids"
 	SeedDzPrefixBlock           = "dzprefixblock"
+	SeedVrfIds                  = "vrfids"
+	SeedAdminGroupBits          = "admingroupbits"
 )
 
 // DeriveGlobalStatePDA derives the PDA for the GlobalState account.
@@ -112,6 +114,24 @@ func GetMulticastPublisherBlockPDA(programID solana.PublicKey) (solana.PublicKey
 	return solana.FindProgramAddress(seeds, programID)
 }
 
+// GetVrfIdsPDA derives the PDA for the global VrfIds resource extension
+func GetVrfIdsPDA(programID solana.PublicKey) (solana.PublicKey, uint8, error) {
+	seeds := [][]byte{
+		[]byte(SeedPrefix),
+		[]byte(SeedVrfIds),
+	}
+	return solana.FindProgramAddress(seeds, programID)
+}
+
+// GetAdminGroupBitsPDA derives the PDA for the global AdminGroupBits resource extension
+func GetAdminGroupBitsPDA(programID solana.PublicKey) (solana.PublicKey, uint8, error) {
+	seeds := [][]byte{
+		[]byte(SeedPrefix),
+		[]byte(SeedAdminGroupBits),
+	}
+	return solana.FindProgramAddress(seeds, programID)
+}
+
 // GetTenantPDA derives the PDA for a tenant account based on its code
 func GetTenantPDA(programID solana.PublicKey, code string) (solana.PublicKey, uint8, error) {
 	seeds := [][]byte{
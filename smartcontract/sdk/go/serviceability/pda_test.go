@@ -111,3 +111,17 @@ func TestUserPDA_DiffersByUserType(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEqual(t, pdaIBRL, pdaMulticast)
 }
+
+func TestGetResourceExtensionPDA(t *testing.T) {
+	programID := solana.NewWallet().PublicKey()
+
+	want, _, err := serviceability.GetVrfIdsPDA(programID)
+	require.NoError(t, err)
+	got, _, err := serviceability.GetResourceExtensionPDA(programID, serviceability.ResourceTypeVrfIds, solana.PublicKey{}, 0)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Equal(t, recomputePDA(t, programID, [][]byte{[]byte("doublezero"), []byte("vrfids")}), got)
+
+	_, _, err = serviceability.GetResourceExtensionPDA(programID, serviceability.ResourceType(99), solana.PublicKey{}, 0)
+	require.ErrorContains(t, err, "unknown resource type 99")
+}
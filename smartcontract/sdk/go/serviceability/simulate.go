@@ -0,0 +1,215 @@
+package serviceability
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ResourceType identifies an on-chain resource pool. Values mirror the
+// ResourceType enum in the serviceability program's resource.rs.
+type ResourceType uint8
+
+const (
+	ResourceTypeDeviceTunnelBlock ResourceType = iota
+	ResourceTypeUserTunnelBlock
+	ResourceTypeMulticastGroupBlock
+	ResourceTypeMulticastPublisherBlock
+	ResourceTypeDzPrefixBlock // per device, keyed by (device pubkey, index)
+	ResourceTypeTunnelIds     // per device, keyed by (device pubkey, index)
+	ResourceTypeLinkIds
+	ResourceTypeSegmentRoutingIds
+	ResourceTypeVrfIds
+	ResourceTypeAdminGroupBits
+)
+
+func (t ResourceType) String() string {
+	switch t {
+	case ResourceTypeDeviceTunnelBlock:
+		return "DeviceTunnelBlock"
+	case ResourceTypeUserTunnelBlock:
+		return "UserTunnelBlock"
+	case ResourceTypeMulticastGroupBlock:
+		return "MulticastGroupBlock"
+	case ResourceTypeMulticastPublisherBlock:
+		return "MulticastPublisherBlock"
+	case ResourceTypeDzPrefixBlock:
+		return "DzPrefixBlock"
+	case ResourceTypeTunnelIds:
+		return "TunnelIds"
+	case ResourceTypeLinkIds:
+		return "LinkIds"
+	case ResourceTypeSegmentRoutingIds:
+		return "SegmentRoutingIds"
+	case ResourceTypeVrfIds:
+		return "VrfIds"
+	case ResourceTypeAdminGroupBits:
+		return "AdminGroupBits"
+	default:
+		return "unknown"
+	}
+}
+
+// GetResourceExtensionPDA derives the PDA of the resource extension for
+// resourceType. associatedPK and index are only used by the per-device pools
+// (DzPrefixBlock and TunnelIds).
+func GetResourceExtensionPDA(programID solana.PublicKey, resourceType ResourceType, associatedPK solana.PublicKey, index uint64) (solana.PublicKey, uint8, error) {
+	switch resourceType {
+	case ResourceTypeDeviceTunnelBlock:
+		return GetDeviceTunnelBlockPDA(programID)
+	case ResourceTypeUserTunnelBlock:
+		return GetUserTunnelBlockPDA(programID)
+	case ResourceTypeMulticastGroupBlock:
+		return GetMulticastGroupBlockPDA(programID)
+	case ResourceTypeMulticastPublisherBlock:
+		return GetMulticastPublisherBlockPDA(programID)
+	case ResourceTypeDzPrefixBlock:
+		return GetDzPrefixBlockPDA(programID, associatedPK, index)
+	case ResourceTypeTunnelIds:
+		return GetTunnelIdsPDA(programID, associatedPK, index)
+	case ResourceTypeLinkIds:
+		return GetLinkIdsPDA(programID)
+	case ResourceTypeSegmentRoutingIds:
+		return GetSegmentRoutingIdsPDA(programID)
+	case ResourceTypeVrfIds:
+		return GetVrfIdsPDA(programID)
+	case ResourceTypeAdminGroupBits:
+		return GetAdminGroupBitsPDA(programID)
+	default:
+		return solana.PublicKey{}, 0, fmt.Errorf("unknown resource type %d", resourceType)
+	}
+}
+
+// AllocationSimulation is the outcome of a dry-run allocation.
+type AllocationSimulation struct {
+	WouldSucceed bool
+	Reason       string
+}
+
+// SimulateAllocate reports whether allocating requested from the resource pool
+// would succeed against current on-chain state, without submitting anything.
+// requested is a CIDR or IP (/32) for IP pools and an integer for ID pools, as
+// accepted by `doublezero resource allocate`; empty means the next free slot.
+func (c *Client) SimulateAllocate(ctx context.Context, resourceType ResourceType, associatedPK solana.PublicKey, index uint64, requested string) (*AllocationSimulation, error) {
+	pda, _, err := GetResourceExtensionPDA(c.programID, resourceType, associatedPK, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive %s PDA: %w", resourceType, err)
+	}
+	ext, err := c.getResourceExtension(ctx, pda)
+	if err != nil {
+		return nil, err
+	}
+	sim := SimulateAllocation(ext, requested)
+	return &sim, nil
+}
+
+// SimulateAllocation applies the program's allocation checks to an
+// already-fetched resource extension. A nil ext means the pool account does not
+// exist.
+func SimulateAllocation(ext *ResourceExtension, requested string) AllocationSimulation {
+	if ext == nil {
+		return AllocationSimulation{Reason: "resource extension account does not exist"}
+	}
+	if requested == "" {
+		if ext.AvailableCount() <= 0 {
+			return AllocationSimulation{Reason: fmt.Sprintf("pool is full (%d of %d allocated)", ext.AllocatedCount(), ext.TotalCapacity())}
+		}
+		return AllocationSimulation{WouldSucceed: true, Reason: fmt.Sprintf("%d of %d free", ext.AvailableCount(), ext.TotalCapacity())}
+	}
+
+	switch ext.Allocator.Type {
+	case AllocatorTypeIp:
+		if ext.Allocator.IpAllocator == nil {
+			return AllocationSimulation{Reason: "pool has no IP allocator"}
+		}
+		return simulateIPAllocation(ext, ext.Allocator.IpAllocator.BaseNet, requested)
+	case AllocatorTypeId:
+		if ext.Allocator.IdAllocator == nil {
+			return AllocationSimulation{Reason: "pool has no ID allocator"}
+		}
+		return simulateIDAllocation(ext, *ext.Allocator.IdAllocator, requested)
+	default:
+		return AllocationSimulation{Reason: fmt.Sprintf("unknown allocator type %d", ext.Allocator.Type)}
+	}
+}
+
+func simulateIPAllocation(ext *ResourceExtension, baseNet [5]byte, requested string) AllocationSimulation {
+	ip, prefixLen, err := parseRequestedNet(requested)
+	if err != nil {
+		return AllocationSimulation{Reason: err.Error()}
+	}
+	basePrefixLen := int(baseNet[4])
+	if prefixLen < basePrefixLen {
+		return AllocationSimulation{Reason: fmt.Sprintf("%s is larger than the pool %s", requested, onChainNetToString(baseNet))}
+	}
+
+	base := binary.BigEndian.Uint32(baseNet[:4])
+	baseMask := ^uint32(0) << (32 - basePrefixLen)
+	if basePrefixLen == 0 {
+		baseMask = 0
+	}
+	if ip&baseMask != base&baseMask {
+		return AllocationSimulation{Reason: fmt.Sprintf("%s is outside the pool %s", requested, onChainNetToString(baseNet))}
+	}
+
+	size := uint64(1) << (32 - prefixLen)
+	offset := uint64(ip - base)
+	if offset%size != 0 {
+		return AllocationSimulation{Reason: fmt.Sprintf("%s is not aligned to a /%d boundary", requested, prefixLen)}
+	}
+	if offset+size > uint64(ext.TotalCapacity()) {
+		return AllocationSimulation{Reason: fmt.Sprintf("%s is outside the allocatable range of %s", requested, onChainNetToString(baseNet))}
+	}
+	for i := offset; i < offset+size; i++ {
+		if isAllocated(ext.Storage, i) {
+			return AllocationSimulation{Reason: fmt.Sprintf("%s is already allocated (or partially allocated)", requested)}
+		}
+	}
+	return AllocationSimulation{WouldSucceed: true, Reason: fmt.Sprintf("%s is free in %s", requested, onChainNetToString(baseNet))}
+}
+
+func simulateIDAllocation(ext *ResourceExtension, alloc IdAllocator, requested string) AllocationSimulation {
+	id, err := strconv.ParseUint(requested, 10, 16)
+	if err != nil {
+		return AllocationSimulation{Reason: fmt.Sprintf("invalid ID %q", requested)}
+	}
+	if uint16(id) < alloc.RangeStart || uint16(id) >= alloc.RangeEnd {
+		return AllocationSimulation{Reason: fmt.Sprintf("%d is outside the range %s", id, ext.RangeString())}
+	}
+	if isAllocated(ext.Storage, uint64(uint16(id)-alloc.RangeStart)) {
+		return AllocationSimulation{Reason: fmt.Sprintf("%d is already allocated", id)}
+	}
+	return AllocationSimulation{WouldSucceed: true, Reason: fmt.Sprintf("%d is free in %s", id, ext.RangeString())}
+}
+
+// parseRequestedNet parses an IPv4 CIDR, or a bare IPv4 address as a /32. The
+// address is kept as given rather than masked, as the program does.
+func parseRequestedNet(requested string) (uint32, int, error) {
+	addr, prefixLen := requested, 32
+	if i := strings.IndexByte(requested, '/'); i >= 0 {
+		n, err := strconv.Atoi(requested[i+1:])
+		if err != nil || n < 0 || n > 32 {
+			return 0, 0, fmt.Errorf("invalid prefix length in %q", requested)
+		}
+		addr, prefixLen = requested[:i], n
+	}
+	ip := net.ParseIP(addr).To4()
+	if ip == nil {
+		return 0, 0, fmt.Errorf("invalid IPv4 address %q", requested)
+	}
+	return binary.BigEndian.Uint32(ip), prefixLen, nil
+}
+
+// isAllocated reports whether bit i of the allocator bitmap is set. Bits past
+// the end of the bitmap are treated as allocated.
+func isAllocated(storage []byte, i uint64) bool {
+	if i/8 >= uint64(len(storage)) {
+		return true
+	}
+	return storage[i/8]&(1<<(i%8)) != 0
+}
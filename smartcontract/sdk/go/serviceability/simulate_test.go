@@ -0,0 +1,135 @@
+package serviceability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateAllocation_ID(t *testing.T) {
+	// IDs [100, 164) with 100 and 102 allocated.
+	ext := &ResourceExtension{
+		Allocator: Allocator{Type: AllocatorTypeId, IdAllocator: &IdAllocator{RangeStart: 100, RangeEnd: 164}},
+		Storage:   []byte{0x05, 0, 0, 0, 0, 0, 0, 0},
+	}
+
+	tests := []struct {
+		requested string
+		want      bool
+		reason    string
+	}{
+		{"101", true, "101 is free in [100, 164)"},
+		{"163", true, "163 is free in [100, 164)"},
+		{"100", false, "100 is already allocated"},
+		{"102", false, "102 is already allocated"},
+		{"99", false, "99 is outside the range [100, 164)"},
+		{"164", false, "164 is outside the range [100, 164)"},
+		{"10.0.0.1", false, `invalid ID "10.0.0.1"`},
+		{"", true, "62 of 64 free"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			got := SimulateAllocation(ext, tt.requested)
+			require.Equal(t, tt.want, got.WouldSucceed)
+			require.Equal(t, tt.reason, got.Reason)
+		})
+	}
+}
+
+func TestSimulateAllocation_IP(t *testing.T) {
+	// 10.0.0.0/26 with 10.0.0.0/31 and 10.0.0.9 allocated.
+	ext := &ResourceExtension{
+		Allocator: Allocator{Type: AllocatorTypeIp, IpAllocator: &IpAllocator{BaseNet: [5]byte{10, 0, 0, 0, 26}}},
+		Storage:   []byte{0x03, 0x02, 0, 0, 0, 0, 0, 0},
+	}
+
+	tests := []struct {
+		requested string
+		want      bool
+		reason    string
+	}{
+		{"10.0.0.2/31", true, "10.0.0.2/31 is free in 10.0.0.0/26"},
+		{"10.0.0.63", true, "10.0.0.63 is free in 10.0.0.0/26"},
+		{"10.0.0.0/31", false, "10.0.0.0/31 is already allocated (or partially allocated)"},
+		{"10.0.0.1", false, "10.0.0.1 is already allocated (or partially allocated)"},
+		{"10.0.0.8/30", false, "10.0.0.8/30 is already allocated (or partially allocated)"},
+		{"10.0.0.3/31", false, "10.0.0.3/31 is not aligned to a /31 boundary"},
+		{"10.0.0.64/31", false, "10.0.0.64/31 is outside the pool 10.0.0.0/26"},
+		{"10.0.0.0/24", false, "10.0.0.0/24 is larger than the pool 10.0.0.0/26"},
+		{"42", false, `invalid IPv4 address "42"`},
+		{"10.0.0.0/33", false, `invalid prefix length in "10.0.0.0/33"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			got := SimulateAllocation(ext, tt.requested)
+			require.Equal(t, tt.want, got.WouldSucceed)
+			require.Equal(t, tt.reason, got.Reason)
+		})
+	}
+}
+
+func TestSimulateAllocation_FullAndMissing(t *testing.T) {
+	full := &ResourceExtension{
+		Allocator: Allocator{Type: AllocatorTypeId, IdAllocator: &IdAllocator{RangeStart: 0, RangeEnd: 8}},
+		Storage:   []byte{0xff, 0, 0, 0, 0, 0, 0, 0},
+	}
+	got := SimulateAllocation(full, "")
+	require.False(t, got.WouldSucceed)
+	require.Equal(t, "pool is full (8 of 8 allocated)", got.Reason)
+
+	got = SimulateAllocation(nil, "1")
+	require.False(t, got.WouldSucceed)
+	require.Equal(t, "resource extension account does not exist", got.Reason)
+}
+
+type resourceExtensionRPCClient struct {
+	accounts map[solana.PublicKey][]byte
+}
+
+func (m *resourceExtensionRPCClient) GetProgramAccounts(context.Context, solana.PublicKey) (rpc.GetProgramAccountsResult, error) {
+	return nil, nil
+}
+
+func (m *resourceExtensionRPCClient) GetAccountInfo(_ context.Context, pk solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	data, ok := m.accounts[pk]
+	if !ok {
+		return &rpc.GetAccountInfoResult{}, nil
+	}
+	return &rpc.GetAccountInfoResult{Value: &rpc.Account{Data: rpc.DataBytesOrJSONFromBytes(data)}}, nil
+}
+
+func TestSimulateAllocate(t *testing.T) {
+	programID := solana.NewWallet().PublicKey()
+	device := solana.NewWallet().PublicKey()
+
+	// TunnelIds for device at index 0: IDs [500, 564) with 500 allocated.
+	data := make([]byte, resourceExtensionBitmapOffset+8)
+	data[0] = byte(ResourceExtensionType)
+	copy(data[34:66], device[:])
+	data[66] = byte(AllocatorTypeId)
+	data[67], data[68] = 0xf4, 0x01 // 500
+	data[69], data[70] = 0x34, 0x02 // 564
+	data[resourceExtensionBitmapOffset] = 0x01
+
+	pda, _, err := GetTunnelIdsPDA(programID, device, 0)
+	require.NoError(t, err)
+	client := New(&resourceExtensionRPCClient{accounts: map[solana.PublicKey][]byte{pda: data}}, programID)
+
+	got, err := client.SimulateAllocate(context.Background(), ResourceTypeTunnelIds, device, 0, "501")
+	require.NoError(t, err)
+	require.True(t, got.WouldSucceed, got.Reason)
+
+	got, err = client.SimulateAllocate(context.Background(), ResourceTypeTunnelIds, device, 0, "500")
+	require.NoError(t, err)
+	require.False(t, got.WouldSucceed)
+	require.Equal(t, "500 is already allocated", got.Reason)
+
+	// A different index resolves to a pool that does not exist.
+	got, err = client.SimulateAllocate(context.Background(), ResourceTypeTunnelIds, device, 1, "501")
+	require.NoError(t, err)
+	require.False(t, got.WouldSucceed)
+	require.Equal(t, "resource extension account does not exist", got.Reason)
+}
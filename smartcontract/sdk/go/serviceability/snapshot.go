@@ -0,0 +1,356 @@
+package serviceability
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mr-tron/base58"
+)
+
+// SnapshotVersion is the format version written by SaveProgramData. LoadProgramData rejects
+// snapshots written with any other version.
+const SnapshotVersion = 1
+
+type programDataSnapshot struct {
+	Version     int             `json:"version"`
+	ProgramData json.RawMessage `json:"program_data"`
+}
+
+// SaveProgramData writes data to w as a JSON snapshot that LoadProgramData restores exactly.
+//
+// Unlike the display-oriented MarshalJSON methods on the account types, the snapshot keeps every
+// field under its Go name and encodes the packed on-chain formats losslessly:
+//
+//   - [32]byte keys are base58 strings.
+//   - [4]uint8 addresses are dotted-decimal strings.
+//   - [5]uint8 networks are CIDR strings; host bits and out-of-range prefix lengths are preserved.
+//   - Enums are their numeric values and []byte is base64.
+//   - Nil slices and pointers are null, so they stay distinct from empty ones.
+//
+// Fields tagged `json:"-"` (such as Device.DeserializeError) are not saved.
+func SaveProgramData(w io.Writer, data *ProgramData) error {
+	if data == nil {
+		return fmt.Errorf("program data is nil")
+	}
+	encoded, err := encodeSnapshotValue(reflect.ValueOf(data).Elem())
+	if err != nil {
+		return fmt.Errorf("failed to encode program data: %w", err)
+	}
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to encode program data: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(programDataSnapshot{Version: SnapshotVersion, ProgramData: raw})
+}
+
+// LoadProgramData reads a snapshot written by SaveProgramData. Unknown fields are rejected so a
+// snapshot that no longer matches the account layout fails loudly instead of loading partially.
+func LoadProgramData(r io.Reader) (*ProgramData, error) {
+	var snapshot programDataSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (want %d)", snapshot.Version, SnapshotVersion)
+	}
+	if len(snapshot.ProgramData) == 0 {
+		return nil, fmt.Errorf("snapshot has no program data")
+	}
+	data := &ProgramData{}
+	if err := decodeSnapshotValue(snapshot.ProgramData, reflect.ValueOf(data).Elem(), "program_data"); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// snapshotObject is a JSON object that keeps struct fields in declaration order, which keeps
+// snapshots stable and readable as golden files.
+type snapshotObject struct {
+	keys   []string
+	values []any
+}
+
+func (o snapshotObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// snapshotField reports whether a struct field is part of the snapshot.
+func snapshotField(f reflect.StructField) bool {
+	return f.IsExported() && f.Tag.Get("json") != "-"
+}
+
+func isByteArray(t reflect.Type, n int) bool {
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 && t.Len() == n
+}
+
+// encodeSnapshotValue converts v into a value whose standard JSON encoding is the snapshot form.
+// It works on reflect kinds rather than calling json.Marshal on v so the display MarshalJSON
+// methods on the account and enum types are bypassed.
+func encodeSnapshotValue(v reflect.Value) (any, error) {
+	t := v.Type()
+	switch {
+	case isByteArray(t, 32):
+		b := make([]byte, 32)
+		reflect.Copy(reflect.ValueOf(b), v)
+		return base58.Encode(b), nil
+	case isByteArray(t, 4):
+		return fmt.Sprintf("%d.%d.%d.%d", v.Index(0).Uint(), v.Index(1).Uint(), v.Index(2).Uint(), v.Index(3).Uint()), nil
+	case isByteArray(t, 5):
+		return fmt.Sprintf("%d.%d.%d.%d/%d", v.Index(0).Uint(), v.Index(1).Uint(), v.Index(2).Uint(), v.Index(3).Uint(), v.Index(4).Uint()), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeSnapshotValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			item, err := encodeSnapshotValue(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Struct:
+		obj := snapshotObject{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !snapshotField(field) {
+				continue
+			}
+			value, err := encodeSnapshotValue(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+			}
+			obj.keys = append(obj.keys, field.Name)
+			obj.values = append(obj.values, value)
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// decodeSnapshotValue is the inverse of encodeSnapshotValue. path names the value in errors.
+func decodeSnapshotValue(raw json.RawMessage, v reflect.Value, path string) error {
+	t := v.Type()
+	isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+	if isByteArray(t, 32) || isByteArray(t, 4) || isByteArray(t, 5) {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		b, err := parseSnapshotBytes(s, t.Len())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%s: %d overflows %s", path, n, t)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("%s: %d overflows %s", path, n, t)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetString(s)
+	case reflect.Pointer:
+		if isNull {
+			v.SetZero()
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := decodeSnapshotValue(raw, elem.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		if isNull {
+			v.SetZero()
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeSnapshotValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(items) != v.Len() {
+			return fmt.Errorf("%s: expected %d elements, got %d", path, v.Len(), len(items))
+		}
+		for i, item := range items {
+			if err := decodeSnapshotValue(item, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if fields == nil {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !snapshotField(field) {
+				continue
+			}
+			fieldRaw, ok := fields[field.Name]
+			if !ok {
+				continue
+			}
+			delete(fields, field.Name)
+			if err := decodeSnapshotValue(fieldRaw, v.Field(i), path+"."+field.Name); err != nil {
+				return err
+			}
+		}
+		for name := range fields {
+			return fmt.Errorf("%s: unknown field %q", path, name)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, t)
+	}
+	return nil
+}
+
+// parseSnapshotBytes parses the string forms of the packed byte arrays: base58 for 32 bytes,
+// dotted-decimal for 4 and "a.b.c.d/len" for 5.
+func parseSnapshotBytes(s string, n int) ([]byte, error) {
+	switch n {
+	case 32:
+		b, err := base58.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base58 key %q: %w", s, err)
+		}
+		if len(b) != 32 {
+			return nil, fmt.Errorf("invalid key %q: decoded to %d bytes, want 32", s, len(b))
+		}
+		return b, nil
+	case 4:
+		ip := net.ParseIP(s).To4()
+		if ip == nil || strings.Contains(s, ":") {
+			return nil, fmt.Errorf("invalid IPv4 address %q", s)
+		}
+		return ip, nil
+	case 5:
+		addr, bits, ok := strings.Cut(s, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid network %q: missing prefix length", s)
+		}
+		ip, err := parseSnapshotBytes(addr, 4)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", s, err)
+		}
+		prefixLen, err := strconv.ParseUint(bits, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: invalid prefix length", s)
+		}
+		return append(ip, byte(prefixLen)), nil
+	}
+	return nil, fmt.Errorf("unsupported byte array length %d", n)
+}
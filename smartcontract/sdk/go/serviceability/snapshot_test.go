@@ -0,0 +1,166 @@
+package serviceability
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshotProgramData() *ProgramData {
+	ams := [32]byte{1}
+	dev1 := [32]byte{10, 0xff}
+	dev2 := [32]byte{11}
+	owner := [32]byte{0xde, 0xad, 0xbe, 0xef}
+
+	return &ProgramData{
+		GlobalState: &GlobalState{
+			AccountType:          GlobalStateType,
+			AccountIndex:         Uint128{High: 1, Low: 1<<64 - 1},
+			FoundationAllowlist:  [][32]byte{owner, {2}},
+			ActivatorAuthorityPK: [32]byte{3},
+			FeatureFlags:         Uint128{Low: 5},
+			PubKey:               [32]byte{4},
+		},
+		GlobalConfig: &GlobalConfig{
+			AccountType:       GlobalConfigType,
+			Owner:             owner,
+			LocalASN:          65000,
+			RemoteASN:         65342,
+			DeviceTunnelBlock: [5]uint8{172, 16, 0, 0, 16},
+			UserTunnelBlock:   [5]uint8{169, 254, 0, 0, 16},
+			// Host bits set: restored as-is rather than masked.
+			MulticastGroupBlock: [5]uint8{233, 84, 178, 7, 24},
+			PubKey:              [32]byte{5},
+		},
+		Locations: []Location{
+			{AccountType: LocationType, Owner: owner, Lat: 52.3676, Lng: 4.9041, LocId: 7, Status: LocationStatusActivated, Code: "ams", Name: "Amsterdam", Country: "NL", PubKey: [32]byte{6}},
+		},
+		Exchanges: []Exchange{
+			{AccountType: ExchangeType, Owner: owner, Code: "xams", Name: "Amsterdam", Device1PK: dev1, Device2PK: dev2, PubKey: ams},
+		},
+		Contributors: []Contributor{
+			{AccountType: ContributorType, Owner: owner, Code: "co01", ReferenceCount: 2, OpsManagerPK: [32]byte{7}, PubKey: [32]byte{8}},
+		},
+		Tenants: []Tenant{
+			{AccountType: TenantType, Code: "acme", VrfId: 3, Administrators: [][32]byte{owner}, MetroRouting: true, BillingRate: 42, PubKey: [32]byte{9}},
+		},
+		Devices: []Device{
+			{
+				AccountType:    DeviceType,
+				Owner:          owner,
+				Index:          Uint128{Low: 12},
+				LocationPubKey: [32]byte{6},
+				ExchangePubKey: ams,
+				DeviceType:     DeviceDeviceTypeHybrid,
+				PublicIp:       [4]uint8{203, 0, 113, 10},
+				Status:         DeviceStatusActivated,
+				Code:           "ams-dz1",
+				DzPrefixes:     [][5]uint8{{100, 64, 0, 0, 29}, {100, 64, 0, 8, 29}},
+				MgmtVrf:        "mgmt",
+				DeprecatedInterfaces: []Interface{
+					{Version: 1, Status: InterfaceStatusActivated, Name: "Loopback255", InterfaceType: InterfaceTypeLoopback, LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}, NodeSegmentIdx: 101},
+				},
+				Interfaces: []Interface{
+					{
+						Size: 64, Version: 2, Status: InterfaceStatusActivated, Name: "Loopback255", InterfaceType: InterfaceTypeLoopback, LoopbackType: LoopbackTypeVpnv4,
+						Bandwidth: 10_000_000_000, Mtu: 9000, IpNet: [5]uint8{172, 16, 0, 1, 32}, NodeSegmentIdx: 101,
+						FlexAlgoNodeSegments: []FlexAlgoNodeSegment{{Topology: [32]byte{30}, NodeSegmentIdx: 201}},
+					},
+				},
+				DeserializeError: errors.New("not saved"),
+				PubKey:           dev1,
+			},
+		},
+		Links: []Link{
+			{AccountType: LinkType, SideAPubKey: dev1, SideZPubKey: dev2, LinkType: LinkLinkTypeWAN, Bandwidth: 10_000_000_000, Mtu: 9000, DelayNs: 1_500_000, TunnelId: 500, TunnelNet: [5]uint8{172, 16, 0, 0, 31}, Status: LinkStatusActivated, Code: "ams-dz1:fra-dz1", SideAIfaceName: "Ethernet1", LinkTopologies: [][32]byte{{30}}, PubKey: [32]byte{20}},
+		},
+		Users: []User{
+			{AccountType: UserType, UserType: UserTypeIBRL, DevicePubKey: dev1, ClientIp: [4]uint8{198, 51, 100, 1}, DzIp: [4]uint8{100, 64, 0, 1}, TunnelNet: [5]uint8{169, 254, 0, 0, 31}, Status: UserStatusActivated, Publishers: [][32]uint8{}, LastBgpUpAt: 1<<63 + 1, PubKey: [32]byte{40}},
+		},
+		MulticastGroups: []MulticastGroup{
+			{AccountType: MulticastGroupType, MulticastIp: [4]uint8{233, 84, 178, 1}, Code: "mg01", PubKey: [32]byte{50}},
+		},
+		ProgramConfig: &ProgramConfig{AccountType: ProgramConfigType, Version: ProgramVersion{Major: 0, Minor: 8, Patch: 3}},
+		AccessPasses: []AccessPass{
+			{AccountType: AccessPassType, AccessPassTypeTag: AccessPassTypeEdgeSeat, FeedSeats: []FeedSeat{{FeedKey: [32]byte{60}, MaxUsers: 2}}, ClientIp: [4]uint8{198, 51, 100, 1}, Status: AccessPassStatusConnected, PubKey: [32]byte{61}},
+		},
+		ResourceExtensions: []ResourceExtension{
+			{AccountType: ResourceExtensionType, AssociatedWith: dev1, Allocator: Allocator{Type: AllocatorTypeId, IdAllocator: &IdAllocator{RangeStart: 500, RangeEnd: 564, FirstFreeIndex: 1}}, Storage: []byte{0x01, 0, 0, 0, 0, 0, 0, 0}, PubKey: [32]byte{70}},
+			{AccountType: ResourceExtensionType, Allocator: Allocator{Type: AllocatorTypeIp, IpAllocator: &IpAllocator{BaseNet: [5]byte{172, 16, 0, 0, 16}}}, Storage: []byte{}, PubKey: [32]byte{71}},
+		},
+		Topologies: []TopologyInfo{
+			{AccountType: TopologyType, PubKey: [32]byte{30}},
+		},
+		Feeds: []Feed{
+			{AccountType: FeedType, Code: "feed1", Exchange: ams, Groups: [][32]byte{{50}}, PubKey: [32]byte{60}},
+		},
+	}
+}
+
+func TestProgramDataSnapshot_RoundTrip(t *testing.T) {
+	data := testSnapshotProgramData()
+
+	var buf bytes.Buffer
+	require.NoError(t, SaveProgramData(&buf, data))
+
+	got, err := LoadProgramData(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	// DeserializeError is not part of the snapshot.
+	data.Devices[0].DeserializeError = nil
+	require.Equal(t, data, got)
+
+	// Nil and empty slices stay distinct.
+	require.Nil(t, got.Users[0].Subscribers)
+	require.NotNil(t, got.Users[0].Publishers)
+	require.NotNil(t, got.ResourceExtensions[1].Storage)
+	require.Nil(t, got.Permissions)
+
+	// Saving the restored data reproduces the snapshot byte for byte.
+	var again bytes.Buffer
+	require.NoError(t, SaveProgramData(&again, got))
+	require.Equal(t, buf.String(), again.String())
+}
+
+func TestProgramDataSnapshot_Encoding(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, SaveProgramData(&buf, testSnapshotProgramData()))
+	out := buf.String()
+
+	require.Contains(t, out, `"version": 1`)
+	require.Contains(t, out, `"PublicIp": "203.0.113.10"`)
+	require.Contains(t, out, `"MulticastGroupBlock": "233.84.178.7/24"`)
+	require.Contains(t, out, `"ExchangePubKey": "4uQeVj5tqViQh7yWWGStvkEG1Zmhx6uasJtWCJziofM"`)
+	// Enums are saved as their numeric values, not the display strings.
+	require.Contains(t, out, `"AccessPassTypeTag": 4`)
+	require.Contains(t, out, `"Storage": "AQAAAAAAAAA="`)
+	require.NotContains(t, out, "DeserializeError")
+}
+
+func TestProgramDataSnapshot_LoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		wantErr  string
+	}{
+		{"version", `{"version": 2, "program_data": {}}`, "unsupported snapshot version 2"},
+		{"missing data", `{"version": 1}`, "snapshot has no program data"},
+		{"unknown field", `{"version": 1, "program_data": {"Widgets": []}}`, `unknown field "Widgets"`},
+		{"bad pubkey", `{"version": 1, "program_data": {"Feeds": [{"PubKey": "0OIl"}]}}`, "program_data.Feeds[0].PubKey: invalid base58 key"},
+		{"short pubkey", `{"version": 1, "program_data": {"Feeds": [{"PubKey": "11"}]}}`, "decoded to 2 bytes, want 32"},
+		{"bad ip", `{"version": 1, "program_data": {"Users": [{"ClientIp": "::1"}]}}`, `invalid IPv4 address "::1"`},
+		{"bad network", `{"version": 1, "program_data": {"Links": [{"TunnelNet": "10.0.0.0"}]}}`, "missing prefix length"},
+		{"overflow", `{"version": 1, "program_data": {"Links": [{"Mtu": 4294967296}]}}`, "overflows uint32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadProgramData(strings.NewReader(tt.snapshot))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	require.ErrorContains(t, SaveProgramData(&bytes.Buffer{}, nil), "program data is nil")
+}
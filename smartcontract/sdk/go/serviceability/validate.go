@@ -0,0 +1,124 @@
+package serviceability
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// multicastNet is the IPv4 multicast range, 224.0.0.0/4.
+var multicastNet = &net.IPNet{IP: net.IPv4(224, 0, 0, 0).To4(), Mask: net.CIDRMask(4, 32)}
+
+// ValidationError is an inconsistency in program data found by
+// ProgramData.Validate.
+type ValidationError struct {
+	Type   string // "device", "link", "user" or "multicast_group"
+	PubKey solana.PublicKey
+	Code   string // account code, empty for users
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s %s (%s): %s", e.Type, e.Code, e.PubKey, e.Reason)
+	}
+	return fmt.Sprintf("%s %s: %s", e.Type, e.PubKey, e.Reason)
+}
+
+// Validate checks invariants across already-fetched program data and returns
+// a *ValidationError for every inconsistency found, or nil if there are none.
+// It is a read-only preflight for migration and audit tools:
+//   - every link side and every user device references an existing device;
+//   - activated links reference activated (or drained) devices;
+//   - multicast group IPs are in 224.0.0.0/4;
+//   - device DZ prefixes, interface IPs and link and user tunnel nets have a
+//     prefix length in 1..32.
+//
+// Unset values (zero multicast IPs, all-zero nets) are not reported. Errors
+// are returned in account order: devices, links, users, multicast groups.
+func (d *ProgramData) Validate() []error {
+	var errs []error
+	report := func(typ string, pubkey [32]byte, code, format string, args ...any) {
+		errs = append(errs, &ValidationError{
+			Type:   typ,
+			PubKey: solana.PublicKeyFromBytes(pubkey[:]),
+			Code:   code,
+			Reason: fmt.Sprintf(format, args...),
+		})
+	}
+
+	devices := make(map[[32]byte]Device, len(d.Devices))
+	for _, dev := range d.Devices {
+		devices[dev.PubKey] = dev
+
+		for _, prefix := range dev.DzPrefixes {
+			if !validNetPrefix(prefix) {
+				report("device", dev.PubKey, dev.Code, "invalid dz prefix length %s", formatOnChainNet(prefix))
+			}
+		}
+		for _, iface := range deviceInterfaces(dev) {
+			if !validNetPrefix(iface.IpNet) {
+				report("device", dev.PubKey, dev.Code, "interface %s has invalid prefix length %s", iface.Name, formatOnChainNet(iface.IpNet))
+			}
+		}
+	}
+
+	for _, link := range d.Links {
+		for _, side := range []struct {
+			name   string
+			pubkey [32]byte
+		}{{"a", link.SideAPubKey}, {"z", link.SideZPubKey}} {
+			dev, ok := devices[side.pubkey]
+			switch {
+			case !ok:
+				report("link", link.PubKey, link.Code, "side %s device %s does not exist", side.name, solana.PublicKeyFromBytes(side.pubkey[:]))
+			case link.Status == LinkStatusActivated && !deviceActivated(dev.Status):
+				report("link", link.PubKey, link.Code, "activated link references side %s device %s with status %s", side.name, dev.Code, dev.Status)
+			}
+		}
+		if !validNetPrefix(link.TunnelNet) {
+			report("link", link.PubKey, link.Code, "invalid tunnel net prefix length %s", formatOnChainNet(link.TunnelNet))
+		}
+	}
+
+	for _, user := range d.Users {
+		if _, ok := devices[user.DevicePubKey]; !ok {
+			report("user", user.PubKey, "", "device %s does not exist", solana.PublicKeyFromBytes(user.DevicePubKey[:]))
+		}
+		if !validNetPrefix(user.TunnelNet) {
+			report("user", user.PubKey, "", "invalid tunnel net prefix length %s", formatOnChainNet(user.TunnelNet))
+		}
+	}
+
+	for _, mg := range d.MulticastGroups {
+		ip := net.IP(mg.MulticastIp[:])
+		if !ip.IsUnspecified() && !multicastNet.Contains(ip) {
+			report("multicast_group", mg.PubKey, mg.Code, "multicast ip %s is not in %s", ip, multicastNet)
+		}
+	}
+
+	return errs
+}
+
+// deviceActivated reports whether a device has been activated and not yet
+// deleted. Drained devices are activated devices taken out of service, so
+// their links remain valid.
+func deviceActivated(status DeviceStatus) bool {
+	return status == DeviceStatusActivated || status == DeviceStatusDrained
+}
+
+// validNetPrefix reports whether an on-chain net is unset (all zero) or has a
+// prefix length in 1..32.
+func validNetPrefix(n [5]uint8) bool {
+	if n == ([5]uint8{}) {
+		return true
+	}
+	return n[4] >= 1 && n[4] <= 32
+}
+
+// formatOnChainNet formats an on-chain net as ip/len without validating the
+// prefix length, for error messages.
+func formatOnChainNet(n [5]uint8) string {
+	return fmt.Sprintf("%s/%d", net.IP(n[:4]), n[4])
+}
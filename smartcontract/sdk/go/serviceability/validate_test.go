@@ -0,0 +1,135 @@
+package serviceability
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validProgramData() *ProgramData {
+	return &ProgramData{
+		Devices: []Device{
+			{
+				PubKey: [32]byte{1}, Code: "dz1", Status: DeviceStatusActivated,
+				DzPrefixes: [][5]uint8{{100, 0, 0, 0, 29}},
+				Interfaces: []Interface{
+					{Name: "Loopback255", LoopbackType: LoopbackTypeVpnv4, IpNet: [5]uint8{172, 16, 0, 1, 32}},
+					{Name: "Ethernet1"},
+				},
+			},
+			{PubKey: [32]byte{2}, Code: "dz2", Status: DeviceStatusDrained},
+			{PubKey: [32]byte{3}, Code: "dz3", Status: DeviceStatusDeviceProvisioning},
+		},
+		Links: []Link{
+			{PubKey: [32]byte{10}, Code: "dz1:dz2", Status: LinkStatusActivated, SideAPubKey: [32]byte{1}, SideZPubKey: [32]byte{2}, TunnelNet: [5]uint8{172, 16, 2, 0, 31}},
+			// A link still provisioning may reference a device still provisioning.
+			{PubKey: [32]byte{11}, Code: "dz1:dz3", Status: LinkStatusProvisioning, SideAPubKey: [32]byte{1}, SideZPubKey: [32]byte{3}},
+		},
+		Users: []User{
+			{PubKey: [32]byte{20}, DevicePubKey: [32]byte{1}, TunnelNet: [5]uint8{169, 254, 0, 0, 31}},
+		},
+		MulticastGroups: []MulticastGroup{
+			{PubKey: [32]byte{30}, Code: "mg1", MulticastIp: [4]uint8{233, 84, 178, 0}},
+			// Unallocated multicast IPs are ignored.
+			{PubKey: [32]byte{31}, Code: "mg2"},
+		},
+	}
+}
+
+func TestProgramData_Validate_Valid(t *testing.T) {
+	assert.Empty(t, validProgramData().Validate())
+	assert.Empty(t, (&ProgramData{}).Validate())
+}
+
+func TestProgramData_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*ProgramData)
+		want   ValidationError
+	}{
+		{
+			name: "activated link references non-activated device",
+			mutate: func(d *ProgramData) {
+				d.Links[0].SideZPubKey = [32]byte{3}
+			},
+			want: ValidationError{Type: "link", PubKey: solana.PublicKey{10}, Code: "dz1:dz2", Reason: "activated link references side z device dz3 with status device-provisioning"},
+		},
+		{
+			name: "link references missing device",
+			mutate: func(d *ProgramData) {
+				d.Links[1].SideAPubKey = [32]byte{9}
+			},
+			want: ValidationError{Type: "link", PubKey: solana.PublicKey{11}, Code: "dz1:dz3", Reason: "side a device " + solana.PublicKey{9}.String() + " does not exist"},
+		},
+		{
+			name: "user references missing device",
+			mutate: func(d *ProgramData) {
+				d.Users[0].DevicePubKey = [32]byte{9}
+			},
+			want: ValidationError{Type: "user", PubKey: solana.PublicKey{20}, Reason: "device " + solana.PublicKey{9}.String() + " does not exist"},
+		},
+		{
+			name: "multicast ip outside multicast range",
+			mutate: func(d *ProgramData) {
+				d.MulticastGroups[0].MulticastIp = [4]uint8{10, 0, 0, 1}
+			},
+			want: ValidationError{Type: "multicast_group", PubKey: solana.PublicKey{30}, Code: "mg1", Reason: "multicast ip 10.0.0.1 is not in 224.0.0.0/4"},
+		},
+		{
+			name: "dz prefix length too long",
+			mutate: func(d *ProgramData) {
+				d.Devices[0].DzPrefixes[0][4] = 33
+			},
+			want: ValidationError{Type: "device", PubKey: solana.PublicKey{1}, Code: "dz1", Reason: "invalid dz prefix length 100.0.0.0/33"},
+		},
+		{
+			name: "interface prefix length zero",
+			mutate: func(d *ProgramData) {
+				d.Devices[0].Interfaces[0].IpNet[4] = 0
+			},
+			want: ValidationError{Type: "device", PubKey: solana.PublicKey{1}, Code: "dz1", Reason: "interface Loopback255 has invalid prefix length 172.16.0.1/0"},
+		},
+		{
+			name: "link tunnel net prefix length too long",
+			mutate: func(d *ProgramData) {
+				d.Links[0].TunnelNet[4] = 40
+			},
+			want: ValidationError{Type: "link", PubKey: solana.PublicKey{10}, Code: "dz1:dz2", Reason: "invalid tunnel net prefix length 172.16.2.0/40"},
+		},
+		{
+			name: "user tunnel net prefix length zero",
+			mutate: func(d *ProgramData) {
+				d.Users[0].TunnelNet[4] = 0
+			},
+			want: ValidationError{Type: "user", PubKey: solana.PublicKey{20}, Reason: "invalid tunnel net prefix length 169.254.0.0/0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := validProgramData()
+			tt.mutate(data)
+
+			errs := data.Validate()
+			require.Len(t, errs, 1)
+			var got *ValidationError
+			require.True(t, errors.As(errs[0], &got))
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestProgramData_Validate_ReportsAll(t *testing.T) {
+	data := validProgramData()
+	data.Devices = data.Devices[:1]
+	data.MulticastGroups[0].MulticastIp = [4]uint8{192, 0, 2, 1}
+
+	errs := data.Validate()
+	require.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], "link dz1:dz2")
+	assert.ErrorContains(t, errs[0], "side z device")
+	assert.ErrorContains(t, errs[1], "link dz1:dz3")
+	assert.ErrorContains(t, errs[2], "multicast_group mg1")
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/lmittmann/tint"
 	"github.com/malbeclabs/doublezero/config"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/malbeclabs/doublezero/smartcontract/sdk/go/serviceability"
 	chwriter "github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/clickhouse"
 	"github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/dz"
@@ -50,6 +51,7 @@ const (
 	defaultMaxIdleTimeout       = 5 * time.Second
 	defaultHandshakeIdleTimeout = 2 * time.Second
 	defaultMaxConcurrency       = 128
+	defaultNamespaceWaitTimeout = 30 * time.Second
 )
 
 var (
@@ -102,6 +104,8 @@ func run() error {
 	publicIPFlag := flag.String("public-ip", "", "public internet ip to monitor solana (default: auto-detected)")
 	dzIfaceFlag := flag.String("dz-iface", "", "doublezero interface to monitor solana (default: auto-detected)")
 	sourceMetroFlag := flag.String("source-metro", "", "source metro to monitor solana (required)")
+	namespaceFlag := flag.String("namespace", "", "network namespace to run probes and the metrics listener in (default: current namespace)")
+	namespaceWaitTimeoutFlag := flag.Duration("namespace-wait-timeout", defaultNamespaceWaitTimeout, "how long to wait for --namespace to be created on startup")
 
 	// GeoIP configuration.
 	geoipCityDBPathFlag := flag.String("geoip-city-db-path", defaultGeoipCityDBPath, "path to the geoip city database")
@@ -143,9 +147,28 @@ func run() error {
 		return err
 	}
 
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// If probing from a namespace, wait for it to be ready.
+	ns := netns.New(*namespaceFlag)
+	if ns.Name() != "" {
+		handle, err := netns.WaitForNamespace(log, ns.Name(), *namespaceWaitTimeoutFlag)
+		if err != nil {
+			log.Error("failed to wait for namespace", "namespace", ns.Name(), "error", err)
+			return err
+		}
+		handle.Close()
+	}
+
 	publicIface := *publicIfaceFlag
 	if publicIface == "" {
-		defaultInterface, err := netutil.DefaultInterface()
+		var defaultInterface *net.Interface
+		if nsErr := ns.Do(func() {
+			defaultInterface, err = netutil.DefaultInterface()
+		}); nsErr != nil {
+			err = nsErr
+		}
 		if err != nil {
 			log.Error("failed to get default interface", "error", err)
 			return err
@@ -163,19 +186,24 @@ func run() error {
 		}
 	}
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
-
 	// Set up prometheus metrics server if enabled.
 	if *metricsAddrFlag != "" {
 		metrics.BuildInfo.WithLabelValues(version, commit, date).Set(1)
 		go func() {
-			listener, err := net.Listen("tcp", *metricsAddrFlag)
+			var (
+				listener net.Listener
+				err      error
+			)
+			if nsErr := ns.Do(func() {
+				listener, err = net.Listen("tcp", *metricsAddrFlag)
+			}); nsErr != nil {
+				err = nsErr
+			}
 			if err != nil {
-				log.Error("Failed to start prometheus metrics server listener", "error", err)
+				log.Error("Failed to start prometheus metrics server listener", "namespace", ns.Name(), "error", err)
 				os.Exit(1)
 			}
-			log.Info("Prometheus metrics server listening", "address", listener.Addr().String())
+			log.Info("Prometheus metrics server listening", "namespace", ns.Name(), "address", listener.Addr().String())
 			http.Handle("/metrics", promhttp.Handler())
 			if err := http.Serve(listener, nil); err != nil {
 				log.Error("Failed to start prometheus metrics server", "error", err)
@@ -268,6 +296,7 @@ func run() error {
 		Serviceability: serviceabilityView,
 		Netlinker:      nlr,
 		DZNetworkEnv:   *dzEnvFlag,
+		Namespace:      ns,
 
 		// Verbosity configuration.
 		VerboseFailures:  *verboseFailuresFlag,
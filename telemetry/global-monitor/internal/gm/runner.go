@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jonboulle/clockwork"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	chwriter "github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/clickhouse"
 	"github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/dz"
 	"github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/metrics"
@@ -44,6 +45,10 @@ type RunnerConfig struct {
 	Netlinker      netlink.Netlinker
 	DZNetworkEnv   string
 
+	// Namespace is the network namespace that interfaces and routes are
+	// resolved in and probes run from; nil means the current one.
+	Namespace netns.Namespace
+
 	// Verbosity configuration.
 	VerboseFailures  bool
 	VerboseSuccesses bool
@@ -122,8 +127,12 @@ func NewRunner(log *slog.Logger, cfg *RunnerConfig) (*Runner, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	if cfg.Namespace == nil {
+		cfg.Namespace = netns.New("")
+	}
 	targets, err := NewTargetSet(log, &TargetSetConfig{
 		Clock:            cfg.Clock,
+		Namespace:        cfg.Namespace,
 		ProbeTimeout:     cfg.ProbeTimeout,
 		MaxConcurrency:   cfg.MaxConcurrency,
 		VerboseFailures:  cfg.VerboseFailures,
@@ -157,7 +166,7 @@ func (r *Runner) Start(ctx context.Context) <-chan error {
 }
 
 func (r *Runner) Run(ctx context.Context) error {
-	source, err := NewSource(ctx, r.log, r.cfg.Source)
+	source, err := r.newSource(ctx)
 	if err != nil {
 		r.log.Error("runner: failed to create source", "error", err)
 		return err
@@ -203,6 +212,19 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 }
 
+// newSource resolves the source interfaces and addresses inside the configured
+// namespace.
+func (r *Runner) newSource(ctx context.Context) (*Source, error) {
+	var source *Source
+	var err error
+	if nsErr := r.cfg.Namespace.Do(func() {
+		source, err = NewSource(ctx, r.log, r.cfg.Source)
+	}); nsErr != nil {
+		return nil, nsErr
+	}
+	return source, err
+}
+
 func (r *Runner) tick(ctx context.Context) {
 	startedAt := r.cfg.Clock.Now()
 	defer func() {
@@ -225,7 +247,7 @@ func (r *Runner) tick(ctx context.Context) {
 	}
 
 	// Get source information.
-	source, err := NewSource(ctx, r.log, r.cfg.Source)
+	source, err := r.newSource(ctx)
 	if err != nil {
 		r.log.Error("runner: failed to create source", "error", err)
 		metrics.TickTotal.WithLabelValues("source_err").Inc()
@@ -235,7 +257,11 @@ func (r *Runner) tick(ctx context.Context) {
 	// Get local kernel BGP routes.
 	routes := make(map[string]netlink.Route)
 	if source.DZIface != "" {
-		routes, err = r.cfg.Netlinker.GetBGPRoutesByDst()
+		if nsErr := r.cfg.Namespace.Do(func() {
+			routes, err = r.cfg.Netlinker.GetBGPRoutesByDst()
+		}); nsErr != nil {
+			err = nsErr
+		}
 		if err != nil {
 			r.log.Error("runner: failed to get BGP routes", "error", err)
 			metrics.TickTotal.WithLabelValues("routes_err").Inc()
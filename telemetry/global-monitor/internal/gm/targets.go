@@ -10,12 +10,16 @@ import (
 	"time"
 
 	"github.com/jonboulle/clockwork"
+	"github.com/malbeclabs/doublezero/controlplane/telemetry/pkg/netns"
 	"github.com/malbeclabs/doublezero/telemetry/global-monitor/internal/metrics"
 )
 
 type TargetSetConfig struct {
 	Clock clockwork.Clock
 
+	// Namespace is the network namespace probes run in; nil means the current one.
+	Namespace netns.Namespace
+
 	ProbeTimeout     time.Duration
 	MaxConcurrency   int
 	VerboseFailures  bool
@@ -47,6 +51,9 @@ func NewTargetSet(log *slog.Logger, cfg *TargetSetConfig) (*TargetSet, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid target set config: %w", err)
 	}
+	if cfg.Namespace == nil {
+		cfg.Namespace = netns.New("")
+	}
 	return &TargetSet{
 		log: log,
 		cfg: cfg,
@@ -160,7 +167,16 @@ func (s *TargetSet) ExecuteProbes(ctx context.Context) (map[ProbeTargetID]*Probe
 			timeoutCtx, cancel := context.WithTimeout(ctx, s.cfg.ProbeTimeout)
 			defer cancel()
 			now := s.cfg.Clock.Now()
-			result, err := target.Probe(timeoutCtx)
+			var (
+				result *ProbeResult
+				err    error
+			)
+			if nsErr := s.cfg.Namespace.Do(func() {
+				result, err = target.Probe(timeoutCtx)
+			}); nsErr != nil {
+				s.log.Error("targets: failed to enter namespace", "target", target.ID(), "namespace", s.cfg.Namespace.Name(), "error", nsErr)
+				return
+			}
 			if err != nil {
 				switch {
 				case errors.Is(err, context.DeadlineExceeded):
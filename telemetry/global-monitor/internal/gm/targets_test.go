@@ -2,6 +2,7 @@ package gm
 
 import (
 	"context"
+	"errors"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -282,6 +283,78 @@ func TestGlobalMonitor_TargetSet_Prune_RespectsMaxConcurrency(t *testing.T) {
 	require.Equal(t, 0, ts.Len())
 }
 
+func TestGlobalMonitor_TargetSet_ExecuteProbes_RunsInNamespace(t *testing.T) {
+	t.Parallel()
+
+	clk := clockwork.NewFakeClockAt(time.Unix(1000, 0))
+	ns := &testNamespace{name: "dz"}
+	ts, err := NewTargetSet(newTestLogger(), &TargetSetConfig{
+		Clock:          clk,
+		Namespace:      ns,
+		ProbeTimeout:   time.Second,
+		MaxConcurrency: 4,
+	})
+	require.NoError(t, err)
+
+	a := &testProbeTarget{id: "a", probeFn: func(ctx context.Context) (*ProbeResult, error) {
+		require.True(t, ns.inside.Load() > 0, "probe must run inside the namespace")
+		return &ProbeResult{OK: true}, nil
+	}}
+	b := &testProbeTarget{id: "b"}
+	ts.Update(map[ProbeTargetID]ProbeTarget{a.ID(): a, b.ID(): b})
+
+	results, err := ts.ExecuteProbes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, int32(2), ns.calls.Load())
+}
+
+func TestGlobalMonitor_TargetSet_ExecuteProbes_NamespaceErrorSkipsResult(t *testing.T) {
+	t.Parallel()
+
+	clk := clockwork.NewFakeClockAt(time.Unix(1000, 0))
+	ts, err := NewTargetSet(newTestLogger(), &TargetSetConfig{
+		Clock:          clk,
+		Namespace:      &testNamespace{name: "missing", err: errors.New("no such file or directory")},
+		ProbeTimeout:   time.Second,
+		MaxConcurrency: 4,
+	})
+	require.NoError(t, err)
+
+	var probed atomic.Bool
+	a := &testProbeTarget{id: "a", probeFn: func(ctx context.Context) (*ProbeResult, error) {
+		probed.Store(true)
+		return &ProbeResult{OK: true}, nil
+	}}
+	ts.Update(map[ProbeTargetID]ProbeTarget{a.ID(): a})
+
+	results, err := ts.ExecuteProbes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 0)
+	require.False(t, probed.Load())
+}
+
+// testNamespace counts Do calls and tracks whether fn is running, or fails
+// every call with err.
+type testNamespace struct {
+	name   string
+	err    error
+	calls  atomic.Int32
+	inside atomic.Int32
+}
+
+func (n *testNamespace) Name() string { return n.name }
+func (n *testNamespace) Do(fn func()) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.calls.Add(1)
+	n.inside.Add(1)
+	defer n.inside.Add(-1)
+	fn()
+	return nil
+}
+
 type testProbeTarget struct {
 	id ProbeTargetID
 
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -31,6 +32,13 @@ var (
 const (
 	defaultMetricsAddr            = ":2112"
 	defaultMetricsShutdownTimeout = 10 * time.Second
+	defaultTracingShutdownTimeout = 10 * time.Second
+
+	// defaultProcessorShutdownTimeout bounds how long shutdown waits for the
+	// processor to stop. It covers the processor's own bounds: waiting for
+	// room in a full write queue and flushing it (30s each), then writing the
+	// sampled records (10s), plus some slack.
+	defaultProcessorShutdownTimeout = 75 * time.Second
 )
 
 // BuildInfo is a Prometheus gauge for build metadata.
@@ -48,6 +56,14 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == selfTestCommand {
+		if err := runSelfTest(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -77,22 +93,50 @@ func run() error {
 		metricsErrCh = startMetricsServer(ctx, log, cfg.MetricsAddr, defaultMetricsShutdownTimeout)
 	}
 
+	tracerProvider, shutdownTracing, err := newTracerProvider(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultTracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Warn("failed to flush traces", "error", err)
+		}
+	}()
+
 	// Create metrics
 	consumerMetrics := gnmi.NewConsumerMetrics(prometheus.DefaultRegisterer)
 	processorMetrics := gnmi.NewProcessorMetrics(prometheus.DefaultRegisterer)
 
-	// Create consumer
-	consumer, err := gnmi.NewKafkaConsumer(
-		gnmi.WithKafkaBrokers(cfg.KafkaBrokers),
-		gnmi.WithKafkaTopic(cfg.KafkaTopic),
-		gnmi.WithKafkaGroup(cfg.KafkaGroup),
-		gnmi.WithKafkaAuthType(cfg.KafkaAuthType),
-		gnmi.WithKafkaUser(cfg.KafkaUser),
-		gnmi.WithKafkaPassword(cfg.KafkaPassword),
-		gnmi.WithKafkaTLSDisabled(cfg.KafkaTLSDisabled),
-		gnmi.WithKafkaLogger(log),
-		gnmi.WithConsumerMetrics(consumerMetrics),
-	)
+	// Create consumer based on input type
+	var consumer gnmi.Consumer
+	switch cfg.Input {
+	case "kafka":
+		consumer, err = gnmi.NewKafkaConsumer(
+			gnmi.WithKafkaBrokers(cfg.KafkaBrokers),
+			gnmi.WithKafkaTopic(cfg.KafkaTopic),
+			gnmi.WithKafkaGroup(cfg.KafkaGroup),
+			gnmi.WithKafkaStartOffset(cfg.KafkaStartOffset),
+			gnmi.WithKafkaAuthType(cfg.KafkaAuthType),
+			gnmi.WithKafkaUser(cfg.KafkaUser),
+			gnmi.WithKafkaPassword(cfg.KafkaPassword),
+			gnmi.WithKafkaTLSDisabled(cfg.KafkaTLSDisabled),
+			gnmi.WithKafkaLogger(log),
+			gnmi.WithConsumerMetrics(consumerMetrics),
+		)
+	case "gnmi":
+		consumer, err = gnmi.NewGNMISubscriber(
+			gnmi.WithGNMITarget(cfg.GNMITarget),
+			gnmi.WithGNMITargetName(cfg.GNMITargetName),
+			gnmi.WithGNMIPaths(cfg.GNMIPaths),
+			gnmi.WithGNMITLS(&cfg.GNMITLS),
+			gnmi.WithGNMILogger(log),
+			gnmi.WithGNMIConsumerMetrics(consumerMetrics),
+		)
+	default:
+		return fmt.Errorf("unknown input type: %s", cfg.Input)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create consumer: %w", err)
 	}
@@ -104,14 +148,13 @@ func run() error {
 		writer = gnmi.NewStdoutRecordWriter()
 	case "clickhouse":
 		if cfg.ClickhouseRunMigrations {
-			if err = migrations.RunMigrations(cfg.ClickhouseAddr, cfg.ClickhouseDB, cfg.ClickhouseUser, cfg.ClickhousePassword, !cfg.ClickhouseTLSDisabled, log); err != nil {
+			if err = runClickhouseMigrations(cfg, log); err != nil {
 				return fmt.Errorf("clickhouse migrations: %w", err)
 			}
-			log.Info("clickhouse migrations applied")
 		}
 		chMetrics := gnmi.NewClickhouseMetrics(prometheus.DefaultRegisterer)
 		writer, err = gnmi.NewClickhouseRecordWriter(
-			gnmi.WithClickhouseAddr(cfg.ClickhouseAddr),
+			gnmi.WithClickhouseAddrs(cfg.ClickhouseAddrs...),
 			gnmi.WithClickhouseDB(cfg.ClickhouseDB),
 			gnmi.WithClickhouseUser(cfg.ClickhouseUser),
 			gnmi.WithClickhousePassword(cfg.ClickhousePassword),
@@ -122,27 +165,99 @@ func run() error {
 		if err != nil {
 			return fmt.Errorf("failed to create clickhouse writer: %w", err)
 		}
+	case "kafka":
+		registry, err := gnmi.NewSchemaRegistryClient(cfg.SchemaRegistryURL,
+			gnmi.WithSchemaRegistryAuth(cfg.SchemaRegistryUser, cfg.SchemaRegistryPassword),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create schema registry client: %w", err)
+		}
+		writer, err = gnmi.NewKafkaRecordWriter(ctx,
+			gnmi.WithKafkaWriterBrokers(cfg.KafkaBrokers),
+			gnmi.WithKafkaWriterTopic(cfg.KafkaOutputTopic),
+			gnmi.WithKafkaWriterAuthType(cfg.KafkaAuthType),
+			gnmi.WithKafkaWriterUser(cfg.KafkaUser),
+			gnmi.WithKafkaWriterPassword(cfg.KafkaPassword),
+			gnmi.WithKafkaWriterTLSDisabled(cfg.KafkaTLSDisabled),
+			gnmi.WithKafkaWriterSchemaRegistry(registry),
+			gnmi.WithKafkaWriterLogger(log),
+			gnmi.WithKafkaWriterMetrics(gnmi.NewKafkaWriterMetrics(prometheus.DefaultRegisterer)),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create kafka writer: %w", err)
+		}
 	default:
 		return fmt.Errorf("unknown output type: %s", cfg.Output)
 	}
 
 	// Create and run processor (uses DefaultExtractors automatically)
-	processor, err := gnmi.NewProcessor(
+	processorOpts := []gnmi.ProcessorOption{
 		gnmi.WithConsumer(consumer),
 		gnmi.WithRecordWriter(writer),
 		gnmi.WithProcessorLogger(log),
 		gnmi.WithProcessorMetrics(processorMetrics),
-	)
+		gnmi.WithTracerProvider(tracerProvider),
+		gnmi.WithOutputBackend(cfg.Output),
+	}
+	for recordType, interval := range cfg.SampleIntervals {
+		log.Info("downsampling enabled", "record_type", recordType, "min_interval", interval)
+		processorOpts = append(processorOpts, gnmi.WithSampling(recordType, interval))
+	}
+	if cfg.MaxClockSkew > 0 {
+		log.Info("clock skew guard enabled", "max_skew", cfg.MaxClockSkew, "action", cfg.ClockSkewAction)
+		processorOpts = append(processorOpts, gnmi.WithClockSkewGuard(cfg.MaxClockSkew, cfg.ClockSkewAction))
+	}
+	if cfg.DeviceWorkers > 0 {
+		log.Info("per-device isolation enabled", "workers", cfg.DeviceWorkers, "queue_depth", cfg.DeviceQueueDepth, "timeout", cfg.DeviceTimeout)
+		processorOpts = append(processorOpts, gnmi.WithDeviceIsolation(cfg.DeviceWorkers, cfg.DeviceQueueDepth, cfg.DeviceTimeout))
+	}
+	if cfg.WriteQueueSize > 0 {
+		log.Info("write queue enabled", "size", cfg.WriteQueueSize, "policy", cfg.WriteQueuePolicy)
+		processorOpts = append(processorOpts, gnmi.WithWriteQueue(cfg.WriteQueueSize, cfg.WriteQueuePolicy))
+	}
+	if len(cfg.EnabledRecordTypes) > 0 {
+		processorOpts = append(processorOpts, gnmi.WithEnabledRecordTypes(cfg.EnabledRecordTypes...))
+	}
+	if len(cfg.DisabledRecordTypes) > 0 {
+		processorOpts = append(processorOpts, gnmi.WithDisabledRecordTypes(cfg.DisabledRecordTypes...))
+	}
+	if cfg.RecordEnv != "" {
+		processorOpts = append(processorOpts, gnmi.WithRecordEnv(cfg.RecordEnv))
+	}
+	processor, err := gnmi.NewProcessor(processorOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create processor: %w", err)
 	}
+	log.Info("record types enabled", "record_types", processor.RecordTypes())
+
+	if cfg.Input == "gnmi" {
+		log.Info("starting gnmi-writer",
+			"input", cfg.Input,
+			"output", cfg.Output,
+			"record_env", cfg.RecordEnv,
+			"gnmi_target", cfg.GNMITarget,
+			"gnmi_target_name", cfg.GNMITargetName,
+			"gnmi_tls", cfg.GNMITLS.Enabled,
+		)
+	} else {
+		log.Info("starting gnmi-writer",
+			"input", cfg.Input,
+			"output", cfg.Output,
+			"record_env", cfg.RecordEnv,
+			"kafka_topic", cfg.KafkaTopic,
+			"kafka_group", cfg.KafkaGroup,
+			"kafka_start_offset", cfg.KafkaStartOffset,
+		)
+	}
 
-	log.Info("starting gnmi-writer",
-		"output", cfg.Output,
-		"kafka_topic", cfg.KafkaTopic,
-		"kafka_group", cfg.KafkaGroup,
-	)
+	return runProcessor(ctx, log, processor, metricsErrCh, defaultProcessorShutdownTimeout)
+}
 
+// runProcessor runs the processor until it stops, the metrics server fails, or
+// ctx is cancelled. On cancellation it waits up to shutdownTimeout for the
+// processor to flush its write queue and sampled records, so batches whose
+// offsets were already committed are written before the process exits.
+func runProcessor(ctx context.Context, log *slog.Logger, processor *gnmi.Processor, metricsErrCh <-chan error, shutdownTimeout time.Duration) error {
 	errCh := make(chan error, 1)
 	go func() {
 		errCh <- processor.Run(ctx)
@@ -150,23 +265,50 @@ func run() error {
 
 	for {
 		select {
-		case err, ok := <-errCh:
-			if !ok {
-				log.Info("processor stopped")
-				return nil
-			}
+		case err := <-errCh:
 			if err != nil {
 				return fmt.Errorf("processor error: %w", err)
 			}
+			log.Info("processor stopped")
+			return nil
 		case err, ok := <-metricsErrCh:
 			if ok && err != nil {
 				return fmt.Errorf("metrics server error: %w", err)
 			}
 			metricsErrCh = nil
 		case <-ctx.Done():
+			select {
+			case err := <-errCh:
+				if err != nil {
+					return fmt.Errorf("processor error: %w", err)
+				}
+				log.Info("processor stopped")
+			case <-time.After(shutdownTimeout):
+				log.Warn("processor did not stop in time, exiting without flushing", "timeout", shutdownTimeout)
+			}
+			return nil
+		}
+	}
+}
+
+// runClickhouseMigrations applies migrations via the first ClickHouse endpoint
+// that accepts them, so a single unavailable replica does not block startup.
+func runClickhouseMigrations(cfg Config, log *slog.Logger) error {
+	var errs []error
+	for _, addr := range cfg.ClickhouseAddrs {
+		err := migrations.RunMigrations(addr, cfg.ClickhouseDB, cfg.ClickhouseUser, cfg.ClickhousePassword, !cfg.ClickhouseTLSDisabled, log)
+		if err == nil {
+			log.Info("clickhouse migrations applied", "addr", addr)
 			return nil
 		}
+		if errors.Is(err, migrations.ErrSchemaTooNew) {
+			// The schema is shared by all endpoints, so retrying elsewhere cannot help.
+			return fmt.Errorf("%s: %w", addr, err)
+		}
+		log.Warn("clickhouse migrations failed on endpoint", "addr", addr, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", addr, err))
 	}
+	return errors.Join(errs...)
 }
 
 func newLogger(verbose bool) *slog.Logger {
@@ -240,20 +382,65 @@ type Config struct {
 	Verbose     bool
 	MetricsAddr string
 
+	// Downsampling: record type (table name) -> minimum interval between written samples
+	SampleIntervals map[string]time.Duration
+
+	// Clock skew guard: notifications further than MaxClockSkew from receipt
+	// time are handled per ClockSkewAction. 0 disables the guard.
+	MaxClockSkew    time.Duration
+	ClockSkewAction gnmi.ClockSkewAction
+
+	// Per-device isolation: devices are sharded over DeviceWorkers workers,
+	// each device with its own queue of at most DeviceQueueDepth
+	// notifications, and a batch waits at most DeviceTimeout for each device.
+	// 0 workers disables isolation.
+	DeviceWorkers    int
+	DeviceQueueDepth int
+	DeviceTimeout    time.Duration
+
+	// Write queue: up to WriteQueueSize extracted batches are buffered for a
+	// separate writer, with WriteQueuePolicy applied when full. 0 writes each
+	// batch before consuming the next.
+	WriteQueueSize   int
+	WriteQueuePolicy gnmi.WriteQueuePolicy
+
+	// Record type (table name) filters; at most one of these is set
+	EnabledRecordTypes  []string
+	DisabledRecordTypes []string
+
+	// Environment stamped in the env column of every record; empty leaves it unset
+	RecordEnv string
+
+	// Input configuration
+	Input string // "kafka" or "gnmi"
+
+	// Direct gNMI input configuration
+	GNMITarget     string
+	GNMITargetName string
+	GNMIPaths      []string
+	GNMITLS        gnmi.GNMITLSConfig
+
 	// Output configuration
-	Output string // "stdout" or "clickhouse"
+	Output string // "stdout", "clickhouse", or "kafka"
 
 	// Kafka configuration
 	KafkaBrokers     []string
 	KafkaTopic       string
 	KafkaGroup       string
+	KafkaStartOffset gnmi.KafkaStartOffset
 	KafkaAuthType    gnmi.KafkaAuthType
 	KafkaUser        string
 	KafkaPassword    string
 	KafkaTLSDisabled bool
 
+	// Kafka output configuration (Avro records validated against a schema registry)
+	KafkaOutputTopic       string
+	SchemaRegistryURL      string
+	SchemaRegistryUser     string
+	SchemaRegistryPassword string
+
 	// ClickHouse configuration
-	ClickhouseAddr          string
+	ClickhouseAddrs         []string
 	ClickhouseDB            string
 	ClickhouseUser          string
 	ClickhousePassword      string
@@ -271,26 +458,65 @@ func getenv(key, def string) string {
 func loadConfig() (Config, error) {
 	var cfg Config
 	var kafkaAuthType string
+	var sampleIntervals []string
+	var clockSkewAction string
+	var writeQueuePolicy string
+	var kafkaStartOffset string
+	var gnmiTLSDisabled bool
 
 	flag.BoolVar(&cfg.ShowVersion, "version", false, "show version and exit")
 	flag.BoolVar(&cfg.Verbose, "verbose", false, "verbose mode - show debug logs")
 	flag.StringVar(&cfg.MetricsAddr, "metrics-addr", getenv("METRICS_ADDR", defaultMetricsAddr), "address for prometheus metrics (env: METRICS_ADDR)")
 
+	flag.StringSliceVar(&sampleIntervals, "sample-interval", nil, "downsample a record type, as <table>=<duration> (e.g. interface_state=30s); repeatable")
+	flag.DurationVar(&cfg.MaxClockSkew, "max-clock-skew", 0, "maximum allowed difference between a notification's timestamp and receipt time (0 disables the check)")
+	flag.StringVar(&clockSkewAction, "clock-skew-action", string(gnmi.ClockSkewRewrite), "what to do with notifications beyond --max-clock-skew: rewrite (use receipt time) or drop")
+	flag.IntVar(&cfg.DeviceWorkers, "device-workers", 0, "extract notifications on this many workers, each device with its own queue, so one device cannot stall the others (0 disables)")
+	flag.IntVar(&cfg.DeviceQueueDepth, "device-queue-depth", 1000, "maximum notifications queued per device when --device-workers is set; notifications arriving while the queue is full are dropped")
+	flag.DurationVar(&cfg.DeviceTimeout, "device-timeout", 5*time.Second, "maximum time a batch waits for a device's records when --device-workers is set; notifications not extracted by then are dropped")
+	flag.IntVar(&cfg.WriteQueueSize, "write-queue-size", 0, "buffer up to this many extracted batches for a separate writer so slow writes do not stall consumption (0 disables)")
+	flag.StringVar(&writeQueuePolicy, "write-queue-policy", string(gnmi.WriteQueueBlock), "what to do when the write queue is full: block (pause consumption, building Kafka lag) or drop-oldest (discard the oldest queued batch)")
+	flag.StringSliceVar(&cfg.EnabledRecordTypes, "enable-record-types", nil, "only produce these record types (tables), comma-separated or repeated; mutually exclusive with --disable-record-types")
+	flag.StringSliceVar(&cfg.DisabledRecordTypes, "disable-record-types", nil, "skip these record types (tables), comma-separated or repeated; mutually exclusive with --enable-record-types")
+	flag.StringVar(&cfg.RecordEnv, "record-env", getenv("RECORD_ENV", os.Getenv("DZ_ENV")), "environment stamped in the env column of every record, e.g. devnet, testnet or mainnet-beta (env: RECORD_ENV, falling back to DZ_ENV)")
+
+	// Input configuration
+	flag.StringVar(&cfg.Input, "input", getenv("INPUT", "kafka"), "input source: kafka, or gnmi to subscribe to a gNMI target directly (env: INPUT)")
+
+	// Direct gNMI input configuration
+	flag.StringVar(&cfg.GNMITarget, "gnmi-target", getenv("GNMI_TARGET", ""), "gNMI target address (host:port) when input is gnmi (env: GNMI_TARGET)")
+	flag.StringVar(&cfg.GNMITargetName, "gnmi-target-name", getenv("GNMI_TARGET_NAME", ""), "gNMI prefix target to subscribe with; records are attributed to it, so use the device pubkey (env: GNMI_TARGET_NAME)")
+	flag.StringSliceVar(&cfg.GNMIPaths, "gnmi-path", gnmi.DefaultGNMISubscribePaths, "gNMI path to subscribe to when input is gnmi; repeatable")
+	flag.BoolVar(&gnmiTLSDisabled, "gnmi-tls-disabled", getenv("GNMI_TLS_DISABLED", "") == "true", "disable TLS for the gNMI connection (env: GNMI_TLS_DISABLED)")
+	flag.StringVar(&cfg.GNMITLS.ServerName, "gnmi-tls-server-name", "", "TLS server name for the gNMI target (SNI/hostname verification); defaults to the host in --gnmi-target")
+	flag.StringVar(&cfg.GNMITLS.CAFile, "gnmi-tls-ca", "", "path to CA PEM to trust for the gNMI target (optional if publicly trusted)")
+	flag.StringVar(&cfg.GNMITLS.CertFile, "gnmi-tls-cert", "", "path to client certificate PEM for gNMI mTLS (optional)")
+	flag.StringVar(&cfg.GNMITLS.KeyFile, "gnmi-tls-key", "", "path to client private key PEM for gNMI mTLS (optional)")
+	flag.BoolVar(&cfg.GNMITLS.SkipVerify, "gnmi-tls-skip-verify", false, "skip TLS certificate verification for the gNMI target (dev only)")
+
 	// Output configuration
-	flag.StringVar(&cfg.Output, "output", getenv("OUTPUT", "stdout"), "output destination: stdout or clickhouse (env: OUTPUT)")
+	flag.StringVar(&cfg.Output, "output", getenv("OUTPUT", "stdout"), "output destination: stdout, clickhouse, or kafka (env: OUTPUT)")
 
 	// Kafka configuration
 	kafkaBrokersStr := getenv("KAFKA_BROKERS", "localhost:9092")
 	flag.StringSliceVar(&cfg.KafkaBrokers, "kafka-brokers", strings.Split(kafkaBrokersStr, ","), "kafka broker addresses (env: KAFKA_BROKERS)")
 	flag.StringVar(&cfg.KafkaTopic, "kafka-topic", getenv("KAFKA_TOPIC", "gnmi-notifications"), "kafka topic (env: KAFKA_TOPIC)")
 	flag.StringVar(&cfg.KafkaGroup, "kafka-group", getenv("KAFKA_GROUP", "gnmi-writer"), "kafka consumer group (env: KAFKA_GROUP)")
+	flag.StringVar(&kafkaStartOffset, "kafka-start-offset", getenv("KAFKA_START_OFFSET", string(gnmi.KafkaStartOffsetLatest)), "where to start consuming when the consumer group has no committed offset: earliest (backfill the retained backlog) or latest; ignored once the group has committed offsets (env: KAFKA_START_OFFSET)")
 	flag.StringVar(&kafkaAuthType, "kafka-auth-type", getenv("KAFKA_AUTH_TYPE", "scram"), "kafka auth type: scram or aws-msk (env: KAFKA_AUTH_TYPE)")
 	flag.StringVar(&cfg.KafkaUser, "kafka-user", getenv("KAFKA_USER", ""), "kafka SCRAM username (env: KAFKA_USER)")
 	flag.StringVar(&cfg.KafkaPassword, "kafka-password", getenv("KAFKA_PASSWORD", ""), "kafka SCRAM password (env: KAFKA_PASSWORD)")
 	flag.BoolVar(&cfg.KafkaTLSDisabled, "kafka-tls-disabled", getenv("KAFKA_TLS_DISABLED", "") == "true", "disable TLS for kafka (env: KAFKA_TLS_DISABLED)")
 
+	// Kafka output configuration
+	flag.StringVar(&cfg.KafkaOutputTopic, "kafka-output-topic", getenv("KAFKA_OUTPUT_TOPIC", "gnmi-records"), "kafka topic for avro-encoded records when output is kafka (env: KAFKA_OUTPUT_TOPIC)")
+	flag.StringVar(&cfg.SchemaRegistryURL, "schema-registry-url", getenv("SCHEMA_REGISTRY_URL", ""), "confluent-compatible schema registry url (env: SCHEMA_REGISTRY_URL)")
+	flag.StringVar(&cfg.SchemaRegistryUser, "schema-registry-user", getenv("SCHEMA_REGISTRY_USER", ""), "schema registry basic auth username (env: SCHEMA_REGISTRY_USER)")
+	flag.StringVar(&cfg.SchemaRegistryPassword, "schema-registry-password", getenv("SCHEMA_REGISTRY_PASSWORD", ""), "schema registry basic auth password (env: SCHEMA_REGISTRY_PASSWORD)")
+
 	// ClickHouse configuration (tables are determined by record types)
-	flag.StringVar(&cfg.ClickhouseAddr, "clickhouse-addr", getenv("CLICKHOUSE_ADDR", "localhost:9440"), "clickhouse address (env: CLICKHOUSE_ADDR)")
+	clickhouseAddrStr := getenv("CLICKHOUSE_ADDR", "localhost:9440")
+	flag.StringSliceVar(&cfg.ClickhouseAddrs, "clickhouse-addr", strings.Split(clickhouseAddrStr, ","), "clickhouse address; comma-separated or repeated for replica failover (env: CLICKHOUSE_ADDR)")
 	flag.StringVar(&cfg.ClickhouseDB, "clickhouse-db", getenv("CLICKHOUSE_DB", "default"), "clickhouse database (env: CLICKHOUSE_DB)")
 	flag.StringVar(&cfg.ClickhouseUser, "clickhouse-user", getenv("CLICKHOUSE_USER", "default"), "clickhouse username (env: CLICKHOUSE_USER)")
 	flag.StringVar(&cfg.ClickhousePassword, "clickhouse-password", getenv("CLICKHOUSE_PASS", ""), "clickhouse password (env: CLICKHOUSE_PASS)")
@@ -313,12 +539,101 @@ func loadConfig() (Config, error) {
 		return Config{}, fmt.Errorf("unknown kafka auth type: %s", kafkaAuthType)
 	}
 
+	// Parse start offset policy
+	cfg.KafkaStartOffset = gnmi.KafkaStartOffset(strings.ToLower(kafkaStartOffset))
+	if cfg.KafkaStartOffset != gnmi.KafkaStartOffsetEarliest && cfg.KafkaStartOffset != gnmi.KafkaStartOffsetLatest {
+		return Config{}, fmt.Errorf("invalid --kafka-start-offset %q (must be earliest or latest)", kafkaStartOffset)
+	}
+
+	// Parse downsampling policies
+	knownTypes := make(map[string]bool)
+	for _, r := range gnmi.KnownRecords() {
+		knownTypes[r.TableName()] = true
+	}
+	cfg.SampleIntervals = make(map[string]time.Duration)
+	for _, spec := range sampleIntervals {
+		recordType, durStr, ok := strings.Cut(spec, "=")
+		if !ok || recordType == "" {
+			return Config{}, fmt.Errorf("invalid --sample-interval %q (expected <table>=<duration>)", spec)
+		}
+		if !knownTypes[recordType] {
+			return Config{}, fmt.Errorf("unknown record type in --sample-interval: %s", recordType)
+		}
+		interval, err := time.ParseDuration(durStr)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid --sample-interval duration %q for %s", durStr, recordType)
+		}
+		cfg.SampleIntervals[recordType] = interval
+	}
+
+	// Validate clock skew guard
+	if cfg.MaxClockSkew < 0 {
+		return Config{}, fmt.Errorf("--max-clock-skew must not be negative")
+	}
+	cfg.ClockSkewAction = gnmi.ClockSkewAction(strings.ToLower(clockSkewAction))
+	if cfg.ClockSkewAction != gnmi.ClockSkewRewrite && cfg.ClockSkewAction != gnmi.ClockSkewDrop {
+		return Config{}, fmt.Errorf("invalid --clock-skew-action %q (must be rewrite or drop)", clockSkewAction)
+	}
+
+	// Validate per-device isolation
+	if cfg.DeviceWorkers < 0 {
+		return Config{}, fmt.Errorf("--device-workers must not be negative")
+	}
+	if cfg.DeviceWorkers > 0 && cfg.DeviceQueueDepth <= 0 {
+		return Config{}, fmt.Errorf("--device-queue-depth must be positive when --device-workers is set")
+	}
+	if cfg.DeviceWorkers > 0 && cfg.DeviceTimeout <= 0 {
+		return Config{}, fmt.Errorf("--device-timeout must be positive when --device-workers is set")
+	}
+
+	// Validate write queue
+	if cfg.WriteQueueSize < 0 {
+		return Config{}, fmt.Errorf("--write-queue-size must not be negative")
+	}
+	cfg.WriteQueuePolicy = gnmi.WriteQueuePolicy(strings.ToLower(writeQueuePolicy))
+	if cfg.WriteQueuePolicy != gnmi.WriteQueueBlock && cfg.WriteQueuePolicy != gnmi.WriteQueueDropOldest {
+		return Config{}, fmt.Errorf("invalid --write-queue-policy %q (must be block or drop-oldest)", writeQueuePolicy)
+	}
+
+	// Validate record type filters
+	if len(cfg.EnabledRecordTypes) > 0 && len(cfg.DisabledRecordTypes) > 0 {
+		return Config{}, fmt.Errorf("--enable-record-types and --disable-record-types are mutually exclusive")
+	}
+	for _, recordType := range append(slices.Clone(cfg.EnabledRecordTypes), cfg.DisabledRecordTypes...) {
+		if !knownTypes[recordType] {
+			return Config{}, fmt.Errorf("unknown record type in --enable-record-types/--disable-record-types: %s", recordType)
+		}
+	}
+
+	// Validate input
+	switch cfg.Input {
+	case "kafka":
+		// valid
+	case "gnmi":
+		if cfg.GNMITarget == "" {
+			return Config{}, fmt.Errorf("--gnmi-target is required when input is gnmi")
+		}
+		if len(cfg.GNMIPaths) == 0 {
+			return Config{}, fmt.Errorf("at least one --gnmi-path is required when input is gnmi")
+		}
+		cfg.GNMITLS.Enabled = !gnmiTLSDisabled
+	default:
+		return Config{}, fmt.Errorf("invalid input type: %s (must be kafka or gnmi)", cfg.Input)
+	}
+
 	// Validate output
 	switch cfg.Output {
 	case "stdout", "clickhouse":
 		// valid
+	case "kafka":
+		if cfg.SchemaRegistryURL == "" {
+			return Config{}, fmt.Errorf("--schema-registry-url is required when output is kafka")
+		}
+		if cfg.Input == "kafka" && cfg.KafkaOutputTopic == cfg.KafkaTopic {
+			return Config{}, fmt.Errorf("--kafka-output-topic must differ from --kafka-topic")
+		}
 	default:
-		return Config{}, fmt.Errorf("invalid output type: %s (must be stdout or clickhouse)", cfg.Output)
+		return Config{}, fmt.Errorf("invalid output type: %s (must be stdout, clickhouse, or kafka)", cfg.Output)
 	}
 
 	return cfg, nil
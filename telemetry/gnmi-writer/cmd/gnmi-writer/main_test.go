@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/malbeclabs/doublezero/telemetry/gnmi-writer/internal/gnmi"
+)
+
+// batchConsumer returns each of its batches once, then signals drained and
+// returns nothing until the context is cancelled.
+type batchConsumer struct {
+	batches [][]*gpb.Notification
+	drained chan struct{}
+
+	mu      sync.Mutex
+	commits int
+}
+
+func (c *batchConsumer) Consume(ctx context.Context) ([]*gpb.Notification, error) {
+	if len(c.batches) > 0 {
+		batch := c.batches[0]
+		c.batches = c.batches[1:]
+		return batch, nil
+	}
+	if c.drained != nil {
+		close(c.drained)
+		c.drained = nil
+	}
+	<-ctx.Done()
+	return nil, nil
+}
+
+func (c *batchConsumer) Commit(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commits++
+	return nil
+}
+
+func (c *batchConsumer) Close() error { return nil }
+
+// slowWriter records every write after a delay.
+type slowWriter struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	records []gnmi.Record
+}
+
+func (w *slowWriter) WriteRecords(_ context.Context, records []gnmi.Record) error {
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.records = append(w.records, records...)
+	return nil
+}
+
+func (w *slowWriter) written() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.records)
+}
+
+func loadFixtureNotifications(t *testing.T) []*gpb.Notification {
+	t.Helper()
+	f, err := os.Open(selfTestFixture)
+	require.NoError(t, err)
+	defer f.Close()
+	notifications, _, err := readNotifications(f, inputFormatJSONL)
+	require.NoError(t, err)
+	return notifications
+}
+
+// runUntilDrained runs the processor through runProcessor, cancelling once the
+// consumer has handed out every batch, and returns runProcessor's error.
+func runUntilDrained(t *testing.T, consumer *batchConsumer, opts ...gnmi.ProcessorOption) error {
+	t.Helper()
+	consumer.drained = make(chan struct{})
+	drained := consumer.drained
+	processor, err := gnmi.NewProcessor(append([]gnmi.ProcessorOption{
+		gnmi.WithConsumer(consumer),
+		gnmi.WithProcessorLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-drained
+		cancel()
+	}()
+	return runProcessor(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)), processor, nil, 10*time.Second)
+}
+
+func TestRunProcessor_DrainsWriteQueueOnShutdown(t *testing.T) {
+	notifications := loadFixtureNotifications(t)
+	consumer := &batchConsumer{batches: [][]*gpb.Notification{notifications, notifications, notifications}}
+	writer := &slowWriter{delay: 50 * time.Millisecond}
+
+	err := runUntilDrained(t, consumer,
+		gnmi.WithRecordWriter(writer),
+		gnmi.WithWriteQueue(4, gnmi.WriteQueueBlock),
+	)
+	require.NoError(t, err)
+
+	// Every queued batch is written and committed before runProcessor returns.
+	require.Equal(t, 9, writer.written())
+	require.Equal(t, 3, consumer.commits)
+}
+
+func TestRunProcessor_FlushesSampledRecordsOnShutdown(t *testing.T) {
+	notifications := loadFixtureNotifications(t)
+	consumer := &batchConsumer{batches: [][]*gpb.Notification{notifications, notifications}}
+	writer := &slowWriter{}
+
+	err := runUntilDrained(t, consumer,
+		gnmi.WithRecordWriter(writer),
+		gnmi.WithSampling("system_state", time.Hour),
+	)
+	require.NoError(t, err)
+
+	// The second batch's system_state record is sampled out, then written by
+	// the shutdown flush before runProcessor returns.
+	require.Equal(t, 6, writer.written())
+	last := writer.records[len(writer.records)-1]
+	require.Equal(t, "system_state", last.TableName())
+}
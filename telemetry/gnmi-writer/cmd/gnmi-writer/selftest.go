@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	flag "github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/malbeclabs/doublezero/telemetry/gnmi-writer/internal/gnmi"
+)
+
+const (
+	// selfTestCommand runs the extractors against a captured notification file
+	// instead of consuming from Kafka.
+	selfTestCommand = "selftest"
+
+	inputFormatJSONL = "jsonl"
+	inputFormatPB    = "pb"
+
+	// maxMessageSize bounds a single JSON line or length-delimited message.
+	maxMessageSize = 64 << 20
+)
+
+// errNoRecords is returned by runSelfTest when the input produced no records.
+var errNoRecords = errors.New("no records produced")
+
+// selfTestReport is the outcome of running the extractors over an input file.
+type selfTestReport struct {
+	Notifications int
+	Counts        map[string]int // record type -> records produced
+	RecordTypes   []string       // enabled record types, in matching order
+	DecodeErrors  []string       // input messages that could not be decoded
+	UnmarshalErrs []string       // updates that could not be unmarshaled into the schema
+}
+
+func (r *selfTestReport) total() int {
+	n := 0
+	for _, c := range r.Counts {
+		n += c
+	}
+	return n
+}
+
+// runSelfTest implements `gnmi-writer selftest --input <file>`: it loads
+// captured notifications, runs the configured extractors without any Kafka or
+// ClickHouse, and prints per-record-type counts plus decode and unmarshal
+// errors. It returns errNoRecords if nothing was produced.
+func runSelfTest(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(selfTestCommand, flag.ContinueOnError)
+	input := fs.String("input", "", "captured notifications file: JSON lines (.jsonl) or length-delimited protobuf (.pb) SubscribeResponse or Notification messages")
+	format := fs.String("format", "", "input format: jsonl or pb (default: from the --input extension)")
+	enabled := fs.StringSlice("enable-record-types", nil, "only run these record types (tables); mutually exclusive with --disable-record-types")
+	disabled := fs.StringSlice("disable-record-types", nil, "skip these record types (tables); mutually exclusive with --enable-record-types")
+	verbose := fs.Bool("verbose", false, "verbose mode - show debug logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return fmt.Errorf("--input is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*input), ".")
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		return fmt.Errorf("failed to open input: %w", err)
+	}
+	defer f.Close()
+
+	opts := []gnmi.ProcessorOption{gnmi.WithProcessorLogger(newLogger(*verbose))}
+	if len(*enabled) > 0 {
+		opts = append(opts, gnmi.WithEnabledRecordTypes(*enabled...))
+	}
+	if len(*disabled) > 0 {
+		opts = append(opts, gnmi.WithDisabledRecordTypes(*disabled...))
+	}
+
+	report, err := selfTest(f, *format, opts...)
+	if err != nil {
+		return err
+	}
+	printSelfTestReport(out, report)
+	if report.total() == 0 {
+		return errNoRecords
+	}
+	return nil
+}
+
+// selfTest decodes notifications from r and runs them through a processor built
+// with opts.
+func selfTest(r io.Reader, format string, opts ...gnmi.ProcessorOption) (*selfTestReport, error) {
+	notifications, decodeErrs, err := readNotifications(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &selfTestReport{
+		Notifications: len(notifications),
+		Counts:        make(map[string]int),
+		DecodeErrors:  decodeErrs,
+	}
+	opts = append(opts,
+		gnmi.WithProcessorMetrics(gnmi.NewProcessorMetrics(prometheus.NewRegistry())),
+		gnmi.WithUnmarshalErrorHandler(func(recordType, path string, err error) {
+			report.UnmarshalErrs = append(report.UnmarshalErrs, fmt.Sprintf("%s %s: %v", recordType, path, err))
+		}),
+	)
+	processor, err := gnmi.NewProcessor(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create processor: %w", err)
+	}
+
+	report.RecordTypes = processor.RecordTypes()
+	for _, recordType := range report.RecordTypes {
+		report.Counts[recordType] = 0
+	}
+	for _, record := range processor.ProcessNotifications(context.Background(), notifications) {
+		report.Counts[record.TableName()]++
+	}
+	return report, nil
+}
+
+// readNotifications decodes SubscribeResponse or Notification messages, as the
+// Kafka consumer does. Messages that fail to decode are reported and skipped.
+func readNotifications(r io.Reader, format string) ([]*gpb.Notification, []string, error) {
+	var notifications []*gpb.Notification
+	var decodeErrs []string
+
+	switch format {
+	case inputFormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxMessageSize)
+		for line := 1; scanner.Scan(); line++ {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+			n, err := decodeNotification(data, protojson.Unmarshal)
+			if err != nil {
+				decodeErrs = append(decodeErrs, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+			notifications = append(notifications, n)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, nil, fmt.Errorf("failed to read input: %w", err)
+		}
+	case inputFormatPB:
+		br := bufio.NewReader(r)
+		for msg := 1; ; msg++ {
+			size, err := binary.ReadUvarint(br)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read length of message %d: %w", msg, err)
+			}
+			if size > maxMessageSize {
+				return nil, nil, fmt.Errorf("message %d: length %d exceeds maximum %d", msg, size, maxMessageSize)
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(br, data); err != nil {
+				return nil, nil, fmt.Errorf("failed to read message %d: %w", msg, err)
+			}
+			n, err := decodeNotification(data, proto.Unmarshal)
+			if err != nil {
+				decodeErrs = append(decodeErrs, fmt.Sprintf("message %d: %v", msg, err))
+				continue
+			}
+			notifications = append(notifications, n)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown input format %q (must be %s or %s)", format, inputFormatJSONL, inputFormatPB)
+	}
+
+	return notifications, decodeErrs, nil
+}
+
+func decodeNotification(data []byte, unmarshal func([]byte, proto.Message) error) (*gpb.Notification, error) {
+	var resp gpb.SubscribeResponse
+	if err := unmarshal(data, &resp); err == nil {
+		if update := resp.GetUpdate(); update != nil {
+			return update, nil
+		}
+	}
+	var n gpb.Notification
+	if err := unmarshal(data, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func printSelfTestReport(w io.Writer, r *selfTestReport) {
+	fmt.Fprintf(w, "notifications: %d\n\n", r.Notifications)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RECORD TYPE\tRECORDS")
+	for _, recordType := range r.RecordTypes {
+		fmt.Fprintf(tw, "%s\t%d\n", recordType, r.Counts[recordType])
+	}
+	fmt.Fprintf(tw, "total\t%d\n", r.total())
+	tw.Flush()
+
+	if len(r.DecodeErrors) > 0 {
+		fmt.Fprintf(w, "\ndecode errors: %d\n", len(r.DecodeErrors))
+		for _, e := range r.DecodeErrors {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+	if len(r.UnmarshalErrs) > 0 {
+		fmt.Fprintf(w, "\nunmarshal errors: %d\n", len(r.UnmarshalErrs))
+		for _, e := range r.UnmarshalErrs {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+}
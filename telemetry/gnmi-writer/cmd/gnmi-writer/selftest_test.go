@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const selfTestFixture = "testdata/selftest.jsonl"
+
+func TestSelfTest_JSONL(t *testing.T) {
+	f, err := os.Open(selfTestFixture)
+	require.NoError(t, err)
+	defer f.Close()
+
+	report, err := selfTest(f, inputFormatJSONL)
+	require.NoError(t, err)
+
+	require.Equal(t, 4, report.Notifications)
+	require.Equal(t, 1, report.Counts["system_state"])
+	require.Equal(t, 1, report.Counts["isis_global_state"])
+	require.Equal(t, 1, report.Counts["isis_overload_bit"])
+	require.Equal(t, 0, report.Counts["bgp_neighbors"], "enabled record types are reported even without records")
+	require.Equal(t, 3, report.total())
+
+	require.Len(t, report.DecodeErrors, 1)
+	require.Contains(t, report.DecodeErrors[0], "line 5")
+	require.NotEmpty(t, report.UnmarshalErrs)
+	require.True(t, strings.HasPrefix(report.UnmarshalErrs[0], "isis_global_state "), report.UnmarshalErrs[0])
+}
+
+func TestSelfTest_PB(t *testing.T) {
+	f, err := os.Open(selfTestFixture)
+	require.NoError(t, err)
+	defer f.Close()
+	notifications, _, err := readNotifications(f, inputFormatJSONL)
+	require.NoError(t, err)
+
+	// Re-encode the fixture as length-delimited protobuf.
+	var buf bytes.Buffer
+	for _, n := range notifications {
+		data, err := proto.Marshal(n)
+		require.NoError(t, err)
+		buf.Write(binary.AppendUvarint(nil, uint64(len(data))))
+		buf.Write(data)
+	}
+
+	report, err := selfTest(&buf, inputFormatPB)
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Notifications)
+	require.Equal(t, 3, report.total())
+	require.Empty(t, report.DecodeErrors)
+}
+
+func TestSelfTest_TruncatedPB(t *testing.T) {
+	_, err := selfTest(bytes.NewReader([]byte{10, 1, 2}), inputFormatPB)
+	require.ErrorContains(t, err, "failed to read message 1")
+}
+
+func TestRunSelfTest_RecordTypeFilter(t *testing.T) {
+	var out bytes.Buffer
+	err := runSelfTest([]string{"--input", selfTestFixture, "--enable-record-types", "system_state"}, &out)
+	require.NoError(t, err)
+
+	require.Contains(t, out.String(), "notifications: 4")
+	require.Regexp(t, `system_state\s+1\n`, out.String())
+	require.Regexp(t, `total\s+1\n`, out.String())
+	require.NotContains(t, out.String(), "isis_global_state  ")
+	require.Contains(t, out.String(), "decode errors: 1")
+}
+
+func TestRunSelfTest_NoRecords(t *testing.T) {
+	var out bytes.Buffer
+	err := runSelfTest([]string{"--input", selfTestFixture, "--enable-record-types", "bgp_neighbors"}, &out)
+	require.ErrorIs(t, err, errNoRecords)
+	require.Regexp(t, `bgp_neighbors\s+0\n`, out.String())
+}
+
+func TestRunSelfTest_InputErrors(t *testing.T) {
+	var out bytes.Buffer
+	require.ErrorContains(t, runSelfTest(nil, &out), "--input is required")
+
+	path := filepath.Join(t.TempDir(), "capture.txt")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	require.ErrorContains(t, runSelfTest([]string{"--input", path}, &out), `unknown input format "txt"`)
+}
@@ -0,0 +1,185 @@
+package gnmi
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AvroNamespace is the Avro namespace used for all generated record schemas.
+const AvroNamespace = "com.malbeclabs.doublezero.gnmi"
+
+// avroField describes how a single struct field is encoded.
+type avroField struct {
+	name  string
+	index int
+	kind  reflect.Kind
+	time  bool
+}
+
+// avroRecordSchema holds the generated Avro schema for a record type along with
+// the field layout used to encode it.
+type avroRecordSchema struct {
+	name   string
+	schema string
+	fields []avroField
+}
+
+// avroSchemaCache caches generated schemas per type to avoid repeated reflection.
+var avroSchemaCache sync.Map // map[reflect.Type]*avroRecordSchema
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// AvroSchema returns the Avro schema (as JSON) for the given record type.
+// Field names are taken from the `ch` struct tags so the schema matches the
+// ClickHouse column layout. Timestamps are encoded as timestamp-micros.
+func AvroSchema(r Record) (string, error) {
+	s, err := getOrComputeAvroSchema(r)
+	if err != nil {
+		return "", err
+	}
+	return s.schema, nil
+}
+
+// AvroFullName returns the fully-qualified Avro record name for the given record type.
+func AvroFullName(r Record) string {
+	return AvroNamespace + "." + r.TableName()
+}
+
+// EncodeAvro encodes the record using the Avro binary encoding for its generated schema.
+func EncodeAvro(r Record) ([]byte, error) {
+	s, err := getOrComputeAvroSchema(r)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Ptr { // nolint:govet
+		v = v.Elem()
+	}
+
+	buf := make([]byte, 0, 256)
+	for _, f := range s.fields {
+		fv := v.Field(f.index)
+		if f.time {
+			buf = binary.AppendVarint(buf, fv.Interface().(time.Time).UnixMicro())
+			continue
+		}
+		switch f.kind {
+		case reflect.String:
+			str := fv.String()
+			buf = binary.AppendVarint(buf, int64(len(str)))
+			buf = append(buf, str...)
+		case reflect.Bool:
+			if fv.Bool() {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			buf = binary.AppendVarint(buf, fv.Int())
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// uint64 values above math.MaxInt64 wrap; counters never reach that range in practice.
+			buf = binary.AppendVarint(buf, int64(fv.Uint()))
+		case reflect.Float32:
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(fv.Float())))
+		case reflect.Float64:
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(fv.Float()))
+		default:
+			return nil, fmt.Errorf("unsupported field kind %s for %s", f.kind, f.name)
+		}
+	}
+	return buf, nil
+}
+
+// getOrComputeAvroSchema returns the cached Avro schema for a record type or generates it.
+func getOrComputeAvroSchema(r Record) (*avroRecordSchema, error) {
+	t := reflect.TypeOf(r)
+	if t.Kind() == reflect.Ptr { // nolint:govet
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %s", t.Kind())
+	}
+
+	if cached, ok := avroSchemaCache.Load(t); ok {
+		return cached.(*avroRecordSchema), nil
+	}
+
+	// Every field carries its zero value as default, so adding a column
+	// stays backward compatible under the schema registry's default check.
+	type schemaField struct {
+		Name    string `json:"name"`
+		Type    any    `json:"type"`
+		Default any    `json:"default"`
+	}
+
+	var fields []avroField
+	var schemaFields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("ch")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		f := avroField{name: name, index: i, kind: field.Type.Kind()}
+		var avroType, avroDefault any
+		switch {
+		case field.Type == timeType:
+			f.time = true
+			avroType, avroDefault = map[string]string{"type": "long", "logicalType": "timestamp-micros"}, 0
+		case f.kind == reflect.String:
+			avroType, avroDefault = "string", ""
+		case f.kind == reflect.Bool:
+			avroType, avroDefault = "boolean", false
+		case f.kind == reflect.Int8, f.kind == reflect.Int16, f.kind == reflect.Int32,
+			f.kind == reflect.Uint8, f.kind == reflect.Uint16:
+			avroType, avroDefault = "int", 0
+		case f.kind == reflect.Int64, f.kind == reflect.Uint32, f.kind == reflect.Uint64:
+			avroType, avroDefault = "long", 0
+		case f.kind == reflect.Float32:
+			avroType, avroDefault = "float", 0
+		case f.kind == reflect.Float64:
+			avroType, avroDefault = "double", 0
+		default:
+			return nil, fmt.Errorf("unsupported field type %s for column %s", field.Type, name)
+		}
+
+		fields = append(fields, f)
+		schemaFields = append(schemaFields, schemaField{Name: name, Type: avroType, Default: avroDefault})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no columns found with 'ch' tags")
+	}
+
+	schema, err := json.Marshal(struct {
+		Type      string        `json:"type"`
+		Name      string        `json:"name"`
+		Namespace string        `json:"namespace"`
+		Fields    []schemaField `json:"fields"`
+	}{
+		Type:      "record",
+		Name:      r.TableName(),
+		Namespace: AvroNamespace,
+		Fields:    schemaFields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling avro schema: %w", err)
+	}
+
+	s := &avroRecordSchema{
+		name:   r.TableName(),
+		schema: string(schema),
+		fields: fields,
+	}
+	avroSchemaCache.Store(t, s)
+	return s, nil
+}
@@ -5,11 +5,15 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -48,27 +52,49 @@ type structMetadata struct {
 // structMetadataCache caches reflection metadata per type to avoid repeated reflection.
 var structMetadataCache sync.Map // map[reflect.Type]*structMetadata
 
+// clickhouseHealthCheckTimeout bounds the ping used to pick a healthy endpoint.
+const clickhouseHealthCheckTimeout = 5 * time.Second
+
 // ClickhouseRecordWriter implements RecordWriter for writing Records to ClickHouse.
 // Records are routed to tables based on their TableName() method.
 // It uses reflection to dynamically build INSERT statements from struct tags.
+//
+// When several replica addresses are configured, writes go to one endpoint at a
+// time. A connection error on the active endpoint triggers a failover to the next
+// healthy endpoint in round-robin order, and the failed batch is retried once there.
 type ClickhouseRecordWriter struct {
-	addr       string
+	addrs      []string
 	db         string
 	user       string
 	pass       string
 	disableTLS bool
-	conn       clickhouse.Conn
 	logger     *slog.Logger
 	metrics    *ClickhouseMetrics
+	dial       func(addr string) (clickhouse.Conn, error)
+
+	mu     sync.Mutex
+	conn   clickhouse.Conn
+	active int // index into addrs of the endpoint behind conn
 }
 
 // ClickhouseWriterOption configures a ClickhouseRecordWriter.
 type ClickhouseWriterOption func(*ClickhouseRecordWriter)
 
-// WithClickhouseAddr sets the ClickHouse server address.
+// WithClickhouseAddr sets the ClickHouse server address. A comma-separated
+// list configures multiple replicas, as with WithClickhouseAddrs.
 func WithClickhouseAddr(addr string) ClickhouseWriterOption {
+	return WithClickhouseAddrs(strings.Split(addr, ",")...)
+}
+
+// WithClickhouseAddrs sets the ClickHouse replica addresses in failover order.
+func WithClickhouseAddrs(addrs ...string) ClickhouseWriterOption {
 	return func(cw *ClickhouseRecordWriter) {
-		cw.addr = addr
+		cw.addrs = nil
+		for _, addr := range addrs {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				cw.addrs = append(cw.addrs, addr)
+			}
+		}
 	}
 }
 
@@ -114,8 +140,15 @@ func WithClickhouseMetrics(metrics *ClickhouseMetrics) ClickhouseWriterOption {
 	}
 }
 
+// withClickhouseDialer is used for testing to inject a mock driver connection.
+func withClickhouseDialer(dial func(addr string) (clickhouse.Conn, error)) ClickhouseWriterOption {
+	return func(cw *ClickhouseRecordWriter) {
+		cw.dial = dial
+	}
+}
+
 // NewClickhouseRecordWriter creates a new ClickhouseRecordWriter with the given options.
-// The ClickHouse address must be configured via WithClickhouseAddr.
+// The ClickHouse address must be configured via WithClickhouseAddr or WithClickhouseAddrs.
 func NewClickhouseRecordWriter(opts ...ClickhouseWriterOption) (*ClickhouseRecordWriter, error) {
 	cw := &ClickhouseRecordWriter{
 		db:      "default",
@@ -127,16 +160,46 @@ func NewClickhouseRecordWriter(opts ...ClickhouseWriterOption) (*ClickhouseRecor
 		opt(cw)
 	}
 
-	if cw.addr == "" {
+	if len(cw.addrs) == 0 {
 		return nil, fmt.Errorf("clickhouse address is required: use WithClickhouseAddr")
 	}
 
 	if cw.logger == nil {
 		cw.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
+	if cw.dial == nil {
+		cw.dial = cw.open
+	}
+
+	// A single endpoint connects lazily, as the driver does by default.
+	if len(cw.addrs) == 1 {
+		conn, err := cw.dial(cw.addrs[0])
+		if err != nil {
+			return nil, fmt.Errorf("error opening clickhouse connection: %w", err)
+		}
+		cw.setActive(0, conn)
+		return cw, nil
+	}
+
+	// With replicas, start on the first healthy endpoint. If none answers yet,
+	// fall back to the first one and let write failures drive failover.
+	idx, conn, err := cw.connectHealthy(context.Background(), 0, -1)
+	if err != nil {
+		cw.logger.Warn("no healthy clickhouse endpoint at startup, using first", "addr", cw.addrs[0], "error", err)
+		idx = 0
+		if conn, err = cw.dial(cw.addrs[0]); err != nil {
+			return nil, fmt.Errorf("error opening clickhouse connection: %w", err)
+		}
+	}
+	cw.setActive(idx, conn)
+	cw.logger.Info("using clickhouse endpoint", "addr", cw.addrs[idx], "endpoints", len(cw.addrs))
+	return cw, nil
+}
 
+// open creates a driver connection to a single ClickHouse endpoint.
+func (cw *ClickhouseRecordWriter) open(addr string) (clickhouse.Conn, error) {
 	chOpts := &clickhouse.Options{
-		Addr: []string{cw.addr},
+		Addr: []string{addr},
 		Auth: clickhouse.Auth{
 			Database: cw.db,
 			Username: cw.user,
@@ -148,13 +211,103 @@ func NewClickhouseRecordWriter(opts ...ClickhouseWriterOption) (*ClickhouseRecor
 		chOpts.TLS = &tls.Config{}
 	}
 
-	conn, err := clickhouse.Open(chOpts)
-	if err != nil {
-		return nil, fmt.Errorf("error opening clickhouse connection: %w", err)
+	return clickhouse.Open(chOpts)
+}
+
+// connectHealthy dials endpoints in round-robin order beginning at start and
+// returns the first that answers a ping. The endpoint at index skip is not
+// tried; pass -1 to try all of them.
+func (cw *ClickhouseRecordWriter) connectHealthy(ctx context.Context, start, skip int) (int, clickhouse.Conn, error) {
+	var errs []error
+	for i := range len(cw.addrs) {
+		idx := (start + i) % len(cw.addrs)
+		if idx == skip {
+			continue
+		}
+		addr := cw.addrs[idx]
+
+		conn, err := cw.dial(addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, clickhouseHealthCheckTimeout)
+		err = conn.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			_ = conn.Close()
+			cw.logger.Debug("clickhouse endpoint failed health check", "addr", addr, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+		return idx, conn, nil
 	}
+	return -1, nil, errors.Join(errs...)
+}
 
+// setActive installs conn as the active connection and updates the endpoint gauge.
+// Callers must hold cw.mu once the writer is in use.
+func (cw *ClickhouseRecordWriter) setActive(idx int, conn clickhouse.Conn) {
 	cw.conn = conn
-	return cw, nil
+	cw.active = idx
+	for i, addr := range cw.addrs {
+		value := 0.0
+		if i == idx {
+			value = 1
+		}
+		cw.metrics.ActiveEndpoint.WithLabelValues(addr).Set(value)
+	}
+}
+
+// activeConn returns the connection to the active endpoint.
+func (cw *ClickhouseRecordWriter) activeConn() clickhouse.Conn {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return cw.conn
+}
+
+// failover switches to the next healthy endpoint after a connection error on
+// the active one. It reports whether a different endpoint is now active.
+func (cw *ClickhouseRecordWriter) failover(ctx context.Context, cause error) bool {
+	if len(cw.addrs) < 2 || ctx.Err() != nil || !isClickhouseConnectionError(cause) {
+		return false
+	}
+
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	from := cw.active
+	idx, conn, err := cw.connectHealthy(ctx, from+1, from)
+	if err != nil {
+		cw.logger.Error("clickhouse failover failed: no healthy endpoint", "addr", cw.addrs[from], "error", err)
+		return false
+	}
+
+	_ = cw.conn.Close()
+	cw.setActive(idx, conn)
+	cw.metrics.Failovers.Inc()
+	cw.logger.Warn("failed over to another clickhouse endpoint",
+		"from", cw.addrs[from],
+		"to", cw.addrs[idx],
+		"error", cause)
+	return true
+}
+
+// isClickhouseConnectionError reports whether err indicates the endpoint itself
+// is unreachable, as opposed to a server-side exception or a bad record.
+func isClickhouseConnectionError(err error) bool {
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
 }
 
 // WriteRecords writes Records to ClickHouse, routing to tables based on record type.
@@ -171,18 +324,29 @@ func (cw *ClickhouseRecordWriter) WriteRecords(ctx context.Context, records []Re
 		byTable[table] = append(byTable[table], r)
 	}
 
-	// Write each table's records
+	err := cw.writeTables(ctx, byTable)
+	if err != nil && cw.failover(ctx, err) {
+		// Retry the whole batch once on the new endpoint.
+		err = cw.writeTables(ctx, byTable)
+	}
+	return err
+}
+
+// writeTables writes each table's records over the active connection.
+func (cw *ClickhouseRecordWriter) writeTables(ctx context.Context, byTable map[string][]Record) error {
+	conn := cw.activeConn()
 	for table, tableRecords := range byTable {
-		if err := cw.writeGeneric(ctx, table, tableRecords); err != nil {
+		if err := cw.writeGeneric(ctx, conn, table, tableRecords); err != nil {
 			return fmt.Errorf("error writing to table %s: %w", table, err)
 		}
 	}
-
 	return nil
 }
 
 // Close closes the ClickHouse connection.
 func (cw *ClickhouseRecordWriter) Close() error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
 	return cw.conn.Close()
 }
 
@@ -190,7 +354,7 @@ func (cw *ClickhouseRecordWriter) Close() error {
 // It uses fail-fast semantics: if any record fails to serialize or append,
 // the entire batch is aborted to ensure atomicity. This prevents partial writes
 // and allows the caller to retry the full batch.
-func (cw *ClickhouseRecordWriter) writeGeneric(ctx context.Context, table string, records []Record) error {
+func (cw *ClickhouseRecordWriter) writeGeneric(ctx context.Context, conn clickhouse.Conn, table string, records []Record) error {
 	if len(records) == 0 {
 		return nil
 	}
@@ -204,7 +368,7 @@ func (cw *ClickhouseRecordWriter) writeGeneric(ctx context.Context, table string
 	// Build INSERT query
 	query := fmt.Sprintf("INSERT INTO %s.%s (%s)", cw.db, table, strings.Join(columns, ", "))
 
-	batch, err := cw.conn.PrepareBatch(ctx, query)
+	batch, err := conn.PrepareBatch(ctx, query)
 	if err != nil {
 		return fmt.Errorf("error preparing batch: %w", err)
 	}
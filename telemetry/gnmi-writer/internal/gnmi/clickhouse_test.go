@@ -1,10 +1,16 @@
 package gnmi
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"syscall"
 	"testing"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestIsRetryableClickhouseError(t *testing.T) {
@@ -48,3 +54,213 @@ func TestIsRetryableClickhouseError(t *testing.T) {
 		})
 	}
 }
+
+// fakeChBatch implements driver.Batch for the methods the writer uses.
+type fakeChBatch struct {
+	driver.Batch
+	conn *fakeChConn
+	rows int
+}
+
+func (b *fakeChBatch) Append(v ...any) error {
+	b.rows++
+	return nil
+}
+
+func (b *fakeChBatch) Send() error {
+	if b.conn.sendErr != nil {
+		return b.conn.sendErr
+	}
+	b.conn.written += b.rows
+	return nil
+}
+
+func (b *fakeChBatch) Close() error { return nil }
+
+// fakeChConn implements driver.Conn for the methods the writer uses.
+type fakeChConn struct {
+	driver.Conn
+	pingErr error
+	sendErr error
+	written int
+	closed  bool
+}
+
+func (c *fakeChConn) Ping(context.Context) error { return c.pingErr }
+
+func (c *fakeChConn) PrepareBatch(_ context.Context, _ string, _ ...driver.PrepareBatchOption) (driver.Batch, error) {
+	return &fakeChBatch{conn: c}, nil
+}
+
+func (c *fakeChConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newFakeChDialer(conns map[string]*fakeChConn) func(addr string) (clickhouse.Conn, error) {
+	return func(addr string) (clickhouse.Conn, error) {
+		conn, ok := conns[addr]
+		if !ok {
+			return nil, fmt.Errorf("unknown addr %s", addr)
+		}
+		return conn, nil
+	}
+}
+
+var errConnRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+func TestClickhouseRecordWriter_SingleAddr(t *testing.T) {
+	conn := &fakeChConn{pingErr: errConnRefused}
+	metrics := NewClickhouseMetrics(prometheus.NewRegistry())
+	cw, err := NewClickhouseRecordWriter(
+		WithClickhouseAddr("ch1:9440"),
+		WithClickhouseMetrics(metrics),
+		withClickhouseDialer(newFakeChDialer(map[string]*fakeChConn{"ch1:9440": conn})),
+	)
+	if err != nil {
+		t.Fatalf("NewClickhouseRecordWriter() error = %v", err)
+	}
+
+	// A single endpoint is used without a startup health check.
+	if cw.activeConn() != clickhouse.Conn(conn) {
+		t.Fatal("expected the only endpoint to be active")
+	}
+	if got := testutil.ToFloat64(metrics.ActiveEndpoint.WithLabelValues("ch1:9440")); got != 1 {
+		t.Errorf("active_endpoint{ch1} = %v, want 1", got)
+	}
+
+	conn.sendErr = errConnRefused
+	if err := cw.WriteRecords(context.Background(), []Record{InterfaceStateRecord{DevicePubkey: "dev1"}}); err == nil {
+		t.Fatal("expected write error")
+	}
+	if got := testutil.ToFloat64(metrics.Failovers); got != 0 {
+		t.Errorf("failovers = %v, want 0", got)
+	}
+}
+
+func TestClickhouseRecordWriter_StartsOnFirstHealthyReplica(t *testing.T) {
+	conns := map[string]*fakeChConn{
+		"ch1:9440": {pingErr: errConnRefused},
+		"ch2:9440": {},
+	}
+	metrics := NewClickhouseMetrics(prometheus.NewRegistry())
+	cw, err := NewClickhouseRecordWriter(
+		WithClickhouseAddr("ch1:9440, ch2:9440"),
+		WithClickhouseMetrics(metrics),
+		withClickhouseDialer(newFakeChDialer(conns)),
+	)
+	if err != nil {
+		t.Fatalf("NewClickhouseRecordWriter() error = %v", err)
+	}
+
+	if cw.activeConn() != clickhouse.Conn(conns["ch2:9440"]) {
+		t.Fatal("expected ch2 to be active")
+	}
+	if !conns["ch1:9440"].closed {
+		t.Error("expected unhealthy ch1 connection to be closed")
+	}
+	if got := testutil.ToFloat64(metrics.ActiveEndpoint.WithLabelValues("ch1:9440")); got != 0 {
+		t.Errorf("active_endpoint{ch1} = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(metrics.ActiveEndpoint.WithLabelValues("ch2:9440")); got != 1 {
+		t.Errorf("active_endpoint{ch2} = %v, want 1", got)
+	}
+}
+
+func TestClickhouseRecordWriter_FailoverOnWriteError(t *testing.T) {
+	conns := map[string]*fakeChConn{
+		"ch1:9440": {},
+		"ch2:9440": {},
+	}
+	metrics := NewClickhouseMetrics(prometheus.NewRegistry())
+	cw, err := NewClickhouseRecordWriter(
+		WithClickhouseAddrs("ch1:9440", "ch2:9440"),
+		WithClickhouseMetrics(metrics),
+		withClickhouseDialer(newFakeChDialer(conns)),
+	)
+	if err != nil {
+		t.Fatalf("NewClickhouseRecordWriter() error = %v", err)
+	}
+	if cw.activeConn() != clickhouse.Conn(conns["ch1:9440"]) {
+		t.Fatal("expected ch1 to be active")
+	}
+
+	// ch1 goes away: the batch is retried on ch2.
+	conns["ch1:9440"].sendErr = errConnRefused
+	conns["ch1:9440"].pingErr = errConnRefused
+	records := []Record{
+		InterfaceStateRecord{DevicePubkey: "dev1"},
+		InterfaceStateRecord{DevicePubkey: "dev2"},
+	}
+	if err := cw.WriteRecords(context.Background(), records); err != nil {
+		t.Fatalf("WriteRecords() error = %v", err)
+	}
+
+	if got := conns["ch2:9440"].written; got != 2 {
+		t.Errorf("ch2 written = %d, want 2", got)
+	}
+	if !conns["ch1:9440"].closed {
+		t.Error("expected failed ch1 connection to be closed")
+	}
+	if got := testutil.ToFloat64(metrics.Failovers); got != 1 {
+		t.Errorf("failovers = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.ActiveEndpoint.WithLabelValues("ch2:9440")); got != 1 {
+		t.Errorf("active_endpoint{ch2} = %v, want 1", got)
+	}
+}
+
+func TestClickhouseRecordWriter_NoFailoverOnServerException(t *testing.T) {
+	conns := map[string]*fakeChConn{
+		"ch1:9440": {},
+		"ch2:9440": {},
+	}
+	metrics := NewClickhouseMetrics(prometheus.NewRegistry())
+	cw, err := NewClickhouseRecordWriter(
+		WithClickhouseAddrs("ch1:9440", "ch2:9440"),
+		WithClickhouseMetrics(metrics),
+		withClickhouseDialer(newFakeChDialer(conns)),
+	)
+	if err != nil {
+		t.Fatalf("NewClickhouseRecordWriter() error = %v", err)
+	}
+
+	conns["ch1:9440"].sendErr = &clickhouse.Exception{Code: 60, Message: "Table does not exist"}
+	if err := cw.WriteRecords(context.Background(), []Record{InterfaceStateRecord{DevicePubkey: "dev1"}}); err == nil {
+		t.Fatal("expected write error")
+	}
+	if cw.activeConn() != clickhouse.Conn(conns["ch1:9440"]) {
+		t.Error("expected ch1 to stay active")
+	}
+	if got := testutil.ToFloat64(metrics.Failovers); got != 0 {
+		t.Errorf("failovers = %v, want 0", got)
+	}
+}
+
+func TestClickhouseRecordWriter_NoHealthyReplicaKeepsActive(t *testing.T) {
+	conns := map[string]*fakeChConn{
+		"ch1:9440": {},
+		"ch2:9440": {},
+	}
+	metrics := NewClickhouseMetrics(prometheus.NewRegistry())
+	cw, err := NewClickhouseRecordWriter(
+		WithClickhouseAddrs("ch1:9440", "ch2:9440"),
+		WithClickhouseMetrics(metrics),
+		withClickhouseDialer(newFakeChDialer(conns)),
+	)
+	if err != nil {
+		t.Fatalf("NewClickhouseRecordWriter() error = %v", err)
+	}
+
+	conns["ch1:9440"].sendErr = errConnRefused
+	conns["ch2:9440"].pingErr = errConnRefused
+	if err := cw.WriteRecords(context.Background(), []Record{InterfaceStateRecord{DevicePubkey: "dev1"}}); err == nil {
+		t.Fatal("expected write error")
+	}
+	if cw.activeConn() != clickhouse.Conn(conns["ch1:9440"]) {
+		t.Error("expected ch1 to stay active")
+	}
+	if got := testutil.ToFloat64(metrics.Failovers); got != 0 {
+		t.Errorf("failovers = %v, want 0", got)
+	}
+}
@@ -41,6 +41,29 @@ const (
 	KafkaAuthTypeAWSMSK
 )
 
+// KafkaStartOffset is where a consumer group with no committed offset starts
+// consuming a partition.
+type KafkaStartOffset string
+
+const (
+	// KafkaStartOffsetEarliest consumes the retained backlog from the start.
+	KafkaStartOffsetEarliest KafkaStartOffset = "earliest"
+	// KafkaStartOffsetLatest skips the backlog and consumes only new messages.
+	KafkaStartOffsetLatest KafkaStartOffset = "latest"
+)
+
+// resetOffset returns the kgo reset offset for the start offset policy.
+func (o KafkaStartOffset) resetOffset() (kgo.Offset, error) {
+	switch o {
+	case KafkaStartOffsetEarliest:
+		return kgo.NewOffset().AtStart(), nil
+	case KafkaStartOffsetLatest:
+		return kgo.NewOffset().AtEnd(), nil
+	default:
+		return kgo.Offset{}, fmt.Errorf("invalid kafka start offset %q (must be earliest or latest)", o)
+	}
+}
+
 // KafkaConsumer consumes gNMI notifications from a Kafka topic.
 type KafkaConsumer struct {
 	brokers    []string
@@ -50,7 +73,9 @@ type KafkaConsumer struct {
 	group      string
 	authType   KafkaAuthType
 	disableTLS bool
+	startAt    KafkaStartOffset
 	client     kafkaClient
+	newClient  func(opts ...kgo.Opt) (kafkaClient, error)
 	logger     *slog.Logger
 	metrics    *ConsumerMetrics
 }
@@ -107,6 +132,15 @@ func WithKafkaTLSDisabled(disabled bool) KafkaConsumerOption {
 	}
 }
 
+// WithKafkaStartOffset sets where to start consuming when the consumer group
+// has no committed offset for a partition (default latest). It has no effect
+// once the group has committed offsets.
+func WithKafkaStartOffset(startAt KafkaStartOffset) KafkaConsumerOption {
+	return func(kc *KafkaConsumer) {
+		kc.startAt = startAt
+	}
+}
+
 // WithKafkaLogger sets the logger for the consumer.
 func WithKafkaLogger(logger *slog.Logger) KafkaConsumerOption {
 	return func(kc *KafkaConsumer) {
@@ -128,11 +162,23 @@ func withKafkaClient(client kafkaClient) KafkaConsumerOption {
 	}
 }
 
+// withKafkaClientFactory is used for testing to capture the options the
+// client is created with.
+func withKafkaClientFactory(newClient func(opts ...kgo.Opt) (kafkaClient, error)) KafkaConsumerOption {
+	return func(kc *KafkaConsumer) {
+		kc.newClient = newClient
+	}
+}
+
 // NewKafkaConsumer creates a new KafkaConsumer with the given options.
 // Brokers, topic, and consumer group must be configured via their respective options.
 func NewKafkaConsumer(opts ...KafkaConsumerOption) (*KafkaConsumer, error) {
 	kc := &KafkaConsumer{
 		metrics: NewConsumerMetrics(nil), // Always set, unregistered by default
+		startAt: KafkaStartOffsetLatest,
+		newClient: func(opts ...kgo.Opt) (kafkaClient, error) {
+			return kgo.NewClient(opts...)
+		},
 	}
 	for _, opt := range opts {
 		opt(kc)
@@ -157,44 +203,21 @@ func NewKafkaConsumer(opts ...KafkaConsumerOption) (*KafkaConsumer, error) {
 		return nil, fmt.Errorf("kafka consumer group is required: use WithKafkaGroup")
 	}
 
-	kOpts := []kgo.Opt{}
-
-	switch kc.authType {
-	case KafkaAuthTypeSCRAM:
-		kOpts = append(kOpts, kgo.SASL(scram.Auth{
-			User: kc.user,
-			Pass: kc.pass,
-		}.AsSha256Mechanism()))
-	case KafkaAuthTypeAWSMSK:
-		kOpts = append(kOpts, kgo.SASL(aws.ManagedStreamingIAM(func(ctx context.Context) (aws.Auth, error) {
-			cfg, err := awsconfig.LoadDefaultConfig(ctx)
-			if err != nil {
-				return aws.Auth{}, fmt.Errorf("error loading aws config: %w", err)
-			}
-			creds, err := cfg.Credentials.Retrieve(ctx)
-			if err != nil {
-				return aws.Auth{}, fmt.Errorf("error retrieving credentials: %w", err)
-			}
-			return aws.Auth{
-				AccessKey:    creds.AccessKeyID,
-				SecretKey:    creds.SecretAccessKey,
-				SessionToken: creds.SessionToken,
-			}, nil
-		})))
+	resetOffset, err := kc.startAt.resetOffset()
+	if err != nil {
+		return nil, err
 	}
 
-	if !kc.disableTLS {
-		kOpts = append(kOpts, kgo.DialTLS())
-	}
+	kOpts := kafkaAuthOpts(kc.authType, kc.user, kc.pass, kc.disableTLS)
 
 	kOpts = append(kOpts,
 		kgo.SeedBrokers(kc.brokers...),
 		kgo.ConsumeTopics(kc.topic),
 		kgo.ConsumerGroup(kc.group),
-		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.ConsumeResetOffset(resetOffset),
 	)
 
-	client, err := kgo.NewClient(kOpts...)
+	client, err := kc.newClient(kOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating kafka client: %w", err)
 	}
@@ -257,3 +280,38 @@ func (kc *KafkaConsumer) Close() error {
 	kc.client.Close()
 	return nil
 }
+
+// kafkaAuthOpts returns the kgo options for the given authentication settings.
+func kafkaAuthOpts(authType KafkaAuthType, user, pass string, disableTLS bool) []kgo.Opt {
+	var kOpts []kgo.Opt
+
+	switch authType {
+	case KafkaAuthTypeSCRAM:
+		kOpts = append(kOpts, kgo.SASL(scram.Auth{
+			User: user,
+			Pass: pass,
+		}.AsSha256Mechanism()))
+	case KafkaAuthTypeAWSMSK:
+		kOpts = append(kOpts, kgo.SASL(aws.ManagedStreamingIAM(func(ctx context.Context) (aws.Auth, error) {
+			cfg, err := awsconfig.LoadDefaultConfig(ctx)
+			if err != nil {
+				return aws.Auth{}, fmt.Errorf("error loading aws config: %w", err)
+			}
+			creds, err := cfg.Credentials.Retrieve(ctx)
+			if err != nil {
+				return aws.Auth{}, fmt.Errorf("error retrieving credentials: %w", err)
+			}
+			return aws.Auth{
+				AccessKey:    creds.AccessKeyID,
+				SecretKey:    creds.SecretAccessKey,
+				SessionToken: creds.SessionToken,
+			}, nil
+		})))
+	}
+
+	if !disableTLS {
+		kOpts = append(kOpts, kgo.DialTLS())
+	}
+
+	return kOpts
+}
@@ -98,3 +98,53 @@ func TestKafkaConsumer_Consume(t *testing.T) {
 		t.Errorf("expected device1, got %s", notifications[0].GetPrefix().GetTarget())
 	}
 }
+
+func TestKafkaConsumer_StartOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []KafkaConsumerOption
+		want    kgo.Offset
+		wantErr bool
+	}{
+		{"default is latest", nil, kgo.NewOffset().AtEnd(), false},
+		{"latest", []KafkaConsumerOption{WithKafkaStartOffset(KafkaStartOffsetLatest)}, kgo.NewOffset().AtEnd(), false},
+		{"earliest", []KafkaConsumerOption{WithKafkaStartOffset(KafkaStartOffsetEarliest)}, kgo.NewOffset().AtStart(), false},
+		{"invalid", []KafkaConsumerOption{WithKafkaStartOffset("newest")}, kgo.Offset{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			opts := append([]KafkaConsumerOption{
+				WithKafkaBrokers([]string{"127.0.0.1:1"}),
+				WithKafkaTopic("test-topic"),
+				WithKafkaGroup("test-group"),
+				WithKafkaTLSDisabled(true),
+				withKafkaClientFactory(func(opts ...kgo.Opt) (kafkaClient, error) {
+					// Build (but never poll) a real client to read back the
+					// reset offset it was configured with.
+					client, err := kgo.NewClient(opts...)
+					if err != nil {
+						return nil, err
+					}
+					got = client.OptValue(kgo.ConsumeResetOffset)
+					client.Close()
+					return &mockKafkaClient{}, nil
+				}),
+			}, tt.opts...)
+
+			_, err := NewKafkaConsumer(opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error for invalid start offset")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to create consumer: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("reset offset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -54,6 +54,8 @@ func extractIsisAdjacencies(device *oc.Device, meta Metadata) []Record {
 						record := IsisAdjacencyRecord{
 							Timestamp:    meta.Timestamp,
 							DevicePubkey: meta.DevicePubkey,
+							Env:          meta.Env,
+							ClockSkew:    meta.ClockSkew,
 							InterfaceID:  ifID,
 							Level:        uint8(levelNum),
 							SystemID:     sysID,
@@ -106,6 +108,8 @@ func extractSystemState(device *oc.Device, meta Metadata) []Record {
 	record := SystemStateRecord{
 		Timestamp:    meta.Timestamp,
 		DevicePubkey: meta.DevicePubkey,
+		Env:          meta.Env,
+		ClockSkew:    meta.ClockSkew,
 	}
 
 	// Hostname is now in State container
@@ -186,6 +190,8 @@ func extractBgpNeighbors(device *oc.Device, meta Metadata) []Record {
 				record := BgpNeighborRecord{
 					Timestamp:       meta.Timestamp,
 					DevicePubkey:    meta.DevicePubkey,
+					Env:             meta.Env,
+					ClockSkew:       meta.ClockSkew,
 					NetworkInstance: niName,
 					NeighborAddress: addr,
 				}
@@ -247,6 +253,8 @@ func extractInterfaceIfindex(device *oc.Device, meta Metadata) []Record {
 		record := InterfaceIfindexRecord{
 			Timestamp:     meta.Timestamp,
 			DevicePubkey:  meta.DevicePubkey,
+			Env:           meta.Env,
+			ClockSkew:     meta.ClockSkew,
 			InterfaceName: ifName,
 			Ifindex:       *iface.State.Ifindex,
 		}
@@ -276,6 +284,8 @@ func extractTransceiverState(device *oc.Device, meta Metadata) []Record {
 			record := TransceiverStateRecord{
 				Timestamp:     meta.Timestamp,
 				DevicePubkey:  meta.DevicePubkey,
+				Env:           meta.Env,
+				ClockSkew:     meta.ClockSkew,
 				InterfaceName: compName,
 				ChannelIndex:  chanIdx,
 			}
@@ -324,6 +334,8 @@ func extractInterfaceState(device *oc.Device, meta Metadata) []Record {
 		record := InterfaceStateRecord{
 			Timestamp:     meta.Timestamp,
 			DevicePubkey:  meta.DevicePubkey,
+			Env:           meta.Env,
+			ClockSkew:     meta.ClockSkew,
 			InterfaceName: ifName,
 		}
 
@@ -425,6 +437,8 @@ func extractIsisOverloadBit(device *oc.Device, meta Metadata) []Record {
 			records = append(records, IsisOverloadBitRecord{
 				Timestamp:       meta.Timestamp,
 				DevicePubkey:    meta.DevicePubkey,
+				Env:             meta.Env,
+				ClockSkew:       meta.ClockSkew,
 				NetworkInstance: niName,
 				OverloadBit:     overloadBit,
 			})
@@ -454,6 +468,8 @@ func extractIsisGlobalState(device *oc.Device, meta Metadata) []Record {
 			record := IsisGlobalStateRecord{
 				Timestamp:       meta.Timestamp,
 				DevicePubkey:    meta.DevicePubkey,
+				Env:             meta.Env,
+				ClockSkew:       meta.ClockSkew,
 				NetworkInstance: niName,
 			}
 			if state.Instance != nil {
@@ -492,6 +508,8 @@ func extractTransceiverThresholds(device *oc.Device, meta Metadata) []Record {
 			record := TransceiverThresholdRecord{
 				Timestamp:     meta.Timestamp,
 				DevicePubkey:  meta.DevicePubkey,
+				Env:           meta.Env,
+				ClockSkew:     meta.ClockSkew,
 				InterfaceName: compName,
 				Severity:      severity.String(),
 			}
@@ -0,0 +1,226 @@
+package gnmi
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Reasons a device's notifications are dropped under per-device isolation,
+// used as the reason label of DeviceNotificationsDropped.
+const (
+	deviceDropQueueFull = "queue_full"
+	deviceDropTimeout   = "timeout"
+)
+
+// deviceJob is one notification queued for extraction.
+type deviceJob struct {
+	ctx          context.Context // done once the batch stops waiting for results
+	notification *gpb.Notification
+	results      chan<- []Record
+}
+
+// deviceBatch tracks the notifications of one device queued for a batch.
+type deviceBatch struct {
+	results chan []Record // buffered for every queued notification, so workers never block
+	queued  int
+}
+
+// deviceShard is a worker and the per-device queues of the devices assigned to
+// it. The worker takes one notification per device in turn, so a device
+// flooding notifications does not starve the others on its shard. A device's
+// queue is removed as soon as it is empty, so idle devices hold no state.
+type deviceShard struct {
+	mu     sync.Mutex
+	queues map[string][]deviceJob
+	order  []string      // devices with queued notifications, in service order
+	wake   chan struct{} // signalled when a notification is queued
+}
+
+// enqueue queues job on device's queue, returning false if the queue already
+// holds depth notifications.
+func (s *deviceShard) enqueue(device string, job deviceJob, depth int) bool {
+	s.mu.Lock()
+	queue, ok := s.queues[device]
+	if len(queue) >= depth {
+		s.mu.Unlock()
+		return false
+	}
+	if !ok {
+		s.order = append(s.order, device)
+	}
+	s.queues[device] = append(queue, job)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// next dequeues the next notification in service order, reaping the device's
+// queue if it is now empty. It returns false if nothing is queued.
+func (s *deviceShard) next() (deviceJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.order) == 0 {
+		return deviceJob{}, false
+	}
+	device := s.order[0]
+	s.order = s.order[1:]
+	queue := s.queues[device]
+	job := queue[0]
+	if len(queue) == 1 {
+		delete(s.queues, device)
+	} else {
+		s.queues[device] = queue[1:]
+		s.order = append(s.order, device)
+	}
+	return job, true
+}
+
+// queued returns the number of notifications queued for device.
+func (s *deviceShard) queued(device string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[device])
+}
+
+// extractIsolated queues each notification on its device's shard and waits up
+// to the device timeout for their records. Notifications that do not fit in a
+// device's queue are dropped, as are those not extracted before the timeout,
+// so a slow device costs each batch at most the timeout. Records are returned
+// grouped by device in the order devices first appear in the batch,
+// preserving each device's own order.
+func (p *Processor) extractIsolated(ctx context.Context, notifications []*gpb.Notification) []Record {
+	if p.deviceShards == nil {
+		p.startDeviceWorkers()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.deviceTimeout)
+	defer cancel()
+
+	counts := make(map[string]int)
+	for _, n := range notifications {
+		counts[n.GetPrefix().GetTarget()]++
+	}
+
+	batches := make(map[string]*deviceBatch, len(counts))
+	var devices []string
+	queueFull := make(map[string]int)
+	for _, n := range notifications {
+		device := n.GetPrefix().GetTarget()
+		b, ok := batches[device]
+		if !ok {
+			b = &deviceBatch{results: make(chan []Record, counts[device])}
+			batches[device] = b
+			devices = append(devices, device)
+		}
+		job := deviceJob{ctx: ctx, notification: n, results: b.results}
+		if !p.deviceShard(device).enqueue(device, job, p.deviceQueueDepth) {
+			queueFull[device]++
+			continue
+		}
+		b.queued++
+	}
+	for device, count := range queueFull {
+		p.logger.Warn("device queue full, dropping its notifications",
+			"device", device,
+			"dropped", count,
+			"queue_depth", p.deviceQueueDepth)
+		p.metrics.DeviceNotificationsDropped.WithLabelValues(device, deviceDropQueueFull).Add(float64(count))
+	}
+
+	var records []Record
+	for _, device := range devices {
+		b := batches[device]
+		received := 0
+		for ; received < b.queued; received++ {
+			r, ok := receiveRecords(ctx, b.results)
+			if !ok {
+				break
+			}
+			records = append(records, r...)
+		}
+		if missed := b.queued - received; missed > 0 {
+			p.logger.Warn("device extraction timed out, dropping its notifications",
+				"device", device,
+				"dropped", missed,
+				"timeout", p.deviceTimeout)
+			p.metrics.DeviceNotificationsDropped.WithLabelValues(device, deviceDropTimeout).Add(float64(missed))
+		}
+	}
+	return records
+}
+
+// receiveRecords returns the next records from results, preferring records
+// already extracted over a done ctx. It returns false once ctx is done.
+func receiveRecords(ctx context.Context, results <-chan []Record) ([]Record, bool) {
+	select {
+	case r := <-results:
+		return r, true
+	default:
+	}
+	select {
+	case r := <-results:
+		return r, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// deviceShard returns the shard device is assigned to.
+func (p *Processor) deviceShard(device string) *deviceShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(device))
+	return p.deviceShards[h.Sum32()%uint32(len(p.deviceShards))]
+}
+
+// startDeviceWorkers starts one worker per shard. They run until
+// stopDeviceWorkers.
+func (p *Processor) startDeviceWorkers() {
+	p.deviceStop = make(chan struct{})
+	p.deviceShards = make([]*deviceShard, p.deviceWorkers)
+	for i := range p.deviceShards {
+		s := &deviceShard{
+			queues: make(map[string][]deviceJob),
+			wake:   make(chan struct{}, 1),
+		}
+		p.deviceShards[i] = s
+		go p.runDeviceWorker(s, p.deviceStop)
+	}
+}
+
+// runDeviceWorker extracts the shard's queued notifications, skipping those
+// whose batch has stopped waiting for them.
+func (p *Processor) runDeviceWorker(s *deviceShard, stop <-chan struct{}) {
+	for {
+		job, ok := s.next()
+		if !ok {
+			select {
+			case <-s.wake:
+				continue
+			case <-stop:
+				return
+			}
+		}
+		if job.ctx.Err() != nil {
+			continue
+		}
+		job.results <- p.extractNotification(job.ctx, job.notification)
+	}
+}
+
+// stopDeviceWorkers stops the device workers. Each exits once it finishes the
+// notification it is extracting and its shard is empty.
+func (p *Processor) stopDeviceWorkers() {
+	if p.deviceStop == nil {
+		return
+	}
+	close(p.deviceStop)
+	p.deviceStop = nil
+	p.deviceShards = nil
+}
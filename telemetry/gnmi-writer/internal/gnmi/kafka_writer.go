@@ -0,0 +1,241 @@
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// kafkaProducer is an interface for the subset of kgo.Client methods used for producing.
+// This allows for mocking in tests.
+type kafkaProducer interface {
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+	Close()
+}
+
+// schemaRegistry is an interface for registering schemas, allowing mocking in tests.
+type schemaRegistry interface {
+	Register(ctx context.Context, subject, schema string) (int, error)
+}
+
+// KafkaRecordWriter implements RecordWriter by encoding Records as Avro against a
+// Confluent-compatible schema registry and producing them to a Kafka topic.
+//
+// Each record type has its own schema, registered under the subject
+// "<topic>-<namespace>.<table>" (TopicRecordNameStrategy). Messages use the
+// Confluent wire format and are keyed by device pubkey.
+type KafkaRecordWriter struct {
+	brokers    []string
+	user       string
+	pass       string
+	topic      string
+	authType   KafkaAuthType
+	disableTLS bool
+	registry   schemaRegistry
+	producer   kafkaProducer
+	schemaIDs  map[string]int // table name -> schema ID
+	logger     *slog.Logger
+	metrics    *KafkaWriterMetrics
+}
+
+// KafkaWriterOption configures a KafkaRecordWriter.
+type KafkaWriterOption func(*KafkaRecordWriter)
+
+// WithKafkaWriterBrokers sets the Kafka broker addresses.
+func WithKafkaWriterBrokers(brokers []string) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.brokers = brokers
+	}
+}
+
+// WithKafkaWriterUser sets the SCRAM username.
+func WithKafkaWriterUser(user string) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.user = user
+	}
+}
+
+// WithKafkaWriterPassword sets the SCRAM password.
+func WithKafkaWriterPassword(pass string) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.pass = pass
+	}
+}
+
+// WithKafkaWriterTopic sets the topic records are produced to.
+func WithKafkaWriterTopic(topic string) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.topic = topic
+	}
+}
+
+// WithKafkaWriterAuthType sets the authentication type (SCRAM or AWS MSK).
+func WithKafkaWriterAuthType(authType KafkaAuthType) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.authType = authType
+	}
+}
+
+// WithKafkaWriterTLSDisabled disables TLS for the Kafka connection.
+func WithKafkaWriterTLSDisabled(disabled bool) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.disableTLS = disabled
+	}
+}
+
+// WithKafkaWriterSchemaRegistry sets the schema registry client.
+func WithKafkaWriterSchemaRegistry(registry *SchemaRegistryClient) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.registry = registry
+	}
+}
+
+// WithKafkaWriterLogger sets the logger.
+func WithKafkaWriterLogger(logger *slog.Logger) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.logger = logger
+	}
+}
+
+// WithKafkaWriterMetrics sets the metrics.
+func WithKafkaWriterMetrics(metrics *KafkaWriterMetrics) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.metrics = metrics
+	}
+}
+
+// withKafkaProducer is used for testing to inject a mock producer.
+func withKafkaProducer(producer kafkaProducer) KafkaWriterOption {
+	return func(kw *KafkaRecordWriter) {
+		kw.producer = producer
+	}
+}
+
+// NewKafkaRecordWriter creates a new KafkaRecordWriter with the given options.
+// Schemas for all KnownRecords are registered up front, so an unreachable
+// registry fails startup rather than the first write.
+func NewKafkaRecordWriter(ctx context.Context, opts ...KafkaWriterOption) (*KafkaRecordWriter, error) {
+	kw := &KafkaRecordWriter{
+		schemaIDs: make(map[string]int),
+		metrics:   NewKafkaWriterMetrics(nil), // Always set, unregistered by default
+	}
+	for _, opt := range opts {
+		opt(kw)
+	}
+
+	if kw.logger == nil {
+		kw.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	if kw.topic == "" {
+		return nil, fmt.Errorf("kafka output topic is required: use WithKafkaWriterTopic")
+	}
+	if kw.registry == nil {
+		return nil, fmt.Errorf("schema registry is required: use WithKafkaWriterSchemaRegistry")
+	}
+
+	for _, r := range KnownRecords() {
+		if _, err := kw.registerSchema(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	// If a producer was injected (for testing), skip creating a real one
+	if kw.producer != nil {
+		return kw, nil
+	}
+
+	if len(kw.brokers) == 0 {
+		return nil, fmt.Errorf("kafka brokers are required: use WithKafkaWriterBrokers")
+	}
+
+	kOpts := kafkaAuthOpts(kw.authType, kw.user, kw.pass, kw.disableTLS)
+	kOpts = append(kOpts,
+		kgo.SeedBrokers(kw.brokers...),
+		kgo.DefaultProduceTopic(kw.topic),
+	)
+
+	client, err := kgo.NewClient(kOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kafka client: %w", err)
+	}
+	kw.producer = client
+
+	return kw, nil
+}
+
+// registerSchema registers the Avro schema for a record type and caches its ID.
+func (kw *KafkaRecordWriter) registerSchema(ctx context.Context, r Record) (int, error) {
+	if id, ok := kw.schemaIDs[r.TableName()]; ok {
+		return id, nil
+	}
+
+	schema, err := AvroSchema(r)
+	if err != nil {
+		return 0, fmt.Errorf("error generating avro schema for %s: %w", r.TableName(), err)
+	}
+
+	subject := kw.topic + "-" + AvroFullName(r)
+	id, err := kw.registry.Register(ctx, subject, schema)
+	if err != nil {
+		return 0, fmt.Errorf("schema registry unavailable: %w", err)
+	}
+
+	kw.logger.Debug("registered avro schema", "subject", subject, "schema_id", id)
+	kw.schemaIDs[r.TableName()] = id
+	return id, nil
+}
+
+// WriteRecords encodes each Record as Avro and produces the batch to Kafka.
+// The call blocks until all records are acknowledged or an error occurs.
+func (kw *KafkaRecordWriter) WriteRecords(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	kRecords := make([]*kgo.Record, 0, len(records))
+	for i, r := range records {
+		id, err := kw.registerSchema(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		payload, err := EncodeAvro(r)
+		if err != nil {
+			kw.metrics.EncodeErrors.Inc()
+			return fmt.Errorf("error encoding record %d: %w", i, err)
+		}
+
+		kRecords = append(kRecords, &kgo.Record{
+			Topic: kw.topic,
+			Key:   []byte(recordDevicePubkey(r)),
+			Value: encodeConfluentWireFormat(id, payload),
+		})
+	}
+
+	if err := kw.producer.ProduceSync(ctx, kRecords...).FirstErr(); err != nil {
+		kw.metrics.ProduceErrors.Inc()
+		return fmt.Errorf("error producing records: %w", err)
+	}
+
+	kw.metrics.RecordsProduced.Add(float64(len(kRecords)))
+	kw.logger.Debug("produced records to kafka", "count", len(kRecords), "topic", kw.topic)
+	return nil
+}
+
+// Close closes the Kafka producer.
+func (kw *KafkaRecordWriter) Close() error {
+	kw.producer.Close()
+	return nil
+}
+
+// recordDevicePubkey returns the device_pubkey column of a record, or "" if absent.
+func recordDevicePubkey(r Record) string {
+	v, ok := recordColumn(r, "device_pubkey")
+	if !ok {
+		return ""
+	}
+	return v.String()
+}
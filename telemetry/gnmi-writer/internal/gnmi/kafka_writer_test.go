@@ -0,0 +1,381 @@
+package gnmi
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// mockKafkaProducer implements kafkaProducer for testing.
+type mockKafkaProducer struct {
+	records []*kgo.Record
+	err     error
+}
+
+func (m *mockKafkaProducer) ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	var results kgo.ProduceResults
+	for _, r := range rs {
+		if m.err == nil {
+			m.records = append(m.records, r)
+		}
+		results = append(results, kgo.ProduceResult{Record: r, Err: m.err})
+	}
+	return results
+}
+
+func (m *mockKafkaProducer) Close() {}
+
+// mockRegistryServer is a minimal Confluent-compatible schema registry.
+type mockRegistryServer struct {
+	mu       sync.Mutex
+	subjects map[string]int
+	schemas  map[int]string
+}
+
+func newMockRegistryServer(t *testing.T) (*mockRegistryServer, *httptest.Server) {
+	reg := &mockRegistryServer{subjects: map[string]int{}, schemas: map[int]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, "/subjects/") || !strings.HasSuffix(r.URL.Path, "/versions") {
+			http.NotFound(w, r)
+			return
+		}
+		subject := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/subjects/"), "/versions")
+
+		var req struct {
+			Schema string `json:"schema"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		reg.mu.Lock()
+		id, ok := reg.subjects[subject]
+		if ok && reg.schemas[id] != req.Schema {
+			// A new version must pass the registry's default BACKWARD check.
+			if err := checkBackwardCompatible(reg.schemas[id], req.Schema); err != nil {
+				reg.mu.Unlock()
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			ok = false
+		}
+		if !ok {
+			id = len(reg.schemas) + 1
+			reg.subjects[subject] = id
+			reg.schemas[id] = req.Schema
+		}
+		reg.mu.Unlock()
+
+		w.Header().Set("Content-Type", schemaRegistryContentType)
+		_ = json.NewEncoder(w).Encode(map[string]int{"id": id})
+	}))
+	t.Cleanup(srv.Close)
+	return reg, srv
+}
+
+// checkBackwardCompatible reports whether data written with oldSchema can be
+// read with newSchema, as far as record fields go: every field newSchema adds
+// needs a default.
+func checkBackwardCompatible(oldSchema, newSchema string) error {
+	type schema struct {
+		Fields []map[string]json.RawMessage `json:"fields"`
+	}
+	var oldS, newS schema
+	if err := json.Unmarshal([]byte(oldSchema), &oldS); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(newSchema), &newS); err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, f := range oldS.Fields {
+		known[string(f["name"])] = true
+	}
+	for _, f := range newS.Fields {
+		if _, ok := f["default"]; !known[string(f["name"])] && !ok {
+			return fmt.Errorf("new field %s has no default", f["name"])
+		}
+	}
+	return nil
+}
+
+// decodeAvro decodes an Avro payload using the generated schema, for round-trip testing.
+func decodeAvro(t *testing.T, schema string, data []byte) map[string]any {
+	t.Helper()
+
+	var s struct {
+		Fields []struct {
+			Name string          `json:"name"`
+			Type json.RawMessage `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	out := make(map[string]any)
+	for _, f := range s.Fields {
+		typ := strings.Trim(string(f.Type), `"`)
+		if strings.HasPrefix(typ, "{") {
+			typ = "timestamp-micros"
+		}
+		switch typ {
+		case "string":
+			n, sz := binary.Varint(data)
+			data = data[sz:]
+			out[f.Name] = string(data[:n])
+			data = data[n:]
+		case "boolean":
+			out[f.Name] = data[0] == 1
+			data = data[1:]
+		case "int", "long":
+			v, sz := binary.Varint(data)
+			out[f.Name] = v
+			data = data[sz:]
+		case "timestamp-micros":
+			v, sz := binary.Varint(data)
+			out[f.Name] = time.UnixMicro(v).UTC()
+			data = data[sz:]
+		case "double":
+			out[f.Name] = math.Float64frombits(binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		default:
+			t.Fatalf("unexpected avro type %s", typ)
+		}
+	}
+	if len(data) != 0 {
+		t.Fatalf("trailing bytes after decode: %d", len(data))
+	}
+	return out
+}
+
+func TestAvroSchema_AllKnownRecords(t *testing.T) {
+	for _, r := range KnownRecords() {
+		schema, err := AvroSchema(r)
+		if err != nil {
+			t.Fatalf("AvroSchema(%s) error: %v", r.TableName(), err)
+		}
+		if !json.Valid([]byte(schema)) {
+			t.Errorf("AvroSchema(%s) is not valid JSON: %s", r.TableName(), schema)
+		}
+		if !strings.Contains(schema, `"name":"`+r.TableName()+`"`) {
+			t.Errorf("AvroSchema(%s) missing record name: %s", r.TableName(), schema)
+		}
+	}
+}
+
+// legacyAvroSchema returns schema as generated before fields carried
+// defaults, without the drop fields.
+func legacyAvroSchema(t *testing.T, schema string, drop ...string) string {
+	t.Helper()
+	var s map[string]any
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	var fields []any
+	for _, f := range s["fields"].([]any) {
+		field := f.(map[string]any)
+		if slices.Contains(drop, field["name"].(string)) {
+			continue
+		}
+		delete(field, "default")
+		fields = append(fields, field)
+	}
+	s["fields"] = fields
+	out, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	return string(out)
+}
+
+func TestAvroSchema_BackwardCompatibleWithAddedColumns(t *testing.T) {
+	_, srv := newMockRegistryServer(t)
+	registry, err := NewSchemaRegistryClient(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to create registry client: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, r := range KnownRecords() {
+		schema, err := AvroSchema(r)
+		if err != nil {
+			t.Fatalf("AvroSchema(%s) error: %v", r.TableName(), err)
+		}
+		// Subjects registered before the env and clock_skew columns existed
+		// must accept the current schema.
+		subject := r.TableName() + "-value"
+		oldID, err := registry.Register(ctx, subject, legacyAvroSchema(t, schema, "env", "clock_skew"))
+		if err != nil {
+			t.Fatalf("register old %s schema: %v", r.TableName(), err)
+		}
+		newID, err := registry.Register(ctx, subject, schema)
+		if err != nil {
+			t.Errorf("current %s schema is not backward compatible: %v", r.TableName(), err)
+		} else if newID == oldID {
+			t.Errorf("current %s schema registered as the old version", r.TableName())
+		}
+
+		// Without defaults the added columns would be rejected.
+		subject = r.TableName() + "-nodefaults"
+		if _, err := registry.Register(ctx, subject, legacyAvroSchema(t, schema, "env", "clock_skew")); err != nil {
+			t.Fatalf("register old %s schema: %v", r.TableName(), err)
+		}
+		if _, err := registry.Register(ctx, subject, legacyAvroSchema(t, schema)); err == nil {
+			t.Errorf("expected %s schema without defaults to be rejected", r.TableName())
+		}
+	}
+}
+
+func TestKafkaRecordWriter_RoundTrip(t *testing.T) {
+	reg, srv := newMockRegistryServer(t)
+	registry, err := NewSchemaRegistryClient(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to create registry client: %v", err)
+	}
+
+	producer := &mockKafkaProducer{}
+	writer, err := NewKafkaRecordWriter(context.Background(),
+		WithKafkaWriterTopic("gnmi-records"),
+		WithKafkaWriterSchemaRegistry(registry),
+		WithKafkaWriterMetrics(NewKafkaWriterMetrics(prometheus.NewRegistry())),
+		withKafkaProducer(producer),
+	)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	if got, want := len(reg.subjects), len(KnownRecords()); got != want {
+		t.Fatalf("expected %d registered subjects, got %d", want, got)
+	}
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	records := []Record{
+		IsisAdjacencyRecord{
+			Timestamp:      ts,
+			DevicePubkey:   "device1",
+			InterfaceID:    "Ethernet1",
+			Level:          2,
+			SystemID:       "1920.0000.0001",
+			AdjacencyState: "UP",
+			UpTimestamp:    -42,
+		},
+		IsisOverloadBitRecord{
+			Timestamp:       ts,
+			DevicePubkey:    "device2",
+			NetworkInstance: "default",
+			OverloadBit:     true,
+		},
+		TransceiverStateRecord{
+			Timestamp:     ts,
+			DevicePubkey:  "device1",
+			InterfaceName: "Ethernet2",
+			ChannelIndex:  3,
+			InputPower:    -2.5,
+		},
+	}
+
+	if err := writer.WriteRecords(context.Background(), records); err != nil {
+		t.Fatalf("WriteRecords error: %v", err)
+	}
+	if len(producer.records) != len(records) {
+		t.Fatalf("expected %d produced records, got %d", len(records), len(producer.records))
+	}
+
+	for i, kr := range producer.records {
+		if kr.Topic != "gnmi-records" {
+			t.Errorf("record %d: expected topic gnmi-records, got %s", i, kr.Topic)
+		}
+		if kr.Value[0] != confluentMagicByte {
+			t.Fatalf("record %d: missing magic byte", i)
+		}
+		id := int(binary.BigEndian.Uint32(kr.Value[1:5]))
+		subject := "gnmi-records-" + AvroFullName(records[i])
+		if reg.subjects[subject] != id {
+			t.Errorf("record %d: schema id %d does not match subject %s (%d)", i, id, subject, reg.subjects[subject])
+		}
+
+		decoded := decodeAvro(t, reg.schemas[id], kr.Value[5:])
+		if decoded["device_pubkey"] != string(kr.Key) {
+			t.Errorf("record %d: key %q does not match device_pubkey %v", i, kr.Key, decoded["device_pubkey"])
+		}
+		if got := decoded["timestamp"].(time.Time); !got.Equal(ts) {
+			t.Errorf("record %d: timestamp = %v, want %v", i, got, ts)
+		}
+	}
+
+	adj := decodeAvro(t, reg.schemas[reg.subjects["gnmi-records-"+AvroFullName(IsisAdjacencyRecord{})]], producer.records[0].Value[5:])
+	if adj["level"] != int64(2) || adj["system_id"] != "1920.0000.0001" || adj["up_timestamp"] != int64(-42) {
+		t.Errorf("unexpected adjacency round-trip: %+v", adj)
+	}
+	ob := decodeAvro(t, reg.schemas[reg.subjects["gnmi-records-"+AvroFullName(IsisOverloadBitRecord{})]], producer.records[1].Value[5:])
+	if ob["overload_bit"] != true {
+		t.Errorf("unexpected overload bit round-trip: %+v", ob)
+	}
+	xcvr := decodeAvro(t, reg.schemas[reg.subjects["gnmi-records-"+AvroFullName(TransceiverStateRecord{})]], producer.records[2].Value[5:])
+	if xcvr["input_power"] != -2.5 || xcvr["channel_index"] != int64(3) {
+		t.Errorf("unexpected transceiver round-trip: %+v", xcvr)
+	}
+}
+
+func TestKafkaRecordWriter_RegistryUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	registry, err := NewSchemaRegistryClient(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to create registry client: %v", err)
+	}
+
+	_, err = NewKafkaRecordWriter(context.Background(),
+		WithKafkaWriterTopic("gnmi-records"),
+		WithKafkaWriterSchemaRegistry(registry),
+		withKafkaProducer(&mockKafkaProducer{}),
+	)
+	if err == nil {
+		t.Fatal("expected error when registry is unavailable")
+	}
+	if !strings.Contains(err.Error(), "schema registry unavailable") {
+		t.Errorf("expected clear registry error, got: %v", err)
+	}
+}
+
+func TestKafkaRecordWriter_ProduceError(t *testing.T) {
+	_, srv := newMockRegistryServer(t)
+	registry, err := NewSchemaRegistryClient(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to create registry client: %v", err)
+	}
+
+	metrics := NewKafkaWriterMetrics(prometheus.NewRegistry())
+	writer, err := NewKafkaRecordWriter(context.Background(),
+		WithKafkaWriterTopic("gnmi-records"),
+		WithKafkaWriterSchemaRegistry(registry),
+		WithKafkaWriterMetrics(metrics),
+		withKafkaProducer(&mockKafkaProducer{err: errors.New("broker down")}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	err = writer.WriteRecords(context.Background(), []Record{SystemStateRecord{DevicePubkey: "device1"}})
+	if err == nil {
+		t.Fatal("expected produce error")
+	}
+}
@@ -44,6 +44,17 @@ type ProcessorMetrics struct {
 	ProcessingDuration prometheus.Histogram
 	WriteErrors        prometheus.Counter
 	CommitErrors       prometheus.Counter
+	RecordsSampledOut  prometheus.Counter
+
+	ClockSkew          prometheus.Histogram
+	ClockSkewRewritten prometheus.Counter
+	ClockSkewDropped   prometheus.Counter
+
+	DeviceNotificationsDropped *prometheus.CounterVec
+
+	WriteQueueDepth   prometheus.Gauge
+	WriteQueueFull    prometheus.Counter
+	WriteQueueDropped prometheus.Counter
 }
 
 // NewProcessorMetrics creates processor metrics registered with the given registerer.
@@ -76,6 +87,47 @@ func NewProcessorMetrics(reg prometheus.Registerer) *ProcessorMetrics {
 			Name:      "commit_errors_total",
 			Help:      "Total number of Kafka commit errors",
 		}),
+		RecordsSampledOut: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "records_sampled_out_total",
+			Help:      "Total number of records dropped by downsampling",
+		}),
+		ClockSkew: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notification_clock_skew_seconds",
+			Help:      "Absolute difference between notification timestamps and receipt time",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600, 86400},
+		}),
+		ClockSkewRewritten: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notifications_clock_skew_rewritten_total",
+			Help:      "Total number of notifications whose timestamp was replaced with receipt time due to clock skew",
+		}),
+		ClockSkewDropped: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "notifications_clock_skew_dropped_total",
+			Help:      "Total number of notifications dropped due to clock skew",
+		}),
+		DeviceNotificationsDropped: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "device_notifications_dropped_total",
+			Help:      "Total number of notifications dropped under per-device isolation, by device and reason (queue_full or timeout)",
+		}, []string{"device", "reason"}),
+		WriteQueueDepth: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "write_queue_depth",
+			Help:      "Number of extracted batches waiting in the write queue",
+		}),
+		WriteQueueFull: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "write_queue_full_total",
+			Help:      "Total number of batches that found the write queue full",
+		}),
+		WriteQueueDropped: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "write_queue_records_dropped_total",
+			Help:      "Total number of records dropped from a full write queue",
+		}),
 	}
 }
 
@@ -84,6 +136,8 @@ type ClickhouseMetrics struct {
 	InsertDuration prometheus.Histogram
 	InsertErrors   prometheus.Counter
 	RecordsWritten prometheus.Counter
+	ActiveEndpoint *prometheus.GaugeVec
+	Failovers      prometheus.Counter
 }
 
 // NewClickhouseMetrics creates ClickHouse metrics registered with the given registerer.
@@ -109,5 +163,49 @@ func NewClickhouseMetrics(reg prometheus.Registerer) *ClickhouseMetrics {
 			Name:      "records_written_total",
 			Help:      "Total number of records written to ClickHouse",
 		}),
+		ActiveEndpoint: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "clickhouse",
+			Name:      "active_endpoint",
+			Help:      "ClickHouse endpoint currently used for writes (1 = active, 0 = standby)",
+		}, []string{"addr"}),
+		Failovers: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "clickhouse",
+			Name:      "failovers_total",
+			Help:      "Total number of failovers to another ClickHouse endpoint",
+		}),
+	}
+}
+
+// KafkaWriterMetrics holds Prometheus metrics for the Kafka record writer.
+type KafkaWriterMetrics struct {
+	RecordsProduced prometheus.Counter
+	ProduceErrors   prometheus.Counter
+	EncodeErrors    prometheus.Counter
+}
+
+// NewKafkaWriterMetrics creates Kafka writer metrics registered with the given registerer.
+func NewKafkaWriterMetrics(reg prometheus.Registerer) *KafkaWriterMetrics {
+	factory := promauto.With(reg)
+	return &KafkaWriterMetrics{
+		RecordsProduced: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "kafka_writer",
+			Name:      "records_produced_total",
+			Help:      "Total number of records produced to the output Kafka topic",
+		}),
+		ProduceErrors: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "kafka_writer",
+			Name:      "produce_errors_total",
+			Help:      "Total number of Kafka produce errors",
+		}),
+		EncodeErrors: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: "kafka_writer",
+			Name:      "encode_errors_total",
+			Help:      "Total number of record encoding errors",
+		}),
 	}
 }
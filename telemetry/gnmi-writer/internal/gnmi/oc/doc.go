@@ -46,4 +46,13 @@
 //	        }
 //	    }
 //	}
+//
+// # Merging
+//
+// MergeDevices combines Device trees built from separate notifications or
+// subscriptions. Conflicting leaves are an error unless WithSrcWins is set:
+//
+//	if err := oc.MergeDevices(full, partial); err != nil {
+//	    return err
+//	}
 package oc
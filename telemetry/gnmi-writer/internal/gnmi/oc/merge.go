@@ -0,0 +1,51 @@
+package oc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openconfig/ygot/ygot"
+)
+
+// MergeOption configures MergeDevices.
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	srcWins bool
+}
+
+// WithSrcWins makes leaves set in both trees take the value from src instead
+// of failing the merge.
+func WithSrcWins() MergeOption {
+	return func(o *mergeOptions) {
+		o.srcWins = true
+	}
+}
+
+// MergeDevices merges src into dst using ygot merge semantics: containers are
+// merged recursively, list entries with the same key are merged, and leaves set
+// only in src are copied. By default a leaf set to different values in both
+// trees is a conflict and returns an error, leaving dst partially merged; use
+// WithSrcWins to overwrite dst instead.
+func MergeDevices(dst, src *Device, opts ...MergeOption) error {
+	if dst == nil {
+		return errors.New("destination device is nil")
+	}
+	if src == nil {
+		return nil
+	}
+
+	var o mergeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var mergeOpts []ygot.MergeOpt
+	if o.srcWins {
+		mergeOpts = append(mergeOpts, &ygot.MergeOverwriteExistingFields{})
+	}
+	if err := ygot.MergeStructInto(dst, src, mergeOpts...); err != nil {
+		return fmt.Errorf("merging devices: %w", err)
+	}
+	return nil
+}
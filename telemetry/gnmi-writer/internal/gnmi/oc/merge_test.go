@@ -0,0 +1,86 @@
+package oc
+
+import (
+	"testing"
+
+	"github.com/openconfig/ygot/ygot"
+)
+
+func newInterfaceDevice(name string, state *OpenconfigInterfaces_Interfaces_Interface_State) *Device {
+	return &Device{
+		Interfaces: &OpenconfigInterfaces_Interfaces{
+			Interface: map[string]*OpenconfigInterfaces_Interfaces_Interface{
+				name: {Name: ygot.String(name), State: state},
+			},
+		},
+	}
+}
+
+func TestMergeDevices_Disjoint(t *testing.T) {
+	dst := newInterfaceDevice("Ethernet1", &OpenconfigInterfaces_Interfaces_Interface_State{Mtu: ygot.Uint16(9000)})
+	src := &Device{
+		System: &OpenconfigSystem_System{
+			State: &OpenconfigSystem_System_State{Hostname: ygot.String("dz1")},
+		},
+	}
+	src.Interfaces = newInterfaceDevice("Ethernet2", &OpenconfigInterfaces_Interfaces_Interface_State{Mtu: ygot.Uint16(1500)}).Interfaces
+
+	if err := MergeDevices(dst, src); err != nil {
+		t.Fatalf("MergeDevices: %v", err)
+	}
+
+	if got := dst.System.State.Hostname; got == nil || *got != "dz1" {
+		t.Errorf("hostname = %v, want dz1", got)
+	}
+	if len(dst.Interfaces.Interface) != 2 {
+		t.Fatalf("expected 2 interfaces, got %d", len(dst.Interfaces.Interface))
+	}
+	if got := *dst.Interfaces.Interface["Ethernet1"].State.Mtu; got != 9000 {
+		t.Errorf("Ethernet1 mtu = %d, want 9000", got)
+	}
+	if got := *dst.Interfaces.Interface["Ethernet2"].State.Mtu; got != 1500 {
+		t.Errorf("Ethernet2 mtu = %d, want 1500", got)
+	}
+}
+
+func TestMergeDevices_OverlappingListEntry(t *testing.T) {
+	dst := newInterfaceDevice("Ethernet1", &OpenconfigInterfaces_Interfaces_Interface_State{Mtu: ygot.Uint16(9000)})
+	src := newInterfaceDevice("Ethernet1", &OpenconfigInterfaces_Interfaces_Interface_State{Ifindex: ygot.Uint32(7)})
+
+	if err := MergeDevices(dst, src); err != nil {
+		t.Fatalf("MergeDevices: %v", err)
+	}
+
+	state := dst.Interfaces.Interface["Ethernet1"].State
+	if state.Mtu == nil || *state.Mtu != 9000 || state.Ifindex == nil || *state.Ifindex != 7 {
+		t.Errorf("merged state = mtu %v ifindex %v, want mtu 9000 ifindex 7", state.Mtu, state.Ifindex)
+	}
+}
+
+func TestMergeDevices_Conflict(t *testing.T) {
+	newDst := func() *Device {
+		return newInterfaceDevice("Ethernet1", &OpenconfigInterfaces_Interfaces_Interface_State{Mtu: ygot.Uint16(9000)})
+	}
+	src := newInterfaceDevice("Ethernet1", &OpenconfigInterfaces_Interfaces_Interface_State{Mtu: ygot.Uint16(1500)})
+
+	if err := MergeDevices(newDst(), src); err == nil {
+		t.Fatal("expected conflict error")
+	}
+
+	dst := newDst()
+	if err := MergeDevices(dst, src, WithSrcWins()); err != nil {
+		t.Fatalf("MergeDevices with WithSrcWins: %v", err)
+	}
+	if got := *dst.Interfaces.Interface["Ethernet1"].State.Mtu; got != 1500 {
+		t.Errorf("mtu = %d, want 1500 from src", got)
+	}
+}
+
+func TestMergeDevices_Nil(t *testing.T) {
+	if err := MergeDevices(nil, &Device{}); err == nil {
+		t.Error("expected error for nil destination")
+	}
+	if err := MergeDevices(&Device{}, nil); err != nil {
+		t.Errorf("nil src should be a no-op, got %v", err)
+	}
+}
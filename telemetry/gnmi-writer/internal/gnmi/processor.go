@@ -6,15 +6,22 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/ytypes"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/malbeclabs/doublezero/telemetry/gnmi-writer/internal/gnmi/oc"
 )
 
+// samplingFlushTimeout bounds the final write of sampled records on shutdown.
+const samplingFlushTimeout = 10 * time.Second
+
 // Processor orchestrates consuming gNMI notifications and writing records.
 // It maintains a registry of extractors that process notifications based on path patterns.
 type Processor struct {
@@ -25,6 +32,32 @@ type Processor struct {
 	listCache  listSchemaCache // Cached container/list -> schema name mappings
 	logger     *slog.Logger
 	metrics    *ProcessorMetrics
+	sampler    *sampler
+
+	maxClockSkew    time.Duration // 0 disables the clock skew guard
+	clockSkewAction ClockSkewAction
+	now             func() time.Time
+
+	enabledRecordTypes  []string
+	disabledRecordTypes []string
+	skipped             map[string]bool // extractor names filtered out by record type
+
+	deviceWorkers    int // 0 processes each batch sequentially
+	deviceQueueDepth int
+	deviceTimeout    time.Duration
+	deviceShards     []*deviceShard // started on first use
+	deviceStop       chan struct{}
+
+	onUnmarshalError func(recordType, path string, err error)
+
+	recordEnv string
+
+	writeQueueSize   int // 0 writes each batch before consuming the next
+	writeQueuePolicy WriteQueuePolicy
+	queue            chan queuedBatch
+
+	tracer        trace.Tracer
+	outputBackend string
 }
 
 // ProcessorOption configures a Processor.
@@ -76,6 +109,104 @@ func WithAdditionalExtractor(name string, match PathMatcher, extract ExtractFunc
 	}
 }
 
+// WithEnabledRecordTypes restricts processing to extractors producing the given
+// record types (table names). All other extractors are skipped.
+func WithEnabledRecordTypes(recordTypes ...string) ProcessorOption {
+	return func(p *Processor) {
+		p.enabledRecordTypes = append(p.enabledRecordTypes, recordTypes...)
+	}
+}
+
+// WithDisabledRecordTypes skips extractors producing the given record types
+// (table names). It cannot be combined with WithEnabledRecordTypes.
+func WithDisabledRecordTypes(recordTypes ...string) ProcessorOption {
+	return func(p *Processor) {
+		p.disabledRecordTypes = append(p.disabledRecordTypes, recordTypes...)
+	}
+}
+
+// WithUnmarshalErrorHandler calls fn for every update that matched an extractor
+// but could not be unmarshaled into the OpenConfig schema, in addition to the
+// processing error metric.
+func WithUnmarshalErrorHandler(fn func(recordType, path string, err error)) ProcessorOption {
+	return func(p *Processor) {
+		p.onUnmarshalError = fn
+	}
+}
+
+// WithRecordEnv stamps every extracted record with env (e.g. devnet, testnet
+// or mainnet-beta) in its env column, so environments writing to the same
+// cluster can be told apart in queries.
+func WithRecordEnv(env string) ProcessorOption {
+	return func(p *Processor) {
+		p.recordEnv = env
+	}
+}
+
+// WithDeviceIsolation extracts notifications on workers long-lived
+// goroutines. Each device is assigned to one worker by hash and gets its own
+// queue of up to queueDepth notifications, served in turn with the other
+// devices on that worker, so a device flooding large or malformed
+// notifications mostly delays its own records. Notifications arriving while a
+// device's queue is full are dropped and counted. A batch waits at most
+// timeout for a device's records, then drops and counts the rest of that
+// device's notifications. The unmarshal error handler may then be called
+// concurrently.
+func WithDeviceIsolation(workers, queueDepth int, timeout time.Duration) ProcessorOption {
+	return func(p *Processor) {
+		p.deviceWorkers = workers
+		p.deviceQueueDepth = queueDepth
+		p.deviceTimeout = timeout
+	}
+}
+
+// ClockSkewAction is what the processor does with a notification whose
+// timestamp is further than the configured maximum from receipt time.
+type ClockSkewAction string
+
+const (
+	// ClockSkewRewrite replaces the notification timestamp with receipt time
+	// and sets the clock_skew column on its records.
+	ClockSkewRewrite ClockSkewAction = "rewrite"
+	// ClockSkewDrop drops the notification.
+	ClockSkewDrop ClockSkewAction = "drop"
+)
+
+// WithClockSkewGuard guards against devices with bad clocks: notifications
+// whose timestamp is more than maxSkew ahead of or behind receipt time are
+// rewritten to receipt time (and tagged clock_skew) or dropped, according to
+// action.
+func WithClockSkewGuard(maxSkew time.Duration, action ClockSkewAction) ProcessorOption {
+	return func(p *Processor) {
+		p.maxClockSkew = maxSkew
+		p.clockSkewAction = action
+	}
+}
+
+// WithSampling downsamples records of the given type (table name), dropping records
+// for the same (device, key) that arrive sooner than minInterval after the last
+// written sample. The most recent dropped sample per key is written on shutdown.
+func WithSampling(recordType string, minInterval time.Duration) ProcessorOption {
+	return func(p *Processor) {
+		if p.sampler == nil {
+			p.sampler = newSampler(defaultSamplingMaxKeys)
+		}
+		p.sampler.policies[recordType] = minInterval
+	}
+}
+
+// WithSamplingMaxKeys bounds the number of (device, key) entries tracked for sampling.
+// Least recently seen keys are evicted first.
+func WithSamplingMaxKeys(maxKeys int) ProcessorOption {
+	return func(p *Processor) {
+		if p.sampler == nil {
+			p.sampler = newSampler(maxKeys)
+			return
+		}
+		p.sampler.maxKeys = maxKeys
+	}
+}
+
 // NewProcessor creates a new Processor with the given options.
 // By default, it uses DefaultExtractors for processing notifications.
 func NewProcessor(opts ...ProcessorOption) (*Processor, error) {
@@ -89,6 +220,8 @@ func NewProcessor(opts ...ProcessorOption) (*Processor, error) {
 		schema:     schema,
 		listCache:  buildListSchemaCache(schema), // Build cache once at startup for O(1) lookups
 		metrics:    NewProcessorMetrics(nil),     // Always set, unregistered by default
+		now:        time.Now,
+		tracer:     noop.NewTracerProvider().Tracer(tracerName),
 	}
 
 	for _, opt := range opts {
@@ -99,9 +232,91 @@ func NewProcessor(opts ...ProcessorOption) (*Processor, error) {
 		p.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
 	}
 
+	if err := p.filterExtractors(); err != nil {
+		return nil, err
+	}
+
+	if p.deviceWorkers < 0 {
+		return nil, fmt.Errorf("device workers must not be negative: %d", p.deviceWorkers)
+	}
+	if p.deviceWorkers > 0 && p.deviceQueueDepth <= 0 {
+		return nil, fmt.Errorf("device queue depth must be positive: %d", p.deviceQueueDepth)
+	}
+	if p.deviceWorkers > 0 && p.deviceTimeout <= 0 {
+		return nil, fmt.Errorf("device timeout must be positive: %s", p.deviceTimeout)
+	}
+
+	if p.maxClockSkew < 0 {
+		return nil, fmt.Errorf("max clock skew must not be negative: %s", p.maxClockSkew)
+	}
+	if p.maxClockSkew > 0 && p.clockSkewAction != ClockSkewRewrite && p.clockSkewAction != ClockSkewDrop {
+		return nil, fmt.Errorf("invalid clock skew action %q (must be %s or %s)", p.clockSkewAction, ClockSkewRewrite, ClockSkewDrop)
+	}
+
+	if p.writeQueueSize < 0 {
+		return nil, fmt.Errorf("write queue size must not be negative: %d", p.writeQueueSize)
+	}
+	if p.writeQueueSize > 0 && p.writeQueuePolicy != WriteQueueBlock && p.writeQueuePolicy != WriteQueueDropOldest {
+		return nil, fmt.Errorf("invalid write queue policy %q (must be %s or %s)", p.writeQueuePolicy, WriteQueueBlock, WriteQueueDropOldest)
+	}
+
 	return p, nil
 }
 
+// filterExtractors applies the enabled/disabled record type lists to the
+// configured extractors, which are named after the record type they produce.
+// Filtered-out extractors stay in the list so they still claim their paths
+// (first match wins); matching updates are dropped without unmarshaling.
+func (p *Processor) filterExtractors() error {
+	if len(p.enabledRecordTypes) == 0 && len(p.disabledRecordTypes) == 0 {
+		return nil
+	}
+	if len(p.enabledRecordTypes) > 0 && len(p.disabledRecordTypes) > 0 {
+		return errors.New("enabled and disabled record types are mutually exclusive")
+	}
+
+	allow := len(p.enabledRecordTypes) > 0
+	requested := p.disabledRecordTypes
+	if allow {
+		requested = p.enabledRecordTypes
+	}
+
+	known := make(map[string]bool, len(p.extractors))
+	for _, e := range p.extractors {
+		known[e.Name] = true
+	}
+	selected := make(map[string]bool, len(requested))
+	for _, recordType := range requested {
+		if !known[recordType] {
+			return fmt.Errorf("unknown record type: %s", recordType)
+		}
+		selected[recordType] = true
+	}
+
+	p.skipped = make(map[string]bool)
+	for _, e := range p.extractors {
+		if selected[e.Name] != allow {
+			p.skipped[e.Name] = true
+		}
+	}
+	if len(p.skipped) == len(known) {
+		return errors.New("all record types are disabled")
+	}
+	return nil
+}
+
+// RecordTypes returns the names of the extractors the processor runs, in
+// matching order, excluding those filtered out by record type.
+func (p *Processor) RecordTypes() []string {
+	names := make([]string, 0, len(p.extractors))
+	for _, e := range p.extractors {
+		if !p.skipped[e.Name] {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
 // Run starts the processor and processes notifications until the context is cancelled.
 func (p *Processor) Run(ctx context.Context) error {
 	if p.consumer == nil {
@@ -115,8 +330,15 @@ func (p *Processor) Run(ctx context.Context) error {
 	}
 
 	defer p.consumer.Close()
+	defer p.flushSampled()
+	if p.writeQueueSize > 0 {
+		defer p.startWriteQueue()()
+	}
+	if p.deviceWorkers > 0 {
+		defer p.stopDeviceWorkers()
+	}
 
-	p.logger.Info("starting gNMI processor", "extractors", len(p.extractors))
+	p.logger.Info("starting gNMI processor", "extractors", len(p.extractors), "record_types", p.RecordTypes(), "write_queue_size", p.writeQueueSize)
 
 	for {
 		select {
@@ -124,6 +346,7 @@ func (p *Processor) Run(ctx context.Context) error {
 			p.logger.Info("processor shutting down")
 			return nil
 		default:
+			consumeStart := time.Now()
 			notifications, err := p.consumer.Consume(ctx)
 			if err != nil {
 				if errors.Is(err, ErrClientClosed) {
@@ -138,47 +361,148 @@ func (p *Processor) Run(ctx context.Context) error {
 				continue
 			}
 
-			timer := prometheus.NewTimer(p.metrics.ProcessingDuration)
-			records := p.processNotifications(ctx, notifications)
-			timer.ObserveDuration()
+			p.processBatch(ctx, consumeStart, notifications)
+		}
+	}
+}
 
-			if len(records) == 0 {
-				continue
-			}
+// processBatch extracts one consumed batch of notifications, then writes and
+// commits it or, with a write queue, hands it to the writer goroutine.
+// consumeStart is when the Consume call that returned the batch began, so the
+// batch span covers the wait for it.
+func (p *Processor) processBatch(ctx context.Context, consumeStart time.Time, notifications []*gpb.Notification) {
+	ctx, span := p.tracer.Start(ctx, spanBatch,
+		trace.WithTimestamp(consumeStart),
+		trace.WithAttributes(
+			attrNotifications.Int(len(notifications)),
+			attrOutput.String(p.outputBackend),
+		))
+	defer span.End()
+	_, consumeSpan := p.tracer.Start(ctx, spanConsume, trace.WithTimestamp(consumeStart))
+	consumeSpan.End()
+
+	timer := prometheus.NewTimer(p.metrics.ProcessingDuration)
+	records := p.processNotifications(ctx, notifications)
+	timer.ObserveDuration()
+
+	commitSampling := func() {}
+	if p.sampler != nil {
+		var dropped int
+		records, dropped, commitSampling = p.sampler.filter(records)
+		p.metrics.RecordsSampledOut.Add(float64(dropped))
+	}
+	span.SetAttributes(attrRecords.Int(len(records)))
 
-			if err := p.writer.WriteRecords(ctx, records); err != nil {
-				p.logger.Error("error writing records", "error", err)
-				p.metrics.WriteErrors.Inc()
-
-				// For non-retryable errors (e.g., table doesn't exist), commit offsets
-				// to avoid infinite loop of reprocessing the same messages
-				if !IsRetryableClickhouseError(err) {
-					p.logger.Warn("non-retryable error, committing offsets to skip messages",
-						"error", err,
-						"records_dropped", len(records))
-					if commitErr := p.consumer.Commit(ctx); commitErr != nil {
-						p.logger.Error("error committing offsets", "error", commitErr)
-					}
-				}
-				continue
-			}
+	if len(records) == 0 {
+		commitSampling()
+		return
+	}
 
-			if err := p.consumer.Commit(ctx); err != nil {
-				p.logger.Error("error committing offsets", "error", err)
-				p.metrics.CommitErrors.Inc()
-				continue
+	if p.queue != nil {
+		p.enqueueBatch(ctx, queuedBatch{spanCtx: span.SpanContext(), records: records, commitSampling: commitSampling})
+		return
+	}
+	p.writeBatch(ctx, records, commitSampling)
+}
+
+// writeBatch writes the records extracted from one batch and commits the
+// consumed offsets, recording failures on the batch span in ctx.
+func (p *Processor) writeBatch(ctx context.Context, records []Record, commitSampling func()) {
+	span := trace.SpanFromContext(ctx)
+
+	if err := p.writeRecords(ctx, records); err != nil {
+		p.logger.Error("error writing records", "error", err)
+		p.metrics.WriteErrors.Inc()
+		span.SetStatus(codes.Error, "write failed")
+
+		// For non-retryable errors (e.g., table doesn't exist), commit offsets
+		// to avoid infinite loop of reprocessing the same messages
+		if !IsRetryableClickhouseError(err) {
+			p.logger.Warn("non-retryable error, committing offsets to skip messages",
+				"error", err,
+				"records_dropped", len(records))
+			commitSampling()
+			if commitErr := p.consumer.Commit(ctx); commitErr != nil {
+				p.logger.Error("error committing offsets", "error", commitErr)
 			}
+		}
+		return
+	}
+	commitSampling()
+
+	if err := p.consumer.Commit(ctx); err != nil {
+		p.logger.Error("error committing offsets", "error", err)
+		p.metrics.CommitErrors.Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit failed")
+		return
+	}
 
-			p.metrics.RecordsProcessed.Add(float64(len(records)))
+	p.metrics.RecordsProcessed.Add(float64(len(records)))
 
-			p.logger.Debug("processed notifications", "count", len(records))
-		}
+	p.logger.Debug("processed notifications", "count", len(records))
+}
+
+// writeRecords writes records to the configured writer under a write span.
+func (p *Processor) writeRecords(ctx context.Context, records []Record) error {
+	ctx, span := p.tracer.Start(ctx, spanWrite, trace.WithAttributes(
+		attrOutput.String(p.outputBackend),
+		attrRecords.Int(len(records)),
+	))
+	defer span.End()
+
+	err := p.writer.WriteRecords(ctx, records)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "write failed")
+	}
+	return err
+}
+
+// flushSampled writes the most recent sampled-out record per key so the latest
+// value is not lost on shutdown.
+func (p *Processor) flushSampled() {
+	if p.sampler == nil {
+		return
+	}
+	records := p.sampler.flush()
+	if len(records) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), samplingFlushTimeout)
+	defer cancel()
+
+	if err := p.writer.WriteRecords(ctx, records); err != nil {
+		p.logger.Error("error flushing sampled records", "error", err, "count", len(records))
+		p.metrics.WriteErrors.Inc()
+		return
 	}
+	p.metrics.RecordsProcessed.Add(float64(len(records)))
+	p.logger.Info("flushed sampled records", "count", len(records))
 }
 
 // processNotifications converts gNMI notifications to Records using registered extractors.
 func (p *Processor) processNotifications(ctx context.Context, notifications []*gpb.Notification) []Record {
 	var records []Record
+	if p.deviceWorkers > 0 {
+		records = p.extractIsolated(ctx, notifications)
+	} else {
+		records = p.extractRecords(ctx, notifications)
+	}
+
+	// Aggregate records that need deduplication.
+	// gNMI sends individual updates for each leaf value, so records with the same
+	// key need to be merged into a single row.
+	records = AggregateTransceiverState(records)
+	records = AggregateTransceiverThresholds(records)
+
+	return records
+}
+
+// extractRecords runs the registered extractors over notifications in order.
+func (p *Processor) extractRecords(ctx context.Context, notifications []*gpb.Notification) []Record {
+	var records []Record
 
 	for _, n := range notifications {
 		// Check for context cancellation to allow early exit during large batches
@@ -188,48 +512,127 @@ func (p *Processor) processNotifications(ctx context.Context, notifications []*g
 		default:
 		}
 
-		meta := Metadata{
-			DevicePubkey: n.GetPrefix().GetTarget(),
-			Timestamp:    time.Unix(0, n.GetTimestamp()),
-		}
+		records = append(records, p.extractNotification(ctx, n)...)
+	}
 
-		for _, update := range n.GetUpdate() {
-			updatePath := update.GetPath()
+	return records
+}
 
-			// Find matching extractors
-			for _, ext := range p.extractors {
-				if !ext.Match(updatePath) {
-					continue
-				}
+// extractNotification runs the registered extractors over the updates of a
+// single notification under a notification span.
+func (p *Processor) extractNotification(ctx context.Context, n *gpb.Notification) []Record {
+	meta := Metadata{
+		DevicePubkey: n.GetPrefix().GetTarget(),
+		Env:          p.recordEnv,
+		Timestamp:    time.Unix(0, n.GetTimestamp()),
+	}
 
-				// Unmarshal the notification into an oc.Device
-				device, err := p.unmarshalNotification(n, update)
-				if err != nil {
-					p.logger.Debug("error unmarshaling notification",
-						"error", err,
-						"extractor", ext.Name,
-						"path", pathToString(updatePath))
-					p.metrics.ProcessingErrors.Inc()
-					continue
-				}
+	ctx, span := p.tracer.Start(ctx, spanNotification, trace.WithAttributes(
+		attrDevice.String(meta.DevicePubkey),
+		attrUpdates.Int(len(n.GetUpdate())),
+	))
+	defer span.End()
 
-				// Extract records
-				extractedRecords := ext.Extract(device, meta)
-				records = append(records, extractedRecords...)
-				break // Only one extractor per update
+	if !p.checkClockSkew(&meta) {
+		span.SetAttributes(attrDropped.String("clock_skew"))
+		return nil
+	}
+
+	var records []Record
+	var recordTypes []string
+	for _, update := range n.GetUpdate() {
+		updatePath := update.GetPath()
+
+		// Find matching extractors
+		for _, ext := range p.extractors {
+			if !ext.Match(updatePath) {
+				continue
+			}
+			if p.skipped[ext.Name] {
+				break // Record type disabled; don't fall through to a less specific extractor
+			}
+
+			extractedRecords, ok := p.extractUpdate(ctx, n, update, ext, meta)
+			if !ok {
+				continue
 			}
+			records = append(records, extractedRecords...)
+			if !slices.Contains(recordTypes, ext.Name) {
+				recordTypes = append(recordTypes, ext.Name)
+			}
+			break // Only one extractor per update
 		}
 	}
 
-	// Aggregate records that need deduplication.
-	// gNMI sends individual updates for each leaf value, so records with the same
-	// key need to be merged into a single row.
-	records = AggregateTransceiverState(records)
-	records = AggregateTransceiverThresholds(records)
-
+	span.SetAttributes(
+		attrRecordTypes.StringSlice(recordTypes),
+		attrRecords.Int(len(records)),
+	)
 	return records
 }
 
+// extractUpdate unmarshals one update and runs ext over it, each step under
+// its own span. It returns false if the update could not be unmarshaled.
+func (p *Processor) extractUpdate(ctx context.Context, n *gpb.Notification, update *gpb.Update, ext ExtractorDef, meta Metadata) ([]Record, bool) {
+	// Unmarshal the notification into an oc.Device
+	_, span := p.tracer.Start(ctx, spanUnmarshal, trace.WithAttributes(attrRecordType.String(ext.Name)))
+	device, err := p.unmarshalNotification(n, update)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "unmarshal failed")
+		span.End()
+
+		path := pathToString(update.GetPath())
+		p.logger.Debug("error unmarshaling notification",
+			"error", err,
+			"extractor", ext.Name,
+			"path", path)
+		p.metrics.ProcessingErrors.Inc()
+		if p.onUnmarshalError != nil {
+			p.onUnmarshalError(ext.Name, path, err)
+		}
+		return nil, false
+	}
+	span.End()
+
+	// Extract records
+	_, span = p.tracer.Start(ctx, spanExtract, trace.WithAttributes(attrRecordType.String(ext.Name)))
+	records := ext.Extract(device, meta)
+	span.SetAttributes(attrRecords.Int(len(records)))
+	span.End()
+	return records, true
+}
+
+// checkClockSkew records the skew between the notification timestamp and
+// receipt time and applies the clock skew guard. It returns false if the
+// notification should be dropped.
+func (p *Processor) checkClockSkew(meta *Metadata) bool {
+	receipt := p.now()
+	skew := meta.Timestamp.Sub(receipt)
+	if skew < 0 {
+		skew = -skew
+	}
+	p.metrics.ClockSkew.Observe(skew.Seconds())
+
+	if p.maxClockSkew == 0 || skew <= p.maxClockSkew {
+		return true
+	}
+
+	p.logger.Debug("notification clock skew exceeds maximum",
+		"device", meta.DevicePubkey,
+		"timestamp", meta.Timestamp,
+		"skew", meta.Timestamp.Sub(receipt),
+		"action", p.clockSkewAction)
+	if p.clockSkewAction == ClockSkewDrop {
+		p.metrics.ClockSkewDropped.Inc()
+		return false
+	}
+	p.metrics.ClockSkewRewritten.Inc()
+	meta.Timestamp = receipt
+	meta.ClockSkew = true
+	return true
+}
+
 // ProcessNotifications is exported for testing - converts gNMI notifications to Records.
 func (p *Processor) ProcessNotifications(ctx context.Context, notifications []*gpb.Notification) []Record {
 	return p.processNotifications(ctx, notifications)
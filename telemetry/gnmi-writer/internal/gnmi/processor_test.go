@@ -6,11 +6,14 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
@@ -317,6 +320,304 @@ func TestProcessor_DefaultExtractors(t *testing.T) {
 	}
 }
 
+func TestProcessor_EnabledRecordTypes(t *testing.T) {
+	processor, err := NewProcessor(
+		WithProcessorMetrics(newTestMetrics()),
+		WithEnabledRecordTypes("system_state"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	if got := processor.RecordTypes(); len(got) != 1 || got[0] != "system_state" {
+		t.Fatalf("expected only system_state enabled, got %v", got)
+	}
+
+	isisResp := loadGoldenPrototext(t, "isis_adjacency.prototext")
+	systemResp := loadGoldenPrototext(t, "system_hostname.prototext")
+	records := processor.ProcessNotifications(context.Background(), []*gpb.Notification{isisResp.GetUpdate(), systemResp.GetUpdate()})
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if _, ok := records[0].(SystemStateRecord); !ok {
+		t.Errorf("expected SystemStateRecord, got %T", records[0])
+	}
+}
+
+func TestProcessor_DisabledRecordTypes(t *testing.T) {
+	processor, err := NewProcessor(
+		WithProcessorMetrics(newTestMetrics()),
+		WithDisabledRecordTypes("system_state"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+
+	isisResp := loadGoldenPrototext(t, "isis_adjacency.prototext")
+	systemResp := loadGoldenPrototext(t, "system_hostname.prototext")
+	records := processor.ProcessNotifications(context.Background(), []*gpb.Notification{isisResp.GetUpdate(), systemResp.GetUpdate()})
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	for _, r := range records {
+		if _, ok := r.(IsisAdjacencyRecord); !ok {
+			t.Errorf("expected only IsisAdjacencyRecord, got %T", r)
+		}
+	}
+}
+
+func TestProcessor_DisabledRecordTypeClaimsPath(t *testing.T) {
+	// A disabled extractor still claims its paths, so they don't fall through
+	// to a less specific extractor further down the list.
+	processor, err := NewProcessor(
+		WithProcessorMetrics(newTestMetrics()),
+		WithExtractors([]ExtractorDef{
+			{"system_state", PathContains("system", "state"), extractSystemState},
+			{"catch_all", func(*gpb.Path) bool { return true }, extractSystemState},
+		}),
+		WithDisabledRecordTypes("system_state"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+
+	systemResp := loadGoldenPrototext(t, "system_hostname.prototext")
+	records := processor.ProcessNotifications(context.Background(), []*gpb.Notification{systemResp.GetUpdate()})
+	if len(records) != 0 {
+		t.Fatalf("expected no records, got %d", len(records))
+	}
+}
+
+func TestProcessor_RecordTypeFilterValidation(t *testing.T) {
+	var allTypes []string
+	for _, r := range KnownRecords() {
+		allTypes = append(allTypes, r.TableName())
+	}
+
+	tests := []struct {
+		name string
+		opts []ProcessorOption
+	}{
+		{"enabled and disabled", []ProcessorOption{WithEnabledRecordTypes("system_state"), WithDisabledRecordTypes("bgp_neighbors")}},
+		{"unknown enabled", []ProcessorOption{WithEnabledRecordTypes("nope")}},
+		{"unknown disabled", []ProcessorOption{WithDisabledRecordTypes("nope")}},
+		{"all disabled", []ProcessorOption{WithDisabledRecordTypes(allTypes...)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]ProcessorOption{WithProcessorMetrics(newTestMetrics())}, tt.opts...)
+			if _, err := NewProcessor(opts...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestProcessor_ClockSkewGuard(t *testing.T) {
+	notification := loadGoldenPrototext(t, "system_hostname.prototext").GetUpdate()
+	deviceTime := time.Unix(0, notification.GetTimestamp())
+
+	tests := []struct {
+		name          string
+		receipt       time.Time
+		action        ClockSkewAction
+		wantRecords   int
+		wantTimestamp time.Time
+		wantClockSkew bool
+		wantRewritten float64
+		wantDropped   float64
+	}{
+		{"within threshold", deviceTime.Add(30 * time.Second), ClockSkewDrop, 1, deviceTime, false, 0, 0},
+		{"future skew rewritten", deviceTime.Add(-time.Hour), ClockSkewRewrite, 1, deviceTime.Add(-time.Hour), true, 1, 0},
+		{"past skew rewritten", deviceTime.Add(time.Hour), ClockSkewRewrite, 1, deviceTime.Add(time.Hour), true, 1, 0},
+		{"future skew dropped", deviceTime.Add(-time.Hour), ClockSkewDrop, 0, time.Time{}, false, 0, 1},
+		{"past skew dropped", deviceTime.Add(time.Hour), ClockSkewDrop, 0, time.Time{}, false, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := newTestMetrics()
+			processor, err := NewProcessor(
+				WithProcessorMetrics(metrics),
+				WithClockSkewGuard(time.Minute, tt.action),
+			)
+			if err != nil {
+				t.Fatalf("failed to create processor: %v", err)
+			}
+			processor.now = func() time.Time { return tt.receipt }
+
+			records := processor.ProcessNotifications(context.Background(), []*gpb.Notification{notification})
+			if len(records) != tt.wantRecords {
+				t.Fatalf("expected %d records, got %d", tt.wantRecords, len(records))
+			}
+			if tt.wantRecords > 0 {
+				rec, ok := records[0].(SystemStateRecord)
+				if !ok {
+					t.Fatalf("expected SystemStateRecord, got %T", records[0])
+				}
+				if !rec.Timestamp.Equal(tt.wantTimestamp) {
+					t.Errorf("timestamp = %s, want %s", rec.Timestamp, tt.wantTimestamp)
+				}
+				if rec.ClockSkew != tt.wantClockSkew {
+					t.Errorf("clock_skew = %v, want %v", rec.ClockSkew, tt.wantClockSkew)
+				}
+			}
+			if got := metrics.ClockSkewRewritten.(*testCounter).val; got != tt.wantRewritten {
+				t.Errorf("rewritten = %v, want %v", got, tt.wantRewritten)
+			}
+			if got := metrics.ClockSkewDropped.(*testCounter).val; got != tt.wantDropped {
+				t.Errorf("dropped = %v, want %v", got, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestProcessor_ClockSkewGuardValidation(t *testing.T) {
+	if _, err := NewProcessor(WithClockSkewGuard(time.Minute, "ignore")); err == nil {
+		t.Error("expected error for invalid action")
+	}
+	if _, err := NewProcessor(WithClockSkewGuard(-time.Minute, ClockSkewDrop)); err == nil {
+		t.Error("expected error for negative max skew")
+	}
+}
+
+func TestProcessor_DeviceIsolation(t *testing.T) {
+	base := loadGoldenPrototext(t, "system_hostname.prototext").GetUpdate()
+	forDevice := func(device string) *gpb.Notification {
+		n := proto.Clone(base).(*gpb.Notification)
+		n.Prefix.Target = device
+		return n
+	}
+
+	// The slow device's extraction hangs until released.
+	stuck := make(chan struct{}, 1)
+	release := make(chan struct{})
+	extractors := []ExtractorDef{{
+		Name:  "system_state",
+		Match: PathContains("system", "state"),
+		Extract: func(device *oc.Device, meta Metadata) []Record {
+			if meta.DevicePubkey == "slow" {
+				select {
+				case stuck <- struct{}{}:
+				default:
+				}
+				<-release
+			}
+			return extractSystemState(device, meta)
+		},
+	}}
+
+	metrics := newTestMetrics()
+	processor, err := NewProcessor(
+		WithProcessorMetrics(metrics),
+		WithExtractors(extractors),
+		WithDeviceIsolation(4, 2, 200*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	processor.startDeviceWorkers()
+	t.Cleanup(processor.stopDeviceWorkers)
+	for _, device := range []string{"quiet-1", "quiet-3"} {
+		if processor.deviceShard(device) == processor.deviceShard("slow") {
+			t.Fatalf("%s shares a worker with slow", device)
+		}
+	}
+
+	countByDevice := func(records []Record) (map[string]int, []string) {
+		t.Helper()
+		perDevice := make(map[string]int)
+		var order []string
+		for _, r := range records {
+			rec, ok := r.(SystemStateRecord)
+			if !ok {
+				t.Fatalf("expected SystemStateRecord, got %T", r)
+			}
+			if perDevice[rec.DevicePubkey] == 0 {
+				order = append(order, rec.DevicePubkey)
+			}
+			perDevice[rec.DevicePubkey]++
+		}
+		return perDevice, order
+	}
+	dropped := func(device, reason string) float64 {
+		return testutil.ToFloat64(metrics.DeviceNotificationsDropped.WithLabelValues(device, reason))
+	}
+
+	// A hanging device costs the batch the timeout, not the hang, and the
+	// other devices' records are still returned in order.
+	records := processor.ProcessNotifications(context.Background(), []*gpb.Notification{
+		forDevice("quiet-1"), forDevice("slow"), forDevice("quiet-3"), forDevice("quiet-1"),
+	})
+	perDevice, order := countByDevice(records)
+	if perDevice["slow"] != 0 || perDevice["quiet-1"] != 2 || perDevice["quiet-3"] != 1 {
+		t.Errorf("records per device = %v, want quiet-1:2 quiet-3:1", perDevice)
+	}
+	if !slices.Equal(order, []string{"quiet-1", "quiet-3"}) {
+		t.Errorf("device order = %v, want [quiet-1 quiet-3]", order)
+	}
+	if got := dropped("slow", deviceDropTimeout); got != 1 {
+		t.Errorf("slow timed out = %v, want 1", got)
+	}
+	<-stuck
+
+	// While the slow worker is stuck its queue fills, and the excess is
+	// dropped without waiting for it.
+	var batch []*gpb.Notification
+	for range 5 {
+		batch = append(batch, forDevice("slow"))
+	}
+	batch = append(batch, forDevice("quiet-1"))
+	records = processor.ProcessNotifications(context.Background(), batch)
+	if perDevice, _ := countByDevice(records); perDevice["slow"] != 0 || perDevice["quiet-1"] != 1 {
+		t.Errorf("records per device = %v, want quiet-1:1", perDevice)
+	}
+	if got := dropped("slow", deviceDropQueueFull); got != 3 {
+		t.Errorf("slow queue full = %v, want 3", got)
+	}
+	if got := dropped("slow", deviceDropTimeout); got != 3 {
+		t.Errorf("slow timed out = %v, want 3", got)
+	}
+	if got := dropped("quiet-1", deviceDropQueueFull) + dropped("quiet-1", deviceDropTimeout); got != 0 {
+		t.Errorf("quiet-1 dropped = %v, want 0", got)
+	}
+
+	// Once released, the slow device skips notifications of abandoned
+	// batches and catches up.
+	close(release)
+	for processor.deviceShard("slow").queued("slow") > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	records = processor.ProcessNotifications(context.Background(), []*gpb.Notification{forDevice("slow")})
+	if perDevice, _ := countByDevice(records); perDevice["slow"] != 1 {
+		t.Errorf("records per device = %v, want slow:1", perDevice)
+	}
+
+	// Devices with nothing queued hold no per-device state.
+	for i, shard := range processor.deviceShards {
+		shard.mu.Lock()
+		if len(shard.queues) != 0 || len(shard.order) != 0 {
+			t.Errorf("shard %d still tracks %d devices", i, len(shard.queues))
+		}
+		shard.mu.Unlock()
+	}
+}
+
+func TestProcessor_DeviceIsolationValidation(t *testing.T) {
+	if _, err := NewProcessor(WithDeviceIsolation(-1, 10, time.Second)); err == nil {
+		t.Error("expected error for negative workers")
+	}
+	if _, err := NewProcessor(WithDeviceIsolation(4, 0, time.Second)); err == nil {
+		t.Error("expected error for zero queue depth")
+	}
+	if _, err := NewProcessor(WithDeviceIsolation(4, 10, 0)); err == nil {
+		t.Error("expected error for zero timeout")
+	}
+	if _, err := NewProcessor(WithDeviceIsolation(0, 0, 0)); err != nil {
+		t.Errorf("expected isolation disabled without error, got %v", err)
+	}
+}
+
 func TestProcessor_BinaryRoundTrip(t *testing.T) {
 	// Test that binary protobuf serialization works correctly for ISIS data
 	resp := loadGoldenPrototext(t, "isis_adjacency.prototext")
@@ -461,6 +762,114 @@ func TestRecordTableName(t *testing.T) {
 	}
 }
 
+func TestProcessor_RecordEnv(t *testing.T) {
+	files := []string{
+		"bgp_neighbors.prototext",
+		"interfaces.prototext",
+		"interfaces_ifindex.prototext",
+		"isis_adjacency.prototext",
+		"isis_global_state.prototext",
+		"isis_overload_bit.prototext",
+		"system_hostname.prototext",
+		"transceiver_state.prototext",
+		"transceiver_thresholds.prototext",
+	}
+	var notifications []*gpb.Notification
+	for _, file := range files {
+		notifications = append(notifications, loadGoldenPrototext(t, file).GetUpdate())
+	}
+
+	// recordEnvs returns the env column of each record, keyed by table.
+	recordEnvs := func(t *testing.T, records []Record) map[string][]any {
+		t.Helper()
+		envs := make(map[string][]any)
+		for _, r := range records {
+			values, err := getStructValues(r, []string{"env"})
+			if err != nil {
+				t.Fatalf("%T has no env column: %v", r, err)
+			}
+			envs[r.TableName()] = append(envs[r.TableName()], values[0])
+		}
+		return envs
+	}
+
+	t.Run("stamped on every record type", func(t *testing.T) {
+		processor, err := NewProcessor(
+			WithProcessorMetrics(newTestMetrics()),
+			WithRecordEnv("testnet"),
+		)
+		if err != nil {
+			t.Fatalf("failed to create processor: %v", err)
+		}
+
+		envs := recordEnvs(t, processor.ProcessNotifications(context.Background(), notifications))
+		for _, known := range KnownRecords() {
+			table := known.TableName()
+			if len(envs[table]) == 0 {
+				t.Errorf("no %s records extracted", table)
+			}
+			for _, env := range envs[table] {
+				if env != "testnet" {
+					t.Errorf("%s record env = %v, want testnet", table, env)
+				}
+			}
+		}
+	})
+
+	t.Run("empty by default", func(t *testing.T) {
+		processor, err := NewProcessor(WithProcessorMetrics(newTestMetrics()))
+		if err != nil {
+			t.Fatalf("failed to create processor: %v", err)
+		}
+
+		for table, values := range recordEnvs(t, processor.ProcessNotifications(context.Background(), notifications)) {
+			for _, env := range values {
+				if env != "" {
+					t.Errorf("%s record env = %v, want empty", table, env)
+				}
+			}
+		}
+	})
+}
+
+func TestKnownRecords_HaveEnvColumn(t *testing.T) {
+	for _, r := range KnownRecords() {
+		columns, err := getStructColumns(r)
+		if err != nil {
+			t.Fatalf("%T: %v", r, err)
+		}
+		if !slices.Contains(columns, "env") {
+			t.Errorf("%s has no env column", r.TableName())
+		}
+		schema, err := AvroSchema(r)
+		if err != nil {
+			t.Fatalf("%T: %v", r, err)
+		}
+		if !strings.Contains(schema, `{"name":"env","type":"string","default":""}`) {
+			t.Errorf("%s avro schema has no env field: %s", r.TableName(), schema)
+		}
+	}
+}
+
+func TestKnownRecords_HaveClockSkewColumn(t *testing.T) {
+	for _, r := range KnownRecords() {
+		columns, err := getStructColumns(r)
+		if err != nil {
+			t.Fatalf("%T: %v", r, err)
+		}
+		if !slices.Contains(columns, "clock_skew") {
+			t.Errorf("%s has no clock_skew column", r.TableName())
+		}
+		schema, err := AvroSchema(r)
+		if err != nil {
+			t.Fatalf("%T: %v", r, err)
+		}
+		if !strings.Contains(schema, `{"name":"clock_skew","type":"boolean","default":false}`) {
+			t.Errorf("%s avro schema has no clock_skew field: %s", r.TableName(), schema)
+		}
+	}
+}
+
 func TestExtractIsisAdjacencies_Isolation(t *testing.T) {
 	// Test the extractor function in isolation
 	resp := loadGoldenPrototext(t, "isis_adjacency.prototext")
@@ -789,6 +1198,18 @@ func newTestMetrics() *ProcessorMetrics {
 		ProcessingDuration: &testHistogram{},
 		WriteErrors:        &testCounter{},
 		CommitErrors:       &testCounter{},
+		RecordsSampledOut:  &testCounter{},
+		ClockSkew:          &testHistogram{},
+		ClockSkewRewritten: &testCounter{},
+		ClockSkewDropped:   &testCounter{},
+
+		DeviceNotificationsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "device_notifications_dropped_total",
+		}, []string{"device", "reason"}),
+
+		WriteQueueDepth:   prometheus.NewGauge(prometheus.GaugeOpts{Name: "write_queue_depth"}),
+		WriteQueueFull:    prometheus.NewCounter(prometheus.CounterOpts{Name: "write_queue_full_total"}),
+		WriteQueueDropped: prometheus.NewCounter(prometheus.CounterOpts{Name: "write_queue_records_dropped_total"}),
 	}
 }
 
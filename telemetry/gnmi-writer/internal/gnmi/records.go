@@ -2,10 +2,28 @@ package gnmi
 
 import "time"
 
+// KnownRecords returns a zero value of every record type produced by DefaultExtractors.
+// Writers that need per-type setup (e.g. schema registration) iterate over this list.
+func KnownRecords() []Record {
+	return []Record{
+		IsisGlobalStateRecord{},
+		IsisOverloadBitRecord{},
+		IsisAdjacencyRecord{},
+		SystemStateRecord{},
+		BgpNeighborRecord{},
+		InterfaceIfindexRecord{},
+		TransceiverStateRecord{},
+		InterfaceStateRecord{},
+		TransceiverThresholdRecord{},
+	}
+}
+
 // IsisGlobalStateRecord represents ISIS global state for storage in ClickHouse.
 type IsisGlobalStateRecord struct {
 	Timestamp       time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey    string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env             string    `json:"env,omitempty" ch:"env"`
+	ClockSkew       bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	NetworkInstance string    `json:"network_instance" ch:"network_instance"`
 	Instance        string    `json:"instance,omitempty" ch:"instance"`
 	Net             string    `json:"net,omitempty" ch:"net"`
@@ -21,6 +39,8 @@ func (r IsisGlobalStateRecord) TableName() string {
 type IsisOverloadBitRecord struct {
 	Timestamp       time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey    string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env             string    `json:"env,omitempty" ch:"env"`
+	ClockSkew       bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	NetworkInstance string    `json:"network_instance" ch:"network_instance"`
 	OverloadBit     bool      `json:"overload_bit" ch:"overload_bit"`
 }
@@ -34,6 +54,8 @@ func (r IsisOverloadBitRecord) TableName() string {
 type IsisAdjacencyRecord struct {
 	Timestamp           time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey        string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env                 string    `json:"env,omitempty" ch:"env"`
+	ClockSkew           bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	InterfaceID         string    `json:"interface_id" ch:"interface_id"`
 	Level               uint8     `json:"level" ch:"level"`
 	SystemID            string    `json:"system_id" ch:"system_id"`
@@ -56,6 +78,8 @@ func (r IsisAdjacencyRecord) TableName() string {
 type SystemStateRecord struct {
 	Timestamp    time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env          string    `json:"env,omitempty" ch:"env"`
+	ClockSkew    bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	Hostname     string    `json:"hostname,omitempty" ch:"hostname"`
 	MemTotal     uint64    `json:"mem_total,omitempty" ch:"mem_total"`
 	MemUsed      uint64    `json:"mem_used,omitempty" ch:"mem_used"`
@@ -74,6 +98,8 @@ func (r SystemStateRecord) TableName() string {
 type BgpNeighborRecord struct {
 	Timestamp              time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey           string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env                    string    `json:"env,omitempty" ch:"env"`
+	ClockSkew              bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	NetworkInstance        string    `json:"network_instance" ch:"network_instance"`
 	NeighborAddress        string    `json:"neighbor_address" ch:"neighbor_address"`
 	Description            string    `json:"description" ch:"description"`
@@ -96,6 +122,8 @@ func (r BgpNeighborRecord) TableName() string {
 type InterfaceIfindexRecord struct {
 	Timestamp     time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey  string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env           string    `json:"env,omitempty" ch:"env"`
+	ClockSkew     bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	InterfaceName string    `json:"interface_name" ch:"interface_name"`
 	Ifindex       uint32    `json:"ifindex" ch:"ifindex"`
 }
@@ -109,6 +137,8 @@ func (r InterfaceIfindexRecord) TableName() string {
 type TransceiverStateRecord struct {
 	Timestamp        time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey     string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env              string    `json:"env,omitempty" ch:"env"`
+	ClockSkew        bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	InterfaceName    string    `json:"interface_name" ch:"interface_name"`
 	ChannelIndex     uint16    `json:"channel_index" ch:"channel_index"`
 	InputPower       float64   `json:"input_power,omitempty" ch:"input_power"`
@@ -184,6 +214,8 @@ func AggregateTransceiverState(records []Record) []Record {
 type InterfaceStateRecord struct {
 	Timestamp          time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey       string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env                string    `json:"env,omitempty" ch:"env"`
+	ClockSkew          bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	InterfaceName      string    `json:"interface_name" ch:"interface_name"`
 	AdminStatus        string    `json:"admin_status" ch:"admin_status"`
 	OperStatus         string    `json:"oper_status" ch:"oper_status"`
@@ -217,6 +249,8 @@ func (r InterfaceStateRecord) TableName() string {
 type TransceiverThresholdRecord struct {
 	Timestamp              time.Time `json:"timestamp" ch:"timestamp"`
 	DevicePubkey           string    `json:"device_pubkey" ch:"device_pubkey"`
+	Env                    string    `json:"env,omitempty" ch:"env"`
+	ClockSkew              bool      `json:"clock_skew,omitempty" ch:"clock_skew"`
 	InterfaceName          string    `json:"interface_name" ch:"interface_name"`
 	Severity               string    `json:"severity" ch:"severity"`
 	InputPowerLower        float64   `json:"input_power_lower,omitempty" ch:"input_power_lower"`
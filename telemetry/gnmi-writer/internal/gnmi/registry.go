@@ -0,0 +1,107 @@
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// confluentMagicByte prefixes every message in the Confluent wire format.
+const confluentMagicByte = 0x0
+
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaRegistryClient registers schemas against a Confluent-compatible schema registry.
+type SchemaRegistryClient struct {
+	baseURL    string
+	user       string
+	pass       string
+	httpClient *http.Client
+}
+
+// SchemaRegistryOption configures a SchemaRegistryClient.
+type SchemaRegistryOption func(*SchemaRegistryClient)
+
+// WithSchemaRegistryAuth sets basic auth credentials for the registry.
+func WithSchemaRegistryAuth(user, pass string) SchemaRegistryOption {
+	return func(c *SchemaRegistryClient) {
+		c.user = user
+		c.pass = pass
+	}
+}
+
+// WithSchemaRegistryHTTPClient sets the HTTP client used for registry requests.
+func WithSchemaRegistryHTTPClient(httpClient *http.Client) SchemaRegistryOption {
+	return func(c *SchemaRegistryClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewSchemaRegistryClient creates a client for the registry at baseURL.
+func NewSchemaRegistryClient(baseURL string, opts ...SchemaRegistryOption) (*SchemaRegistryClient, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("schema registry url is required")
+	}
+	c := &SchemaRegistryClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Register registers an Avro schema under the given subject and returns its schema ID.
+// Registering an identical schema again is idempotent and returns the existing ID.
+func (c *SchemaRegistryClient) Register(ctx context.Context, subject, schema string) (int, error) {
+	body, err := json.Marshal(map[string]string{"schema": schema, "schemaType": "AVRO"})
+	if err != nil {
+		return 0, fmt.Errorf("error marshaling schema request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/subjects/%s/versions", c.baseURL, url.PathEscape(subject))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating schema registry request: %w", err)
+	}
+	req.Header.Set("Content-Type", schemaRegistryContentType)
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error registering schema for subject %s: %w", subject, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry returned status %d for subject %s: %s", resp.StatusCode, subject, strings.TrimSpace(string(respBody)))
+	}
+
+	var out struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return 0, fmt.Errorf("error decoding schema registry response: %w", err)
+	}
+	return out.ID, nil
+}
+
+// encodeConfluentWireFormat prepends the Confluent magic byte and schema ID to an encoded payload.
+func encodeConfluentWireFormat(schemaID int, payload []byte) []byte {
+	buf := make([]byte, 5, 5+len(payload))
+	buf[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(buf[1:5], uint32(schemaID))
+	return append(buf, payload...)
+}
@@ -0,0 +1,240 @@
+package gnmi
+
+import (
+	"container/list"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultSamplingMaxKeys bounds the number of (device, key) entries tracked by the sampler.
+const defaultSamplingMaxKeys = 100_000
+
+// sampler downsamples records per record type, dropping records for a given
+// (device, key) that arrive sooner than the configured minimum interval since
+// the last written sample. The most recent dropped record per key is retained
+// so it can be flushed on shutdown. State is bounded by evicting the least
+// recently used key; an evicted key's retained record is written with the
+// batch that evicts it.
+type sampler struct {
+	mu       sync.Mutex
+	policies map[string]time.Duration // table name -> min interval
+	maxKeys  int
+	lru      *list.List               // front = most recently used
+	entries  map[string]*list.Element // sample key -> element holding *sampleEntry
+}
+
+// sampleEntry tracks sampling state for a single (device, key).
+type sampleEntry struct {
+	key         string
+	lastWritten time.Time
+	pending     Record // most recent dropped record, nil if none
+	version     uint64 // incremented on every committed update
+}
+
+func newSampler(maxKeys int) *sampler {
+	if maxKeys <= 0 {
+		maxKeys = defaultSamplingMaxKeys
+	}
+	return &sampler{
+		policies: make(map[string]time.Duration),
+		maxKeys:  maxKeys,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// filter returns the records that should be written and the number dropped.
+// State is only advanced when the returned commit function is called, so a
+// failed write that is retried does not have its records sampled out. Keys
+// that will be evicted on commit to make room for the batch's new keys have
+// their retained record appended to out so the latest value is not lost.
+func (s *sampler) filter(records []Record) (out []Record, dropped int, commit func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type update struct {
+		key     string
+		written time.Time
+		pending Record
+	}
+	var updates []update
+	staged := make(map[string]time.Time) // last written within this batch
+	batchKeys := make(map[string]bool)
+
+	for _, r := range records {
+		interval, ok := s.policies[r.TableName()]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+
+		key := recordSampleKey(r)
+		ts := recordTimestamp(r)
+		batchKeys[key] = true
+
+		last, seen := staged[key]
+		if !seen {
+			if el, ok := s.entries[key]; ok {
+				last, seen = el.Value.(*sampleEntry).lastWritten, true
+			}
+		}
+
+		if seen && ts.Sub(last) < interval {
+			dropped++
+			updates = append(updates, update{key: key, pending: r})
+			continue
+		}
+
+		staged[key] = ts
+		out = append(out, r)
+		updates = append(updates, update{key: key, written: ts})
+	}
+	evictions := s.planEvictions(batchKeys)
+	for _, ev := range evictions {
+		if ev.entry.pending != nil {
+			out = append(out, ev.entry.pending)
+		}
+	}
+
+	commit = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, ev := range evictions {
+			// Skip entries updated since filter; a newer pending record would
+			// otherwise be lost. They stay eligible for the next eviction.
+			el, ok := s.entries[ev.entry.key]
+			if !ok || el.Value.(*sampleEntry) != ev.entry || ev.entry.version != ev.version {
+				continue
+			}
+			s.lru.Remove(el)
+			delete(s.entries, ev.entry.key)
+		}
+		for _, u := range updates {
+			e := s.touch(u.key)
+			e.version++
+			if u.pending != nil {
+				e.pending = u.pending
+				continue
+			}
+			e.lastWritten = u.written
+			e.pending = nil
+		}
+	}
+	return out, dropped, commit
+}
+
+// eviction is an entry chosen for eviction by filter, as of version.
+type eviction struct {
+	entry   *sampleEntry
+	version uint64
+}
+
+// planEvictions picks the least recently used entries not in keep that must be
+// evicted for the keys in keep to fit within maxKeys. Must be called with s.mu
+// held.
+func (s *sampler) planEvictions(keep map[string]bool) []eviction {
+	incoming := 0
+	for key := range keep {
+		if _, ok := s.entries[key]; !ok {
+			incoming++
+		}
+	}
+
+	var evictions []eviction
+	excess := s.lru.Len() + incoming - s.maxKeys
+	for el := s.lru.Back(); el != nil && len(evictions) < excess; el = el.Prev() {
+		e := el.Value.(*sampleEntry)
+		if !keep[e.key] {
+			evictions = append(evictions, eviction{entry: e, version: e.version})
+		}
+	}
+	return evictions
+}
+
+// touch returns the entry for key, creating it if needed. Entries are only
+// evicted by the commits planned in filter, so a batch with more new keys than
+// maxKeys may exceed the bound until the next commit. Must be called with s.mu
+// held.
+func (s *sampler) touch(key string) *sampleEntry {
+	if el, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(el)
+		return el.Value.(*sampleEntry)
+	}
+	e := &sampleEntry{key: key}
+	s.entries[key] = s.lru.PushFront(e)
+	return e
+}
+
+// flush returns and clears all pending (dropped but most recent) records.
+func (s *sampler) flush() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Record
+	for el := s.lru.Front(); el != nil; el = el.Next() {
+		e := el.Value.(*sampleEntry)
+		if e.pending != nil {
+			out = append(out, e.pending)
+			e.pending = nil
+		}
+	}
+	return out
+}
+
+// recordSampleKey returns the identity of the entity a record describes, so
+// that samples for the same (device, key) are downsampled together.
+func recordSampleKey(r Record) string {
+	var key string
+	switch rec := r.(type) {
+	case IsisGlobalStateRecord:
+		key = rec.DevicePubkey + "|" + rec.NetworkInstance + "|" + rec.Instance
+	case IsisOverloadBitRecord:
+		key = rec.DevicePubkey + "|" + rec.NetworkInstance
+	case IsisAdjacencyRecord:
+		key = rec.DevicePubkey + "|" + rec.InterfaceID + "|" + strconv.Itoa(int(rec.Level)) + "|" + rec.SystemID
+	case SystemStateRecord:
+		key = rec.DevicePubkey
+	case BgpNeighborRecord:
+		key = rec.DevicePubkey + "|" + rec.NetworkInstance + "|" + rec.NeighborAddress
+	case InterfaceIfindexRecord:
+		key = rec.DevicePubkey + "|" + rec.InterfaceName
+	case TransceiverStateRecord:
+		key = rec.DevicePubkey + "|" + rec.InterfaceName + "|" + strconv.Itoa(int(rec.ChannelIndex))
+	case InterfaceStateRecord:
+		key = rec.DevicePubkey + "|" + rec.InterfaceName
+	case TransceiverThresholdRecord:
+		key = rec.DevicePubkey + "|" + rec.InterfaceName + "|" + rec.Severity
+	default:
+		key = recordDevicePubkey(r)
+	}
+	return r.TableName() + "|" + key
+}
+
+// recordTimestamp returns the timestamp column of a record, or the zero time if absent.
+func recordTimestamp(r Record) time.Time {
+	v, ok := recordColumn(r, "timestamp")
+	if !ok {
+		return time.Time{}
+	}
+	ts, _ := v.Interface().(time.Time)
+	return ts
+}
+
+// recordColumn returns the struct field for the given ch column name.
+func recordColumn(r Record, column string) (reflect.Value, bool) {
+	v := reflect.ValueOf(r)
+	if v.Kind() == reflect.Ptr { // nolint:govet
+		v = v.Elem()
+	}
+	meta, err := getOrComputeMetadata(v.Type())
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	idx, ok := meta.tagToIndex[column]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.Field(idx), true
+}
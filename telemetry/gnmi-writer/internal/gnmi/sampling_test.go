@@ -0,0 +1,213 @@
+package gnmi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/proto"
+)
+
+// sliceConsumer returns one batch per Consume call, then reports the client closed.
+type sliceConsumer struct {
+	batches [][]*gpb.Notification
+}
+
+func (c *sliceConsumer) Consume(ctx context.Context) ([]*gpb.Notification, error) {
+	if len(c.batches) == 0 {
+		return nil, ErrClientClosed
+	}
+	batch := c.batches[0]
+	c.batches = c.batches[1:]
+	return batch, nil
+}
+
+func (c *sliceConsumer) Commit(ctx context.Context) error { return nil }
+func (c *sliceConsumer) Close() error                     { return nil }
+
+// captureWriter records every batch written.
+type captureWriter struct {
+	mu      sync.Mutex
+	batches [][]Record
+}
+
+func (w *captureWriter) WriteRecords(ctx context.Context, records []Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batches = append(w.batches, records)
+	return nil
+}
+
+func ifaceRecord(device, iface string, ts time.Time, inOctets uint64) InterfaceStateRecord {
+	return InterfaceStateRecord{Timestamp: ts, DevicePubkey: device, InterfaceName: iface, InOctets: inOctets}
+}
+
+func TestSampler_DropsWithinInterval(t *testing.T) {
+	s := newSampler(0)
+	s.policies["interface_state"] = 30 * time.Second
+
+	base := time.Unix(1_700_000_000, 0)
+	batches := [][]Record{
+		{ifaceRecord("dev1", "Ethernet1", base, 1), ifaceRecord("dev1", "Ethernet2", base, 1)},
+		{ifaceRecord("dev1", "Ethernet1", base.Add(10*time.Second), 2)},
+		{ifaceRecord("dev1", "Ethernet1", base.Add(20*time.Second), 3), SystemStateRecord{Timestamp: base, DevicePubkey: "dev1"}},
+		{ifaceRecord("dev1", "Ethernet1", base.Add(30*time.Second), 4)},
+	}
+	wantOut := []int{2, 0, 1, 1}
+	wantDropped := []int{0, 1, 1, 0}
+
+	for i, batch := range batches {
+		out, dropped, commit := s.filter(batch)
+		commit()
+		if len(out) != wantOut[i] || dropped != wantDropped[i] {
+			t.Fatalf("batch %d: got %d out / %d dropped, want %d / %d", i, len(out), dropped, wantOut[i], wantDropped[i])
+		}
+	}
+
+	// The sample at +30s passed and superseded the pending +20s sample.
+	if pending := s.flush(); len(pending) != 0 {
+		t.Errorf("expected no pending records, got %d", len(pending))
+	}
+}
+
+func TestSampler_UncommittedBatchIsNotSampled(t *testing.T) {
+	s := newSampler(0)
+	s.policies["interface_state"] = time.Minute
+
+	base := time.Unix(1_700_000_000, 0)
+	first := []Record{ifaceRecord("dev1", "Ethernet1", base, 1)}
+
+	// Simulate a failed write that is retried: the first filter is never committed.
+	if out, _, _ := s.filter(first); len(out) != 1 {
+		t.Fatalf("expected record to pass, got %d", len(out))
+	}
+	if out, _, _ := s.filter(first); len(out) != 1 {
+		t.Fatalf("expected retried record to pass, got %d", len(out))
+	}
+}
+
+func TestSampler_FlushReturnsMostRecentDropped(t *testing.T) {
+	s := newSampler(0)
+	s.policies["interface_state"] = time.Minute
+
+	base := time.Unix(1_700_000_000, 0)
+	for i := range 4 {
+		_, _, commit := s.filter([]Record{ifaceRecord("dev1", "Ethernet1", base.Add(time.Duration(i)*time.Second), uint64(i))})
+		commit()
+	}
+
+	pending := s.flush()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending record, got %d", len(pending))
+	}
+	if got := pending[0].(InterfaceStateRecord).InOctets; got != 3 {
+		t.Errorf("expected most recent sample (3), got %d", got)
+	}
+	if again := s.flush(); len(again) != 0 {
+		t.Errorf("expected flush to clear pending, got %d", len(again))
+	}
+}
+
+func TestSampler_BoundedKeys(t *testing.T) {
+	s := newSampler(2)
+	s.policies["interface_state"] = time.Minute
+
+	base := time.Unix(1_700_000_000, 0)
+	for _, iface := range []string{"Ethernet1", "Ethernet2", "Ethernet3"} {
+		_, _, commit := s.filter([]Record{ifaceRecord("dev1", iface, base, 1)})
+		commit()
+	}
+	if got := len(s.entries); got != 2 {
+		t.Fatalf("expected 2 tracked keys, got %d", got)
+	}
+
+	// Ethernet1 was evicted, so a sample within the interval passes again.
+	out, _, _ := s.filter([]Record{ifaceRecord("dev1", "Ethernet1", base.Add(time.Second), 2)})
+	if len(out) != 1 {
+		t.Errorf("expected evicted key to pass, got %d", len(out))
+	}
+}
+
+func TestSampler_EvictionWritesPendingRecord(t *testing.T) {
+	s := newSampler(2)
+	s.policies["interface_state"] = time.Minute
+
+	base := time.Unix(1_700_000_000, 0)
+	batches := [][]Record{
+		{ifaceRecord("dev1", "Ethernet1", base, 1), ifaceRecord("dev1", "Ethernet2", base, 1)},
+		// Ethernet1 is sampled out and retained as pending.
+		{ifaceRecord("dev1", "Ethernet1", base.Add(time.Second), 2)},
+		// Ethernet2 is sampled out too, making Ethernet1 the least recently used.
+		{ifaceRecord("dev1", "Ethernet2", base.Add(time.Second), 2)},
+	}
+	for _, batch := range batches {
+		_, _, commit := s.filter(batch)
+		commit()
+	}
+
+	// A new key evicts Ethernet1, whose pending record is written with the batch.
+	out, dropped, commit := s.filter([]Record{ifaceRecord("dev1", "Ethernet3", base, 1)})
+	commit()
+	if dropped != 0 || len(out) != 2 {
+		t.Fatalf("expected the new record and the evicted pending record, got %d out / %d dropped", len(out), dropped)
+	}
+	evicted := out[1].(InterfaceStateRecord)
+	if evicted.InterfaceName != "Ethernet1" || evicted.InOctets != 2 {
+		t.Errorf("expected Ethernet1's latest sample (2), got %s (%d)", evicted.InterfaceName, evicted.InOctets)
+	}
+	if got := len(s.entries); got != 2 {
+		t.Fatalf("expected 2 tracked keys, got %d", got)
+	}
+
+	// Only Ethernet2's pending record is left to flush.
+	pending := s.flush()
+	if len(pending) != 1 || pending[0].(InterfaceStateRecord).InterfaceName != "Ethernet2" {
+		t.Errorf("expected only Ethernet2 pending, got %v", pending)
+	}
+}
+
+func TestProcessor_SamplingFlushesOnShutdown(t *testing.T) {
+	resp := loadGoldenPrototext(t, "system_hostname.prototext")
+	base := resp.GetUpdate()
+
+	notificationAt := func(offset time.Duration) *gpb.Notification {
+		n := proto.Clone(base).(*gpb.Notification)
+		n.Timestamp = base.GetTimestamp() + offset.Nanoseconds()
+		return n
+	}
+
+	consumer := &sliceConsumer{batches: [][]*gpb.Notification{
+		{notificationAt(0)},
+		{notificationAt(5 * time.Second)},
+		{notificationAt(10 * time.Second)},
+	}}
+	writer := &captureWriter{}
+	p, err := NewProcessor(
+		WithConsumer(consumer),
+		WithRecordWriter(writer),
+		WithProcessorMetrics(newTestMetrics()),
+		WithSampling("system_state", time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	// First sample written, the next two dropped, and the latest flushed on shutdown.
+	if len(writer.batches) != 2 {
+		t.Fatalf("expected 2 writes, got %d", len(writer.batches))
+	}
+	flushed := writer.batches[1]
+	if len(flushed) != 1 {
+		t.Fatalf("expected 1 flushed record, got %d", len(flushed))
+	}
+	wantTS := time.Unix(0, base.GetTimestamp()+(10*time.Second).Nanoseconds())
+	if got := recordTimestamp(flushed[0]); !got.Equal(wantTS) {
+		t.Errorf("expected flushed record at %v, got %v", wantTS, got)
+	}
+}
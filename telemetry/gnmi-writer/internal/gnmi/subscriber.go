@@ -0,0 +1,373 @@
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	defaultGNMIBatchSize      = 1000
+	defaultGNMIInitialBackoff = time.Second
+	defaultGNMIMaxBackoff     = time.Minute
+)
+
+// DefaultGNMISubscribePaths covers the paths matched by DefaultExtractors.
+var DefaultGNMISubscribePaths = []string{
+	"/network-instances/network-instance/protocols/protocol/isis",
+	"/network-instances/network-instance/protocols/protocol/bgp/neighbors",
+	"/system/state",
+	"/interfaces/interface/state",
+	"/components/component/transceiver",
+}
+
+// GNMITLSConfig holds TLS settings for connecting to a gNMI target.
+type GNMITLSConfig struct {
+	Enabled    bool   // Use TLS
+	ServerName string // SNI/hostname verification override
+	CAFile     string // Path to CA PEM (optional if publicly trusted)
+	CertFile   string // Path to client certificate PEM for mTLS
+	KeyFile    string // Path to client private key PEM for mTLS
+	SkipVerify bool   // Skip certificate verification (dev only)
+}
+
+// GNMISubscriber consumes gNMI notifications from a Subscribe stream opened
+// directly against a gNMI target, without Kafka. The stream is reopened with
+// exponential backoff whenever it fails.
+type GNMISubscriber struct {
+	target         string
+	targetName     string
+	paths          []*gpb.Path
+	tls            *GNMITLSConfig
+	dialOpts       []grpc.DialOption
+	batchSize      int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logger         *slog.Logger
+	metrics        *ConsumerMetrics
+
+	rawPaths      []string
+	notifications chan *gpb.Notification
+	cancel        context.CancelFunc
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// GNMISubscriberOption configures a GNMISubscriber.
+type GNMISubscriberOption func(*GNMISubscriber)
+
+// WithGNMITarget sets the gNMI target address (host:port).
+func WithGNMITarget(target string) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.target = target
+	}
+}
+
+// WithGNMITargetName sets the gNMI prefix target sent with the subscription.
+// Notifications that come back without a prefix target are stamped with it, so
+// it should be the device pubkey that records are attributed to.
+func WithGNMITargetName(name string) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.targetName = name
+	}
+}
+
+// WithGNMIPaths sets the paths to subscribe to (default DefaultGNMISubscribePaths).
+func WithGNMIPaths(paths []string) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.rawPaths = paths
+	}
+}
+
+// WithGNMITLS sets the TLS settings for the gNMI connection (default insecure).
+func WithGNMITLS(cfg *GNMITLSConfig) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.tls = cfg
+	}
+}
+
+// WithGNMIBatchSize sets the maximum number of notifications returned by one
+// Consume call (default 1000).
+func WithGNMIBatchSize(n int) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.batchSize = n
+	}
+}
+
+// WithGNMIBackoff sets the initial and maximum delay between reconnection
+// attempts (default 1s and 1m).
+func WithGNMIBackoff(initial, maxBackoff time.Duration) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.initialBackoff = initial
+		s.maxBackoff = maxBackoff
+	}
+}
+
+// WithGNMILogger sets the logger for the subscriber.
+func WithGNMILogger(logger *slog.Logger) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.logger = logger
+	}
+}
+
+// WithGNMIConsumerMetrics sets the metrics for the subscriber. Stream failures
+// are counted as fetch errors.
+func WithGNMIConsumerMetrics(metrics *ConsumerMetrics) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.metrics = metrics
+	}
+}
+
+// withGNMIDialOptions is used for testing to add gRPC dial options.
+func withGNMIDialOptions(opts ...grpc.DialOption) GNMISubscriberOption {
+	return func(s *GNMISubscriber) {
+		s.dialOpts = append(s.dialOpts, opts...)
+	}
+}
+
+// NewGNMISubscriber creates a GNMISubscriber and starts its Subscribe stream in
+// the background. The target must be configured via WithGNMITarget.
+func NewGNMISubscriber(opts ...GNMISubscriberOption) (*GNMISubscriber, error) {
+	s := &GNMISubscriber{
+		metrics:        NewConsumerMetrics(nil), // Always set, unregistered by default
+		rawPaths:       DefaultGNMISubscribePaths,
+		tls:            &GNMITLSConfig{},
+		batchSize:      defaultGNMIBatchSize,
+		initialBackoff: defaultGNMIInitialBackoff,
+		maxBackoff:     defaultGNMIMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.logger == nil {
+		s.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	if s.target == "" {
+		return nil, fmt.Errorf("gnmi target is required: use WithGNMITarget")
+	}
+	if len(s.rawPaths) == 0 {
+		return nil, fmt.Errorf("at least one gnmi subscribe path is required")
+	}
+	if s.batchSize <= 0 {
+		return nil, fmt.Errorf("gnmi batch size must be positive, got %d", s.batchSize)
+	}
+	if s.initialBackoff <= 0 || s.maxBackoff < s.initialBackoff {
+		return nil, fmt.Errorf("invalid gnmi backoff: initial %s, max %s", s.initialBackoff, s.maxBackoff)
+	}
+	for _, p := range s.rawPaths {
+		path, err := ygot.StringToStructuredPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gnmi subscribe path %q: %w", p, err)
+		}
+		s.paths = append(s.paths, path)
+	}
+
+	creds, err := s.tls.transportCredentials(s.target)
+	if err != nil {
+		return nil, fmt.Errorf("configure gnmi TLS: %w", err)
+	}
+	s.dialOpts = append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, s.dialOpts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.notifications = make(chan *gpb.Notification, s.batchSize)
+	s.done = make(chan struct{})
+	go s.run(ctx)
+
+	return s, nil
+}
+
+// Consume waits for at least one notification and returns it along with any
+// others already received, up to the batch size.
+func (s *GNMISubscriber) Consume(ctx context.Context) ([]*gpb.Notification, error) {
+	var batch []*gpb.Notification
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	case <-s.done:
+		return nil, ErrClientClosed
+	case n := <-s.notifications:
+		batch = append(batch, n)
+	}
+
+drain:
+	for len(batch) < s.batchSize {
+		select {
+		case n := <-s.notifications:
+			batch = append(batch, n)
+		default:
+			break drain
+		}
+	}
+
+	s.metrics.NotificationsConsumed.Add(float64(len(batch)))
+	return batch, nil
+}
+
+// Commit is a no-op: a gNMI stream has no offsets to commit.
+func (s *GNMISubscriber) Commit(ctx context.Context) error {
+	return nil
+}
+
+// Close stops the Subscribe stream and waits for it to exit.
+func (s *GNMISubscriber) Close() error {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		<-s.done
+	})
+	return nil
+}
+
+// run keeps a Subscribe stream open until ctx is cancelled, reconnecting with
+// exponential backoff. The backoff resets after a stream that delivered data.
+func (s *GNMISubscriber) run(ctx context.Context) {
+	defer close(s.done)
+
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = 0
+	bo.InitialInterval = s.initialBackoff
+	bo.MaxInterval = s.maxBackoff
+
+	for {
+		received, err := s.subscribe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			bo.Reset()
+		}
+		s.metrics.FetchErrors.Inc()
+		wait := bo.NextBackOff()
+		s.logger.Warn("gnmi subscription ended, reconnecting", "target", s.target, "error", err, "in", wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// subscribe opens one Subscribe stream and forwards its notifications until
+// the stream fails. It reports whether any notification was received.
+func (s *GNMISubscriber) subscribe(ctx context.Context) (bool, error) {
+	conn, err := grpc.NewClient(s.target, s.dialOpts...)
+	if err != nil {
+		return false, fmt.Errorf("grpc dial: %w", err)
+	}
+	defer conn.Close()
+
+	stream, err := gpb.NewGNMIClient(conn).Subscribe(ctx)
+	if err != nil {
+		return false, fmt.Errorf("open subscribe stream: %w", err)
+	}
+	if err := stream.Send(s.subscribeRequest()); err != nil {
+		return false, fmt.Errorf("send subscribe request: %w", err)
+	}
+	s.logger.Info("gnmi subscription started", "target", s.target, "paths", len(s.paths))
+
+	received := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return received, fmt.Errorf("receive: %w", err)
+		}
+		if resp.GetSyncResponse() {
+			s.logger.Debug("gnmi initial sync complete", "target", s.target)
+			continue
+		}
+		n := resp.GetUpdate()
+		if n == nil {
+			continue
+		}
+		if n.GetPrefix().GetTarget() == "" && s.targetName != "" {
+			if n.Prefix == nil {
+				n.Prefix = &gpb.Path{}
+			}
+			n.Prefix.Target = s.targetName
+		}
+		received = true
+
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case s.notifications <- n:
+		}
+	}
+}
+
+func (s *GNMISubscriber) subscribeRequest() *gpb.SubscribeRequest {
+	subs := make([]*gpb.Subscription, 0, len(s.paths))
+	for _, p := range s.paths {
+		subs = append(subs, &gpb.Subscription{Path: p, Mode: gpb.SubscriptionMode_TARGET_DEFINED})
+	}
+	return &gpb.SubscribeRequest{
+		Request: &gpb.SubscribeRequest_Subscribe{
+			Subscribe: &gpb.SubscriptionList{
+				Prefix:       &gpb.Path{Target: s.targetName},
+				Subscription: subs,
+				Mode:         gpb.SubscriptionList_STREAM,
+				Encoding:     gpb.Encoding_JSON_IETF,
+			},
+		},
+	}
+}
+
+// transportCredentials builds gRPC credentials for target from the TLS settings.
+func (c *GNMITLSConfig) transportCredentials(target string) (credentials.TransportCredentials, error) {
+	if c == nil || !c.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsCfg := &tls.Config{
+		InsecureSkipVerify: c.SkipVerify,
+		MinVersion:         tls.VersionTLS12,
+		NextProtos:         []string{"h2"}, // gRPC uses HTTP/2
+	}
+
+	// Set SNI: use explicit override, otherwise extract from address
+	if c.ServerName != "" {
+		tlsCfg.ServerName = c.ServerName
+	} else if host, _, err := net.SplitHostPort(target); err == nil {
+		tlsCfg.ServerName = host
+	}
+
+	if c.CAFile != "" {
+		b, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls-ca %s: %w", c.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("tls-ca %s: no valid certs found in PEM", c.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("both cert and key required for mTLS (cert=%q, key=%q)", c.CertFile, c.KeyFile)
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client keypair: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
@@ -0,0 +1,238 @@
+package gnmi
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeGNMIServer streams one batch of responses per Subscribe call, then ends
+// the stream with an error so the subscriber has to reconnect.
+type fakeGNMIServer struct {
+	gpb.UnimplementedGNMIServer
+
+	mu       sync.Mutex
+	streams  [][]*gpb.SubscribeResponse
+	requests []*gpb.SubscribeRequest
+}
+
+func (s *fakeGNMIServer) Subscribe(stream gpb.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var responses []*gpb.SubscribeResponse
+	if len(s.streams) > 0 {
+		responses = s.streams[0]
+		s.streams = s.streams[1:]
+	}
+	s.mu.Unlock()
+
+	if responses == nil {
+		// Nothing left to send: hold the stream open until the client leaves.
+		<-stream.Context().Done()
+		return nil
+	}
+	for _, resp := range responses {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return status.Error(codes.Unavailable, "stream reset")
+}
+
+func (s *fakeGNMIServer) subscribeRequests() []*gpb.SubscribeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*gpb.SubscribeRequest(nil), s.requests...)
+}
+
+func startFakeGNMIServer(t *testing.T, srv *fakeGNMIServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := grpc.NewServer()
+	gpb.RegisterGNMIServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func updateResponse(n *gpb.Notification) *gpb.SubscribeResponse {
+	return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}}
+}
+
+func syncResponse() *gpb.SubscribeResponse {
+	return &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}}
+}
+
+// consumeN calls Consume until n notifications have been received.
+func consumeN(t *testing.T, c Consumer, n int) []*gpb.Notification {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var all []*gpb.Notification
+	for len(all) < n {
+		batch, err := c.Consume(ctx)
+		if err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("timed out after %d of %d notifications", len(all), n)
+		}
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestGNMISubscriber_ReconnectsAndStampsTarget(t *testing.T) {
+	srv := &fakeGNMIServer{
+		streams: [][]*gpb.SubscribeResponse{
+			{
+				updateResponse(&gpb.Notification{Timestamp: 1}),
+				syncResponse(),
+				updateResponse(&gpb.Notification{Timestamp: 2, Prefix: &gpb.Path{Target: "other-device"}}),
+			},
+			{
+				updateResponse(&gpb.Notification{Timestamp: 3}),
+			},
+		},
+	}
+	addr := startFakeGNMIServer(t, srv)
+
+	sub, err := NewGNMISubscriber(
+		WithGNMITarget(addr),
+		WithGNMITargetName("device1"),
+		WithGNMIPaths([]string{"/system/state", "/interfaces/interface/state"}),
+		WithGNMIBackoff(10*time.Millisecond, 50*time.Millisecond),
+		WithGNMIConsumerMetrics(NewConsumerMetrics(prometheus.NewRegistry())),
+	)
+	if err != nil {
+		t.Fatalf("failed to create subscriber: %v", err)
+	}
+	defer sub.Close()
+
+	notifications := consumeN(t, sub, 3)
+
+	wantTargets := []string{"device1", "other-device", "device1"}
+	for i, n := range notifications {
+		if n.GetTimestamp() != int64(i+1) {
+			t.Errorf("notification %d: expected timestamp %d, got %d", i, i+1, n.GetTimestamp())
+		}
+		if got := n.GetPrefix().GetTarget(); got != wantTargets[i] {
+			t.Errorf("notification %d: expected target %s, got %s", i, wantTargets[i], got)
+		}
+	}
+
+	requests := srv.subscribeRequests()
+	if len(requests) < 2 {
+		t.Fatalf("expected the subscriber to reconnect, got %d subscribe requests", len(requests))
+	}
+	list := requests[0].GetSubscribe()
+	if list.GetPrefix().GetTarget() != "device1" {
+		t.Errorf("expected prefix target device1, got %q", list.GetPrefix().GetTarget())
+	}
+	if list.GetMode() != gpb.SubscriptionList_STREAM {
+		t.Errorf("expected STREAM mode, got %s", list.GetMode())
+	}
+	if len(list.GetSubscription()) != 2 {
+		t.Fatalf("expected 2 subscriptions, got %d", len(list.GetSubscription()))
+	}
+	if elem := list.GetSubscription()[0].GetPath().GetElem(); len(elem) != 2 || elem[0].GetName() != "system" {
+		t.Errorf("unexpected first subscription path: %v", elem)
+	}
+}
+
+func TestGNMISubscriber_FeedsProcessor(t *testing.T) {
+	resp := loadGoldenPrototext(t, "system_hostname.prototext")
+	srv := &fakeGNMIServer{streams: [][]*gpb.SubscribeResponse{{resp}}}
+	addr := startFakeGNMIServer(t, srv)
+
+	sub, err := NewGNMISubscriber(
+		WithGNMITarget(addr),
+		WithGNMIBackoff(10*time.Millisecond, 50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create subscriber: %v", err)
+	}
+
+	writer := &captureWriter{}
+	processor, err := NewProcessor(
+		WithConsumer(sub),
+		WithRecordWriter(writer),
+		WithProcessorMetrics(newTestMetrics()),
+		WithExtractors([]ExtractorDef{
+			{"system_state", PathContains("system", "state"), extractSystemState},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- processor.Run(ctx) }()
+
+	deadline := time.After(10 * time.Second)
+	for {
+		writer.mu.Lock()
+		n := len(writer.batches)
+		writer.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for records")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("processor error: %v", err)
+	}
+
+	record, ok := writer.batches[0][0].(SystemStateRecord)
+	if !ok {
+		t.Fatalf("expected SystemStateRecord, got %T", writer.batches[0][0])
+	}
+	if record.Hostname != "e76554a34f51" {
+		t.Errorf("expected hostname e76554a34f51, got %s", record.Hostname)
+	}
+	if record.DevicePubkey != "DZd011111111111111111111111111111111111111111" {
+		t.Errorf("expected device pubkey from the notification prefix, got %s", record.DevicePubkey)
+	}
+}
+
+func TestGNMISubscriber_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []GNMISubscriberOption
+	}{
+		{"missing target", nil},
+		{"no paths", []GNMISubscriberOption{WithGNMITarget("127.0.0.1:1"), WithGNMIPaths(nil)}},
+		{"invalid batch size", []GNMISubscriberOption{WithGNMITarget("127.0.0.1:1"), WithGNMIBatchSize(0)}},
+		{"invalid backoff", []GNMISubscriberOption{WithGNMITarget("127.0.0.1:1"), WithGNMIBackoff(time.Minute, time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGNMISubscriber(tt.opts...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
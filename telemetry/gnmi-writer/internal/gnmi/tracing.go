@@ -0,0 +1,49 @@
+package gnmi
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the processor's spans.
+const tracerName = "github.com/malbeclabs/doublezero/telemetry/gnmi-writer/internal/gnmi"
+
+// Span names. Each consumed batch gets a batch span with consume and write
+// children; each notification in it gets a notification span with one
+// unmarshal and one extract child per update an extractor handles.
+const (
+	spanBatch        = "gnmi.batch"
+	spanConsume      = "gnmi.consume"
+	spanNotification = "gnmi.notification"
+	spanUnmarshal    = "gnmi.unmarshal"
+	spanExtract      = "gnmi.extract"
+	spanWrite        = "gnmi.write"
+)
+
+// Span attribute keys.
+const (
+	attrDevice        = attribute.Key("gnmi.device")
+	attrRecordType    = attribute.Key("gnmi.record_type")
+	attrRecordTypes   = attribute.Key("gnmi.record_types")
+	attrOutput        = attribute.Key("gnmi.output")
+	attrNotifications = attribute.Key("gnmi.notifications")
+	attrUpdates       = attribute.Key("gnmi.updates")
+	attrRecords       = attribute.Key("gnmi.records")
+	attrDropped       = attribute.Key("gnmi.dropped")
+)
+
+// WithTracerProvider enables tracing with spans from the given provider. By
+// default the processor uses a no-op tracer.
+func WithTracerProvider(tp trace.TracerProvider) ProcessorOption {
+	return func(p *Processor) {
+		p.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithOutputBackend names the output backend (e.g. "clickhouse") recorded on
+// batch and write spans.
+func WithOutputBackend(name string) ProcessorOption {
+	return func(p *Processor) {
+		p.outputBackend = name
+	}
+}
@@ -0,0 +1,207 @@
+package gnmi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/protobuf/proto"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) WriteRecords(ctx context.Context, records []Record) error {
+	return errors.New("connection refused")
+}
+
+func newTracedProcessor(t *testing.T, consumer Consumer, writer RecordWriter) (*Processor, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	p, err := NewProcessor(
+		WithConsumer(consumer),
+		WithRecordWriter(writer),
+		WithProcessorMetrics(newTestMetrics()),
+		WithTracerProvider(tp),
+		WithOutputBackend("clickhouse"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	return p, recorder
+}
+
+func spansNamed(spans []sdktrace.ReadOnlySpan, name string) []sdktrace.ReadOnlySpan {
+	var out []sdktrace.ReadOnlySpan
+	for _, s := range spans {
+		if s.Name() == name {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestProcessor_TracingSpanStructure(t *testing.T) {
+	base := loadGoldenPrototext(t, "system_hostname.prototext").GetUpdate()
+	forDevice := func(device string) *gpb.Notification {
+		n := proto.Clone(base).(*gpb.Notification)
+		n.Prefix.Target = device
+		return n
+	}
+
+	// dev2 also carries an update the schema does not know, which matches the
+	// system_state extractor but fails to unmarshal.
+	bad := forDevice("dev2")
+	bad.Update = append(bad.Update, &gpb.Update{
+		Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "system"}, {Name: "state"}, {Name: "no-such-leaf"}}},
+		Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "x"}},
+	})
+
+	consumer := &sliceConsumer{batches: [][]*gpb.Notification{{forDevice("dev1"), bad}}}
+	writer := &captureWriter{}
+	p, recorder := newTracedProcessor(t, consumer, writer)
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if len(writer.batches) != 1 {
+		t.Fatalf("expected 1 write, got %d", len(writer.batches))
+	}
+
+	spans := recorder.Ended()
+	batches := spansNamed(spans, spanBatch)
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batch span, got %d", len(batches))
+	}
+	batch := batches[0]
+	if batch.Parent().IsValid() {
+		t.Error("expected batch span to be a root span")
+	}
+	if v, _ := spanAttr(batch, attrOutput); v.AsString() != "clickhouse" {
+		t.Errorf("batch output = %q, want clickhouse", v.AsString())
+	}
+	if v, _ := spanAttr(batch, attrNotifications); v.AsInt64() != 2 {
+		t.Errorf("batch notifications = %d, want 2", v.AsInt64())
+	}
+
+	childOf := func(s, parent sdktrace.ReadOnlySpan) bool {
+		return s.Parent().SpanID() == parent.SpanContext().SpanID() &&
+			s.SpanContext().TraceID() == parent.SpanContext().TraceID()
+	}
+
+	for _, name := range []string{spanConsume, spanWrite} {
+		got := spansNamed(spans, name)
+		if len(got) != 1 {
+			t.Fatalf("expected 1 %s span, got %d", name, len(got))
+		}
+		if !childOf(got[0], batch) {
+			t.Errorf("expected %s span to be a child of the batch span", name)
+		}
+	}
+	write := spansNamed(spans, spanWrite)[0]
+	if v, _ := spanAttr(write, attrOutput); v.AsString() != "clickhouse" {
+		t.Errorf("write output = %q, want clickhouse", v.AsString())
+	}
+	if v, _ := spanAttr(write, attrRecords); v.AsInt64() != 2 {
+		t.Errorf("write records = %d, want 2", v.AsInt64())
+	}
+
+	notifications := spansNamed(spans, spanNotification)
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notification spans, got %d", len(notifications))
+	}
+	byDevice := make(map[string]sdktrace.ReadOnlySpan)
+	for _, n := range notifications {
+		if !childOf(n, batch) {
+			t.Errorf("expected notification span to be a child of the batch span")
+		}
+		device, _ := spanAttr(n, attrDevice)
+		byDevice[device.AsString()] = n
+		recordTypes, _ := spanAttr(n, attrRecordTypes)
+		if got := recordTypes.AsStringSlice(); len(got) != 1 || got[0] != "system_state" {
+			t.Errorf("%s record types = %v, want [system_state]", device.AsString(), got)
+		}
+	}
+	if byDevice["dev1"] == nil || byDevice["dev2"] == nil {
+		t.Fatalf("expected notification spans for dev1 and dev2, got %v", byDevice)
+	}
+
+	// dev1: one unmarshal and one extract. dev2: a second, failed unmarshal
+	// with no extract.
+	unmarshals := spansNamed(spans, spanUnmarshal)
+	extracts := spansNamed(spans, spanExtract)
+	if len(unmarshals) != 3 || len(extracts) != 2 {
+		t.Fatalf("expected 3 unmarshal and 2 extract spans, got %d and %d", len(unmarshals), len(extracts))
+	}
+	var failed int
+	for _, s := range append(unmarshals, extracts...) {
+		if !childOf(s, byDevice["dev1"]) && !childOf(s, byDevice["dev2"]) {
+			t.Errorf("expected %s span to be a child of a notification span", s.Name())
+		}
+		if v, _ := spanAttr(s, attrRecordType); v.AsString() != "system_state" {
+			t.Errorf("%s record type = %q, want system_state", s.Name(), v.AsString())
+		}
+		if s.Status().Code == codes.Error {
+			failed++
+			if s.Name() != spanUnmarshal || !childOf(s, byDevice["dev2"]) {
+				t.Errorf("unexpected failed %s span", s.Name())
+			}
+		}
+	}
+	if failed != 1 {
+		t.Errorf("expected 1 failed span, got %d", failed)
+	}
+}
+
+func TestProcessor_TracingWriteError(t *testing.T) {
+	n := loadGoldenPrototext(t, "system_hostname.prototext").GetUpdate()
+	consumer := &sliceConsumer{batches: [][]*gpb.Notification{{n}}}
+	p, recorder := newTracedProcessor(t, consumer, failingWriter{})
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	for _, name := range []string{spanWrite, spanBatch} {
+		got := spansNamed(spans, name)
+		if len(got) != 1 {
+			t.Fatalf("expected 1 %s span, got %d", name, len(got))
+		}
+		if got[0].Status().Code != codes.Error {
+			t.Errorf("%s span status = %v, want error", name, got[0].Status().Code)
+		}
+	}
+	if events := spansNamed(spans, spanWrite)[0].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("expected the write error recorded as an exception event, got %v", events)
+	}
+}
+
+func TestProcessor_TracingDisabledByDefault(t *testing.T) {
+	p, err := NewProcessor(WithProcessorMetrics(newTestMetrics()))
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	_, span := p.tracer.Start(context.Background(), spanBatch)
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("expected the default tracer to be a no-op")
+	}
+}
@@ -19,7 +19,9 @@ type Record interface {
 // Metadata contains common fields extracted from gNMI notifications.
 type Metadata struct {
 	DevicePubkey string
+	Env          string // set by WithRecordEnv; empty if unset
 	Timestamp    time.Time
+	ClockSkew    bool // Timestamp was rewritten to receipt time by the clock skew guard
 }
 
 // PathMatcher is a function that determines if a gNMI path should be processed.
@@ -0,0 +1,122 @@
+package gnmi
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// writeQueueFlushTimeout bounds how long shutdown waits for queued batches to
+// be written.
+const writeQueueFlushTimeout = 30 * time.Second
+
+// WriteQueuePolicy is what the processor does with a new batch when the write
+// queue is full.
+type WriteQueuePolicy string
+
+const (
+	// WriteQueueBlock waits for the writer to make room, pausing consumption so
+	// lag builds up in Kafka instead of records being lost.
+	WriteQueueBlock WriteQueuePolicy = "block"
+	// WriteQueueDropOldest discards the oldest queued batch to make room,
+	// keeping consumption current at the cost of losing its records.
+	WriteQueueDropOldest WriteQueuePolicy = "drop-oldest"
+)
+
+// WithWriteQueue decouples extraction from writing with a queue of up to size
+// batches, drained by a single writer goroutine, so a slow writer only stalls
+// consumption once the queue is full; policy decides what happens then.
+// Offsets are still committed after each write, which also covers batches
+// consumed since, so queued batches are lost if the process crashes. They are
+// written on shutdown.
+func WithWriteQueue(size int, policy WriteQueuePolicy) ProcessorOption {
+	return func(p *Processor) {
+		p.writeQueueSize = size
+		p.writeQueuePolicy = policy
+	}
+}
+
+// queuedBatch is the extracted output of one consumed batch waiting to be
+// written.
+type queuedBatch struct {
+	spanCtx        trace.SpanContext // batch span, parent of the write span
+	records        []Record
+	commitSampling func()
+}
+
+// startWriteQueue starts the writer goroutine draining the write queue. The
+// returned function closes the queue and waits for the queued batches to be
+// written, cancelling writes still running after writeQueueFlushTimeout.
+func (p *Processor) startWriteQueue() (stop func()) {
+	p.queue = make(chan queuedBatch, p.writeQueueSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for b := range p.queue {
+			p.metrics.WriteQueueDepth.Set(float64(len(p.queue)))
+			p.writeBatch(trace.ContextWithSpanContext(ctx, b.spanCtx), b.records, b.commitSampling)
+		}
+	}()
+
+	return func() {
+		if n := len(p.queue); n > 0 {
+			p.logger.Info("flushing write queue", "batches", n)
+		}
+		close(p.queue)
+		timer := time.AfterFunc(writeQueueFlushTimeout, cancel)
+		defer timer.Stop()
+		<-done
+		cancel()
+	}
+}
+
+// enqueueBatch hands a batch to the writer goroutine, applying the write queue
+// policy if the queue is full.
+func (p *Processor) enqueueBatch(ctx context.Context, b queuedBatch) {
+	defer func() {
+		p.metrics.WriteQueueDepth.Set(float64(len(p.queue)))
+	}()
+
+	select {
+	case p.queue <- b:
+		return
+	default:
+	}
+	p.metrics.WriteQueueFull.Inc()
+
+	if p.writeQueuePolicy == WriteQueueDropOldest {
+		for {
+			select {
+			case old := <-p.queue:
+				p.dropQueuedBatch(old, "write queue full, dropping oldest batch")
+			default:
+			}
+			select {
+			case p.queue <- b:
+				return
+			default:
+			}
+		}
+	}
+
+	p.logger.Debug("write queue full, waiting for writer", "batches", len(p.queue))
+	select {
+	case p.queue <- b:
+	case <-ctx.Done():
+		// Shutting down: give the writer until the flush timeout to make room
+		// rather than losing the batch.
+		select {
+		case p.queue <- b:
+		case <-time.After(writeQueueFlushTimeout):
+			p.dropQueuedBatch(b, "write queue still full on shutdown, dropping batch")
+		}
+	}
+}
+
+func (p *Processor) dropQueuedBatch(b queuedBatch, msg string) {
+	p.logger.Warn(msg, "records_dropped", len(b.records))
+	p.metrics.WriteQueueDropped.Add(float64(len(b.records)))
+}
@@ -0,0 +1,185 @@
+package gnmi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+// countingConsumer counts Consume calls on a sliceConsumer. After the first
+// call, it waits for ready to be closed before returning a batch.
+type countingConsumer struct {
+	sliceConsumer
+	calls atomic.Int32
+	ready <-chan struct{}
+}
+
+func (c *countingConsumer) Consume(ctx context.Context) ([]*gpb.Notification, error) {
+	if c.calls.Add(1) > 1 {
+		<-c.ready
+	}
+	return c.sliceConsumer.Consume(ctx)
+}
+
+// slowWriter blocks every write until release is closed. started is closed
+// when the first write begins.
+type slowWriter struct {
+	captureWriter
+	release chan struct{}
+	started chan struct{}
+	once    sync.Once
+}
+
+func newSlowWriter() *slowWriter {
+	return &slowWriter{release: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (w *slowWriter) WriteRecords(ctx context.Context, records []Record) error {
+	w.once.Do(func() { close(w.started) })
+	select {
+	case <-w.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return w.captureWriter.WriteRecords(ctx, records)
+}
+
+// hostnameBatches returns n single-notification batches, each yielding one
+// system_state record stamped one second after the previous.
+func hostnameBatches(t *testing.T, n int) [][]*gpb.Notification {
+	t.Helper()
+	base := loadGoldenPrototext(t, "system_hostname.prototext").GetUpdate()
+	batches := make([][]*gpb.Notification, n)
+	for i := range batches {
+		notif := proto.Clone(base).(*gpb.Notification)
+		notif.Timestamp = base.GetTimestamp() + int64(i)*time.Second.Nanoseconds()
+		batches[i] = []*gpb.Notification{notif}
+	}
+	return batches
+}
+
+// writtenSeconds returns the offset in seconds of each written batch's first
+// record from the first batch's timestamp.
+func writtenSeconds(w *captureWriter, base time.Time) []int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []int
+	for _, batch := range w.batches {
+		out = append(out, int(recordTimestamp(batch[0]).Sub(base)/time.Second))
+	}
+	return out
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func runQueuedProcessor(t *testing.T, consumer Consumer, writer RecordWriter, policy WriteQueuePolicy) (*ProcessorMetrics, <-chan error) {
+	t.Helper()
+	metrics := newTestMetrics()
+	p, err := NewProcessor(
+		WithConsumer(consumer),
+		WithRecordWriter(writer),
+		WithProcessorMetrics(metrics),
+		WithWriteQueue(1, policy),
+	)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- p.Run(context.Background()) }()
+	return metrics, done
+}
+
+func TestProcessor_WriteQueueBlock(t *testing.T) {
+	batches := hostnameBatches(t, 4)
+	base := time.Unix(0, batches[0][0].GetTimestamp())
+	writer := newSlowWriter()
+	consumer := &countingConsumer{sliceConsumer: sliceConsumer{batches: batches}, ready: writer.started}
+
+	metrics, done := runQueuedProcessor(t, consumer, writer, WriteQueueBlock)
+
+	// The writer holds batch 0 and the queue holds batch 1, so batch 2 waits
+	// for room and consumption stops.
+	waitFor(t, "third consume", func() bool { return consumer.calls.Load() == 3 })
+	time.Sleep(50 * time.Millisecond)
+	if got := consumer.calls.Load(); got != 3 {
+		t.Fatalf("expected consumption to block at 3 calls, got %d", got)
+	}
+	if got := testutil.ToFloat64(metrics.WriteQueueDepth); got != 1 {
+		t.Errorf("expected write queue depth 1, got %v", got)
+	}
+
+	close(writer.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	// Every batch was written in order, including those queued at shutdown.
+	if got := writtenSeconds(&writer.captureWriter, base); len(got) != 4 || got[0] != 0 || got[1] != 1 || got[2] != 2 || got[3] != 3 {
+		t.Fatalf("expected batches [0 1 2 3] written, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.WriteQueueDropped); got != 0 {
+		t.Errorf("expected no dropped records, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.WriteQueueFull); got == 0 {
+		t.Error("expected the queue full counter to be incremented")
+	}
+	if got := metrics.RecordsProcessed.(*testCounter).val; got != 4 {
+		t.Errorf("expected 4 records processed, got %v", got)
+	}
+}
+
+func TestProcessor_WriteQueueDropOldest(t *testing.T) {
+	batches := hostnameBatches(t, 4)
+	base := time.Unix(0, batches[0][0].GetTimestamp())
+	writer := newSlowWriter()
+	consumer := &countingConsumer{sliceConsumer: sliceConsumer{batches: batches}, ready: writer.started}
+
+	metrics, done := runQueuedProcessor(t, consumer, writer, WriteQueueDropOldest)
+
+	// Consumption runs to the end of the input while the writer is stuck.
+	waitFor(t, "input exhausted", func() bool { return consumer.calls.Load() == 5 })
+
+	close(writer.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	// Batch 0 was being written; batches 1 and 2 were displaced by newer ones
+	// and the newest was flushed on shutdown.
+	if got := writtenSeconds(&writer.captureWriter, base); len(got) != 2 || got[0] != 0 || got[1] != 3 {
+		t.Fatalf("expected batches [0 3] written, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.WriteQueueDropped); got != 2 {
+		t.Errorf("expected 2 dropped records, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.WriteQueueDepth); got != 0 {
+		t.Errorf("expected empty write queue, got depth %v", got)
+	}
+}
+
+func TestProcessor_WriteQueueValidation(t *testing.T) {
+	if _, err := NewProcessor(WithWriteQueue(-1, WriteQueueBlock)); err == nil {
+		t.Error("expected error for negative write queue size")
+	}
+	if _, err := NewProcessor(WithWriteQueue(10, "drop-newest")); err == nil {
+		t.Error("expected error for unknown write queue policy")
+	}
+	if _, err := NewProcessor(WithWriteQueue(0, "")); err != nil {
+		t.Errorf("expected disabled write queue to need no policy, got %v", err)
+	}
+}
@@ -0,0 +1,367 @@
+-- +goose Up
+
+-- Flag gNMI records whose timestamp the gnmi-writer clock skew guard
+-- (--max-clock-skew with --clock-skew-action rewrite) replaced with receipt
+-- time, so they can be told apart from device-stamped records.
+-- +goose StatementBegin
+ALTER TABLE bgp_neighbors
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE interface_ifindex
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE interface_state
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_adjacencies
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_global_state
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_overload_bit
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE system_state
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transceiver_state
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transceiver_thresholds
+    ADD COLUMN IF NOT EXISTS clock_skew Bool AFTER env;
+-- +goose StatementEnd
+
+-- Recreate the latest views so SELECT * surfaces the new column.
+-- +goose StatementBegin
+DROP VIEW IF EXISTS bgp_neighbors_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS bgp_neighbors_latest AS
+SELECT *
+FROM bgp_neighbors
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM bgp_neighbors
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS interface_ifindex_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS interface_ifindex_latest AS
+SELECT *
+FROM interface_ifindex
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM interface_ifindex
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS interface_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS interface_state_latest AS
+SELECT *
+FROM interface_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM interface_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_adjacencies_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_adjacencies_latest AS
+SELECT *
+FROM isis_adjacencies
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM isis_adjacencies
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_global_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_global_state_latest AS
+SELECT *
+FROM isis_global_state
+WHERE (device_pubkey, network_instance, timestamp) IN (
+    SELECT device_pubkey, network_instance, max(timestamp)
+    FROM isis_global_state
+    GROUP BY device_pubkey, network_instance
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_overload_bit_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_overload_bit_latest AS
+SELECT *
+FROM isis_overload_bit
+WHERE (device_pubkey, network_instance, timestamp) IN (
+    SELECT device_pubkey, network_instance, max(timestamp)
+    FROM isis_overload_bit
+    GROUP BY device_pubkey, network_instance
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS system_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS system_state_latest AS
+SELECT *
+FROM system_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM system_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS transceiver_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS transceiver_state_latest AS
+SELECT *
+FROM transceiver_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM transceiver_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS transceiver_thresholds_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS transceiver_thresholds_latest AS
+SELECT *
+FROM transceiver_thresholds
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM transceiver_thresholds
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose Down
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS bgp_neighbors_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE bgp_neighbors
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS bgp_neighbors_latest AS
+SELECT *
+FROM bgp_neighbors
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM bgp_neighbors
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS interface_ifindex_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE interface_ifindex
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS interface_ifindex_latest AS
+SELECT *
+FROM interface_ifindex
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM interface_ifindex
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS interface_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE interface_state
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS interface_state_latest AS
+SELECT *
+FROM interface_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM interface_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_adjacencies_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_adjacencies
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_adjacencies_latest AS
+SELECT *
+FROM isis_adjacencies
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM isis_adjacencies
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_global_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_global_state
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_global_state_latest AS
+SELECT *
+FROM isis_global_state
+WHERE (device_pubkey, network_instance, timestamp) IN (
+    SELECT device_pubkey, network_instance, max(timestamp)
+    FROM isis_global_state
+    GROUP BY device_pubkey, network_instance
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS isis_overload_bit_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE isis_overload_bit
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS isis_overload_bit_latest AS
+SELECT *
+FROM isis_overload_bit
+WHERE (device_pubkey, network_instance, timestamp) IN (
+    SELECT device_pubkey, network_instance, max(timestamp)
+    FROM isis_overload_bit
+    GROUP BY device_pubkey, network_instance
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS system_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE system_state
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS system_state_latest AS
+SELECT *
+FROM system_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM system_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS transceiver_state_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transceiver_state
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS transceiver_state_latest AS
+SELECT *
+FROM transceiver_state
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM transceiver_state
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP VIEW IF EXISTS transceiver_thresholds_latest;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transceiver_thresholds
+    DROP COLUMN IF EXISTS clock_skew;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE VIEW IF NOT EXISTS transceiver_thresholds_latest AS
+SELECT *
+FROM transceiver_thresholds
+WHERE (device_pubkey, timestamp) IN (
+    SELECT device_pubkey, max(timestamp)
+    FROM transceiver_thresholds
+    GROUP BY device_pubkey
+);
+-- +goose StatementEnd
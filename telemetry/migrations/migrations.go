@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -11,7 +12,16 @@ import (
 	"github.com/pressly/goose/v3"
 )
 
-// RunMigrations applies pending goose migrations against ClickHouse.
+// ErrSchemaTooNew is returned when the database has a migration applied that
+// is newer than any migration embedded in this binary, i.e. it was migrated by a
+// newer release whose tables this binary may not write correctly.
+var ErrSchemaTooNew = errors.New("clickhouse schema is newer than this binary supports")
+
+// RunMigrations applies pending goose migrations against ClickHouse. Tables
+// are only created by migrations that have not been applied yet, so running it
+// against an up-to-date database issues no DDL. It returns ErrSchemaTooNew,
+// before applying anything, if the database is ahead of the embedded
+// migrations.
 func RunMigrations(addr, database, username, password string, secure bool, log *slog.Logger) error {
 	db, err := NewDB(addr, database, username, password, secure)
 	if err != nil {
@@ -19,16 +29,44 @@ func RunMigrations(addr, database, username, password string, secure bool, log *
 	}
 	defer func() { _ = db.Close() }()
 
+	return runMigrations(context.Background(), db, log)
+}
+
+// runMigrations checks the schema version of db against the embedded
+// migrations, then applies the pending ones.
+func runMigrations(ctx context.Context, db *sql.DB, log *slog.Logger) error {
 	provider, err := goose.NewProvider(goose.DialectClickHouse, db, FS, goose.WithSlog(log))
 	if err != nil {
 		return fmt.Errorf("goose provider: %w", err)
 	}
-	if _, err = provider.Up(context.Background()); err != nil {
+	// On a fresh database goose creates its version table here and reports
+	// version 0, so only a database migrated by a newer release is refused.
+	dbVersion, err := provider.GetDBVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("goose version: %w", err)
+	}
+	if err := checkSchemaVersion(dbVersion, provider.ListSources()); err != nil {
+		return err
+	}
+	if _, err = provider.Up(ctx); err != nil {
 		return fmt.Errorf("goose up: %w", err)
 	}
 	return nil
 }
 
+// checkSchemaVersion returns ErrSchemaTooNew if dbVersion is newer than the
+// latest of sources.
+func checkSchemaVersion(dbVersion int64, sources []*goose.Source) error {
+	var latest int64
+	for _, src := range sources {
+		latest = max(latest, src.Version)
+	}
+	if dbVersion > latest {
+		return fmt.Errorf("%w: database is at version %d, latest known migration is %d", ErrSchemaTooNew, dbVersion, latest)
+	}
+	return nil
+}
+
 // NewDB opens a ClickHouse database connection for use in tests or custom migration scenarios.
 func NewDB(addr, database, username, password string, secure bool) (*sql.DB, error) {
 	opts := &clickhouse.Options{
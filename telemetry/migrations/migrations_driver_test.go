@@ -0,0 +1,152 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClickHouse is an in-memory database/sql driver that answers the goose
+// ClickHouse dialect's version table queries and records every other
+// statement as DDL.
+type fakeClickHouse struct {
+	mu           sync.Mutex
+	versionTable bool
+	applied      []int64
+	ddl          []string
+}
+
+func (f *fakeClickHouse) Connect(context.Context) (driver.Conn, error) { return &fakeConn{f}, nil }
+func (f *fakeClickHouse) Driver() driver.Driver                        { return nil }
+
+func (f *fakeClickHouse) statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.ddl)
+}
+
+type fakeConn struct{ db *fakeClickHouse }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	f := c.db
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case strings.Contains(query, "CREATE TABLE IF NOT EXISTS goose_db_version"):
+		f.versionTable = true
+	case strings.HasPrefix(query, "INSERT INTO goose_db_version"):
+		f.applied = append(f.applied, args[0].Value.(int64))
+	default:
+		f.ddl = append(f.ddl, query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	f := c.db
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.versionTable {
+		return nil, errors.New("table goose_db_version does not exist")
+	}
+	switch {
+	case strings.HasPrefix(query, "SELECT tstamp, is_applied"):
+		rows := &fakeRows{columns: []string{"tstamp", "is_applied"}}
+		if slices.Contains(f.applied, args[0].Value.(int64)) {
+			rows.values = append(rows.values, []driver.Value{time.Now(), true})
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT max(version_id)"):
+		var latest driver.Value
+		if len(f.applied) > 0 {
+			latest = slices.Max(f.applied)
+		}
+		return &fakeRows{columns: []string{"max"}, values: [][]driver.Value{{latest}}}, nil
+	case strings.HasPrefix(query, "SELECT version_id, is_applied"):
+		rows := &fakeRows{columns: []string{"version_id", "is_applied"}}
+		versions := slices.Clone(f.applied)
+		slices.Sort(versions)
+		slices.Reverse(versions)
+		for _, v := range versions {
+			rows.values = append(rows.values, []driver.Value{v, true})
+		}
+		return rows, nil
+	}
+	return nil, errors.New("unexpected query: " + query)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newFakeDB(t *testing.T, f *fakeClickHouse) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(f)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestRunMigrations_DDLOnlyWhenTablesMissing(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := &fakeClickHouse{}
+	db := newFakeDB(t, f)
+
+	if err := runMigrations(context.Background(), db, log); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	created := f.statements()
+	if !slices.ContainsFunc(created, func(q string) bool { return strings.Contains(q, "CREATE TABLE") }) {
+		t.Fatalf("expected tables to be created on a fresh database, got %d statements", len(created))
+	}
+
+	if err := runMigrations(context.Background(), db, log); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if again := f.statements(); len(again) != len(created) {
+		t.Errorf("up-to-date database issued %d statements, want none", len(again)-len(created))
+	}
+}
+
+func TestRunMigrations_RefusesNewerSchemaBeforeDDL(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := &fakeClickHouse{versionTable: true, applied: []int64{0, 99990101000000}}
+	db := newFakeDB(t, f)
+
+	err := runMigrations(context.Background(), db, log)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+	if ddl := f.statements(); len(ddl) != 0 {
+		t.Errorf("expected no DDL against a newer schema, got %d statements", len(ddl))
+	}
+}
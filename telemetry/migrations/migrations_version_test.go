@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestCheckSchemaVersion(t *testing.T) {
+	sources := []*goose.Source{{Version: 20250303200212}, {Version: 20260410000001}}
+
+	for _, v := range []int64{0, 20250303200212, 20260410000001} {
+		if err := checkSchemaVersion(v, sources); err != nil {
+			t.Errorf("checkSchemaVersion(%d): unexpected error %v", v, err)
+		}
+	}
+	if err := checkSchemaVersion(20260410000002, sources); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew for a newer database, got %v", err)
+	}
+}
+
+func TestEmbeddedMigrationsHaveVersions(t *testing.T) {
+	names, err := fs.Glob(FS, "*.sql")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(names) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for _, name := range names {
+		if _, err := goose.NumericComponent(name); err != nil {
+			t.Errorf("migration %s has no version: %v", name, err)
+		}
+	}
+}
@@ -154,7 +154,8 @@ func reportRTTMetrics(b *testing.B, rtts []float64, failures int) {
 func startLinuxReflector(ctx context.Context, b *testing.B) *net.UDPAddr {
 	b.Helper()
 
-	reflector, err := twamplight.NewLinuxReflector("127.0.0.1:0", 100*time.Millisecond)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reflector, err := twamplight.NewLinuxReflector(log, "127.0.0.1:0", 100*time.Millisecond)
 	require.NoError(b, err)
 
 	runCtx, runCancel := context.WithCancel(ctx)
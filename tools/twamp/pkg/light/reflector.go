@@ -2,8 +2,10 @@ package twamplight
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net"
+	"syscall"
 	"time"
 )
 
@@ -15,12 +17,42 @@ type Reflector interface {
 	Run(ctx context.Context) error
 	Close() error
 	LocalAddr() *net.UDPAddr
+	// RecoverableErrors returns the number of transient socket errors the
+	// reflector has logged and continued past.
+	RecoverableErrors() uint64
 }
 
 func NewReflector(log *slog.Logger, addr string, timeout time.Duration) (Reflector, error) {
-	reflector, err := NewLinuxReflector(addr, timeout)
+	reflector, err := NewLinuxReflector(log, addr, timeout)
 	if err == ErrPlatformNotSupported {
 		return NewBasicReflector(log, addr, timeout)
 	}
 	return reflector, err
 }
+
+// recoverableSocketErrors are errors reported on a UDP socket that are caused by
+// the network or a single peer (e.g. an ICMP port-unreachable surfacing as
+// ECONNREFUSED) rather than by the socket itself, so the reflector keeps running.
+var recoverableSocketErrors = []error{
+	syscall.EINTR,
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.EHOSTUNREACH,
+	syscall.ENETUNREACH,
+	syscall.EHOSTDOWN,
+	syscall.ENETDOWN,
+	syscall.ENOBUFS,
+	syscall.ENOMEM,
+	syscall.EMSGSIZE,
+}
+
+// isRecoverableSocketError reports whether err is a transient socket error that
+// should be logged and skipped rather than stopping the reflector.
+func isRecoverableSocketError(err error) bool {
+	for _, target := range recoverableSocketErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,6 +26,12 @@ type BasicReflector struct {
 	conn    *net.UDPConn
 	timeout time.Duration
 	once    sync.Once
+
+	// readFrom and writeTo default to the conn methods; overridden in tests to inject errors.
+	readFrom func(b []byte) (int, *net.UDPAddr, error)
+	writeTo  func(b []byte, addr *net.UDPAddr) (int, error)
+
+	recoverableErrors atomic.Uint64
 }
 
 func NewBasicReflector(log *slog.Logger, addr string, timeout time.Duration) (*BasicReflector, error) {
@@ -38,9 +45,11 @@ func NewBasicReflector(log *slog.Logger, addr string, timeout time.Duration) (*B
 		return nil, fmt.Errorf("failed to listen on UDP port %d: %w", udpAddr.Port, err)
 	}
 	return &BasicReflector{
-		log:     log,
-		conn:    conn,
-		timeout: timeout,
+		log:      log,
+		conn:     conn,
+		timeout:  timeout,
+		readFrom: conn.ReadFromUDP,
+		writeTo:  conn.WriteToUDP,
 	}, nil
 }
 
@@ -89,7 +98,7 @@ func (r *BasicReflector) Run(ctx context.Context) error {
 		}
 
 		// Receive packet.
-		n, addr, err := r.conn.ReadFromUDP(buf)
+		n, addr, err := r.readFrom(buf)
 		if err != nil {
 			if ne, ok := err.(net.Error); ok && ne.Timeout() {
 				continue
@@ -98,8 +107,12 @@ func (r *BasicReflector) Run(ctx context.Context) error {
 				r.log.Debug("TWAMP reflector socket closed")
 				return nil
 			}
-			r.log.Error("error reading from UDP", "address", addr, "error", err)
-			continue
+			if isRecoverableSocketError(err) {
+				r.recoverableErrors.Add(1)
+				r.log.Debug("Recoverable error reading from UDP", "address", addr, "error", err)
+				continue
+			}
+			return fmt.Errorf("error reading from UDP: %w", err)
 		}
 
 		// Validate packet size.
@@ -131,7 +144,7 @@ func (r *BasicReflector) Run(ctx context.Context) error {
 		}
 
 		// Send response.
-		_, err = r.conn.WriteToUDP(buf[:n], addr)
+		_, err = r.writeTo(buf[:n], addr)
 		if err != nil {
 			if ne, ok := err.(net.Error); ok && ne.Timeout() {
 				continue
@@ -140,7 +153,9 @@ func (r *BasicReflector) Run(ctx context.Context) error {
 				r.log.Debug("TWAMP reflector socket closed")
 				return nil
 			}
-			r.log.Error("error writing to UDP", "address", addr, "error", err)
+			// Send failures are specific to the destination, so keep reflecting.
+			r.recoverableErrors.Add(1)
+			r.log.Debug("Recoverable error writing to UDP", "address", addr, "error", err)
 			continue
 		}
 	}
@@ -156,6 +171,11 @@ func (r *BasicReflector) Close() error {
 	return err
 }
 
+// RecoverableErrors returns the number of transient socket errors skipped by Run.
+func (r *BasicReflector) RecoverableErrors() uint64 {
+	return r.recoverableErrors.Load()
+}
+
 // LocalAddr returns the address the reflector is listening on.
 func (r *BasicReflector) LocalAddr() *net.UDPAddr {
 	addr := r.conn.LocalAddr().(*net.UDPAddr)
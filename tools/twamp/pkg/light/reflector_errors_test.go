@@ -0,0 +1,130 @@
+package twamplight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTWAMP_IsRecoverableSocketError(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(errno syscall.Errno) error {
+		return &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("recvfrom", errno)}
+	}
+
+	require.True(t, isRecoverableSocketError(syscall.ECONNREFUSED))
+	require.True(t, isRecoverableSocketError(wrap(syscall.ECONNREFUSED)))
+	require.True(t, isRecoverableSocketError(wrap(syscall.EHOSTUNREACH)))
+	require.True(t, isRecoverableSocketError(fmt.Errorf("wrapped: %w", syscall.ENOBUFS)))
+	require.False(t, isRecoverableSocketError(syscall.EBADF))
+	require.False(t, isRecoverableSocketError(wrap(syscall.ENOTSOCK)))
+	require.False(t, isRecoverableSocketError(errors.New("boom")))
+}
+
+func newTestBasicReflector(t *testing.T) *BasicReflector {
+	t.Helper()
+	r, err := NewBasicReflector(slog.New(slog.NewTextHandler(io.Discard, nil)), "127.0.0.1:0", 50*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestTWAMP_BasicReflector_RecoverableReadError(t *testing.T) {
+	t.Parallel()
+
+	r := newTestBasicReflector(t)
+	readFrom := r.readFrom
+	injected := 0
+	r.readFrom = func(b []byte) (int, *net.UDPAddr, error) {
+		if injected < 3 {
+			injected++
+			return 0, nil, &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("recvfrom", syscall.ECONNREFUSED)}
+		}
+		return readFrom(b)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	// The reflector keeps serving after the injected errors.
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+	payload := make([]byte, PacketSize)
+	payload[0] = 1
+	_, err = conn.Write(payload)
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, payload, buf[:n])
+	require.Equal(t, uint64(3), r.RecoverableErrors())
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("reflector did not exit on context cancel")
+	}
+}
+
+func TestTWAMP_BasicReflector_RecoverableWriteError(t *testing.T) {
+	t.Parallel()
+
+	r := newTestBasicReflector(t)
+	writes := make(chan struct{}, 1)
+	r.writeTo = func(b []byte, addr *net.UDPAddr) (int, error) {
+		writes <- struct{}{}
+		return 0, &net.OpError{Op: "write", Net: "udp", Err: os.NewSyscallError("sendto", syscall.EPERM)}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(make([]byte, PacketSize))
+	require.NoError(t, err)
+
+	select {
+	case <-writes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("reply was not attempted")
+	}
+	require.Eventually(t, func() bool { return r.RecoverableErrors() == 1 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case err := <-done:
+		t.Fatalf("reflector exited after a write error: %v", err)
+	default:
+	}
+}
+
+func TestTWAMP_BasicReflector_FatalReadError(t *testing.T) {
+	t.Parallel()
+
+	r := newTestBasicReflector(t)
+	r.readFrom = func(b []byte) (int, *net.UDPAddr, error) {
+		return 0, nil, &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("recvfrom", syscall.EBADF)}
+	}
+
+	err := r.Run(t.Context())
+	require.ErrorIs(t, err, syscall.EBADF)
+	require.Zero(t, r.RecoverableErrors())
+}
@@ -3,8 +3,10 @@ package twamplight
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
 	"runtime"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -12,15 +14,22 @@ import (
 )
 
 type LinuxReflector struct {
+	log      *slog.Logger
 	fd       int
 	epfd     int
 	port     uint16
 	timeout  time.Duration
 	shutdown chan struct{}
 	closed   chan struct{}
+
+	// recvfrom and sendto default to the unix syscalls; overridden in tests to inject errors.
+	recvfrom func(fd int, p []byte, flags int) (int, unix.Sockaddr, error)
+	sendto   func(fd int, p []byte, flags int, to unix.Sockaddr) error
+
+	recoverableErrors atomic.Uint64
 }
 
-func NewLinuxReflector(addr string, timeout time.Duration) (*LinuxReflector, error) {
+func NewLinuxReflector(log *slog.Logger, addr string, timeout time.Duration) (*LinuxReflector, error) {
 	udpAddr, err := net.ResolveUDPAddr("udp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("resolve addr: %w", err)
@@ -55,15 +64,23 @@ func NewLinuxReflector(addr string, timeout time.Duration) (*LinuxReflector, err
 	}
 
 	return &LinuxReflector{
+		log:      log,
 		fd:       fd,
 		epfd:     epfd,
 		port:     uint16(udpAddr.Port),
 		timeout:  timeout,
 		shutdown: make(chan struct{}),
 		closed:   make(chan struct{}),
+		recvfrom: unix.Recvfrom,
+		sendto:   unix.Sendto,
 	}, nil
 }
 
+// RecoverableErrors returns the number of transient socket errors skipped by Run.
+func (r *LinuxReflector) RecoverableErrors() uint64 {
+	return r.recoverableErrors.Load()
+}
+
 func (r *LinuxReflector) Run(ctx context.Context) error {
 	runtime.LockOSThread()
 	defer close(r.closed)
@@ -103,11 +120,18 @@ func (r *LinuxReflector) Run(ctx context.Context) error {
 
 		for {
 			// Receive packet.
-			n, from, err := unix.Recvfrom(r.fd, buf, 0)
+			n, from, err := r.recvfrom(r.fd, buf, 0)
 			if err != nil {
 				if err == syscall.EAGAIN || err == syscall.EWOULDBLOCK {
 					break
 				}
+				if isRecoverableSocketError(err) {
+					// The pending socket error is cleared by the failed read; go back
+					// to epoll_wait rather than spinning on recvfrom.
+					r.recoverableErrors.Add(1)
+					r.log.Debug("Recoverable error receiving TWAMP packet", "error", err)
+					break
+				}
 				return fmt.Errorf("recvfrom: %w", err)
 			}
 
@@ -123,7 +147,15 @@ func (r *LinuxReflector) Run(ctx context.Context) error {
 			}
 
 			// Send response.
-			_ = unix.Sendto(r.fd, buf[:n], 0, from)
+			if err := r.sendto(r.fd, buf[:n], 0, from); err != nil {
+				// Send failures are specific to the destination (unreachable, filtered,
+				// buffer full), so only a broken socket stops the reflector.
+				if err == syscall.EBADF || err == syscall.ENOTSOCK {
+					return fmt.Errorf("sendto: %w", err)
+				}
+				r.recoverableErrors.Add(1)
+				r.log.Debug("Recoverable error sending TWAMP reply", "error", err)
+			}
 		}
 	}
 }
@@ -0,0 +1,120 @@
+package twamplight
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestTWAMP_LinuxReflector_RecoverableRecvError(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewLinuxReflector(slog.New(slog.NewTextHandler(io.Discard, nil)), "127.0.0.1:0", 100*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+
+	var injected atomic.Bool
+	r.recvfrom = func(fd int, p []byte, flags int) (int, unix.Sockaddr, error) {
+		if injected.CompareAndSwap(false, true) {
+			return 0, nil, syscall.ECONNREFUSED
+		}
+		return unix.Recvfrom(fd, p, flags)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The first packet triggers the injected error and is left queued; the
+	// reflector must survive and reflect it on the next wakeup.
+	payload := make([]byte, PacketSize)
+	payload[0] = 1
+	_, err = conn.Write(payload)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, payload, buf[:n])
+	require.Equal(t, uint64(1), r.RecoverableErrors())
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("reflector did not exit on context cancel")
+	}
+}
+
+func TestTWAMP_LinuxReflector_RecoverableSendError(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewLinuxReflector(slog.New(slog.NewTextHandler(io.Discard, nil)), "127.0.0.1:0", 100*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+
+	r.sendto = func(fd int, p []byte, flags int, to unix.Sockaddr) error {
+		return syscall.EPERM
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(make([]byte, PacketSize))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return r.RecoverableErrors() == 1 }, 2*time.Second, 10*time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("reflector exited after a send error: %v", err)
+	default:
+	}
+}
+
+func TestTWAMP_LinuxReflector_FatalRecvError(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewLinuxReflector(slog.New(slog.NewTextHandler(io.Discard, nil)), "127.0.0.1:0", 100*time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+
+	r.recvfrom = func(fd int, p []byte, flags int) (int, unix.Sockaddr, error) {
+		return 0, nil, syscall.EBADF
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- r.Run(t.Context()) }()
+
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(make([]byte, PacketSize))
+	require.NoError(t, err)
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, syscall.EBADF)
+	case <-time.After(2 * time.Second):
+		t.Fatal("reflector did not exit on fatal error")
+	}
+	require.Zero(t, r.RecoverableErrors())
+}
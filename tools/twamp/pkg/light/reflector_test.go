@@ -18,7 +18,7 @@ func TestTWAMP_Reflector_Linux(t *testing.T) {
 	}
 
 	runReflectorTests(t, func(addr string) (twamplight.Reflector, error) {
-		return twamplight.NewLinuxReflector(addr, 100*time.Millisecond)
+		return twamplight.NewLinuxReflector(log, addr, 100*time.Millisecond)
 	})
 }
 
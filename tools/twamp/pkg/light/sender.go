@@ -2,6 +2,7 @@ package twamplight
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net"
 	"time"
@@ -11,16 +12,50 @@ var (
 	defaultProbeTimeout = 1 * time.Second
 )
 
+// MaxDSCP is the largest valid DSCP value (6 bits).
+const MaxDSCP = 63
+
+// SenderOption configures optional sender behavior.
+type SenderOption func(*senderOptions)
+
+type senderOptions struct {
+	dscp uint8
+}
+
+// WithDSCP marks outgoing probes with the given DSCP value. The default is 0
+// (best effort).
+func WithDSCP(dscp uint8) SenderOption {
+	return func(o *senderOptions) {
+		o.dscp = dscp
+	}
+}
+
+func newSenderOptions(opts []SenderOption) (senderOptions, error) {
+	var o senderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.dscp > MaxDSCP {
+		return o, fmt.Errorf("invalid DSCP %d: must be 0-%d", o.dscp, MaxDSCP)
+	}
+	return o, nil
+}
+
+// tos returns the IP TOS byte carrying the DSCP value, with ECN bits cleared.
+func (o senderOptions) tos() int {
+	return int(o.dscp) << 2
+}
+
 type Sender interface {
 	Probe(ctx context.Context) (time.Duration, error)
 	Close() error
 	LocalAddr() *net.UDPAddr
 }
 
-func NewSender(ctx context.Context, log *slog.Logger, iface string, localAddr, remoteAddr *net.UDPAddr) (Sender, error) {
-	sender, err := NewLinuxSender(ctx, iface, localAddr, remoteAddr)
+func NewSender(ctx context.Context, log *slog.Logger, iface string, localAddr, remoteAddr *net.UDPAddr, opts ...SenderOption) (Sender, error) {
+	sender, err := NewLinuxSender(ctx, iface, localAddr, remoteAddr, opts...)
 	if err == ErrPlatformNotSupported {
-		return NewBasicSender(ctx, log, iface, localAddr, remoteAddr)
+		return NewBasicSender(ctx, log, iface, localAddr, remoteAddr, opts...)
 	}
 	return sender, err
 }
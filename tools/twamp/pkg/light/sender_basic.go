@@ -7,6 +7,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"golang.org/x/net/ipv4"
 )
 
 type BasicSender struct {
@@ -23,7 +25,11 @@ type BasicSender struct {
 	receivedMu sync.Mutex
 }
 
-func NewBasicSender(ctx context.Context, log *slog.Logger, iface string, localAddr, remoteAddr *net.UDPAddr) (*BasicSender, error) {
+func NewBasicSender(ctx context.Context, log *slog.Logger, iface string, localAddr, remoteAddr *net.UDPAddr, opts ...SenderOption) (*BasicSender, error) {
+	options, err := newSenderOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 	if iface != "" {
 		_, err := net.InterfaceByName(iface)
 		if err != nil {
@@ -37,6 +43,12 @@ func NewBasicSender(ctx context.Context, log *slog.Logger, iface string, localAd
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial: %w", err)
 	}
+	if options.dscp != 0 {
+		if err := ipv4.NewConn(conn).SetTOS(options.tos()); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to set DSCP %d: %w", options.dscp, err)
+		}
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	s := &BasicSender{
 		log:      log,
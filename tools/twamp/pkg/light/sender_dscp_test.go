@@ -0,0 +1,45 @@
+package twamplight
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestTWAMP_SenderOptions_DSCP(t *testing.T) {
+	t.Parallel()
+
+	o, err := newSenderOptions(nil)
+	require.NoError(t, err)
+	require.Zero(t, o.dscp)
+	require.Zero(t, o.tos())
+
+	o, err = newSenderOptions([]SenderOption{WithDSCP(46)})
+	require.NoError(t, err)
+	require.Equal(t, 0xb8, o.tos())
+
+	_, err = newSenderOptions([]SenderOption{WithDSCP(MaxDSCP + 1)})
+	require.ErrorContains(t, err, "invalid DSCP 64")
+}
+
+func TestTWAMP_BasicSender_DSCP(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	remote := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9}
+
+	s, err := NewBasicSender(t.Context(), log, "", nil, remote, WithDSCP(34))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	tos, err := ipv4.NewConn(s.conn).TOS()
+	require.NoError(t, err)
+	require.Equal(t, 34<<2, tos)
+
+	_, err = NewBasicSender(t.Context(), log, "", nil, remote, WithDSCP(64))
+	require.Error(t, err)
+}
@@ -26,12 +26,24 @@ type LinuxSender struct {
 	receivedMu sync.Mutex
 }
 
-func NewLinuxSender(ctx context.Context, iface string, local *net.UDPAddr, remote *net.UDPAddr) (*LinuxSender, error) {
+func NewLinuxSender(ctx context.Context, iface string, local *net.UDPAddr, remote *net.UDPAddr, opts ...SenderOption) (*LinuxSender, error) {
+	options, err := newSenderOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_NONBLOCK, unix.IPPROTO_UDP)
 	if err != nil {
 		return nil, fmt.Errorf("socket: %w", err)
 	}
 
+	if options.dscp != 0 {
+		if err := unix.SetsockoptInt(fd, unix.IPPROTO_IP, unix.IP_TOS, options.tos()); err != nil {
+			unix.Close(fd)
+			return nil, fmt.Errorf("IP_TOS(dscp=%d): %w", options.dscp, err)
+		}
+	}
+
 	if iface != "" {
 		if err := unix.SetsockoptString(fd, unix.SOL_SOCKET, unix.SO_BINDTODEVICE, iface); err != nil {
 			unix.Close(fd)
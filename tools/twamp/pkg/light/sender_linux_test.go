@@ -1,8 +1,12 @@
 package twamplight
 
 import (
+	"net"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func TestDecideRTT(t *testing.T) {
@@ -45,3 +49,26 @@ func TestDecideRTT(t *testing.T) {
 		})
 	}
 }
+
+func TestTWAMP_LinuxSender_DSCP(t *testing.T) {
+	t.Parallel()
+
+	remote := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9}
+
+	s, err := NewLinuxSender(t.Context(), "", nil, remote, WithDSCP(46))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	tos, err := unix.GetsockoptInt(s.fd, unix.IPPROTO_IP, unix.IP_TOS)
+	require.NoError(t, err)
+	require.Equal(t, 46<<2, tos)
+
+	// Best effort leaves the socket default untouched.
+	s2, err := NewLinuxSender(t.Context(), "", nil, remote)
+	require.NoError(t, err)
+	t.Cleanup(func() { s2.Close() })
+
+	tos, err = unix.GetsockoptInt(s2.fd, unix.IPPROTO_IP, unix.IP_TOS)
+	require.NoError(t, err)
+	require.Zero(t, tos)
+}
@@ -21,7 +21,7 @@ func TestTWAMP_Sender_Linux(t *testing.T) {
 	runSenderTests(t, func(iface string, localAddr, remoteAddr *net.UDPAddr) (twamplight.Sender, error) {
 		return twamplight.NewLinuxSender(t.Context(), iface, localAddr, remoteAddr)
 	}, func(addr string) (twamplight.Reflector, error) {
-		return twamplight.NewLinuxReflector(addr, 100*time.Millisecond)
+		return twamplight.NewLinuxReflector(log, addr, 100*time.Millisecond)
 	})
 }
 
@@ -5,14 +5,15 @@ package twamplight
 
 import (
 	"context"
+	"log/slog"
 	"net"
 	"time"
 )
 
-func NewLinuxSender(ctx context.Context, iface string, localAddr, remoteAddr *net.UDPAddr) (Sender, error) {
+func NewLinuxSender(ctx context.Context, iface string, localAddr, remoteAddr *net.UDPAddr, opts ...SenderOption) (Sender, error) {
 	return nil, ErrPlatformNotSupported
 }
 
-func NewLinuxReflector(addr string, timeout time.Duration) (Reflector, error) {
+func NewLinuxReflector(log *slog.Logger, addr string, timeout time.Duration) (Reflector, error) {
 	return nil, ErrPlatformNotSupported
 }